@@ -0,0 +1,80 @@
+// bootstrap-admin gives a member the admin role
+//
+//	use it to create the first admin, after that admins can assign roles from the dashboard
+//
+//	Usage:
+//	  go run ./cmd/bootstrap-admin member@example.com
+package main
+
+import (
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/dbstore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// auditActor is who the audit log says made the change
+const auditActor = "bootstrap-admin"
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("provide the email of the member to make an admin")
+	}
+
+	c, _ := config.Load()
+	if c.DBConnectionString == "" {
+		log.Fatal("DB_CONNECTION_STRING is not set, the in memory store doesn't outlive this command")
+	}
+
+	db, err := dbstore.Setup()
+	if err != nil {
+		log.Fatalf("error setting up db: %s", err)
+	}
+
+	email := strings.ToLower(os.Args[1])
+	if err := bootstrapAdmin(db, email); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Infof("%s is now an admin, they need to log in again to pick up the role", email)
+}
+
+// bootstrapAdmin adds the admin role to a member's existing roles
+func bootstrapAdmin(store datastore.DataStore, email string) error {
+	if _, err := store.GetMemberByEmail(email); err != nil {
+		return err
+	}
+
+	roles, err := store.GetMemberRoles(email)
+	if err != nil {
+		return err
+	}
+
+	for _, role := range roles {
+		if role == models.RoleAdmin {
+			return nil
+		}
+	}
+
+	roles = append(roles, models.RoleAdmin)
+	if err := store.SetMemberRoles(email, roles); err != nil {
+		return err
+	}
+
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+
+	return store.LogAuditEvent(models.AuditEntry{
+		Actor:  auditActor,
+		Action: models.AuditActionRoles,
+		Target: email,
+		Detail: strings.Join(names, ","),
+	})
+}
@@ -17,8 +17,11 @@ import (
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/dbstore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/anomaly"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/logger"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/member"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/occupancy"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/scheduler"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/scheduler/jobs"
@@ -51,9 +54,18 @@ func main() {
 	rm := resourcemanager.New(mqtt.New(), db, slack.Notifier{WebHookURL: c.SlackAccessEvents}, log)
 	pp := paypal.Setup(c.PaypalURL, c.PaypalClientID, c.PaypalClientSecret, log)
 
-	auth := auth.New(db)
-	api := controllers.Setup(db, auth, rm, pp, log)
-	router := router.New(api, auth)
+	occ := occupancy.New(db, rm.MQTT(), slack.Notifier{WebHookURL: c.SlackAccessEvents}, log, c)
+	accessEvents, _ := rm.AccessEvents().Subscribe()
+	go occ.Run(accessEvents)
+
+	mailAPI, _ := mail.Setup()
+	detector := anomaly.New(db, slack.Notifier{WebHookURL: c.SlackAccessEvents}, mail.NewMailer(db, mailAPI, c), log, c)
+	anomalyEvents, _ := rm.AccessEvents().Subscribe()
+	go detector.Run(anomalyEvents)
+
+	auth := auth.New(db, mail.NewMailer(db, mailAPI, c))
+	api := controllers.Setup(db, auth, rm, pp, occ, log)
+	router := router.New(api, auth, db)
 
 	srv := &http.Server{
 		Handler: router.UnAuthedRouter,
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"strconv"
 
 	log "github.com/sirupsen/logrus"
 )
@@ -34,7 +35,76 @@ type Config struct {
 	EmailOverrideAddress string `json:"emailOverrideAddress"`
 	SlackToken           string `json:"slackToken"`
 	AdminEmail           string `json:"adminEmail"`
-	AlwaysAdmin          string `json:"alwaysAdmin"`
+	// SlackSigningSecret verifies slash commands from slack. Slash commands are disabled without it
+	SlackSigningSecret string `json:"slackSigningSecret"`
+	// SlackDoorModeUsers is a comma separated list of slack user IDs that can change door modes
+	SlackDoorModeUsers string `json:"slackDoorModeUsers"`
+	// OccupancyDwellMinutes is how long a member counts as being in the space after a swipe
+	OccupancyDwellMinutes int `json:"occupancyDwellMinutes"`
+	// OccupancyExitReaders is a comma separated list of resource names that members swipe on the way out
+	OccupancyExitReaders string `json:"occupancyExitReaders"`
+	// OccupancyTopic is the mqtt topic that occupancy changes are published to
+	OccupancyTopic string `json:"occupancyTopic"`
+	// KeyholderCertification is the name of the certification that makes a member a keyholder
+	KeyholderCertification string `json:"keyholderCertification"`
+	// AnomalyImpossibleTravel alerts when a fob is used at two doors within AnomalyImpossibleTravelSeconds
+	AnomalyImpossibleTravel        bool `json:"anomalyImpossibleTravel"`
+	AnomalyImpossibleTravelSeconds int  `json:"anomalyImpossibleTravelSeconds"`
+	// AnomalyInactiveMember alerts when a member whose level is Inactive swipes
+	AnomalyInactiveMember bool `json:"anomalyInactiveMember"`
+	// AnomalyFirstNightAccess alerts the first time a member swipes between AnomalyNightStartHour and AnomalyNightEndHour
+	AnomalyFirstNightAccess bool `json:"anomalyFirstNightAccess"`
+	AnomalyNightStartHour   int  `json:"anomalyNightStartHour"`
+	AnomalyNightEndHour     int  `json:"anomalyNightEndHour"`
+	// AnomalyDeniedBurst alerts when a door denies AnomalyDeniedBurstCount swipes within AnomalyDeniedBurstSeconds
+	AnomalyDeniedBurst        bool `json:"anomalyDeniedBurst"`
+	AnomalyDeniedBurstCount   int  `json:"anomalyDeniedBurstCount"`
+	AnomalyDeniedBurstSeconds int  `json:"anomalyDeniedBurstSeconds"`
+	// AccessEventRetentionDays is how many days of raw access events to keep, 0 keeps them forever
+	AccessEventRetentionDays int `json:"accessEventRetentionDays"`
+	// AccessEventRetentionMode is what happens to expired access events, either "delete" or "pseudonymise"
+	AccessEventRetentionMode string `json:"accessEventRetentionMode"`
+	// AccessEventPseudonymKey is the HMAC key fobs are pseudonymised with, it's needed for the pseudonymise mode
+	AccessEventPseudonymKey string `json:"accessEventPseudonymKey"`
+	// AccessEventArchiveDir is where expired access events are archived as gzipped ndjson, leave empty to skip archiving
+	AccessEventArchiveDir string `json:"accessEventArchiveDir"`
+	// MQTTDiscoveryTopic is where new devices announce themselves
+	MQTTDiscoveryTopic string `json:"mqttDiscoveryTopic"`
+	// MQTTProvisionTopic is where an approved device is told its name, the device's mac address is appended
+	MQTTProvisionTopic string `json:"mqttProvisionTopic"`
+	// PublicURL is where members reach the dashboard, links in emails start with it
+	PublicURL string `json:"publicURL"`
+	// EmailVerificationHours is how long an email verification link works for
+	EmailVerificationHours int `json:"emailVerificationHours"`
+	// PasswordResetMinutes is how long a password reset link works for
+	PasswordResetMinutes int `json:"passwordResetMinutes"`
+	// OIDCIssuer is the OpenID Connect provider members can sign in with, sign in with OIDC is disabled without it
+	OIDCIssuer       string `json:"oidcIssuer"`
+	OIDCClientID     string `json:"oidcClientID"`
+	OIDCClientSecret string `json:"oidcClientSecret"`
+	// OIDCProviderName is shown on the sign in button, e.g. "Google"
+	OIDCProviderName string `json:"oidcProviderName"`
+	// RequireAdminTwoFactor makes users whose roles can assign roles use two-factor authentication
+	RequireAdminTwoFactor bool `json:"requireAdminTwoFactor"`
+	// TwoFactorIssuer is the account name shown in authenticator apps
+	TwoFactorIssuer string `json:"twoFactorIssuer"`
+	// TwoFactorSecretKey encrypts authenticator app secrets in the database, they're encrypted with a key derived from AccessSecret without it
+	TwoFactorSecretKey string `json:"twoFactorSecretKey"`
+	// AccessTokenMinutes is how long an access token works for, the dashboard refreshes it before then
+	AccessTokenMinutes int `json:"accessTokenMinutes"`
+	// RefreshTokenDays is how long a session lasts without being used
+	RefreshTokenDays int `json:"refreshTokenDays"`
+	// LoginMaxAttempts is how many failed logins lock an account for LoginLockoutMinutes
+	LoginMaxAttempts    int `json:"loginMaxAttempts"`
+	LoginLockoutMinutes int `json:"loginLockoutMinutes"`
+	// LoginIPMaxAttempts is how many failed logins from one address stop it signing in for LoginLockoutMinutes
+	LoginIPMaxAttempts int `json:"loginIPMaxAttempts"`
+	// RateLimitPerMinute is how many requests an address can make each minute to routes that send emails or make accounts
+	RateLimitPerMinute int `json:"rateLimitPerMinute"`
+	// RateLimitStore is where rate limit counters are kept, either "memory" or "database"
+	RateLimitStore string `json:"rateLimitStore"`
+	// TrustedProxies is a comma separated list of addresses and CIDR ranges whose X-Forwarded-For header is believed
+	TrustedProxies string `json:"trustedProxies"`
 }
 
 // Get gets the config and ignores errors
@@ -68,7 +138,45 @@ func Load() (Config, error) {
 	c.EnableNotificationEmailsToMembers = false
 	c.SlackToken = os.Getenv("SLACK_TOKEN")
 	c.AdminEmail = getEnvOrDefault("ADMIN_EMAIL", "info@hackrva.org")
-	c.AlwaysAdmin = getEnvOrDefault("ALWAYS_ADMIN", "false")
+	c.SlackSigningSecret = os.Getenv("SLACK_SIGNING_SECRET")
+	c.SlackDoorModeUsers = os.Getenv("SLACK_DOOR_MODE_USERS")
+	c.OccupancyDwellMinutes = getEnvIntOrDefault("OCCUPANCY_DWELL_MINUTES", 240)
+	c.OccupancyExitReaders = os.Getenv("OCCUPANCY_EXIT_READERS")
+	c.OccupancyTopic = getEnvOrDefault("OCCUPANCY_TOPIC", "occupancy")
+	c.KeyholderCertification = getEnvOrDefault("KEYHOLDER_CERTIFICATION", "Keyholder")
+	c.AnomalyImpossibleTravel = getEnvBoolOrDefault("ANOMALY_IMPOSSIBLE_TRAVEL", true)
+	c.AnomalyImpossibleTravelSeconds = getEnvIntOrDefault("ANOMALY_IMPOSSIBLE_TRAVEL_SECONDS", 30)
+	c.AnomalyInactiveMember = getEnvBoolOrDefault("ANOMALY_INACTIVE_MEMBER", true)
+	c.AnomalyFirstNightAccess = getEnvBoolOrDefault("ANOMALY_FIRST_NIGHT_ACCESS", true)
+	c.AnomalyNightStartHour = getEnvIntOrDefault("ANOMALY_NIGHT_START_HOUR", 22)
+	c.AnomalyNightEndHour = getEnvIntOrDefault("ANOMALY_NIGHT_END_HOUR", 6)
+	c.AnomalyDeniedBurst = getEnvBoolOrDefault("ANOMALY_DENIED_BURST", true)
+	c.AnomalyDeniedBurstCount = getEnvIntOrDefault("ANOMALY_DENIED_BURST_COUNT", 5)
+	c.AnomalyDeniedBurstSeconds = getEnvIntOrDefault("ANOMALY_DENIED_BURST_SECONDS", 120)
+	c.AccessEventRetentionDays = getEnvIntOrDefault("ACCESS_EVENT_RETENTION_DAYS", 0)
+	c.AccessEventRetentionMode = getEnvOrDefault("ACCESS_EVENT_RETENTION_MODE", "pseudonymise")
+	c.AccessEventPseudonymKey = os.Getenv("ACCESS_EVENT_PSEUDONYM_KEY")
+	c.AccessEventArchiveDir = os.Getenv("ACCESS_EVENT_ARCHIVE_DIR")
+	c.MQTTDiscoveryTopic = getEnvOrDefault("MQTT_DISCOVERY_TOPIC", "memberserver/discovery")
+	c.MQTTProvisionTopic = getEnvOrDefault("MQTT_PROVISION_TOPIC", "memberserver/provision")
+	c.PublicURL = getEnvOrDefault("PUBLIC_URL", "http://localhost:3000")
+	c.EmailVerificationHours = getEnvIntOrDefault("EMAIL_VERIFICATION_HOURS", 48)
+	c.PasswordResetMinutes = getEnvIntOrDefault("PASSWORD_RESET_MINUTES", 60)
+	c.OIDCIssuer = os.Getenv("OIDC_ISSUER")
+	c.OIDCClientID = os.Getenv("OIDC_CLIENT_ID")
+	c.OIDCClientSecret = os.Getenv("OIDC_CLIENT_SECRET")
+	c.OIDCProviderName = getEnvOrDefault("OIDC_PROVIDER_NAME", "single sign-on")
+	c.RequireAdminTwoFactor = getEnvBoolOrDefault("REQUIRE_ADMIN_2FA", true)
+	c.TwoFactorIssuer = getEnvOrDefault("TWO_FACTOR_ISSUER", "HackRVA")
+	c.TwoFactorSecretKey = os.Getenv("TWO_FACTOR_SECRET_KEY")
+	c.AccessTokenMinutes = getEnvIntOrDefault("ACCESS_TOKEN_MINUTES", 15)
+	c.RefreshTokenDays = getEnvIntOrDefault("REFRESH_TOKEN_DAYS", 14)
+	c.LoginMaxAttempts = getEnvIntOrDefault("LOGIN_MAX_ATTEMPTS", 10)
+	c.LoginLockoutMinutes = getEnvIntOrDefault("LOGIN_LOCKOUT_MINUTES", 15)
+	c.LoginIPMaxAttempts = getEnvIntOrDefault("LOGIN_IP_MAX_ATTEMPTS", 50)
+	c.RateLimitPerMinute = getEnvIntOrDefault("RATE_LIMIT_PER_MINUTE", 10)
+	c.RateLimitStore = getEnvOrDefault("RATE_LIMIT_STORE", "memory")
+	c.TrustedProxies = os.Getenv("TRUSTED_PROXIES")
 
 	if len(os.Getenv("ENABLE_INFO_EMAILS")) > 0 {
 		c.EnableInfoEmails = true
@@ -107,3 +215,31 @@ func getEnvOrDefault(key string, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		log.Errorf("%s should be a number: %s", key, err)
+		return defaultValue
+	}
+	return i
+}
+
+func getEnvBoolOrDefault(key string, defaultValue bool) bool {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		log.Errorf("%s should be true or false: %s", key, err)
+		return defaultValue
+	}
+	return b
+}
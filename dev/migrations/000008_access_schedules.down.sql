@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.access_schedules;
+
+ALTER TABLE membership.resources
+DROP COLUMN IF EXISTS supports_time_windows;
+
+COMMIT;
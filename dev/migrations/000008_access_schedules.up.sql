@@ -0,0 +1,16 @@
+ALTER TABLE membership.resources
+ADD COLUMN supports_time_windows boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS membership.access_schedules
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    resource_id UUID NOT NULL,
+    member_tier_id integer NOT NULL DEFAULT 0,
+    weekdays integer[] NOT NULL,
+    start_time text NOT NULL,
+    end_time text NOT NULL,
+    CONSTRAINT resource
+        FOREIGN KEY (resource_id)
+            REFERENCES membership.resources(id)
+            ON DELETE CASCADE
+);
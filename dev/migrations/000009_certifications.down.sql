@@ -0,0 +1,13 @@
+BEGIN;
+
+DELETE FROM membership.communication_log
+WHERE communication_id IN (SELECT id FROM membership.communication WHERE name = 'CertificationExpired');
+
+DELETE FROM membership.communication
+WHERE name = 'CertificationExpired';
+
+DROP TABLE IF EXISTS membership.resource_certifications;
+DROP TABLE IF EXISTS membership.member_certifications;
+DROP TABLE IF EXISTS membership.certifications;
+
+COMMIT;
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS membership.certifications
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    description text NOT NULL DEFAULT '',
+    valid_for_days integer NOT NULL DEFAULT 0 CHECK (valid_for_days >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS membership.member_certifications
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    member_id UUID NOT NULL REFERENCES membership.members(id),
+    certification_id UUID NOT NULL REFERENCES membership.certifications(id) ON DELETE CASCADE,
+    trainer_email text NOT NULL,
+    trained_at timestamp NOT NULL DEFAULT NOW(),
+    expires_at timestamp DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS membership.resource_certifications
+(
+    resource_id UUID NOT NULL REFERENCES membership.resources(id) ON DELETE CASCADE,
+    certification_id UUID NOT NULL REFERENCES membership.certifications(id) ON DELETE CASCADE,
+    PRIMARY KEY (resource_id, certification_id)
+);
+
+INSERT INTO membership.communication
+    (name, subject, frequency_throttle, template)
+VALUES
+    ('CertificationExpired', 'Equipment Certification Expired', 0, 'certification_expired.html.tmpl');
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.audit_log;
+
+ALTER TABLE membership.member_resource
+DROP COLUMN IF EXISTS valid_from,
+DROP COLUMN IF EXISTS valid_until;
+
+COMMIT;
@@ -0,0 +1,13 @@
+ALTER TABLE membership.member_resource
+ADD COLUMN valid_from timestamp DEFAULT NULL,
+ADD COLUMN valid_until timestamp DEFAULT NULL;
+
+CREATE TABLE IF NOT EXISTS membership.audit_log
+(
+    id BIGSERIAL PRIMARY KEY,
+    created_at timestamp NOT NULL DEFAULT NOW(),
+    actor text NOT NULL,
+    action text NOT NULL,
+    target text NOT NULL,
+    detail text NOT NULL DEFAULT ''
+);
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.member_resource_groups;
+DROP TABLE IF EXISTS membership.resource_group_resources;
+DROP TABLE IF EXISTS membership.resource_groups;
+
+COMMIT;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS membership.resource_groups
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name text NOT NULL UNIQUE,
+    description text NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS membership.resource_group_resources
+(
+    group_id UUID NOT NULL REFERENCES membership.resource_groups(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES membership.resources(id) ON DELETE CASCADE,
+    PRIMARY KEY (group_id, resource_id)
+);
+
+CREATE TABLE IF NOT EXISTS membership.member_resource_groups
+(
+    member_id UUID NOT NULL REFERENCES membership.members(id) ON DELETE CASCADE,
+    group_id UUID NOT NULL REFERENCES membership.resource_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (member_id, group_id)
+);
@@ -0,0 +1,12 @@
+-- a row with no resource_id applies to every resource
+CREATE TABLE IF NOT EXISTS membership.door_modes
+(
+    resource_id UUID REFERENCES membership.resources(id) ON DELETE CASCADE,
+    mode text NOT NULL CHECK (mode IN ('lockdown', 'holdopen')),
+    reason text NOT NULL DEFAULT '',
+    set_by text NOT NULL,
+    set_at timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS door_modes_resource_idx
+ON membership.door_modes ((COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::uuid)));
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS membership.open_windows
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    resource_id UUID NOT NULL REFERENCES membership.resources(id) ON DELETE CASCADE,
+    title text NOT NULL,
+    start_time timestamp with time zone NOT NULL,
+    end_time timestamp with time zone NOT NULL CHECK (end_time > start_time),
+    rrule text NOT NULL DEFAULT '',
+    hold_open boolean NOT NULL DEFAULT true
+);
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS membership.access_events_member_id_idx;
+DROP INDEX IF EXISTS membership.access_events_event_time_idx;
+
+ALTER TABLE membership.access_events
+DROP COLUMN IF EXISTS member_id;
+
+COMMIT;
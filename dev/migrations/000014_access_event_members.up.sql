@@ -0,0 +1,10 @@
+ALTER TABLE membership.access_events
+ADD COLUMN member_id UUID DEFAULT NULL REFERENCES membership.members(id) ON DELETE SET NULL;
+
+UPDATE membership.access_events
+SET member_id = members.id
+FROM membership.members
+WHERE access_events.rfid = members.rfid AND members.rfid <> '';
+
+CREATE INDEX IF NOT EXISTS access_events_event_time_idx ON membership.access_events (event_time DESC, id DESC);
+CREATE INDEX IF NOT EXISTS access_events_member_id_idx ON membership.access_events (member_id);
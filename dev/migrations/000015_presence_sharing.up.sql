@@ -0,0 +1,2 @@
+ALTER TABLE membership.members
+ADD COLUMN share_presence boolean NOT NULL DEFAULT false;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DELETE FROM membership.communication_log
+WHERE communication_id IN (SELECT id FROM membership.communication WHERE name = 'AccessAnomaly');
+
+DELETE FROM membership.communication
+WHERE name = 'AccessAnomaly';
+
+COMMIT;
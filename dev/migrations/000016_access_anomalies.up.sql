@@ -0,0 +1,4 @@
+INSERT INTO membership.communication
+    (name, subject, frequency_throttle, template)
+VALUES
+    ('AccessAnomaly', 'Suspicious Access Activity', 0, 'access_anomaly.html.tmpl');
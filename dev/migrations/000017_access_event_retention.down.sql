@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE membership.access_events
+DROP COLUMN IF EXISTS pseudonymised;
+
+DROP TABLE IF EXISTS membership.access_event_daily;
+
+COMMIT;
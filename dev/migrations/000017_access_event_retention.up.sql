@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS membership.access_event_daily (
+    day DATE NOT NULL,
+    door TEXT NOT NULL,
+    access_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (day, door)
+);
+
+ALTER TABLE membership.access_events
+ADD COLUMN pseudonymised BOOLEAN NOT NULL DEFAULT false;
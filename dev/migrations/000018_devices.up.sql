@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS membership.devices
+(
+    mac_address TEXT PRIMARY KEY,
+    model TEXT NOT NULL DEFAULT '',
+    firmware TEXT NOT NULL DEFAULT '',
+    address TEXT NOT NULL DEFAULT '',
+    resource_id UUID DEFAULT NULL REFERENCES membership.resources(id) ON DELETE SET NULL,
+    first_seen TIMESTAMP NOT NULL DEFAULT now(),
+    last_seen TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE membership.resources
+DROP COLUMN IF EXISTS driver;
+
+COMMIT;
@@ -0,0 +1,2 @@
+ALTER TABLE membership.resources
+ADD COLUMN driver TEXT NOT NULL DEFAULT 'esp-rfid';
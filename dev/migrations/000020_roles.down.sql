@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.member_roles;
+DROP TABLE IF EXISTS membership.roles;
+
+COMMIT;
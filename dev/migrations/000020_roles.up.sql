@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS membership.roles
+(
+    name TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+INSERT INTO membership.roles(name, description) VALUES
+    ('admin', 'full access to the member dashboard'),
+    ('treasurer', 'manages dues and member credits'),
+    ('board', 'read access to members, reports and the audit log'),
+    ('area-host', 'trains members on the equipment in their area'),
+    ('member', 'every signed in member')
+ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS membership.member_roles
+(
+    member_id UUID NOT NULL REFERENCES membership.members(id) ON DELETE CASCADE,
+    role TEXT NOT NULL REFERENCES membership.roles(name) ON DELETE CASCADE,
+    PRIMARY KEY (member_id, role)
+);
+
+-- members on the old admin resource keep their admin rights
+INSERT INTO membership.member_roles(member_id, role)
+SELECT mr.member_id, 'admin'
+FROM membership.member_resource mr
+JOIN membership.resources r ON r.id = mr.resource_id
+WHERE r.description = 'admin'
+ON CONFLICT DO NOTHING;
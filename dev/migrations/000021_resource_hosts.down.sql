@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.resource_group_hosts;
+DROP TABLE IF EXISTS membership.resource_hosts;
+
+COMMIT;
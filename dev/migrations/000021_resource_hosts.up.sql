@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS membership.resource_hosts
+(
+    member_id UUID NOT NULL REFERENCES membership.members(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES membership.resources(id) ON DELETE CASCADE,
+    PRIMARY KEY (member_id, resource_id)
+);
+
+CREATE TABLE IF NOT EXISTS membership.resource_group_hosts
+(
+    member_id UUID NOT NULL REFERENCES membership.members(id) ON DELETE CASCADE,
+    group_id UUID NOT NULL REFERENCES membership.resource_groups(id) ON DELETE CASCADE,
+    PRIMARY KEY (member_id, group_id)
+);
@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM membership.communication_log
+WHERE communication_id IN (SELECT id FROM membership.communication WHERE name IN ('VerifyEmail', 'PasswordReset'));
+
+DELETE FROM membership.communication
+WHERE name IN ('VerifyEmail', 'PasswordReset');
+
+ALTER TABLE membership.users
+DROP COLUMN IF EXISTS verified;
+
+COMMIT;
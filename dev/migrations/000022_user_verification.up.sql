@@ -0,0 +1,12 @@
+-- users who registered before verification existed stay signed up
+ALTER TABLE membership.users
+ADD COLUMN verified BOOLEAN NOT NULL DEFAULT TRUE;
+
+ALTER TABLE membership.users
+ALTER COLUMN verified SET DEFAULT FALSE;
+
+INSERT INTO membership.communication
+    (name, subject, frequency_throttle, template)
+VALUES
+    ('VerifyEmail', 'Verify your email address', 0, 'verify_email.html.tmpl'),
+    ('PasswordReset', 'Reset your password', 0, 'password_reset.html.tmpl');
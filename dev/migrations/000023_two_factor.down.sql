@@ -0,0 +1,10 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.user_recovery_codes;
+
+ALTER TABLE membership.users
+DROP COLUMN IF EXISTS totp_last_step,
+DROP COLUMN IF EXISTS totp_enabled,
+DROP COLUMN IF EXISTS totp_secret;
+
+COMMIT;
@@ -0,0 +1,12 @@
+ALTER TABLE membership.users
+ADD COLUMN totp_secret TEXT,
+ADD COLUMN totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+-- the last time step a code was accepted for, so a code can't be used twice
+ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS membership.user_recovery_codes
+(
+    email TEXT NOT NULL REFERENCES membership.users(email) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL,
+    PRIMARY KEY (email, code_hash)
+);
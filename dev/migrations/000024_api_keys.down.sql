@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS membership.api_key_resources;
+DROP TABLE IF EXISTS membership.api_keys;
+
+COMMIT;
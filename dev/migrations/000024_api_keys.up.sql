@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS membership.api_keys
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    -- prefix is the start of the key, it's shown so admins can tell keys apart
+    prefix TEXT NOT NULL,
+    -- only a hash of the key is kept, the key itself is shown once when it's created
+    key_hash TEXT NOT NULL UNIQUE,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+-- the resources a key with the resources.open scope can open
+CREATE TABLE IF NOT EXISTS membership.api_key_resources
+(
+    api_key_id UUID NOT NULL REFERENCES membership.api_keys(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL REFERENCES membership.resources(id) ON DELETE CASCADE,
+    PRIMARY KEY (api_key_id, resource_id)
+);
@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS member_roles_revoke_sessions ON membership.member_roles;
+DROP TRIGGER IF EXISTS members_level_revokes_sessions ON membership.members;
+DROP FUNCTION IF EXISTS membership.revoke_member_sessions();
+DROP TABLE IF EXISTS membership.sessions;
+
+COMMIT;
@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS membership.sessions
+(
+    id UUID DEFAULT gen_random_uuid() PRIMARY KEY,
+    -- members who sign in with OIDC don't always have a user, so this isn't a reference
+    email TEXT NOT NULL,
+    -- only a hash of the refresh token is kept, it changes every time the session is refreshed
+    refresh_hash TEXT NOT NULL,
+    -- the token it replaced, accepted for a few seconds so two tabs refreshing at once don't sign the user out
+    previous_hash TEXT,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS sessions_email_idx ON membership.sessions (lower(email));
+
+-- a member's sessions carry their level and roles, so they are signed out when either changes
+CREATE OR REPLACE FUNCTION membership.revoke_member_sessions() RETURNS TRIGGER AS $$
+DECLARE
+    member_email TEXT;
+BEGIN
+    IF TG_TABLE_NAME = 'members' THEN
+        member_email := NEW.email;
+    ELSE
+        SELECT email INTO member_email
+        FROM membership.members
+        WHERE id = COALESCE(NEW.member_id, OLD.member_id);
+    END IF;
+
+    UPDATE membership.sessions
+    SET revoked_at = now()
+    WHERE lower(email) = lower(member_email) AND revoked_at IS NULL;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER members_level_revokes_sessions
+AFTER UPDATE OF member_tier_id ON membership.members
+FOR EACH ROW
+WHEN (OLD.member_tier_id IS DISTINCT FROM NEW.member_tier_id)
+EXECUTE FUNCTION membership.revoke_member_sessions();
+
+CREATE TRIGGER member_roles_revoke_sessions
+AFTER INSERT OR DELETE ON membership.member_roles
+FOR EACH ROW
+EXECUTE FUNCTION membership.revoke_member_sessions();
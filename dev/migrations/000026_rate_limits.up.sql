@@ -0,0 +1,9 @@
+-- rate limit and failed login counters, used when RATE_LIMIT_STORE is database
+CREATE UNLOGGED TABLE IF NOT EXISTS membership.rate_limits
+(
+    key TEXT PRIMARY KEY,
+    hits INTEGER NOT NULL,
+    reset_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS rate_limits_reset_at_idx ON membership.rate_limits (reset_at);
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE membership.member_resource
+DROP COLUMN IF EXISTS group_id;
+
+COMMIT;
@@ -0,0 +1,4 @@
+-- the group a grant came from, so removing a member or resource from a group only revokes what the group granted
+--  grants made before this column existed are treated as direct grants
+ALTER TABLE membership.member_resource
+ADD COLUMN IF NOT EXISTS group_id UUID REFERENCES membership.resource_groups(id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+-- what the device did with the swipe, e.g. Granted or Denied
+--  events logged before this column existed are left empty
+ALTER TABLE membership.access_events
+ADD COLUMN IF NOT EXISTS access TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE membership.access_events
+DROP COLUMN IF EXISTS rolled_up;
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- marks the events counted in membership.access_event_daily, so events that arrive late are still rolled up
+--  events from days that were already rolled up are assumed to have been counted
+ALTER TABLE membership.access_events
+ADD COLUMN IF NOT EXISTS rolled_up BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE membership.access_events e
+SET rolled_up = true
+WHERE EXISTS (
+	SELECT 1 FROM membership.access_event_daily d
+	WHERE d.day = e.event_time::date AND d.door = e.door
+);
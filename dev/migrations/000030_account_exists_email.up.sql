@@ -0,0 +1,4 @@
+INSERT INTO membership.communication
+    (name, subject, frequency_throttle, template)
+VALUES
+    ('AccountExists', 'You already have an account', 0, 'account_exists.html.tmpl');
@@ -0,0 +1,12 @@
+BEGIN;
+
+DELETE FROM membership.communication_log
+WHERE communication_id IN (SELECT id FROM membership.communication WHERE name = 'TwoFactorApproval');
+
+DELETE FROM membership.communication
+WHERE name = 'TwoFactorApproval';
+
+ALTER TABLE membership.users
+DROP COLUMN IF EXISTS totp_enrollment_approved;
+
+COMMIT;
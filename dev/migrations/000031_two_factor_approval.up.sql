@@ -0,0 +1,8 @@
+-- users who have to enrol confirm it from their email first, so a stolen password isn't enough to enrol
+ALTER TABLE membership.users
+ADD COLUMN totp_enrollment_approved BOOLEAN NOT NULL DEFAULT FALSE;
+
+INSERT INTO membership.communication
+    (name, subject, frequency_throttle, template)
+VALUES
+    ('TwoFactorApproval', 'Confirm setting up two-factor authentication', 0, 'two_factor_approval.html.tmpl');
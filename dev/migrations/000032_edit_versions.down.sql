@@ -0,0 +1,11 @@
+BEGIN;
+
+DROP INDEX IF EXISTS membership.resources_description_lower_idx;
+DROP INDEX IF EXISTS membership.members_email_lower_idx;
+DROP TRIGGER IF EXISTS resources_bump_version ON membership.resources;
+DROP TRIGGER IF EXISTS members_bump_version ON membership.members;
+DROP FUNCTION IF EXISTS membership.bump_version();
+ALTER TABLE membership.resources DROP COLUMN IF EXISTS version;
+ALTER TABLE membership.members DROP COLUMN IF EXISTS version;
+
+COMMIT;
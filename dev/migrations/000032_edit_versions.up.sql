@@ -0,0 +1,29 @@
+-- version goes up when a field that's edited through the API changes
+-- updates made from a copy that was read earlier only apply while it still matches
+ALTER TABLE membership.members ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE membership.resources ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION membership.bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- the level is kept in step with payments, that isn't an edit
+CREATE TRIGGER members_bump_version
+BEFORE UPDATE ON membership.members
+FOR EACH ROW
+WHEN ((OLD.name, OLD.email, OLD.rfid, OLD.subscription_id) IS DISTINCT FROM (NEW.name, NEW.email, NEW.rfid, NEW.subscription_id))
+EXECUTE FUNCTION membership.bump_version();
+
+CREATE TRIGGER resources_bump_version
+BEFORE UPDATE ON membership.resources
+FOR EACH ROW
+WHEN ((OLD.description, OLD.device_identifier, OLD.is_default, OLD.supports_time_windows, OLD.driver)
+    IS DISTINCT FROM (NEW.description, NEW.device_identifier, NEW.is_default, NEW.supports_time_windows, NEW.driver))
+EXECUTE FUNCTION membership.bump_version();
+
+-- the v2 lists page through members and resources in these orders
+CREATE INDEX IF NOT EXISTS members_email_lower_idx ON membership.members (lower(email));
+CREATE INDEX IF NOT EXISTS resources_description_lower_idx ON membership.resources (lower(description), (id::text));
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/eventbus"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+const (
+	defaultAccessEventLimit = 100
+	maxAccessEventLimit     = 1000
+	// maxAccessEventExport is the most rows a single csv export will return
+	maxAccessEventExport = 10000
+
+	accessEventFormatCSV = "csv"
+)
+
+type AccessEventServer struct {
+	store  datastore.DataStore
+	logger Logger
+	events *eventbus.Bus
+}
+
+// GetAccessEvents responds with a page of the access log
+//
+//	filters: member (email), rfid, door, known, from and to (RFC3339)
+//	pagination: limit and the nextCursor of the previous page as cursor
+//	format=csv exports the page as csv instead of json
+func (as *AccessEventServer) GetAccessEvents(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	filter, err := parseAccessEventFilter(query)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	filter.RFID = query.Get("rfid")
+	filter.Door = query.Get("door")
+
+	if email := query.Get("member"); len(email) > 0 {
+		member, err := as.store.GetMemberByEmail(email)
+		if err != nil {
+			notFound(w, "member not found")
+			return
+		}
+		filter.MemberID = member.ID
+	}
+
+	as.writeAccessEvents(w, req, filter)
+}
+
+// GetSelfAccessEvents responds with the logged in member's swipe history
+//
+//	takes the same door, known, time range and pagination parameters as GetAccessEvents
+func (as *AccessEventServer) GetSelfAccessEvents(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+
+	filter, err := parseAccessEventFilter(query)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	member, err := as.store.GetMemberByEmail(auth.User(req).GetUserName())
+	if err != nil {
+		notFound(w, "error getting member by email")
+		return
+	}
+
+	filter.MemberID = member.ID
+	filter.Door = query.Get("door")
+
+	as.writeAccessEvents(w, req, filter)
+}
+
+func (as *AccessEventServer) writeAccessEvents(w http.ResponseWriter, req *http.Request, filter models.AccessEventFilter) {
+	page, err := as.store.GetAccessEvents(filter)
+	if err != nil {
+		as.logger.Error(err)
+		internalServerError(w, "error getting access events")
+		return
+	}
+
+	if !wantsCSV(req) {
+		ok(w, page)
+		return
+	}
+
+	if len(page.NextCursor) > 0 {
+		w.Header().Set("X-Next-Cursor", page.NextCursor)
+	}
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="access-events.csv"`)
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeAccessEventsCSV(w, page.Events); err != nil {
+		as.logger.Errorf("error writing access events csv: %s", err)
+	}
+}
+
+// parseAccessEventFilter reads the filters that every access event query shares
+func parseAccessEventFilter(query url.Values) (models.AccessEventFilter, error) {
+	var filter models.AccessEventFilter
+
+	if known := query.Get("known"); len(known) > 0 {
+		isKnown, err := strconv.ParseBool(known)
+		if err != nil {
+			return filter, errors.New("known must be true or false")
+		}
+		filter.IsKnown = &isKnown
+	}
+
+	from, err := parseQueryTime(query, "from")
+	if err != nil {
+		return filter, err
+	}
+	filter.From = from
+
+	to, err := parseQueryTime(query, "to")
+	if err != nil {
+		return filter, err
+	}
+	filter.To = to
+
+	if from != nil && to != nil && !to.After(*from) {
+		return filter, errors.New("to must be after from")
+	}
+
+	if cursor := query.Get("cursor"); len(cursor) > 0 {
+		after, err := models.DecodeAccessEventCursor(cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &after
+	}
+
+	maxLimit := maxAccessEventLimit
+	if strings.EqualFold(query.Get("format"), accessEventFormatCSV) {
+		maxLimit = maxAccessEventExport
+	}
+
+	filter.Limit, err = strconv.Atoi(query.Get("limit"))
+	if err != nil || filter.Limit <= 0 {
+		filter.Limit = defaultAccessEventLimit
+		if maxLimit == maxAccessEventExport {
+			filter.Limit = maxAccessEventExport
+		}
+	}
+
+	if filter.Limit > maxLimit {
+		filter.Limit = maxLimit
+	}
+
+	return filter, nil
+}
+
+func parseQueryTime(query url.Values, key string) (*time.Time, error) {
+	value := query.Get(key)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New(key + " must be an RFC3339 timestamp")
+	}
+	return &t, nil
+}
+
+func wantsCSV(req *http.Request) bool {
+	if format := req.URL.Query().Get("format"); len(format) > 0 {
+		return strings.EqualFold(format, accessEventFormatCSV)
+	}
+	return strings.Contains(req.Header.Get("Accept"), "text/csv")
+}
+
+func writeAccessEventsCSV(w http.ResponseWriter, events []models.AccessEvent) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"id", "eventTime", "type", "door", "isKnown", "username", "rfid", "memberID", "access"}); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		err := writer.Write([]string{
+			e.ID,
+			e.EventTime.Format(time.RFC3339),
+			e.Type,
+			e.Door,
+			strconv.FormatBool(e.IsKnown),
+			e.Username,
+			e.RFID,
+			e.MemberID,
+			e.Access,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
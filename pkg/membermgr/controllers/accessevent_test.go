@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func accessEventStore() *in_memory.In_memory {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"alice@test.com": {ID: "1", Name: "alice", Email: "alice@test.com", RFID: "111"},
+			"bob@test.com":   {ID: "2", Name: "bob", Email: "bob@test.com", RFID: "222"},
+		},
+	}
+
+	start := time.Date(2023, time.March, 1, 18, 0, 0, 0, time.UTC)
+	swipes := []models.LogMessage{
+		{Type: "access", IsKnown: "true", Username: "alice", RFID: "111", Door: "frontdoor", MemberID: "1"},
+		{Type: "access", IsKnown: "true", Username: "bob", RFID: "222", Door: "frontdoor", MemberID: "2"},
+		{Type: "access", IsKnown: "false", RFID: "999", Door: "frontdoor"},
+		{Type: "access", IsKnown: "true", Username: "alice", RFID: "111", Door: "woodshop", MemberID: "1"},
+		{Type: "access", IsKnown: "true", Username: "alice", RFID: "111", Door: "frontdoor", MemberID: "1"},
+	}
+	for i, swipe := range swipes {
+		swipe.EventTime = start.Add(time.Duration(i) * time.Hour).Unix()
+		store.LogAccessEvent(swipe)
+	}
+
+	return store
+}
+
+func TestGetAccessEvents(t *testing.T) {
+	tests := []struct {
+		TestName           string
+		query              string
+		expectedHTTPStatus int
+		expectedEvents     int
+		expectedCursor     bool
+	}{
+		{
+			TestName:           "should list every event",
+			query:              "",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     5,
+		},
+		{
+			TestName:           "should filter by member",
+			query:              "?member=alice@test.com",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     3,
+		},
+		{
+			TestName:           "should filter by door and member",
+			query:              "?member=alice@test.com&door=frontdoor",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     2,
+		},
+		{
+			TestName:           "should filter unknown fobs",
+			query:              "?known=false",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     1,
+		},
+		{
+			TestName:           "should filter by time range",
+			query:              "?from=2023-03-01T19:00:00Z&to=2023-03-01T21:00:00Z",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     2,
+		},
+		{
+			TestName:           "should return a cursor when there are more events",
+			query:              "?limit=2",
+			expectedHTTPStatus: http.StatusOK,
+			expectedEvents:     2,
+			expectedCursor:     true,
+		},
+		{
+			TestName:           "should reject an unknown member",
+			query:              "?member=nobody@test.com",
+			expectedHTTPStatus: http.StatusNotFound,
+		},
+		{
+			TestName:           "should reject a bad time",
+			query:              "?from=yesterday",
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			TestName:           "should reject a bad cursor",
+			query:              "?cursor=nope",
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			server := &AccessEventServer{accessEventStore(), logrus.New(), nil}
+
+			request, _ := http.NewRequest(http.MethodGet, "/api/access-events"+tt.query, nil)
+			response := httptest.NewRecorder()
+
+			server.GetAccessEvents(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+			if tt.expectedHTTPStatus != http.StatusOK {
+				return
+			}
+
+			var page models.AccessEventPage
+			json.NewDecoder(response.Body).Decode(&page)
+
+			if len(page.Events) != tt.expectedEvents {
+				t.Fatalf("expected %d events, got %d", tt.expectedEvents, len(page.Events))
+			}
+
+			if (len(page.NextCursor) > 0) != tt.expectedCursor {
+				t.Errorf("unexpected cursor: %q", page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestAccessEventPagination(t *testing.T) {
+	server := &AccessEventServer{accessEventStore(), logrus.New(), nil}
+
+	var seen []string
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		request, _ := http.NewRequest(http.MethodGet, "/api/access-events?limit=2&cursor="+cursor, nil)
+		response := httptest.NewRecorder()
+
+		server.GetAccessEvents(response, request)
+		assertStatus(t, response.Code, http.StatusOK)
+
+		var page models.AccessEventPage
+		json.NewDecoder(response.Body).Decode(&page)
+
+		for _, e := range page.Events {
+			seen = append(seen, e.ID)
+		}
+
+		if len(page.NextCursor) == 0 {
+			break
+		}
+		cursor = page.NextCursor
+	}
+
+	expected := []string{"00000005", "00000004", "00000003", "00000002", "00000001"}
+	if len(seen) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, seen)
+	}
+	for i := range expected {
+		if seen[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, seen)
+		}
+	}
+}
+
+func TestExportAccessEventsCSV(t *testing.T) {
+	server := &AccessEventServer{accessEventStore(), logrus.New(), nil}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/access-events?format=csv&door=woodshop", nil)
+	response := httptest.NewRecorder()
+
+	server.GetAccessEvents(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if response.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("expected a csv response, got %s", response.Header().Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(response.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("expected a header and 1 row, got %d rows", len(records))
+	}
+
+	if records[1][3] != "woodshop" || records[1][5] != "alice" {
+		t.Errorf("unexpected row: %v", records[1])
+	}
+}
+
+func TestGetSelfAccessEvents(t *testing.T) {
+	server := &AccessEventServer{accessEventStore(), logrus.New(), nil}
+
+	// a member can't look at someone else's swipes
+	request, _ := http.NewRequest(http.MethodGet, "/api/member/self/access-events?member=alice@test.com&rfid=111", nil)
+	response := httptest.NewRecorder()
+
+	bob := auth.NewDefaultUser("bob@test.com", "bob@test.com", nil, nil)
+	server.GetSelfAccessEvents(response, auth.RequestWithUser(bob, request))
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var page models.AccessEventPage
+	json.NewDecoder(response.Body).Decode(&page)
+
+	if len(page.Events) != 1 || page.Events[0].Username != "bob" {
+		t.Errorf("expected only bob's swipe, got %+v", page.Events)
+	}
+}
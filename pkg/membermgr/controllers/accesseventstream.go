@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// streamKeepAlive - how often an idle stream is pinged so that proxies don't close it
+	streamKeepAlive = 30 * time.Second
+	streamWriteWait = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+}
+
+// StreamAccessEvents streams access events as they happen
+//
+//	served as Server-Sent Events, or over a WebSocket when the request asks for an upgrade
+//	pass door to only get the events of one door
+func (as *AccessEventServer) StreamAccessEvents(w http.ResponseWriter, req *http.Request) {
+	if as.events == nil {
+		internalServerError(w, "access event stream is not available")
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(req) {
+		as.streamWebSocket(w, req)
+		return
+	}
+
+	as.streamSSE(w, req)
+}
+
+func (as *AccessEventServer) streamSSE(w http.ResponseWriter, req *http.Request) {
+	flusher, canFlush := w.(http.Flusher)
+	if !canFlush {
+		internalServerError(w, "streaming is not supported")
+		return
+	}
+
+	// the server's write timeout would otherwise cut the stream off
+	http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	events, unsubscribe := as.events.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	door := req.URL.Query().Get("door")
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case e, open := <-events:
+			if !open {
+				return
+			}
+			if len(door) > 0 && e.Door != door {
+				continue
+			}
+
+			j, err := json.Marshal(streamedAccessEvent(e))
+			if err != nil {
+				as.logger.Errorf("error marshalling access event: %s", err)
+				continue
+			}
+
+			fmt.Fprintf(w, "event: access\ndata: %s\n\n", j)
+			flusher.Flush()
+		}
+	}
+}
+
+func (as *AccessEventServer) streamWebSocket(w http.ResponseWriter, req *http.Request) {
+	conn, err := upgrader.Upgrade(w, req, nil)
+	if err != nil {
+		// the upgrader has already responded with an error
+		as.logger.Errorf("error upgrading access event stream: %s", err)
+		return
+	}
+	defer conn.Close()
+
+	events, unsubscribe := as.events.Subscribe()
+	defer unsubscribe()
+
+	// we don't expect anything from the client, but we have to read to notice when it goes away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(2 * streamKeepAlive))
+		})
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	door := req.URL.Query().Get("door")
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		case e, open := <-events:
+			if !open {
+				return
+			}
+			if len(door) > 0 && e.Door != door {
+				continue
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(streamWriteWait))
+			if err := conn.WriteJSON(streamedAccessEvent(e)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// streamedAccessEvent is what goes out on the live stream
+//
+//	the stream ends up on dashboards and kiosk displays where anyone walking by can read it.
+//	Fob IDs are left out so they can't be copied onto a cloned fob
+func streamedAccessEvent(e models.AccessEvent) models.AccessEvent {
+	e.RFID = ""
+	return e
+}
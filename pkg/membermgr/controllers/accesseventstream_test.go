@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/eventbus"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+// publishWhenSubscribed waits for the stream to subscribe before publishing
+func publishWhenSubscribed(t *testing.T, bus *eventbus.Bus, events ...models.AccessEvent) {
+	deadline := time.Now().Add(time.Second)
+	for bus.Subscribers() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("stream never subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, e := range events {
+		bus.Publish(e)
+	}
+}
+
+func TestStreamAccessEventsSSE(t *testing.T) {
+	bus := eventbus.New()
+	server := &AccessEventServer{&in_memory.In_memory{}, logrus.New(), bus}
+	ts := httptest.NewServer(http.HandlerFunc(server.StreamAccessEvents))
+	defer ts.Close()
+
+	response, err := http.Get(ts.URL + "/api/access-events/stream?door=frontdoor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	assertStatus(t, response.StatusCode, http.StatusOK)
+	if response.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected an event stream, got %s", response.Header.Get("Content-Type"))
+	}
+
+	publishWhenSubscribed(t, bus,
+		models.AccessEvent{Username: "someone", RFID: "111", Door: "woodshop"},
+		models.AccessEvent{Username: "member", RFID: "222", Door: "frontdoor"},
+	)
+
+	reader := bufio.NewReader(response.Body)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+
+		var e models.AccessEvent
+		json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &e)
+
+		if e.Username != "member" || e.Door != "frontdoor" {
+			t.Errorf("expected only events for the frontdoor, got %+v", e)
+		}
+		if len(e.RFID) > 0 {
+			t.Errorf("rfid shouldn't be streamed, got %s", e.RFID)
+		}
+		return
+	}
+}
+
+func TestStreamAccessEventsWebSocket(t *testing.T) {
+	bus := eventbus.New()
+	server := &AccessEventServer{&in_memory.In_memory{}, logrus.New(), bus}
+	ts := httptest.NewServer(http.HandlerFunc(server.StreamAccessEvents))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/api/access-events/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	publishWhenSubscribed(t, bus, models.AccessEvent{Username: "member", RFID: "222", Door: "frontdoor"})
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+
+	var e models.AccessEvent
+	if err := conn.ReadJSON(&e); err != nil {
+		t.Fatal(err)
+	}
+
+	if e.Username != "member" || e.Door != "frontdoor" || len(e.RFID) > 0 {
+		t.Errorf("unexpected event: %+v", e)
+	}
+
+	conn.Close()
+
+	deadline := time.Now().Add(time.Second)
+	for bus.Subscribers() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stream to unsubscribe when the socket closed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
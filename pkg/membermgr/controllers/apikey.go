@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+type APIKeyServer struct {
+	store  datastore.DataStore
+	logger Logger
+}
+
+// GetAPIKeys responds with every API key, the keys themselves aren't stored so they aren't included
+func (as *APIKeyServer) GetAPIKeys(w http.ResponseWriter, req *http.Request) {
+	keys, err := as.store.GetAPIKeys()
+	if err != nil {
+		as.logger.Error(err)
+		internalServerError(w, "error getting api keys")
+		return
+	}
+
+	ok(w, keys)
+}
+
+// CreateAPIKey creates a key for a script or integration
+//
+//	the response is the only time the key is shown
+func (as *APIKeyServer) CreateAPIKey(w http.ResponseWriter, req *http.Request) {
+	var request models.CreateAPIKeyRequest
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	key, err := as.validateAPIKey(request)
+	if err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	plaintext, prefix, hash := auth.NewAPIKey()
+	key.Prefix = prefix
+	key.CreatedBy = requestActor(req)
+
+	created, err := as.store.CreateAPIKey(key, hash)
+	if errors.Is(err, datastore.ErrAPIKeyNameTaken) {
+		conflict(w, err.Error())
+		return
+	}
+	if err != nil {
+		as.logger.Error(err)
+		internalServerError(w, "error creating api key")
+		return
+	}
+
+	logAudit(as.store, as.logger, key.CreatedBy, models.AuditActionAPIKeyCreate, created.Name, strings.Join(created.Scopes, ","))
+
+	ok(w, models.CreateAPIKeyResponse{APIKey: created, Key: plaintext})
+}
+
+// RevokeAPIKey stops a key working straight away
+func (as *APIKeyServer) RevokeAPIKey(w http.ResponseWriter, req *http.Request) {
+	key, err := as.store.RevokeAPIKey(mux.Vars(req)["id"])
+	if err != nil {
+		notFound(w, "api key not found")
+		return
+	}
+
+	logAudit(as.store, as.logger, requestActor(req), models.AuditActionAPIKeyRevoke, key.Name, "")
+
+	ok(w, key)
+}
+
+// validateAPIKey checks the scopes in a request and dedupes them
+//
+//	resources.open and access_events.post have to name the resources the key can use them on
+func (as *APIKeyServer) validateAPIKey(request models.CreateAPIKeyRequest) (models.APIKey, error) {
+	key := models.APIKey{
+		Name:        strings.TrimSpace(request.Name),
+		Scopes:      []string{},
+		ResourceIDs: []string{},
+		ExpiresAt:   request.ExpiresAt,
+	}
+
+	if len(key.Name) == 0 {
+		return key, fmt.Errorf("name is required")
+	}
+
+	for _, scope := range request.Scopes {
+		if !rbac.APIKeyScope(rbac.Permission(scope)) {
+			return key, fmt.Errorf("unknown scope: %s", scope)
+		}
+		if !contains(key.Scopes, scope) {
+			key.Scopes = append(key.Scopes, scope)
+		}
+	}
+
+	if len(key.Scopes) == 0 {
+		return key, fmt.Errorf("at least one scope is required")
+	}
+
+	scoped := false
+	for _, scope := range key.Scopes {
+		scoped = scoped || rbac.ResourceScope(rbac.Permission(scope))
+	}
+	if scoped != (len(request.ResourceIDs) > 0) {
+		return key, fmt.Errorf("resourceIDs are needed with the %s and %s scopes, and only with them", rbac.OpenResources, rbac.PostAccessEvents)
+	}
+
+	for _, id := range request.ResourceIDs {
+		if _, err := as.store.GetResourceByID(id); err != nil {
+			return key, fmt.Errorf("resource not found: %s", id)
+		}
+		if !contains(key.ResourceIDs, id) {
+			key.ResourceIDs = append(key.ResourceIDs, id)
+		}
+	}
+
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(time.Now()) {
+		return key, fmt.Errorf("expiresAt must be in the future")
+	}
+
+	sort.Strings(key.Scopes)
+	return key, nil
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	guardian "github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	in_memory.Resources["kiosk-door"] = models.Resource{ID: "kiosk-door", Name: "kiosk-door"}
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		TestName           string
+		request            models.CreateAPIKeyRequest
+		expectedHTTPStatus int
+		expectedScopes     []string
+	}{
+		{
+			TestName:           "should create a key that opens a door",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"resources.open", "members.view", "resources.open"}, ResourceIDs: []string{"kiosk-door"}},
+			expectedHTTPStatus: http.StatusOK,
+			expectedScopes:     []string{"members.view", "resources.open"},
+		},
+		{
+			TestName:           "should create a key that posts access events",
+			request:            models.CreateAPIKeyRequest{Name: "resourcebridge", Scopes: []string{"access_events.post"}, ResourceIDs: []string{"kiosk-door"}},
+			expectedHTTPStatus: http.StatusOK,
+			expectedScopes:     []string{"access_events.post"},
+		},
+		{
+			TestName:           "should need the doors a key posts access events for",
+			request:            models.CreateAPIKeyRequest{Name: "resourcebridge", Scopes: []string{"access_events.post"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should reject doors for a key that doesn't need them",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"members.view"}, ResourceIDs: []string{"kiosk-door"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should reject a scope keys can't have",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"members.roles"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should need the doors a key opens",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"resources.open"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should reject a door that doesn't exist",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"resources.open"}, ResourceIDs: []string{"nowhere"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should need a name",
+			request:            models.CreateAPIKeyRequest{Scopes: []string{"members.view"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should not create a key that has already expired",
+			request:            models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"members.view"}, ExpiresAt: &past},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{}
+			server := &APIKeyServer{store, logrus.New()}
+
+			reqBody, _ := json.Marshal(tt.request)
+			request, _ := http.NewRequest(http.MethodPost, "/api/api-keys", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			admin := guardian.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+			server.CreateAPIKey(response, guardian.RequestWithUser(admin, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+			if tt.expectedHTTPStatus != http.StatusOK {
+				if len(store.APIKeys) != 0 || len(store.AuditLog) != 0 {
+					t.Error("expected no key to be created")
+				}
+				return
+			}
+
+			var created models.CreateAPIKeyResponse
+			json.NewDecoder(response.Body).Decode(&created)
+
+			if !reflect.DeepEqual(created.Scopes, tt.expectedScopes) {
+				t.Errorf("expected scopes %v, got %v", tt.expectedScopes, created.Scopes)
+			}
+			if created.CreatedBy != "admin@test.com" {
+				t.Errorf("expected the key to be created by the admin, got %s", created.CreatedBy)
+			}
+			if _, err := store.GetAPIKeyByHash(auth.HashAPIKey(created.Key)); err != nil {
+				t.Errorf("expected the key's hash to be stored: %s", err)
+			}
+			if len(store.AuditLog) != 1 || store.AuditLog[0].Action != models.AuditActionAPIKeyCreate {
+				t.Errorf("expected creating the key to be audited, got %+v", store.AuditLog)
+			}
+		})
+	}
+}
+
+func TestCreateAPIKeyNameTaken(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.CreateAPIKey(models.APIKey{Name: "kiosk", Scopes: []string{"members.view"}}, "hash")
+	server := &APIKeyServer{store, logrus.New()}
+	admin := guardian.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+
+	reqBody, _ := json.Marshal(models.CreateAPIKeyRequest{Name: "kiosk", Scopes: []string{"members.view"}})
+	request, _ := http.NewRequest(http.MethodPost, "/api/api-keys", bytes.NewReader(reqBody))
+	response := httptest.NewRecorder()
+	server.CreateAPIKey(response, guardian.RequestWithUser(admin, request))
+
+	assertStatus(t, response.Code, http.StatusConflict)
+	if len(store.APIKeys) != 1 || len(store.AuditLog) != 0 {
+		t.Error("expected no key to be created")
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.CreateAPIKey(models.APIKey{Name: "kiosk", Scopes: []string{"members.view"}}, "hash")
+	server := &APIKeyServer{store, logrus.New()}
+	admin := guardian.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+
+	request, _ := http.NewRequest(http.MethodDelete, "/api/api-keys/2", nil)
+	request = mux.SetURLVars(request, map[string]string{"id": "2"})
+	response := httptest.NewRecorder()
+	server.RevokeAPIKey(response, guardian.RequestWithUser(admin, request))
+	assertStatus(t, response.Code, http.StatusNotFound)
+
+	request, _ = http.NewRequest(http.MethodDelete, "/api/api-keys/1", nil)
+	request = mux.SetURLVars(request, map[string]string{"id": "1"})
+	response = httptest.NewRecorder()
+	server.RevokeAPIKey(response, guardian.RequestWithUser(admin, request))
+	assertStatus(t, response.Code, http.StatusOK)
+
+	key, _ := store.GetAPIKeyByHash("hash")
+	if key.Active(time.Now()) {
+		t.Error("expected the key to be revoked")
+	}
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Action != models.AuditActionAPIKeyRevoke || store.AuditLog[0].Target != "kiosk" {
+		t.Errorf("expected revoking the key to be audited, got %+v", store.AuditLog)
+	}
+}
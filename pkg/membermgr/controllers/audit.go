@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditServer struct {
+	store  datastore.DataStore
+	logger Logger
+}
+
+// GetAuditLog returns the audit log, most recent first
+func (as *AuditServer) GetAuditLog(w http.ResponseWriter, req *http.Request) {
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultAuditLimit
+	}
+
+	if limit > maxAuditLimit {
+		limit = maxAuditLimit
+	}
+
+	offset, err := strconv.Atoi(req.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	entries, err := as.store.GetAuditEvents(limit, offset)
+	if err != nil {
+		as.logger.Error(err)
+		internalServerError(w, "error getting audit log")
+		return
+	}
+
+	ok(w, entries)
+}
+
+// requestActor is the user making the request, for the audit log
+func requestActor(req *http.Request) string {
+	if user := auth.User(req); user != nil {
+		return user.GetUserName()
+	}
+	return "unknown"
+}
+
+// logAudit records an action in the audit log
+func logAudit(store datastore.AuditStore, logger Logger, actor string, action string, target string, detail string) {
+	err := store.LogAuditEvent(models.AuditEntry{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+		Detail: detail,
+	})
+	if err != nil {
+		logger.Errorf("error writing audit log: %s", err)
+	}
+}
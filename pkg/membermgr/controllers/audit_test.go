@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func TestTemporaryGrant(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	nextWeek := time.Now().Add(7 * 24 * time.Hour)
+	yesterday := time.Now().Add(-24 * time.Hour)
+
+	tests := []struct {
+		TestName           string
+		grant              models.MembersResourceRelation
+		expectedHTTPStatus int
+		expectedAuditLogs  int
+	}{
+		{
+			TestName:           "should reject a grant that has already expired",
+			grant:              models.MembersResourceRelation{ID: "1", Emails: []string{"guest@test.com"}, ValidUntil: &yesterday},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should reject a grant that ends before it starts",
+			grant:              models.MembersResourceRelation{ID: "1", Emails: []string{"guest@test.com"}, ValidFrom: &nextWeek, ValidUntil: &tomorrow},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should record a grant in the audit log",
+			grant:              models.MembersResourceRelation{ID: "1", Emails: []string{"guest@test.com", "instructor@test.com"}, ValidFrom: &tomorrow, ValidUntil: &nextWeek},
+			expectedHTTPStatus: http.StatusOK,
+			expectedAuditLogs:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{}
+			server := resourceAPI{db: store, logger: logrus.New()}
+
+			reqBody, _ := json.Marshal(tt.grant)
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/member/bulk", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			admin := auth.NewDefaultUser("admin@test.com", "admin@test.com", nil, nil)
+			server.AddMultipleMembersToResource(response, auth.RequestWithUser(admin, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			entries, _ := store.GetAuditEvents(defaultAuditLimit, 0)
+			if len(entries) != tt.expectedAuditLogs {
+				t.Fatalf("expected %d audit entries, got %d", tt.expectedAuditLogs, len(entries))
+			}
+
+			for _, e := range entries {
+				if e.Actor != "admin@test.com" || e.Action != models.AuditActionGrant {
+					t.Errorf("unexpected audit entry: %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAuditLog(t *testing.T) {
+	store := &in_memory.In_memory{}
+	for _, target := range []string{"first@test.com", "second@test.com", "third@test.com"} {
+		store.LogAuditEvent(models.AuditEntry{Actor: models.AuditActorScheduler, Action: models.AuditActionGrantExpired, Target: target})
+	}
+	server := &AuditServer{store, logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/audit?limit=2", nil)
+	response := httptest.NewRecorder()
+
+	server.GetAuditLog(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var entries []models.AuditEntry
+	json.NewDecoder(response.Body).Decode(&entries)
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	if entries[0].Target != "third@test.com" {
+		t.Errorf("expected the most recent entry first, got %s", entries[0].Target)
+	}
+}
@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// accountEmail is the model for the verification and password reset templates
+type accountEmail struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}
+
+// sendVerification emails a verification link to the address on the member's record
+func (a *AuthController) sendVerification(member models.Member) error {
+	account, err := a.store.GetUserAccount(strings.ToLower(member.Email))
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(a.config.EmailVerificationHours) * time.Hour
+	token, err := issueAccountToken(a.config.AccessSecret, purposeVerifyEmail, account.Email, account.PasswordHash, ttl)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.SendAccountEmail(mail.VerifyEmail, member.Email, accountEmail{
+		Name:      member.Name,
+		Link:      a.link("/api/auth/verify", token),
+		ExpiresIn: expiresIn(ttl),
+	})
+}
+
+// sendAccountExists tells a member who registers again that they already have an account
+func (a *AuthController) sendAccountExists(member models.Member) error {
+	return a.mailer.SendAccountEmail(mail.AccountExists, member.Email, accountEmail{
+		Name: member.Name,
+		Link: strings.TrimRight(a.config.PublicURL, "/") + "/login",
+	})
+}
+
+// VerifyEmail finishes registration when a user follows the link in their verification email
+func (a *AuthController) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	claims, err := parseAccountToken(a.config.AccessSecret, purposeVerifyEmail, r.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account, err := a.store.GetUserAccount(claims.Subject)
+	if err != nil || passwordFingerprint(account.PasswordHash) != claims.Fingerprint {
+		http.Error(w, errInvalidToken.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := a.store.VerifyUser(account.Email); err != nil {
+		log.Error(err)
+		http.Error(w, "error verifying email", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/login?verified=true", http.StatusFound)
+}
+
+// ForgotPassword emails a password reset link to a registered user
+//
+//	the response is the same whether or not the user exists
+func (a *AuthController) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var request models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(request.Email))
+	if err := a.sendPasswordReset(email); err != nil {
+		log.Infof("not sending a password reset to %s: %s", email, err)
+	}
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+func (a *AuthController) sendPasswordReset(email string) error {
+	account, err := a.store.GetUserAccount(email)
+	if err != nil {
+		return err
+	}
+
+	if !account.Verified {
+		return fmt.Errorf("email has not been verified")
+	}
+
+	member, err := a.store.GetMemberByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(a.config.PasswordResetMinutes) * time.Minute
+	token, err := issueAccountToken(a.config.AccessSecret, purposeResetPassword, account.Email, account.PasswordHash, ttl)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.SendAccountEmail(mail.PasswordReset, member.Email, accountEmail{
+		Name:      member.Name,
+		Link:      a.link("/reset-password", token),
+		ExpiresIn: expiresIn(ttl),
+	})
+}
+
+// ResetPassword sets a new password using the token from a password reset link
+func (a *AuthController) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var request models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	claims, err := parseAccountToken(a.config.AccessSecret, purposeResetPassword, request.Token)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account, err := a.store.GetUserAccount(claims.Subject)
+	if err != nil || passwordFingerprint(account.PasswordHash) != claims.Fingerprint {
+		http.Error(w, errInvalidToken.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := models.CheckPasswordPolicy(request.Password, account.Email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := a.store.UpdatePassword(models.Credentials{Email: account.Email, Password: request.Password}); err != nil {
+		log.Error(err)
+		http.Error(w, "error resetting password", http.StatusInternalServerError)
+		return
+	}
+
+	// whoever had the old password may still be signed in
+	if _, err := a.store.RevokeSessions(account.Email); err != nil {
+		log.Errorf("error revoking sessions for %s: %s", account.Email, err)
+	}
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+// link builds a link to the dashboard with a token in the query string
+func (a *AuthController) link(path string, token string) string {
+	return strings.TrimRight(a.config.PublicURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
+
+func expiresIn(ttl time.Duration) string {
+	if ttl%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(ttl.Hours()))
+	}
+	return fmt.Sprintf("%d minutes", int(ttl.Minutes()))
+}
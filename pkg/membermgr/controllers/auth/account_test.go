@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+)
+
+type sentEmail struct {
+	communication mail.CommunicationTemplate
+	recipient     string
+	model         accountEmail
+}
+
+type recordingMailer struct {
+	sent []sentEmail
+}
+
+func (m *recordingMailer) SendAccountEmail(communication mail.CommunicationTemplate, recipient string, model interface{}) error {
+	m.sent = append(m.sent, sentEmail{communication, recipient, model.(accountEmail)})
+	return nil
+}
+
+// token pulls the token out of the link in the last email
+func (m *recordingMailer) token(t *testing.T) string {
+	t.Helper()
+	if len(m.sent) == 0 {
+		t.Fatal("no email was sent")
+	}
+
+	link, err := url.Parse(m.sent[len(m.sent)-1].model.Link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return link.Query().Get("token")
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		AccessSecret:           "secret",
+		PublicURL:              "http://localhost:3000",
+		EmailVerificationHours: 48,
+		PasswordResetMinutes:   60,
+		AccessTokenMinutes:     15,
+		RefreshTokenDays:       14,
+		LoginMaxAttempts:       10,
+		LoginLockoutMinutes:    15,
+		LoginIPMaxAttempts:     50,
+	}
+}
+
+func newAccountTestServer() (*AuthController, *in_memory.In_memory, *recordingMailer) {
+	db := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {Name: "member", Email: "member@test.com"},
+		},
+	}
+	mailer := &recordingMailer{}
+	return &AuthController{store: db, config: testConfig(), mailer: mailer}, db, mailer
+}
+
+func register(t *testing.T, server *AuthController, email string, password string) {
+	t.Helper()
+	reqBody, _ := json.Marshal(models.Credentials{Email: email, Password: password})
+	request, _ := http.NewRequest(http.MethodPost, "/api/auth/register", bytes.NewReader(reqBody))
+	response := httptest.NewRecorder()
+	server.RegisterUser(response, request)
+	assertStatus(t, response.Code, http.StatusOK)
+	server.registrations.Wait()
+}
+
+func verify(server *AuthController, token string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(http.MethodGet, "/api/auth/verify?token="+url.QueryEscape(token), nil)
+	response := httptest.NewRecorder()
+	server.VerifyEmail(response, request)
+	return response
+}
+
+func resetPassword(server *AuthController, token string, password string) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(models.ResetPasswordRequest{Token: token, Password: password})
+	request, _ := http.NewRequest(http.MethodPost, "/api/auth/password/reset", bytes.NewReader(reqBody))
+	response := httptest.NewRecorder()
+	server.ResetPassword(response, request)
+	return response
+}
+
+func TestVerifyEmail(t *testing.T) {
+	server, db, mailer := newAccountTestServer()
+
+	register(t, server, "member@test.com", "correct-battery-horse")
+
+	if err := db.UserSignin("member@test.com", "correct-battery-horse"); err == nil {
+		t.Fatal("expected sign in to fail before the email is verified")
+	}
+
+	link := mailer.sent[0].model.Link
+	if !strings.HasPrefix(link, "http://localhost:3000/api/auth/verify?token=") {
+		t.Errorf("unexpected verification link %s", link)
+	}
+
+	response := verify(server, mailer.token(t))
+	assertStatus(t, response.Code, http.StatusFound)
+
+	if err := db.UserSignin("member@test.com", "correct-battery-horse"); err != nil {
+		t.Errorf("expected sign in to work after verifying: %s", err)
+	}
+}
+
+func TestVerifyEmailRejectsBadTokens(t *testing.T) {
+	server, _, mailer := newAccountTestServer()
+	register(t, server, "member@test.com", "correct-battery-horse")
+	token := mailer.token(t)
+
+	register(t, server, "member@test.com", "a-different-long-password")
+
+	expired, _ := issueAccountToken("secret", purposeVerifyEmail, "member@test.com", "", -time.Minute)
+	reset, _ := issueAccountToken("secret", purposeResetPassword, "member@test.com", "", time.Hour)
+	forged, _ := issueAccountToken("not the secret", purposeVerifyEmail, "member@test.com", "", time.Hour)
+
+	tests := []struct {
+		TestName string
+		token    string
+	}{
+		{"should reject a link sent before the user signed up again", token},
+		{"should reject an expired link", expired},
+		{"should reject a password reset token", reset},
+		{"should reject a token signed with another secret", forged},
+		{"should reject garbage", "not a token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			response := verify(server, tt.token)
+			assertStatus(t, response.Code, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestPasswordReset(t *testing.T) {
+	server, db, mailer := newAccountTestServer()
+	register(t, server, "member@test.com", "correct-battery-horse")
+	verify(server, mailer.token(t))
+
+	for _, email := range []string{"member@test.com", "nobody@test.com"} {
+		reqBody, _ := json.Marshal(models.ForgotPasswordRequest{Email: email})
+		request, _ := http.NewRequest(http.MethodPost, "/api/auth/password/forgot", bytes.NewReader(reqBody))
+		response := httptest.NewRecorder()
+		server.ForgotPassword(response, request)
+		assertStatus(t, response.Code, http.StatusOK)
+	}
+
+	if len(mailer.sent) != 2 || mailer.sent[1].communication != mail.PasswordReset {
+		t.Fatalf("expected one password reset email, got %+v", mailer.sent)
+	}
+	token := mailer.token(t)
+
+	response := resetPassword(server, token, "short")
+	assertStatus(t, response.Code, http.StatusBadRequest)
+
+	response = resetPassword(server, token, "a-brand-new-password")
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if err := db.UserSignin("member@test.com", "a-brand-new-password"); err != nil {
+		t.Errorf("expected the new password to work: %s", err)
+	}
+
+	response = resetPassword(server, token, "another-new-password")
+	assertStatus(t, response.Code, http.StatusBadRequest)
+}
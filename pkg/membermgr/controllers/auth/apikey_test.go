@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+func newAPIKey(t *testing.T, db *in_memory.In_memory, name string, expiresAt *time.Time) (models.APIKey, string) {
+	t.Helper()
+	key, prefix, hash := NewAPIKey()
+	created, err := db.CreateAPIKey(models.APIKey{
+		Name:        name,
+		Prefix:      prefix,
+		Scopes:      []string{string(rbac.ViewMembers), string(rbac.OpenResources)},
+		ResourceIDs: []string{"frontdoor"},
+		ExpiresAt:   expiresAt,
+	}, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created, key
+}
+
+// apiKeyRequest goes through the auth middleware like a request from a script would
+func apiKeyRequest(server *AuthController, key string) (*httptest.ResponseRecorder, auth.Info) {
+	var user auth.Info
+	request := httptest.NewRequest(http.MethodGet, "/api/member", nil)
+	request.Header.Set(APIKeyHeader, key)
+
+	response := httptest.NewRecorder()
+	server.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user = auth.User(r)
+	})).ServeHTTP(response, request)
+	return response, user
+}
+
+func TestAPIKeySignsIn(t *testing.T) {
+	db := &in_memory.In_memory{}
+	server := newAuthController(db, &recordingMailer{}, testConfig())
+	created, key := newAPIKey(t, db, "kiosk", nil)
+
+	if !strings.HasPrefix(key, created.Prefix) || created.Prefix == key {
+		t.Errorf("expected the prefix to be the start of the key, got %s", created.Prefix)
+	}
+
+	response, user := apiKeyRequest(server, key)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if !IsAPIKey(user) || user.GetUserName() != APIKeyUserName("kiosk") {
+		t.Fatalf("expected the request to be made by the key, got %+v", user)
+	}
+	if !rbac.Allowed(user.GetGroups(), rbac.ViewMembers) || rbac.Allowed(user.GetGroups(), rbac.OpenResources) {
+		t.Errorf("expected the key's scopes as its groups, got %v", user.GetGroups())
+	}
+	if !rbac.AllowedOnResource(user.GetGroups(), rbac.OpenResources, "frontdoor") {
+		t.Errorf("expected the key to open its resource, got %v", user.GetGroups())
+	}
+
+	keys, _ := db.GetAPIKeys()
+	if keys[0].LastUsedAt == nil {
+		t.Error("expected the key's use to be recorded")
+	}
+}
+
+func TestAPIKeyRejected(t *testing.T) {
+	db := &in_memory.In_memory{}
+	server := newAuthController(db, &recordingMailer{}, testConfig())
+
+	_, key := newAPIKey(t, db, "kiosk", nil)
+	expired := time.Now().Add(-time.Minute)
+	_, expiredKey := newAPIKey(t, db, "old", &expired)
+
+	response, _ := apiKeyRequest(server, key+"x")
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+
+	response, _ = apiKeyRequest(server, expiredKey)
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+
+	response, _ = apiKeyRequest(server, key)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	// revoking isn't cached, the very next request is refused
+	db.RevokeAPIKey("1")
+	response, _ = apiKeyRequest(server, key)
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+}
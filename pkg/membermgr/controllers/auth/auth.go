@@ -6,11 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	config "github.com/HackRVA/memberserver/configs"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/ratelimit"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+	"github.com/HackRVA/memberserver/pkg/oidc"
 
 	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/basic"
@@ -22,31 +26,61 @@ import (
 
 type AuthController struct {
 	store            datastore.DataStore
+	config           config.Config
+	mailer           AccountMailer
+	oidcProvider     *oidc.Provider
 	jwtStrategy      auth.Strategy
 	AuthStrategy     union.Union
 	JWTSecretsKeeper jwt.SecretsKeeper
+	// RateLimits counts failed logins, and requests to rate limited routes
+	RateLimits ratelimit.Counter
+	proxies    ratelimit.Proxies
+	// registrations are finished after the response is sent
+	registrations sync.WaitGroup
 }
 
-// JWTExpireInterval - how long the JWT will last in hours
-const JWTExpireInterval = 8
+// AccountMailer sends the emails a user asks for, like verification and password reset links
+type AccountMailer interface {
+	SendAccountEmail(communication mail.CommunicationTemplate, recipient string, model interface{}) error
+}
 
-func New(dataStore datastore.DataStore) *AuthController {
+func New(dataStore datastore.DataStore, mailer AccountMailer) *AuthController {
 	c, _ := config.Load()
+	return newAuthController(dataStore, mailer, c)
+}
+
+func newAuthController(dataStore datastore.DataStore, mailer AccountMailer, c config.Config) *AuthController {
 	auth := AuthController{
-		store: dataStore,
+		store:  dataStore,
+		config: c,
+		mailer: mailer,
+		// counters are kept in memory unless they're needed across restarts or servers
+		RateLimits: ratelimit.NewMemoryCounter(),
+		proxies:    ratelimit.ParseProxies(c.TrustedProxies),
+	}
+	if c.RateLimitStore == "database" {
+		auth.RateLimits = ratelimit.NewStoreCounter(dataStore)
 	}
 	keeper := jwt.StaticSecret{
 		ID:        "secret-id",
 		Secret:    []byte(c.AccessSecret),
 		Algorithm: jwt.HS256,
 	}
+	if len(c.OIDCIssuer) > 0 {
+		auth.oidcProvider = oidc.New(oidc.Config{
+			Issuer:       c.OIDCIssuer,
+			ClientID:     c.OIDCClientID,
+			ClientSecret: c.OIDCClientSecret,
+			RedirectURL:  strings.TrimSuffix(c.PublicURL, "/") + oidcCallbackPath,
+		})
+	}
 	cache := libcache.FIFO.New(0)
 	cache.SetTTL(time.Minute * 5)
 
 	basicStrategy := basic.NewCached(auth.buildValidator(), cache)
 	jwtStrategy := jwt.New(cache, keeper)
 	auth.jwtStrategy = jwtStrategy
-	auth.AuthStrategy = union.New(jwtStrategy, basicStrategy)
+	auth.AuthStrategy = union.New(jwtStrategy, basicStrategy, newAPIKeyStrategy(dataStore))
 	auth.JWTSecretsKeeper = keeper
 
 	return &auth
@@ -54,33 +88,40 @@ func New(dataStore datastore.DataStore) *AuthController {
 
 func (a *AuthController) buildValidator() func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
 	return func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
-		log.Errorf("signing in: %s", userName)
 		err := a.store.UserSignin(userName, password)
 		if err != nil {
-			log.Errorf("error signing in: %s", err)
+			log.Debugf("error signing in: %s", err)
 			return nil, fmt.Errorf("invalid credentials")
 		}
 		// If we reach this point, that means the users password was correct, and that they are authorized
-		// we could attach some of their privledges to this return val I think
+		// their roles become the groups on the token, rbac checks permissions against them
+		return auth.NewDefaultUser(userName, userName, a.groups(userName), nil), nil
+	}
+}
 
-		// get the user's resources/roles from the db
-		user, _ := a.store.GetMemberByEmail(userName)
-		var resources []string
-		for _, resource := range user.Resources {
-			resources = append(resources, resource.Name)
-		}
+// groups returns the names of a user's roles
+//
+//	every user that can sign in holds the member role
+func (a *AuthController) groups(email string) []string {
+	groups := []string{string(models.RoleMember)}
 
-		conf, _ := config.Load()
-		if strings.Contains(conf.AlwaysAdmin, "true") {
-			resources = append(resources, "admin")
-		}
+	roles, err := a.store.GetMemberRoles(strings.ToLower(email))
+	if err != nil {
+		log.Errorf("error getting roles for %s: %s", email, err)
+		return groups
+	}
 
-		return auth.NewDefaultUser(userName, userName, resources, nil), nil
+	for _, role := range roles {
+		if role != models.RoleMember {
+			groups = append(groups, string(role))
+		}
 	}
+
+	return groups
 }
 
-func (AuthController) getAuthCookie(request http.Request) string {
-	cookie, err := request.Cookie("memberserver")
+func (*AuthController) getAuthCookie(request http.Request) string {
+	cookie, err := request.Cookie(authCookie)
 
 	if err != nil {
 		return ""
@@ -89,20 +130,20 @@ func (AuthController) getAuthCookie(request http.Request) string {
 	return cookie.Value
 }
 
-func (AuthController) setAuthCookie(writer http.ResponseWriter, jwt string) {
+func (a *AuthController) setAuthCookie(writer http.ResponseWriter, jwt string) {
 	http.SetCookie(writer, &http.Cookie{
-		Name:     "memberserver",
+		Name:     authCookie,
 		Value:    jwt,
-		Expires:  time.Now().Add(JWTExpireInterval * time.Hour),
+		Expires:  time.Now().Add(a.accessTokenLifetime()),
 		HttpOnly: true,
 		SameSite: http.SameSiteStrictMode,
 		Path:     "/",
 	})
 }
 
-func (AuthController) removeAuthCookie(writer http.ResponseWriter) {
+func (*AuthController) removeAuthCookie(writer http.ResponseWriter) {
 	http.SetCookie(writer, &http.Cookie{
-		Name:     "memberserver",
+		Name:     authCookie,
 		Value:    "",
 		MaxAge:   -1,
 		HttpOnly: true,
@@ -133,57 +174,108 @@ func (a *AuthController) isValidBearer(request *http.Request) bool {
 
 func (a *AuthController) AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// passwords can be guessed, so they're throttled before they're checked
+		email, _, isPassword := r.BasicAuth()
+		if isPassword {
+			if wait := a.loginWait(r, email); wait > 0 {
+				ratelimit.TooManyRequests(w, wait)
+				return
+			}
+		}
+
 		_, user, err := a.AuthStrategy.AuthenticateRequest(r)
-		if err != nil || !a.isValidBearer(r) {
+		if err != nil || (!IsAPIKey(user) && !a.isValidBearer(r)) {
 			log.Println(err)
+			if isPassword {
+				a.loginFailed(r, email)
+			}
+			code := http.StatusUnauthorized
+			http.Error(w, http.StatusText(code), code)
+			return
+		}
+
+		// access tokens stop working as soon as their session is revoked
+		if a.sessionEnded(r, user) {
 			code := http.StatusUnauthorized
 			http.Error(w, http.StatusText(code), code)
 			return
 		}
+
+		// a password alone only reaches the login, or enrolment when it's required
+		if state := a.pendingSecondFactor(r, user); len(state) > 0 && !allowedWhilePending(state, r.URL.Path) {
+			twoFactorChallenge(w, models.TwoFactorChallenge{
+				TwoFactorRequired: state == twoFactorVerify,
+				SetupRequired:     state == twoFactorSetup,
+			})
+			return
+		}
 		r = auth.RequestWithUser(user, r)
 		next.ServeHTTP(w, r)
 	})
 }
 
-// Logout endpoint for user signin
+// Logout ends the user's session, its refresh and access tokens stop working
 func (a *AuthController) Logout(w http.ResponseWriter, r *http.Request) {
 	j, _ := json.Marshal(struct{ Message string }{
 		Message: "user logged out!",
 	})
 
+	if id := sessionID(auth.User(r)); len(id) > 0 {
+		if err := a.store.RevokeSession(id); err != nil {
+			log.Errorf("error revoking session %s: %s", id, err)
+		}
+	}
+
 	a.removeAuthCookie(w)
+	a.setRefreshCookie(w, "", -1)
 
 	ok(w, j)
 
 	http.Redirect(w, r, "/", http.StatusFound)
 }
 
+// Login issues a token to a user who signed in with their password
+//
+//	users with two-factor authentication send their code in the body as a second step
 func (a *AuthController) Login(w http.ResponseWriter, r *http.Request) {
-	println("attempting to login")
-
-	var body []byte
-	r.Body.Read(body)
-	log.Debug(string(body))
-	exp := jwt.SetExpDuration(time.Hour * JWTExpireInterval)
 	u := auth.User(r)
-	u.SetUserName(strings.ToLower(u.GetUserName()))
-	token, err := jwt.IssueAccessToken(u, a.JWTSecretsKeeper, exp)
+	email := strings.ToLower(u.GetUserName())
+
+	switch a.pendingSecondFactor(r, u) {
+	case twoFactorSetup:
+		twoFactorChallenge(w, models.TwoFactorChallenge{SetupRequired: true})
+		return
+	case twoFactorVerify:
+		var request models.LoginRequest
+		json.NewDecoder(r.Body).Decode(&request)
+		if !a.checkSecondFactor(email, request) {
+			// asking for the code isn't a failure, a wrong one is
+			if len(request.Code) > 0 || len(request.RecoveryCode) > 0 {
+				a.loginFailed(r, email)
+			}
+			twoFactorChallenge(w, models.TwoFactorChallenge{TwoFactorRequired: true})
+			return
+		}
+	}
+	a.loginSucceeded(email)
+
+	token, err := a.startSession(w, r, auth.NewDefaultUser(email, u.GetID(), u.GetGroups(), nil))
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 		return
 	}
 
-	tokenJSON := &models.TokenResponse{
+	ok(w, &models.TokenResponse{
 		Token: token,
-	}
-
-	a.setAuthCookie(w, tokenJSON.Token)
-
-	ok(w, tokenJSON)
+	})
 }
 
-// Signup register a user to the db
+// RegisterUser starts registration by emailing a verification link to the member
+//
+//	the user can't sign in until they follow the link.
+//	The response is the same whether or not the email belongs to a member or already has an account,
+//	members who already have one are emailed to say so
 func (a *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 	// Parse and decode the request body into a new `Credentials` instance
 	creds := &models.Credentials{}
@@ -194,24 +286,45 @@ func (a *AuthController) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if len(creds.Password) < 3 {
-		http.Error(w, "password must be longer", http.StatusBadRequest)
+	email := strings.ToLower(strings.TrimSpace(creds.Email))
+	if err := models.CheckPasswordPolicy(creds.Password, email); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// the response is sent before we look the email up, so how long it takes doesn't say whether they're a member
+	a.registrations.Add(1)
+	go func() {
+		defer a.registrations.Done()
+		if err := a.register(email, creds.Password); err != nil {
+			log.Infof("not registering %s: %s", email, err)
+		}
+	}()
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+// register stores the account and emails a verification link,
+// or tells the member they already have an account
+func (a *AuthController) register(email string, password string) error {
+	member, err := a.store.GetMemberByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	if account, err := a.store.GetUserAccount(email); err == nil && account.Verified {
+		return a.sendAccountExists(member)
+	}
+
 	err = a.store.RegisterUser(models.Credentials{
-		Email:    strings.ToLower(creds.Email),
-		Password: creds.Password,
+		Email:    email,
+		Password: password,
 	})
-
 	if err != nil {
-		log.Error(err)
-		http.Error(w, "error registering user", http.StatusBadRequest)
-		return
+		return err
 	}
 
-	// We reach this point if the credentials we correctly stored in the database, and the default status of 200 is sent back
-	ok(w, models.EndpointSuccess{Ack: true})
+	return a.sendVerification(member)
 }
 
 func ok(writer http.ResponseWriter, result interface{}) {
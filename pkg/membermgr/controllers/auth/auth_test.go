@@ -2,13 +2,16 @@ package auth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
 )
 
 func TestRegisterUser(t *testing.T) {
@@ -19,9 +22,19 @@ func TestRegisterUser(t *testing.T) {
 		Name:  "test",
 		Email: "test",
 	}
+	db.Members["new@test.com"] = models.Member{
+		Name:  "new",
+		Email: "new@test.com",
+	}
+	db.Users = map[string]models.UserAccount{
+		"test": {Email: "test", Verified: true},
+	}
 
+	mailer := &recordingMailer{}
 	server := AuthController{
-		store: db,
+		store:  db,
+		config: testConfig(),
+		mailer: mailer,
 	}
 
 	tests := []struct {
@@ -37,20 +50,20 @@ func TestRegisterUser(t *testing.T) {
 			userName: "doesnt exist",
 			creds: models.Credentials{
 				Email:    "doesnt exist",
-				Password: "password",
+				Password: "correct-battery-horse",
 			},
 			expectedHTTPStastub: http.StatusOK,
 			expectedResponse:    "{\"ack\":true}",
 		},
 		{
-			TestName: "should fail to register a user if they already exist",
+			TestName: "should answer the same if they already have an account",
 			userName: "test",
 			creds: models.Credentials{
 				Email:    "test",
-				Password: "password",
+				Password: "correct-battery-horse",
 			},
-			expectedHTTPStastub: http.StatusBadRequest,
-			expectedResponse:    "error registering user\n",
+			expectedHTTPStastub: http.StatusOK,
+			expectedResponse:    "{\"ack\":true}",
 		},
 		{
 			TestName: "should fail if password isn't provided",
@@ -62,6 +75,26 @@ func TestRegisterUser(t *testing.T) {
 			expectedHTTPStastub: http.StatusBadRequest,
 			expectedResponse:    "password must be longer\n",
 		},
+		{
+			TestName: "should reject a common password",
+			userName: "new@test.com",
+			creds: models.Credentials{
+				Email:    "new@test.com",
+				Password: "password1234",
+			},
+			expectedHTTPStastub: http.StatusBadRequest,
+			expectedResponse:    "password is too common\n",
+		},
+		{
+			TestName: "should email a verification link to a member",
+			userName: "new@test.com",
+			creds: models.Credentials{
+				Email:    "New@test.com",
+				Password: "correct-battery-horse",
+			},
+			expectedHTTPStastub: http.StatusOK,
+			expectedResponse:    "{\"ack\":true}",
+		},
 	}
 
 	for _, tt := range tests {
@@ -71,11 +104,26 @@ func TestRegisterUser(t *testing.T) {
 
 			// authInfo := auth.NewDefaultUser(tt.userName, tt.userName, tt.resources, nil)
 			server.RegisterUser(response, request)
+			server.registrations.Wait()
 
 			assertStatus(t, response.Code, tt.expectedHTTPStastub)
 			assertResponseBody(t, response.Body.String(), tt.expectedResponse)
 		})
 	}
+
+	if len(mailer.sent) != 2 {
+		t.Fatalf("expected two emails, got %+v", mailer.sent)
+	}
+	if mailer.sent[0].recipient != "test" || mailer.sent[0].communication != mail.AccountExists {
+		t.Errorf("expected the member with an account to be told they have one, got %+v", mailer.sent[0])
+	}
+	if mailer.sent[1].recipient != "new@test.com" || mailer.sent[1].communication != mail.VerifyEmail {
+		t.Errorf("expected a verification email to the new member, got %+v", mailer.sent[1])
+	}
+
+	if account, _ := db.GetUserAccount("new@test.com"); account.Verified {
+		t.Error("expected the user to wait for verification")
+	}
 }
 
 func newRegisterUserRequest(creds models.Credentials) *http.Request {
@@ -97,3 +145,50 @@ func assertResponseBody(t testing.TB, got, want string) {
 		t.Errorf("response body is wrong, got %q want %q", got, want)
 	}
 }
+
+func TestValidatorGroups(t *testing.T) {
+	db := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"admin@test.com": {Name: "admin", Email: "admin@test.com"},
+			"member@test.com": {Name: "member", Email: "member@test.com", Resources: []models.MemberResource{
+				{Name: "admin"},
+			}},
+		},
+	}
+	db.SetMemberRoles("admin@test.com", []models.Role{models.RoleAdmin})
+
+	server := AuthController{
+		store: db,
+	}
+	validate := server.buildValidator()
+
+	tests := []struct {
+		TestName       string
+		userName       string
+		expectedGroups []string
+	}{
+		{
+			TestName:       "should use the member's roles as their groups",
+			userName:       "admin@test.com",
+			expectedGroups: []string{"member", "admin"},
+		},
+		{
+			TestName:       "should not make a member an admin because of a resource's name",
+			userName:       "member@test.com",
+			expectedGroups: []string{"member"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			info, err := validate(context.Background(), nil, tt.userName, "password")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(info.GetGroups(), tt.expectedGroups) {
+				t.Errorf("expected groups %v, got %v", tt.expectedGroups, info.GetGroups())
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// freeLoginAttempts is how many failed logins an account gets before it has to wait between attempts
+	freeLoginAttempts = 3
+	// maxLoginBackoff is the longest an account waits between attempts before it's locked
+	maxLoginBackoff = 5 * time.Minute
+)
+
+func failedLoginsKey(email string) string { return "login:failed:" + strings.ToLower(email) }
+func loginBackoffKey(email string) string { return "login:backoff:" + strings.ToLower(email) }
+func lockedKey(email string) string       { return "login:locked:" + strings.ToLower(email) }
+func failedLoginsIPKey(ip string) string  { return "login:failed-ip:" + ip }
+
+// ClientIP is the address a request came from, it's read from X-Forwarded-For behind a trusted proxy
+func (a *AuthController) ClientIP(r *http.Request) string {
+	return a.proxies.ClientIP(r)
+}
+
+// loginWait is how long a password sign in has to wait, it's 0 when it can go ahead
+//
+//	counters that can't be read don't hold anyone up
+func (a *AuthController) loginWait(r *http.Request, email string) time.Duration {
+	for _, key := range []string{lockedKey(email), loginBackoffKey(email)} {
+		if hits, resetAt, err := a.RateLimits.Count(key); err != nil {
+			log.Errorf("error checking %s: %s", key, err)
+		} else if hits > 0 {
+			return time.Until(resetAt)
+		}
+	}
+
+	hits, resetAt, err := a.RateLimits.Count(failedLoginsIPKey(a.ClientIP(r)))
+	if err != nil {
+		log.Errorf("error checking failed logins from %s: %s", a.ClientIP(r), err)
+	} else if hits >= a.config.LoginIPMaxAttempts {
+		return time.Until(resetAt)
+	}
+
+	return 0
+}
+
+// loginFailed counts a wrong password or code against the account and the address it came from
+//
+//	after freeLoginAttempts the account waits twice as long after each failure,
+//	and LoginMaxAttempts locks it until the lockout period is over or an admin unlocks it
+func (a *AuthController) loginFailed(r *http.Request, email string) {
+	ip := a.ClientIP(r)
+	log.Warnf("failed login for %s from %s", email, ip)
+
+	if _, _, err := a.RateLimits.Hit(failedLoginsIPKey(ip), a.lockoutPeriod()); err != nil {
+		log.Errorf("error counting failed logins from %s: %s", ip, err)
+	}
+
+	failures, _, err := a.RateLimits.Hit(failedLoginsKey(email), a.lockoutPeriod())
+	if err != nil {
+		log.Errorf("error counting failed logins for %s: %s", email, err)
+		return
+	}
+
+	switch {
+	case failures >= a.config.LoginMaxAttempts:
+		log.Warnf("locking %s after %d failed logins", email, failures)
+		_, _, err = a.RateLimits.Hit(lockedKey(email), a.lockoutPeriod())
+	case failures >= freeLoginAttempts:
+		_, _, err = a.RateLimits.Hit(loginBackoffKey(email), loginBackoff(failures))
+	}
+	if err != nil {
+		log.Errorf("error holding off logins for %s: %s", email, err)
+	}
+}
+
+// loginSucceeded forgets an account's failed logins
+func (a *AuthController) loginSucceeded(email string) {
+	for _, key := range []string{failedLoginsKey(email), loginBackoffKey(email)} {
+		if err := a.RateLimits.Reset(key); err != nil {
+			log.Errorf("error resetting %s: %s", key, err)
+		}
+	}
+}
+
+// Lockout reports whether an account is locked after failed logins
+func (a *AuthController) Lockout(email string) (models.Lockout, error) {
+	lockout := models.Lockout{Email: strings.ToLower(email)}
+
+	failures, _, err := a.RateLimits.Count(failedLoginsKey(email))
+	if err != nil {
+		return lockout, err
+	}
+	lockout.FailedLogins = failures
+
+	locked, lockedUntil, err := a.RateLimits.Count(lockedKey(email))
+	if err != nil {
+		return lockout, err
+	}
+	if locked > 0 {
+		lockout.Locked = true
+		lockout.LockedUntil = &lockedUntil
+	}
+
+	return lockout, nil
+}
+
+// Unlock lets a locked account sign in again straight away, and forgets its failed logins
+func (a *AuthController) Unlock(email string) error {
+	for _, key := range []string{lockedKey(email), failedLoginsKey(email), loginBackoffKey(email)} {
+		if err := a.RateLimits.Reset(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *AuthController) lockoutPeriod() time.Duration {
+	return time.Duration(a.config.LoginLockoutMinutes) * time.Minute
+}
+
+// loginBackoff doubles for every failure after the free ones
+func loginBackoff(failures int) time.Duration {
+	shift := failures - freeLoginAttempts
+	if shift >= 16 {
+		return maxLoginBackoff
+	}
+	if backoff := time.Second << shift; backoff < maxLoginBackoff {
+		return backoff
+	}
+	return maxLoginBackoff
+}
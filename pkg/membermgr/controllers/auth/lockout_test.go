@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// loginAttempt signs in through the auth middleware from an address
+func loginAttempt(server *AuthController, email string, password string, remoteAddr string, body interface{}) *httptest.ResponseRecorder {
+	reqBody, _ := json.Marshal(body)
+	request := httptest.NewRequest(http.MethodPost, loginPath, bytes.NewReader(reqBody))
+	request.RemoteAddr = remoteAddr
+	request.SetBasicAuth(email, password)
+
+	response := httptest.NewRecorder()
+	server.AuthMiddleware(http.HandlerFunc(server.Login)).ServeHTTP(response, request)
+	return response
+}
+
+func TestFailedLoginsBackOff(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+
+	for n := 0; n < freeLoginAttempts; n++ {
+		assertStatus(t, loginAttempt(server, "member@test.com", "wrong", "192.0.2.1:1234", nil).Code, http.StatusUnauthorized)
+	}
+
+	// even the right password has to wait
+	response := loginAttempt(server, "member@test.com", testPassword, "192.0.2.1:1234", nil)
+	assertStatus(t, response.Code, http.StatusTooManyRequests)
+	if retryAfter := response.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Errorf("expected to wait a second, got %q", retryAfter)
+	}
+
+	// other accounts aren't held up
+	assertStatus(t, loginAttempt(server, "admin@test.com", "wrong", "192.0.2.1:1234", nil).Code, http.StatusUnauthorized)
+
+	if backoff := loginBackoff(freeLoginAttempts + 2); backoff.Seconds() != 4 {
+		t.Errorf("expected the wait to double with each failure, got %s", backoff)
+	}
+	if backoff := loginBackoff(100); backoff != maxLoginBackoff {
+		t.Errorf("expected the wait to stop growing, got %s", backoff)
+	}
+}
+
+func TestSuccessfulLoginForgetsFailures(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+
+	for n := 0; n < freeLoginAttempts-1; n++ {
+		loginAttempt(server, "member@test.com", "wrong", "192.0.2.1:1234", nil)
+	}
+	assertStatus(t, loginAttempt(server, "member@test.com", testPassword, "192.0.2.1:1234", nil).Code, http.StatusOK)
+
+	lockout, _ := server.Lockout("member@test.com")
+	if lockout.FailedLogins != 0 {
+		t.Errorf("expected failed logins to be forgotten, got %d", lockout.FailedLogins)
+	}
+}
+
+func TestAccountLockout(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+	server.config.LoginMaxAttempts = freeLoginAttempts
+
+	for n := 0; n < freeLoginAttempts; n++ {
+		loginAttempt(server, "member@test.com", "wrong", "192.0.2.1:1234", nil)
+	}
+
+	lockout, _ := server.Lockout("Member@test.com")
+	if !lockout.Locked || lockout.LockedUntil == nil || lockout.FailedLogins != freeLoginAttempts {
+		t.Fatalf("expected the account to be locked, got %+v", lockout)
+	}
+
+	// the lockout follows the account to other addresses
+	response := loginAttempt(server, "member@test.com", testPassword, "198.51.100.7:1234", nil)
+	assertStatus(t, response.Code, http.StatusTooManyRequests)
+	if response.Header().Get("Retry-After") != "900" {
+		t.Errorf("expected to wait for the lockout period, got %q", response.Header().Get("Retry-After"))
+	}
+
+	if err := server.Unlock("member@test.com"); err != nil {
+		t.Fatal(err)
+	}
+	assertStatus(t, loginAttempt(server, "member@test.com", testPassword, "198.51.100.7:1234", nil).Code, http.StatusOK)
+}
+
+func TestFailedLoginsPerAddress(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+	server.config.LoginIPMaxAttempts = 2
+
+	// guessing across accounts is stopped at the address
+	loginAttempt(server, "member@test.com", "wrong", "192.0.2.1:1234", nil)
+	loginAttempt(server, "admin@test.com", "wrong", "192.0.2.1:1234", nil)
+	assertStatus(t, loginAttempt(server, "nobody@test.com", "wrong", "192.0.2.1:1234", nil).Code, http.StatusTooManyRequests)
+
+	assertStatus(t, loginAttempt(server, "member@test.com", testPassword, "198.51.100.7:1234", nil).Code, http.StatusOK)
+}
+
+func TestWrongTwoFactorCodesCount(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	db.TwoFactor = map[string]models.TwoFactor{
+		"member@test.com": {Email: "member@test.com", Secret: "JBSWY3DPEHPK3PXP", Enabled: true},
+	}
+
+	// being asked for a code isn't a failure
+	decodeChallenge(t, loginAttempt(server, "member@test.com", testPassword, "192.0.2.1:1234", nil))
+
+	for n := 0; n < freeLoginAttempts; n++ {
+		decodeChallenge(t, loginAttempt(server, "member@test.com", testPassword, "192.0.2.1:1234", models.LoginRequest{Code: "000000"}))
+	}
+
+	assertStatus(t, loginAttempt(server, "member@test.com", testPassword, "192.0.2.1:1234", nil).Code, http.StatusTooManyRequests)
+}
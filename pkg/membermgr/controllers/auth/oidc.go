@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/oidc"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/shaj13/go-guardian/v2/auth"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	oidcCallbackPath = "/api/auth/oidc/callback"
+	oidcCookie       = "memberserver_oidc"
+	// oidcLoginTimeout is how long a user has to sign in with the provider
+	oidcLoginTimeout = 10 * time.Minute
+)
+
+// oidcLoginClaims remember a sign in that was sent to the provider
+//
+//	they are kept in a signed cookie so the callback can check it came from the same browser
+type oidcLoginClaims struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.StandardClaims
+}
+
+// OIDCProvider tells the login page whether to show the sign in button
+func (a *AuthController) OIDCProvider(w http.ResponseWriter, r *http.Request) {
+	if a.oidcProvider == nil {
+		ok(w, models.OIDCProviderResponse{})
+		return
+	}
+
+	ok(w, models.OIDCProviderResponse{
+		Enabled: true,
+		Name:    a.config.OIDCProviderName,
+	})
+}
+
+// OIDCLogin sends the user to the provider to sign in
+func (a *AuthController) OIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if a.oidcProvider == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	login := oidcLoginClaims{
+		State:    oidc.NewState(),
+		Nonce:    oidc.NewState(),
+		Verifier: oidc.NewVerifier(),
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(oidcLoginTimeout).Unix(),
+		},
+	}
+
+	authCodeURL, err := a.oidcProvider.AuthCodeURL(r.Context(), login.State, login.Nonce, login.Verifier)
+	if err != nil {
+		log.Errorf("error starting oidc login: %s", err)
+		http.Error(w, "sign in provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	cookie, err := jwt.NewWithClaims(jwt.SigningMethodHS256, login).SignedString(accountTokenKey(a.config.AccessSecret, purposeOIDCLogin))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	a.setOIDCCookie(w, cookie, int(oidcLoginTimeout.Seconds()))
+	http.Redirect(w, r, authCodeURL, http.StatusFound)
+}
+
+// OIDCCallback is where the provider sends the user back to
+//
+//	the provider's verified email has to belong to a member,
+//	they get the same token as a password login
+func (a *AuthController) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if a.oidcProvider == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	login, err := a.oidcLogin(r)
+	a.setOIDCCookie(w, "", -1)
+	if err != nil {
+		http.Error(w, "sign in expired, please try again", http.StatusBadRequest)
+		return
+	}
+
+	if providerErr := r.URL.Query().Get("error"); len(providerErr) > 0 {
+		log.Infof("oidc provider returned an error: %s", providerErr)
+		http.Error(w, "sign in was cancelled", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := a.oidcProvider.Exchange(r.Context(), r.URL.Query().Get("code"), login.Nonce, login.Verifier)
+	if err != nil {
+		log.Errorf("error finishing oidc login: %s", err)
+		http.Error(w, "sign in failed", http.StatusUnauthorized)
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(claims.Email))
+	if len(email) == 0 || !claims.EmailVerified {
+		http.Error(w, "your email address isn't verified with your sign in provider", http.StatusUnauthorized)
+		return
+	}
+
+	if _, err := a.store.GetMemberByEmail(email); err != nil {
+		log.Infof("oidc login for %s, who isn't a member: %s", email, err)
+		http.Error(w, "no member has that email address", http.StatusUnauthorized)
+		return
+	}
+
+	// the provider can't stand in for a second factor, we don't know how it signed the user in
+	groups := a.groups(email)
+	if len(a.twoFactorState(email, groups)) > 0 {
+		http.Error(w, "two-factor authentication is on for your account, sign in with your password and code", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := a.startSession(w, r, auth.NewDefaultUser(email, email, groups, nil))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	// the token goes in the fragment so the dashboard can pick it up without it reaching any server logs
+	http.Redirect(w, r, "/login#token="+token, http.StatusFound)
+}
+
+// oidcLogin checks the callback belongs to a sign in this browser started
+func (a *AuthController) oidcLogin(r *http.Request) (oidcLoginClaims, error) {
+	cookie, err := r.Cookie(oidcCookie)
+	if err != nil {
+		return oidcLoginClaims{}, err
+	}
+
+	login := oidcLoginClaims{}
+	_, err = jwt.ParseWithClaims(cookie.Value, &login, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, errInvalidToken
+		}
+		return accountTokenKey(a.config.AccessSecret, purposeOIDCLogin), nil
+	})
+	if err != nil || len(login.State) == 0 || login.State != r.URL.Query().Get("state") {
+		return oidcLoginClaims{}, errInvalidToken
+	}
+
+	return login, nil
+}
+
+// setOIDCCookie is lax, it has to be sent when the provider redirects back to us
+func (a *AuthController) setOIDCCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcCookie,
+		Value:    value,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(a.config.PublicURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/api/auth/oidc",
+	})
+}
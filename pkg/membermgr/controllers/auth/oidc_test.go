@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/oidc"
+	"github.com/HackRVA/memberserver/pkg/oidc/oidctest"
+
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	"github.com/shaj13/libcache"
+	_ "github.com/shaj13/libcache/fifo"
+)
+
+func newOIDCTestServer(t *testing.T) (*AuthController, *oidctest.Provider) {
+	t.Helper()
+	mock := oidctest.NewProvider("memberserver", "client secret")
+	t.Cleanup(mock.Close)
+
+	db := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {Name: "member", Email: "member@test.com"},
+		},
+		MemberRoles: map[string][]models.Role{
+			"member@test.com": {models.RoleBoard},
+		},
+	}
+
+	c := testConfig()
+	return &AuthController{
+		store:  db,
+		config: c,
+		oidcProvider: oidc.New(oidc.Config{
+			Issuer:       mock.Issuer(),
+			ClientID:     "memberserver",
+			ClientSecret: "client secret",
+			RedirectURL:  c.PublicURL + oidcCallbackPath,
+		}),
+		JWTSecretsKeeper: jwt.StaticSecret{ID: "secret-id", Secret: []byte(c.AccessSecret), Algorithm: jwt.HS256},
+	}, mock
+}
+
+// signInWithProvider starts a login and follows the provider back to the callback
+func signInWithProvider(t *testing.T, server *AuthController) *http.Request {
+	t.Helper()
+	request, _ := http.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil)
+	response := httptest.NewRecorder()
+	server.OIDCLogin(response, request)
+	assertStatus(t, response.Code, http.StatusFound)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	res, err := client.Get(response.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	callback, _ := http.NewRequest(http.MethodGet, res.Header.Get("Location"), nil)
+	for _, cookie := range response.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+	return callback
+}
+
+func TestOIDCLogin(t *testing.T) {
+	server, mock := newOIDCTestServer(t)
+	mock.SetUser(oidctest.User{Email: "Member@test.com", EmailVerified: true})
+
+	response := httptest.NewRecorder()
+	server.OIDCCallback(response, signInWithProvider(t, server))
+	assertStatus(t, response.Code, http.StatusFound)
+
+	location := response.Header().Get("Location")
+	token := strings.TrimPrefix(location, "/login#token=")
+	if token == location {
+		t.Fatalf("expected a redirect to the dashboard with a token, got %s", location)
+	}
+
+	var cookie string
+	for _, c := range response.Result().Cookies() {
+		if c.Name == "memberserver" {
+			cookie = c.Value
+		}
+	}
+	if cookie != token {
+		t.Error("expected the token to be set as the auth cookie")
+	}
+
+	// the token has to work like one from a password login
+	cache := libcache.FIFO.New(0)
+	cache.SetTTL(time.Minute)
+	request, _ := http.NewRequest(http.MethodGet, "/api/user", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	user, err := jwt.New(cache, server.JWTSecretsKeeper).Authenticate(request.Context(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.GetUserName() != "member@test.com" {
+		t.Errorf("got user %s", user.GetUserName())
+	}
+	if strings.Join(user.GetGroups(), ",") != "member,board" {
+		t.Errorf("expected the member's roles, got %v", user.GetGroups())
+	}
+}
+
+func TestOIDCLoginFailures(t *testing.T) {
+	tests := []struct {
+		TestName       string
+		user           oidctest.User
+		tamper         func(r *http.Request) *http.Request
+		expectedStatus int
+	}{
+		{
+			TestName:       "should reject someone who isn't a member",
+			user:           oidctest.User{Email: "stranger@test.com", EmailVerified: true},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			TestName:       "should reject an email the provider hasn't verified",
+			user:           oidctest.User{Email: "member@test.com"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			TestName: "should reject a callback from another browser",
+			user:     oidctest.User{Email: "member@test.com", EmailVerified: true},
+			tamper: func(r *http.Request) *http.Request {
+				r.Header.Del("Cookie")
+				return r
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			TestName: "should reject a callback with the wrong state",
+			user:     oidctest.User{Email: "member@test.com", EmailVerified: true},
+			tamper: func(r *http.Request) *http.Request {
+				q := r.URL.Query()
+				q.Set("state", "forged")
+				r.URL.RawQuery = q.Encode()
+				return r
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			TestName: "should reject a made up code",
+			user:     oidctest.User{Email: "member@test.com", EmailVerified: true},
+			tamper: func(r *http.Request) *http.Request {
+				q := r.URL.Query()
+				q.Set("code", "forged")
+				r.URL.RawQuery = q.Encode()
+				return r
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			server, mock := newOIDCTestServer(t)
+			mock.SetUser(tt.user)
+
+			callback := signInWithProvider(t, server)
+			if tt.tamper != nil {
+				callback = tt.tamper(callback)
+			}
+
+			response := httptest.NewRecorder()
+			server.OIDCCallback(response, callback)
+			assertStatus(t, response.Code, tt.expectedStatus)
+
+			for _, c := range response.Result().Cookies() {
+				if c.Name == "memberserver" {
+					t.Error("expected no auth cookie")
+				}
+			}
+		})
+	}
+}
+
+func TestOIDCDisabled(t *testing.T) {
+	server := &AuthController{config: testConfig()}
+
+	response := httptest.NewRecorder()
+	server.OIDCProvider(response, httptest.NewRequest(http.MethodGet, "/api/auth/oidc", nil))
+	assertResponseBody(t, response.Body.String(), `{"enabled":false,"name":""}`)
+
+	response = httptest.NewRecorder()
+	server.OIDCLogin(response, httptest.NewRequest(http.MethodGet, "/api/auth/oidc/login", nil))
+	assertStatus(t, response.Code, http.StatusNotFound)
+
+	response = httptest.NewRecorder()
+	server.OIDCCallback(response, httptest.NewRequest(http.MethodGet, oidcCallbackPath+"?code=code", nil))
+	assertStatus(t, response.Code, http.StatusNotFound)
+}
@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/jwt"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	authCookie    = "memberserver"
+	refreshCookie = "memberserver_refresh"
+	refreshPath   = "/api/auth/refresh"
+
+	// sessionExtension holds the session an access token belongs to
+	sessionExtension = "sid"
+	// refreshGracePeriod is how long a refresh token still works after it's replaced,
+	// so two tabs refreshing at the same time don't sign the user out
+	refreshGracePeriod = 30 * time.Second
+)
+
+// startSession signs the user in, it sets the refresh token cookie and returns an access token
+func (a *AuthController) startSession(w http.ResponseWriter, r *http.Request, u auth.Info) (string, error) {
+	refreshToken, refreshHash := newRefreshToken()
+	session, err := a.store.CreateSession(models.Session{
+		Email:       strings.ToLower(u.GetUserName()),
+		RefreshHash: refreshHash,
+		UserAgent:   r.UserAgent(),
+		IPAddress:   a.ClientIP(r),
+		ExpiresAt:   time.Now().Add(a.refreshTokenLifetime()),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	a.setRefreshCookie(w, session.ID+"."+refreshToken, int(a.refreshTokenLifetime().Seconds()))
+	return a.issueAccessToken(w, session.ID, u)
+}
+
+// issueAccessToken signs a short lived JWT for the user's session and sets it as the auth cookie
+func (a *AuthController) issueAccessToken(w http.ResponseWriter, sessionID string, u auth.Info) (string, error) {
+	extensions := auth.Extensions{}
+	extensions.Set(sessionExtension, sessionID)
+	user := auth.NewDefaultUser(u.GetUserName(), u.GetID(), u.GetGroups(), extensions)
+
+	token, err := jwt.IssueAccessToken(user, a.JWTSecretsKeeper, jwt.SetExpDuration(a.accessTokenLifetime()))
+	if err != nil {
+		return "", err
+	}
+
+	a.setAuthCookie(w, token)
+	return token, nil
+}
+
+// Refresh swaps the refresh token cookie for a new one and a new access token
+//
+//	the user's roles are read again, so the new access token has their current ones
+//	a refresh token that was already swapped ends the session, it may have been copied
+func (a *AuthController) Refresh(w http.ResponseWriter, r *http.Request) {
+	session, refreshHash, err := a.refreshSession(r)
+	if err != nil || !session.Active(time.Now()) {
+		a.endSession(w, "your session has ended, please sign in again")
+		return
+	}
+
+	switch {
+	case refreshHash == session.RefreshHash:
+		refreshToken, newRefreshHash := newRefreshToken()
+		rotated, err := a.store.RotateSession(session.ID, refreshHash, newRefreshHash, time.Now().Add(a.refreshTokenLifetime()))
+		if err != nil || !rotated {
+			a.endSession(w, "your session has ended, please sign in again")
+			return
+		}
+		a.setRefreshCookie(w, session.ID+"."+refreshToken, int(a.refreshTokenLifetime().Seconds()))
+	case refreshHash == session.PreviousHash && session.RotatedAt != nil && time.Since(*session.RotatedAt) < refreshGracePeriod:
+		// another request from the same browser already swapped it, the browser has the new cookie
+	default:
+		log.Warnf("refresh token reused for %s, ending session %s", session.Email, session.ID)
+		if err := a.store.RevokeSession(session.ID); err != nil {
+			log.Errorf("error revoking session %s: %s", session.ID, err)
+		}
+		a.endSession(w, "your session has ended, please sign in again")
+		return
+	}
+
+	token, err := a.issueAccessToken(w, session.ID, auth.NewDefaultUser(session.Email, session.Email, a.groups(session.Email), nil))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	ok(w, &models.TokenResponse{
+		Token: token,
+	})
+}
+
+// refreshSession finds the session for the refresh token cookie
+func (a *AuthController) refreshSession(r *http.Request) (models.Session, string, error) {
+	cookie, err := r.Cookie(refreshCookie)
+	if err != nil {
+		return models.Session{}, "", err
+	}
+
+	id, refreshToken, found := strings.Cut(cookie.Value, ".")
+	if !found {
+		return models.Session{}, "", errInvalidToken
+	}
+
+	session, err := a.store.GetSession(id)
+	return session, hashRefreshToken(refreshToken), err
+}
+
+// sessionEnded reports whether a request's access token belongs to a session that has ended
+//
+//	passwords and API keys are checked on every request, they don't have sessions
+func (a *AuthController) sessionEnded(r *http.Request, user auth.Info) bool {
+	if _, _, ok := r.BasicAuth(); ok || IsAPIKey(user) {
+		return false
+	}
+
+	session, err := a.store.GetSession(sessionID(user))
+	return err != nil || !session.Active(time.Now()) || !strings.EqualFold(session.Email, user.GetUserName())
+}
+
+// endSession clears the session cookies and tells the client to sign in again
+func (a *AuthController) endSession(w http.ResponseWriter, message string) {
+	a.removeAuthCookie(w)
+	a.setRefreshCookie(w, "", -1)
+	http.Error(w, message, http.StatusUnauthorized)
+}
+
+func (a *AuthController) accessTokenLifetime() time.Duration {
+	return time.Duration(a.config.AccessTokenMinutes) * time.Minute
+}
+
+func (a *AuthController) refreshTokenLifetime() time.Duration {
+	return time.Duration(a.config.RefreshTokenDays) * 24 * time.Hour
+}
+
+// setRefreshCookie is only sent to the refresh route, so the refresh token isn't sent with every request
+func (a *AuthController) setRefreshCookie(w http.ResponseWriter, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookie,
+		Value:    value,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(a.config.PublicURL, "https://"),
+		SameSite: http.SameSiteStrictMode,
+		Path:     refreshPath,
+	})
+}
+
+func sessionID(user auth.Info) string {
+	if user == nil {
+		return ""
+	}
+	return user.GetExtensions().Get(sessionExtension)
+}
+
+// newRefreshToken returns a token for the cookie and the hash to store
+func newRefreshToken() (string, string) {
+	b := make([]byte, 32)
+	rand.Read(b)
+	token := hex.EncodeToString(b)
+	return token, hashRefreshToken(token)
+}
+
+// hashRefreshToken is how refresh tokens are stored, they're random so a fast hash is enough
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// signIn logs in with a password and returns the access token and the refresh token cookie
+func signIn(t *testing.T, server *AuthController, email string) (string, *http.Cookie) {
+	t.Helper()
+	response := passwordRequest(server, email, loginPath, nil)
+	assertStatus(t, response.Code, http.StatusOK)
+	return decodeToken(t, response), findCookie(response, refreshCookie)
+}
+
+func decodeToken(t *testing.T, response *httptest.ResponseRecorder) string {
+	t.Helper()
+	var token models.TokenResponse
+	if err := json.NewDecoder(response.Body).Decode(&token); err != nil {
+		t.Fatal(err)
+	}
+	return token.Token
+}
+
+func findCookie(response *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range response.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func refresh(server *AuthController, cookie *http.Cookie) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+	request.AddCookie(cookie)
+	response := httptest.NewRecorder()
+	server.Refresh(response, request)
+	return response
+}
+
+// bearerRequest goes through the auth middleware with an access token like the dashboard does
+func bearerRequest(server *AuthController, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/api/member", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.AddCookie(&http.Cookie{Name: authCookie, Value: token})
+
+	response := httptest.NewRecorder()
+	server.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ok(w, "reached")
+	})).ServeHTTP(response, request)
+	return response
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	token, cookie := signIn(t, server, "member@test.com")
+
+	if cookie == nil || cookie.Path != refreshPath || !cookie.HttpOnly {
+		t.Fatalf("expected an http only refresh cookie for the refresh route, got %+v", cookie)
+	}
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusOK)
+
+	response := refresh(server, cookie)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	refreshed := decodeToken(t, response)
+	rotated := findCookie(response, refreshCookie)
+	if rotated == nil || rotated.Value == cookie.Value {
+		t.Fatal("expected the refresh token to be replaced")
+	}
+	assertStatus(t, bearerRequest(server, refreshed).Code, http.StatusOK)
+
+	if len(db.Sessions) != 1 {
+		t.Errorf("expected refreshing to keep the same session, got %d", len(db.Sessions))
+	}
+}
+
+func TestRefreshTokenReuseEndsSession(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	_, stolen := signIn(t, server, "member@test.com")
+
+	response := refresh(server, stolen)
+	assertStatus(t, response.Code, http.StatusOK)
+	token := decodeToken(t, response)
+	rotated := findCookie(response, refreshCookie)
+
+	// a second tab refreshing with the old token at the same time is fine
+	response = refresh(server, stolen)
+	assertStatus(t, response.Code, http.StatusOK)
+	if findCookie(response, refreshCookie) != nil {
+		t.Error("expected the grace period not to replace the refresh token again")
+	}
+
+	// after that the old token was copied, so the whole session ends
+	past := time.Now().Add(-time.Minute)
+	db.Sessions[0].RotatedAt = &past
+
+	assertStatus(t, refresh(server, stolen).Code, http.StatusUnauthorized)
+	assertStatus(t, refresh(server, rotated).Code, http.StatusUnauthorized)
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusUnauthorized)
+}
+
+func TestRefreshRejectsBadTokens(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+	_, cookie := signIn(t, server, "member@test.com")
+
+	for _, value := range []string{"", "1", "1.nope", "2." + cookie.Value[2:]} {
+		assertStatus(t, refresh(server, &http.Cookie{Name: refreshCookie, Value: value}).Code, http.StatusUnauthorized)
+	}
+}
+
+func TestLogoutRevokesSession(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	token, cookie := signIn(t, server, "member@test.com")
+
+	request := httptest.NewRequest(http.MethodDelete, "/api/auth/logout", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	request.AddCookie(&http.Cookie{Name: authCookie, Value: token})
+	server.AuthMiddleware(http.HandlerFunc(server.Logout)).ServeHTTP(httptest.NewRecorder(), request)
+
+	if db.Sessions[0].Active(time.Now()) {
+		t.Error("expected logging out to revoke the session")
+	}
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusUnauthorized)
+	assertStatus(t, refresh(server, cookie).Code, http.StatusUnauthorized)
+}
+
+func TestRoleChangeRevokesSessions(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	token, cookie := signIn(t, server, "member@test.com")
+
+	// saving the same roles doesn't sign anyone out
+	db.SetMemberRoles("member@test.com", nil)
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusOK)
+
+	db.SetMemberRoles("member@test.com", []models.Role{models.RoleAdmin})
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusUnauthorized)
+	assertStatus(t, refresh(server, cookie).Code, http.StatusUnauthorized)
+}
+
+func TestAccessTokenNeedsSession(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	token, _ := signIn(t, server, "member@test.com")
+
+	db.Sessions = nil
+	assertStatus(t, bearerRequest(server, token).Code, http.StatusUnauthorized)
+}
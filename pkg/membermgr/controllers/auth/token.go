@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// tokenPurpose is what an account token can be used for
+type tokenPurpose string
+
+const (
+	purposeVerifyEmail   tokenPurpose = "verify-email"
+	purposeResetPassword tokenPurpose = "reset-password"
+	purposeOIDCLogin     tokenPurpose = "oidc-login"
+	purposeApproveTOTP   tokenPurpose = "approve-2fa"
+)
+
+var errInvalidToken = errors.New("link is invalid or has expired")
+
+// accountClaims are the claims in the tokens sent in verification and password reset links
+//
+//	Fingerprint is derived from the user's password hash when the token was issued,
+//	so a token stops working once the password changes and a reset link can only be used once
+type accountClaims struct {
+	Purpose     tokenPurpose `json:"purpose"`
+	Fingerprint string       `json:"fingerprint"`
+	jwt.StandardClaims
+}
+
+// accountTokenKey derives a signing key for one purpose from the access secret
+//
+//	account tokens are never accepted as login tokens, or as each other
+func accountTokenKey(secret string, purpose tokenPurpose) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("memberserver account token " + purpose))
+	return mac.Sum(nil)
+}
+
+// passwordFingerprint identifies a password hash without revealing it
+func passwordFingerprint(passwordHash string) string {
+	sum := sha256.Sum256([]byte(passwordHash))
+	return hex.EncodeToString(sum[:8])
+}
+
+func issueAccountToken(secret string, purpose tokenPurpose, email string, passwordHash string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := accountClaims{
+		Purpose:     purpose,
+		Fingerprint: passwordFingerprint(passwordHash),
+		StandardClaims: jwt.StandardClaims{
+			Subject:   email,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(ttl).Unix(),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(accountTokenKey(secret, purpose))
+}
+
+// parseAccountToken checks a token's signature, expiry and purpose
+func parseAccountToken(secret string, purpose tokenPurpose, token string) (accountClaims, error) {
+	claims := accountClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		if t.Method != jwt.SigningMethodHS256 {
+			return nil, errInvalidToken
+		}
+		return accountTokenKey(secret, purpose), nil
+	})
+	if err != nil || claims.Purpose != purpose || len(claims.Subject) == 0 {
+		return accountClaims{}, errInvalidToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,391 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+	"github.com/HackRVA/memberserver/pkg/totp"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	log "github.com/sirupsen/logrus"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	loginPath = "/api/auth/login"
+
+	// twoFactorVerify means the user is enrolled and has to give a code to sign in
+	twoFactorVerify = "verify"
+	// twoFactorSetup means the user has to enrol before they can sign in
+	twoFactorSetup = "setup"
+
+	recoveryCodeCount = 10
+)
+
+// twoFactorPendingRoutes are what a user can reach with only their password while a second factor is pending
+var twoFactorPendingRoutes = map[string][]string{
+	twoFactorVerify: {loginPath},
+	twoFactorSetup:  {loginPath, "/api/auth/2fa", "/api/auth/2fa/enroll", "/api/auth/2fa/qr", "/api/auth/2fa/confirm"},
+}
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var errEnrollmentNotApproved = errors.New("follow the link we emailed you to confirm setting up two-factor authentication, then sign in again")
+
+// twoFactorRequired reports whether the policy makes users with these roles use two-factor authentication
+func (a *AuthController) twoFactorRequired(groups []string) bool {
+	return a.config.RequireAdminTwoFactor && rbac.Allowed(groups, rbac.ManageRoles)
+}
+
+// twoFactorState is what a user still has to do after giving their password
+func (a *AuthController) twoFactorState(email string, groups []string) string {
+	tf, err := a.store.GetTwoFactor(strings.ToLower(email))
+	if err == nil && tf.Enabled {
+		return twoFactorVerify
+	}
+
+	if a.twoFactorRequired(groups) {
+		return twoFactorSetup
+	}
+
+	return ""
+}
+
+// pendingSecondFactor is the two-factor state of a request signed with a password
+//
+//	requests with a token don't have one, tokens are only issued once the second factor is done
+func (a *AuthController) pendingSecondFactor(r *http.Request, user auth.Info) string {
+	if _, _, ok := r.BasicAuth(); !ok {
+		return ""
+	}
+
+	return a.twoFactorState(user.GetUserName(), user.GetGroups())
+}
+
+func allowedWhilePending(state string, path string) bool {
+	for _, route := range twoFactorPendingRoutes[state] {
+		if route == path {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSecondFactor accepts either a code from the user's app or an unused recovery code
+func (a *AuthController) checkSecondFactor(email string, request models.LoginRequest) bool {
+	if len(request.RecoveryCode) > 0 {
+		used, err := a.store.UseRecoveryCode(email, hashRecoveryCode(request.RecoveryCode))
+		if err != nil {
+			log.Errorf("error using recovery code for %s: %s", email, err)
+		}
+		return used
+	}
+
+	return a.checkCode(email, request.Code)
+}
+
+// checkCode accepts a code from the user's app, each code only works once
+func (a *AuthController) checkCode(email string, code string) bool {
+	tf, err := a.twoFactor(email)
+	if err != nil || !tf.Enabled {
+		return false
+	}
+
+	step, valid := totp.Validate(tf.Secret, code, time.Now())
+	if !valid {
+		return false
+	}
+
+	used, err := a.store.UseTOTPStep(email, step)
+	if err != nil {
+		log.Errorf("error using totp code for %s: %s", email, err)
+	}
+	return used
+}
+
+// TwoFactorStatus responds with whether the user has two-factor authentication
+func (a *AuthController) TwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	tf, _ := a.store.GetTwoFactor(strings.ToLower(user.GetUserName()))
+
+	ok(w, models.TwoFactorStatus{
+		Enabled:           tf.Enabled,
+		Required:          a.twoFactorRequired(user.GetGroups()),
+		RecoveryCodesLeft: tf.RecoveryCodesLeft,
+	})
+}
+
+// EnrollTwoFactor starts enrolling an authenticator app
+//
+//	the app isn't used until the user confirms a code from it.
+//	A user who has to enrol is emailed a link to confirm it's them first
+func (a *AuthController) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	email := strings.ToLower(auth.User(r).GetUserName())
+
+	tf, err := a.store.GetTwoFactor(email)
+	if err != nil && !errors.Is(err, datastore.ErrUserNotFound) {
+		log.Errorf("error getting two-factor for %s: %s", email, err)
+		http.Error(w, "error enrolling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	if err == nil && !a.enrollmentApproved(r, tf) {
+		if err := a.sendTwoFactorApproval(email); err != nil {
+			log.Errorf("error sending two-factor approval email to %s: %s", email, err)
+			http.Error(w, "error sending confirmation email", http.StatusInternalServerError)
+			return
+		}
+		http.Error(w, errEnrollmentNotApproved.Error(), http.StatusForbidden)
+		return
+	}
+
+	secret := totp.NewSecret()
+	sealed, err := a.sealSecret(email, secret)
+	if err != nil {
+		log.Error(err)
+		http.Error(w, "error enrolling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	if err := a.store.SetTwoFactorSecret(email, sealed); err != nil {
+		log.Infof("not enrolling %s: %s", email, err)
+		http.Error(w, "two-factor authentication is already enabled, disable it first", http.StatusPreconditionFailed)
+		return
+	}
+
+	ok(w, models.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.ProvisioningURI(a.config.TwoFactorIssuer, email, secret),
+	})
+}
+
+// TwoFactorQRCode responds with a QR code for the authenticator app to scan
+func (a *AuthController) TwoFactorQRCode(w http.ResponseWriter, r *http.Request) {
+	email := strings.ToLower(auth.User(r).GetUserName())
+	tf, err := a.twoFactor(email)
+	if err != nil || tf.Enabled || len(tf.Secret) == 0 {
+		http.Error(w, "start enrolling first", http.StatusNotFound)
+		return
+	}
+
+	if !a.enrollmentApproved(r, tf) {
+		http.Error(w, errEnrollmentNotApproved.Error(), http.StatusForbidden)
+		return
+	}
+
+	png, err := qrcode.Encode(totp.ProvisioningURI(a.config.TwoFactorIssuer, email, tf.Secret), qrcode.Medium, 256)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(png)
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user shows their app works
+//
+//	the response has the user's recovery codes, they aren't shown again
+func (a *AuthController) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	email := strings.ToLower(auth.User(r).GetUserName())
+
+	var request models.TwoFactorCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	tf, err := a.twoFactor(email)
+	if err != nil || tf.Enabled || len(tf.Secret) == 0 {
+		http.Error(w, "start enrolling first", http.StatusPreconditionFailed)
+		return
+	}
+
+	if !a.enrollmentApproved(r, tf) {
+		http.Error(w, errEnrollmentNotApproved.Error(), http.StatusForbidden)
+		return
+	}
+
+	step, valid := totp.Validate(tf.Secret, request.Code, time.Now())
+	if !valid {
+		http.Error(w, "invalid code", http.StatusBadRequest)
+		return
+	}
+
+	codes, hashes := newRecoveryCodes()
+	if err := a.store.EnableTwoFactor(email, step, hashes); err != nil {
+		log.Error(err)
+		http.Error(w, "error enabling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	a.audit(email, models.AuditActionTwoFactorEnable, email)
+	ok(w, models.RecoveryCodesResponse{Codes: codes})
+}
+
+// enrollmentApproved reports whether the user can enrol
+//
+//	users who have to enrol before they can sign in only have their password,
+//	so they confirm from their email first and a stolen password isn't enough to enrol
+func (a *AuthController) enrollmentApproved(r *http.Request, tf models.TwoFactor) bool {
+	return tf.EnrollmentApproved || a.pendingSecondFactor(r, auth.User(r)) != twoFactorSetup
+}
+
+// sendTwoFactorApproval emails a user who has to enrol a link to confirm it's them
+func (a *AuthController) sendTwoFactorApproval(email string) error {
+	account, err := a.store.GetUserAccount(email)
+	if err != nil {
+		return err
+	}
+
+	member, err := a.store.GetMemberByEmail(email)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(a.config.PasswordResetMinutes) * time.Minute
+	token, err := issueAccountToken(a.config.AccessSecret, purposeApproveTOTP, account.Email, account.PasswordHash, ttl)
+	if err != nil {
+		return err
+	}
+
+	return a.mailer.SendAccountEmail(mail.TwoFactorApproval, member.Email, accountEmail{
+		Name:      member.Name,
+		Link:      a.link("/api/auth/2fa/approve", token),
+		ExpiresIn: expiresIn(ttl),
+	})
+}
+
+// ApproveTwoFactor lets a user who has to enrol start enrolling when they follow the link in their email
+func (a *AuthController) ApproveTwoFactor(w http.ResponseWriter, r *http.Request) {
+	claims, err := parseAccountToken(a.config.AccessSecret, purposeApproveTOTP, r.URL.Query().Get("token"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	account, err := a.store.GetUserAccount(claims.Subject)
+	if err != nil || passwordFingerprint(account.PasswordHash) != claims.Fingerprint {
+		http.Error(w, errInvalidToken.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := a.store.ApproveTwoFactorEnrollment(account.Email); err != nil {
+		log.Error(err)
+		http.Error(w, "error approving two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	a.audit(account.Email, models.AuditActionTwoFactorApprove, account.Email)
+	http.Redirect(w, r, "/login", http.StatusFound)
+}
+
+// RegenerateRecoveryCodes replaces the user's recovery codes
+func (a *AuthController) RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	email := strings.ToLower(auth.User(r).GetUserName())
+	if !a.decodeAndCheckCode(w, r, email) {
+		return
+	}
+
+	codes, hashes := newRecoveryCodes()
+	if err := a.store.SetRecoveryCodes(email, hashes); err != nil {
+		log.Error(err)
+		http.Error(w, "error saving recovery codes", http.StatusInternalServerError)
+		return
+	}
+
+	ok(w, models.RecoveryCodesResponse{Codes: codes})
+}
+
+// DisableTwoFactor turns two-factor authentication off, unless the user's roles require it
+func (a *AuthController) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	user := auth.User(r)
+	email := strings.ToLower(user.GetUserName())
+
+	if a.twoFactorRequired(user.GetGroups()) {
+		http.Error(w, "two-factor authentication is required for your roles", http.StatusPreconditionFailed)
+		return
+	}
+
+	if !a.decodeAndCheckCode(w, r, email) {
+		return
+	}
+
+	if err := a.store.DisableTwoFactor(email); err != nil {
+		log.Error(err)
+		http.Error(w, "error disabling two-factor authentication", http.StatusInternalServerError)
+		return
+	}
+
+	a.audit(email, models.AuditActionTwoFactorDisable, email)
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+// decodeAndCheckCode makes changes to an enrolment need a current code
+func (a *AuthController) decodeAndCheckCode(w http.ResponseWriter, r *http.Request, email string) bool {
+	var request models.LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	if !a.checkSecondFactor(email, request) {
+		http.Error(w, "invalid code", http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+func (a *AuthController) audit(actor string, action string, target string) {
+	err := a.store.LogAuditEvent(models.AuditEntry{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+	})
+	if err != nil {
+		log.Errorf("error writing audit log: %s", err)
+	}
+}
+
+// newRecoveryCodes returns codes to show the user once, and the hashes to store
+func newRecoveryCodes() ([]string, []string) {
+	codes := []string{}
+	hashes := []string{}
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 7)
+		rand.Read(b)
+		code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes
+}
+
+// hashRecoveryCode ignores case and formatting so codes can be typed loosely
+//
+//	the codes are random, so they don't need a slow hash like passwords
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// twoFactorChallenge tells the client a login needs another step
+func twoFactorChallenge(w http.ResponseWriter, challenge models.TwoFactorChallenge) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(challenge)
+}
@@ -0,0 +1,316 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+	"github.com/HackRVA/memberserver/pkg/totp"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+const testPassword = "correct-battery-horse"
+
+func newTwoFactorTestServer(t *testing.T) (*AuthController, *in_memory.In_memory) {
+	t.Helper()
+	db := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"admin@test.com":  {Name: "admin", Email: "admin@test.com"},
+			"member@test.com": {Name: "member", Email: "member@test.com"},
+		},
+		MemberRoles: map[string][]models.Role{
+			"admin@test.com": {models.RoleAdmin},
+		},
+	}
+
+	for email := range db.Members {
+		if err := db.RegisterUser(models.Credentials{Email: email, Password: testPassword}); err != nil {
+			t.Fatal(err)
+		}
+		db.VerifyUser(email)
+	}
+
+	c := testConfig()
+	c.RequireAdminTwoFactor = true
+	c.TwoFactorIssuer = "HackRVA"
+	return newAuthController(db, &recordingMailer{}, c), db
+}
+
+// passwordRequest goes through the auth middleware like a request from the dashboard would
+func passwordRequest(server *AuthController, email string, path string, body interface{}) *httptest.ResponseRecorder {
+	handlers := map[string]http.HandlerFunc{
+		loginPath:                      server.Login,
+		"/api/auth/2fa":                server.TwoFactorStatus,
+		"/api/auth/2fa/enroll":         server.EnrollTwoFactor,
+		"/api/auth/2fa/qr":             server.TwoFactorQRCode,
+		"/api/auth/2fa/confirm":        server.ConfirmTwoFactor,
+		"/api/auth/2fa/recovery-codes": server.RegenerateRecoveryCodes,
+		"/api/auth/2fa/disable":        server.DisableTwoFactor,
+		"/api/member":                  func(w http.ResponseWriter, r *http.Request) { ok(w, "reached") },
+	}
+
+	reqBody, _ := json.Marshal(body)
+	request := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(reqBody))
+	request.SetBasicAuth(email, testPassword)
+
+	response := httptest.NewRecorder()
+	server.AuthMiddleware(handlers[path]).ServeHTTP(response, request)
+	return response
+}
+
+func decodeChallenge(t *testing.T, response *httptest.ResponseRecorder) models.TwoFactorChallenge {
+	t.Helper()
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+
+	var challenge models.TwoFactorChallenge
+	if err := json.NewDecoder(response.Body).Decode(&challenge); err != nil {
+		t.Fatal(err)
+	}
+	return challenge
+}
+
+func approveTwoFactor(server *AuthController, token string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodGet, "/api/auth/2fa/approve?token="+url.QueryEscape(token), nil)
+	response := httptest.NewRecorder()
+	server.ApproveTwoFactor(response, request)
+	return response
+}
+
+func code(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+	c, err := totp.Code(secret, totp.Step(time.Now())+offset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestTwoFactorRequiredForAdmins(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+
+	if challenge := decodeChallenge(t, passwordRequest(server, "admin@test.com", loginPath, nil)); !challenge.SetupRequired {
+		t.Fatal("expected an admin without two-factor to have to enrol")
+	}
+
+	if challenge := decodeChallenge(t, passwordRequest(server, "admin@test.com", "/api/member", nil)); !challenge.SetupRequired {
+		t.Fatal("expected a password alone not to reach other routes")
+	}
+
+	// a password alone isn't enough to enrol, the admin confirms it from their email first
+	response := passwordRequest(server, "admin@test.com", "/api/auth/2fa/enroll", nil)
+	assertStatus(t, response.Code, http.StatusForbidden)
+
+	mailer := server.mailer.(*recordingMailer)
+	if len(mailer.sent) != 1 || mailer.sent[0].communication != mail.TwoFactorApproval {
+		t.Fatalf("expected an approval email, got %+v", mailer.sent)
+	}
+
+	response = approveTwoFactor(server, mailer.token(t))
+	assertStatus(t, response.Code, http.StatusFound)
+
+	response = passwordRequest(server, "admin@test.com", "/api/auth/2fa/enroll", nil)
+	assertStatus(t, response.Code, http.StatusOK)
+	var enrollment models.TwoFactorEnrollment
+	json.NewDecoder(response.Body).Decode(&enrollment)
+
+	if tf, _ := db.GetTwoFactor("admin@test.com"); !strings.HasPrefix(tf.Secret, sealedSecretPrefix) || strings.Contains(tf.Secret, enrollment.Secret) {
+		t.Errorf("expected the secret to be stored encrypted, got %s", tf.Secret)
+	}
+
+	response = passwordRequest(server, "admin@test.com", "/api/auth/2fa/qr", nil)
+	assertStatus(t, response.Code, http.StatusOK)
+	if response.Header().Get("Content-Type") != "image/png" {
+		t.Errorf("expected a png, got %s", response.Header().Get("Content-Type"))
+	}
+
+	response = passwordRequest(server, "admin@test.com", "/api/auth/2fa/confirm", models.TwoFactorCodeRequest{Code: "000000"})
+	assertStatus(t, response.Code, http.StatusBadRequest)
+
+	confirmCode := code(t, enrollment.Secret, 0)
+	response = passwordRequest(server, "admin@test.com", "/api/auth/2fa/confirm", models.TwoFactorCodeRequest{Code: confirmCode})
+	assertStatus(t, response.Code, http.StatusOK)
+	var recovery models.RecoveryCodesResponse
+	json.NewDecoder(response.Body).Decode(&recovery)
+	if len(recovery.Codes) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(recovery.Codes))
+	}
+
+	if len(db.AuditLog) != 2 || db.AuditLog[0].Action != models.AuditActionTwoFactorApprove || db.AuditLog[1].Action != models.AuditActionTwoFactorEnable {
+		t.Errorf("expected approving and enrolling to be audited, got %+v", db.AuditLog)
+	}
+
+	if challenge := decodeChallenge(t, passwordRequest(server, "admin@test.com", loginPath, nil)); !challenge.TwoFactorRequired {
+		t.Fatal("expected login to ask for a code")
+	}
+
+	response = passwordRequest(server, "admin@test.com", "/api/auth/2fa/enroll", nil)
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+
+	response = passwordRequest(server, "admin@test.com", loginPath, models.LoginRequest{Code: confirmCode})
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+
+	response = passwordRequest(server, "admin@test.com", loginPath, models.LoginRequest{Code: code(t, enrollment.Secret, 1)})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	response = passwordRequest(server, "admin@test.com", loginPath, models.LoginRequest{RecoveryCode: recovery.Codes[0]})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	response = passwordRequest(server, "admin@test.com", loginPath, models.LoginRequest{RecoveryCode: recovery.Codes[0]})
+	assertStatus(t, response.Code, http.StatusUnauthorized)
+}
+
+func TestTwoFactorApprovalRejectsBadTokens(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	passwordRequest(server, "admin@test.com", "/api/auth/2fa/enroll", nil)
+	token := server.mailer.(*recordingMailer).token(t)
+
+	verifyToken, _ := issueAccountToken("secret", purposeVerifyEmail, "admin@test.com", "", time.Hour)
+
+	tests := []struct {
+		TestName string
+		token    string
+	}{
+		{"should reject an email verification token", verifyToken},
+		{"should reject garbage", "not a token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			response := approveTwoFactor(server, tt.token)
+			assertStatus(t, response.Code, http.StatusBadRequest)
+		})
+	}
+
+	// changing the password invalidates the link
+	db.UpdatePassword(models.Credentials{Email: "admin@test.com", Password: "a-different-long-password"})
+	response := approveTwoFactor(server, token)
+	assertStatus(t, response.Code, http.StatusBadRequest)
+
+	if tf, _ := db.GetTwoFactor("admin@test.com"); tf.EnrollmentApproved {
+		t.Error("expected the enrolment not to be approved")
+	}
+}
+
+func TestSealedSecrets(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+
+	sealed, err := server.sealSecret("admin@test.com", "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if secret, err := server.openSecret("admin@test.com", sealed); err != nil || secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("expected the secret back, got %s %v", secret, err)
+	}
+
+	if _, err := server.openSecret("member@test.com", sealed); err == nil {
+		t.Error("expected a secret copied to another account not to open")
+	}
+
+	server.config.TwoFactorSecretKey = "another key"
+	if _, err := server.openSecret("admin@test.com", sealed); err == nil {
+		t.Error("expected a secret sealed with another key not to open")
+	}
+
+	if secret, _ := server.openSecret("admin@test.com", "JBSWY3DPEHPK3PXP"); secret != "JBSWY3DPEHPK3PXP" {
+		t.Errorf("expected a secret saved before encryption to be read as it is, got %s", secret)
+	}
+}
+
+func TestTwoFactorOptionalForMembers(t *testing.T) {
+	server, _ := newTwoFactorTestServer(t)
+
+	response := passwordRequest(server, "member@test.com", loginPath, nil)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	response = passwordRequest(server, "member@test.com", "/api/auth/2fa/enroll", nil)
+	assertStatus(t, response.Code, http.StatusOK)
+	var enrollment models.TwoFactorEnrollment
+	json.NewDecoder(response.Body).Decode(&enrollment)
+
+	response = passwordRequest(server, "member@test.com", "/api/auth/2fa/confirm", models.TwoFactorCodeRequest{Code: code(t, enrollment.Secret, 0)})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	status := models.TwoFactorStatus{}
+	request := httptest.NewRequest(http.MethodGet, "/api/auth/2fa", nil)
+	request.SetBasicAuth("member@test.com", testPassword)
+	_, user, _ := server.AuthStrategy.AuthenticateRequest(request)
+	response = httptest.NewRecorder()
+	server.TwoFactorStatus(response, auth.RequestWithUser(user, request))
+	json.NewDecoder(response.Body).Decode(&status)
+	if !status.Enabled || status.Required || status.RecoveryCodesLeft != recoveryCodeCount {
+		t.Errorf("unexpected status %+v", status)
+	}
+
+	// disabling has to get past the login first, so it's done with a session token in practice
+	response = httptest.NewRecorder()
+	request = httptest.NewRequest(http.MethodPost, "/api/auth/2fa/disable", bytes.NewReader([]byte(`{"code":"`+code(t, enrollment.Secret, 1)+`"}`)))
+	server.DisableTwoFactor(response, auth.RequestWithUser(user, request))
+	assertStatus(t, response.Code, http.StatusOK)
+
+	response = passwordRequest(server, "member@test.com", loginPath, nil)
+	assertStatus(t, response.Code, http.StatusOK)
+}
+
+func TestTwoFactorCantBeDisabledWhenRequired(t *testing.T) {
+	server, db := newTwoFactorTestServer(t)
+	db.SetTwoFactorSecret("admin@test.com", totp.NewSecret())
+	db.EnableTwoFactor("admin@test.com", 0, nil)
+
+	request := httptest.NewRequest(http.MethodPost, "/api/auth/2fa/disable", nil)
+	request.SetBasicAuth("admin@test.com", testPassword)
+	_, user, _ := server.AuthStrategy.AuthenticateRequest(request)
+
+	response := httptest.NewRecorder()
+	server.DisableTwoFactor(response, auth.RequestWithUser(user, request))
+	assertStatus(t, response.Code, http.StatusPreconditionFailed)
+}
+
+func TestRecoveryCodesIgnoreFormatting(t *testing.T) {
+	codes, hashes := newRecoveryCodes()
+	if hashRecoveryCode(codes[0]) != hashes[0] {
+		t.Fatal("expected a code to match its hash")
+	}
+
+	loose := " " + string(bytes.ToUpper([]byte(codes[0][:5]))) + codes[0][6:]
+	if hashRecoveryCode(loose) != hashes[0] {
+		t.Error("expected case, dashes and spaces to be ignored")
+	}
+}
+
+// brokenTwoFactorStore can't read anyone's two-factor enrolment
+type brokenTwoFactorStore struct {
+	*in_memory.In_memory
+}
+
+func (s brokenTwoFactorStore) GetTwoFactor(email string) (models.TwoFactor, error) {
+	return models.TwoFactor{}, errors.New("connection refused")
+}
+
+func TestTwoFactorEnrollmentFailsClosed(t *testing.T) {
+	_, db := newTwoFactorTestServer(t)
+	c := testConfig()
+	c.RequireAdminTwoFactor = true
+	server := newAuthController(brokenTwoFactorStore{db}, &recordingMailer{}, c)
+
+	admin := auth.NewDefaultUser("admin@test.com", "admin@test.com", []string{string(models.RoleAdmin)}, nil)
+	request := httptest.NewRequest(http.MethodPost, "/api/auth/2fa/enroll", nil)
+	response := httptest.NewRecorder()
+	server.EnrollTwoFactor(response, auth.RequestWithUser(admin, request))
+
+	assertStatus(t, response.Code, http.StatusInternalServerError)
+
+	if tf, _ := db.GetTwoFactor("admin@test.com"); len(tf.Secret) > 0 {
+		t.Error("expected nothing to be enrolled when the enrolment can't be read")
+	}
+}
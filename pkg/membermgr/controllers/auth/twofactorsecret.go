@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// sealedSecretPrefix marks an encrypted authenticator app secret
+//
+//	secrets saved before they were encrypted don't have it, they're read as they are
+const sealedSecretPrefix = "v1:"
+
+var errSealedSecret = errors.New("can't decrypt two-factor secret")
+
+// twoFactorCipher is the AES-GCM cipher authenticator app secrets are encrypted with
+//
+//	the key is TwoFactorSecretKey, or derived from the access secret without it
+func (a *AuthController) twoFactorCipher() (cipher.AEAD, error) {
+	var key []byte
+	if len(a.config.TwoFactorSecretKey) > 0 {
+		sum := sha256.Sum256([]byte(a.config.TwoFactorSecretKey))
+		key = sum[:]
+	} else {
+		mac := hmac.New(sha256.New, []byte(a.config.AccessSecret))
+		mac.Write([]byte("memberserver two-factor secret"))
+		key = mac.Sum(nil)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealSecret encrypts a user's authenticator app secret to store it
+//
+//	the email is authenticated along with it, so a secret can't be copied to another account
+func (a *AuthController) sealSecret(email string, secret string) (string, error) {
+	gcm, err := a.twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(secret), []byte(email))
+	return sealedSecretPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret decrypts a secret sealed by sealSecret
+func (a *AuthController) openSecret(email string, stored string) (string, error) {
+	if !strings.HasPrefix(stored, sealedSecretPrefix) {
+		return stored, nil
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, sealedSecretPrefix))
+	if err != nil {
+		return "", errSealedSecret
+	}
+
+	gcm, err := a.twoFactorCipher()
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return "", errSealedSecret
+	}
+
+	secret, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], []byte(email))
+	if err != nil {
+		return "", errSealedSecret
+	}
+	return string(secret), nil
+}
+
+// twoFactor is a user's enrolment with their secret decrypted
+func (a *AuthController) twoFactor(email string) (models.TwoFactor, error) {
+	tf, err := a.store.GetTwoFactor(email)
+	if err != nil || len(tf.Secret) == 0 {
+		return tf, err
+	}
+
+	tf.Secret, err = a.openSecret(email, tf.Secret)
+	return tf, err
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type CertificationServer struct {
+	store  datastore.DataStore
+	logger Logger
+}
+
+// Certification http handlers for certifications
+func (cs *CertificationServer) Certification(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		cs.get(w, req)
+	}
+
+	if req.Method == http.MethodPost {
+		cs.add(w, req)
+	}
+
+	if req.Method == http.MethodPut {
+		cs.update(w, req)
+	}
+
+	if req.Method == http.MethodDelete {
+		cs.delete(w, req)
+	}
+}
+
+func (cs *CertificationServer) get(w http.ResponseWriter, req *http.Request) {
+	certifications, err := cs.store.GetCertifications()
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, certifications)
+}
+
+func (cs *CertificationServer) add(w http.ResponseWriter, req *http.Request) {
+	var c models.Certification
+
+	err := json.NewDecoder(req.Body).Decode(&c)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(strings.TrimSpace(c.Name)) == 0 {
+		preconditionFailed(w, "name is required")
+		return
+	}
+
+	if c.ValidForDays < 0 {
+		preconditionFailed(w, "validForDays can not be negative")
+		return
+	}
+
+	certification, err := cs.store.AddCertification(c)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, certification)
+}
+
+func (cs *CertificationServer) update(w http.ResponseWriter, req *http.Request) {
+	var c models.Certification
+
+	err := json.NewDecoder(req.Body).Decode(&c)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(strings.TrimSpace(c.Name)) == 0 {
+		preconditionFailed(w, "name is required")
+		return
+	}
+
+	if c.ValidForDays < 0 {
+		preconditionFailed(w, "validForDays can not be negative")
+		return
+	}
+
+	certification, err := cs.store.UpdateCertification(c)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, certification)
+}
+
+func (cs *CertificationServer) delete(w http.ResponseWriter, req *http.Request) {
+	var deleteRequest models.CertificationDeleteRequest
+
+	err := json.NewDecoder(req.Body).Decode(&deleteRequest)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	err = cs.store.DeleteCertification(deleteRequest.ID)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// RecordTraining records that the logged in user trained some members
+func (cs *CertificationServer) RecordTraining(w http.ResponseWriter, req *http.Request) {
+	var training models.TrainingRequest
+
+	err := json.NewDecoder(req.Body).Decode(&training)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(training.CertificationID) == 0 {
+		preconditionFailed(w, "certificationID is required")
+		return
+	}
+
+	if len(training.Emails) == 0 {
+		preconditionFailed(w, "at least one email is required")
+		return
+	}
+
+	trainer := auth.User(req).GetUserName()
+
+	var certifications []models.MemberCertification
+	for _, email := range training.Emails {
+		mc, err := cs.store.RecordTraining(training.CertificationID, email, trainer, training.TrainedAt)
+		if err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+		certifications = append(certifications, mc)
+	}
+
+	ok(w, certifications)
+}
+
+// GetMemberCertifications responds with every training a member has received
+func (cs *CertificationServer) GetMemberCertifications(w http.ResponseWriter, req *http.Request) {
+	email := mux.Vars(req)["email"]
+
+	if len(email) == 0 || !govalidator.IsEmail(email) {
+		preconditionFailed(w, "invalid email")
+		return
+	}
+
+	certifications, err := cs.store.GetMemberCertifications(email)
+	if err != nil {
+		notFound(w, "error getting certifications")
+		return
+	}
+
+	ok(w, certifications)
+}
+
+// GetSelfCertifications responds with the logged in member's trainings
+func (cs *CertificationServer) GetSelfCertifications(w http.ResponseWriter, req *http.Request) {
+	certifications, err := cs.store.GetMemberCertifications(auth.User(req).GetUserName())
+	if err != nil {
+		notFound(w, "error getting certifications")
+		return
+	}
+
+	ok(w, certifications)
+}
+
+// ResourceCertifications gets or sets the certifications a resource requires
+func (cs *CertificationServer) ResourceCertifications(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		certifications, err := cs.store.GetResourceCertifications(req.URL.Query().Get("resourceID"))
+		if err != nil {
+			internalServerError(w, err.Error())
+			return
+		}
+
+		ok(w, certifications)
+		return
+	}
+
+	var update models.ResourceCertificationRequest
+
+	err := json.NewDecoder(req.Body).Decode(&update)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(update.ResourceID) == 0 {
+		preconditionFailed(w, "resourceID is required")
+		return
+	}
+
+	err = cs.store.SetResourceCertifications(update.ResourceID, update.CertificationIDs)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
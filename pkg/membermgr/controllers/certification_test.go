@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func TestRecordTraining(t *testing.T) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"trainee@test.com": {ID: "1", Name: "trainee", Email: "trainee@test.com"},
+		},
+	}
+	certification, _ := store.AddCertification(models.Certification{Name: "Laser Cutter", ValidForDays: 365})
+	server := &CertificationServer{store, logrus.New()}
+
+	tests := []struct {
+		TestName           string
+		training           models.TrainingRequest
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "should record a training",
+			training:           models.TrainingRequest{CertificationID: certification.ID, Emails: []string{"trainee@test.com"}},
+			expectedHTTPStatus: http.StatusOK,
+		},
+		{
+			TestName:           "should require a certification",
+			training:           models.TrainingRequest{Emails: []string{"trainee@test.com"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should fail for an unknown member",
+			training:           models.TrainingRequest{CertificationID: certification.ID, Emails: []string{"nobody@test.com"}},
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			reqBody, _ := json.Marshal(tt.training)
+			request, _ := http.NewRequest(http.MethodPost, "/api/certification/training", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			trainer := auth.NewDefaultUser("trainer@test.com", "trainer@test.com", nil, nil)
+			server.RecordTraining(response, auth.RequestWithUser(trainer, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+
+	trainings, _ := store.GetMemberCertifications("trainee@test.com")
+	if len(trainings) != 1 {
+		t.Fatalf("expected 1 training to be recorded, got %d", len(trainings))
+	}
+
+	if trainings[0].TrainerEmail != "trainer@test.com" || trainings[0].ExpiresAt == nil {
+		t.Errorf("training was not recorded correctly: %+v", trainings[0])
+	}
+}
+
+func TestGrantRequiresCertification(t *testing.T) {
+	today := time.Now()
+	lastYear := today.AddDate(-1, 0, 0)
+	// the grant starts later so it isn't pushed to a device
+	inAnHour := today.Add(time.Hour)
+
+	tests := []struct {
+		TestName           string
+		trainedAt          *time.Time
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "should refuse a member who was never trained",
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			TestName:           "should refuse a member whose certification has expired",
+			trainedAt:          &lastYear,
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			TestName:           "should grant a certified member",
+			trainedAt:          &today,
+			expectedHTTPStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{
+				Members: map[string]models.Member{
+					"trainee@test.com": {ID: "1", Name: "trainee", Email: "trainee@test.com"},
+				},
+			}
+			certification, _ := store.AddCertification(models.Certification{Name: "Laser Cutter", ValidForDays: 180})
+			store.SetResourceCertifications("laser", []string{certification.ID})
+			if tt.trainedAt != nil {
+				store.RecordTraining(certification.ID, "trainee@test.com", "trainer@test.com", *tt.trainedAt)
+			}
+			server := resourceAPI{db: store, logger: logrus.New()}
+
+			reqBody, _ := json.Marshal(models.MembersResourceRelation{ID: "laser", Emails: []string{"trainee@test.com"}, ValidFrom: &inAnHour})
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/member/bulk", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			admin := auth.NewDefaultUser("admin@test.com", "admin@test.com", nil, nil)
+			server.AddMultipleMembersToResource(response, auth.RequestWithUser(admin, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	config "github.com/HackRVA/memberserver/configs"
 	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	v2 "github.com/HackRVA/memberserver/pkg/membermgr/controllers/v2"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/integrations"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services"
@@ -15,15 +16,25 @@ import (
 
 // API endpoints
 type API struct {
-	db             datastore.DataStore
-	ResourceServer resourceAPI
-	VersionServer  *VersionServer
-	MemberServer   *MemberServer
-	ReportsServer  *ReportsServer
-	UserServer     *UserServer
-	AuthStrategy   union.Union
-	JWTKeeper      jwt.SecretsKeeper
-	logger         Logger
+	db                  datastore.DataStore
+	ResourceServer      resourceAPI
+	CertificationServer *CertificationServer
+	AuditServer         *AuditServer
+	RoleServer          *RoleServer
+	APIKeyServer        *APIKeyServer
+	SessionServer       *SessionServer
+	LockoutServer       *LockoutServer
+	AccessEventServer   *AccessEventServer
+	OccupancyServer     *OccupancyServer
+	VersionServer       *VersionServer
+	MemberServer        *MemberServer
+	ReportsServer       *ReportsServer
+	UserServer          *UserServer
+	V2MemberServer      *v2.MemberServer
+	V2ResourceServer    *v2.ResourceServer
+	AuthStrategy        union.Union
+	JWTKeeper           jwt.SecretsKeeper
+	logger              Logger
 }
 
 type resourceAPI struct {
@@ -34,10 +45,11 @@ type resourceAPI struct {
 }
 
 // Setup - setup us up the routes
-func Setup(store datastore.DataStore, auth *auth.AuthController, rm services.Resource, pp integrations.PaymentProvider, log services.Logger) API {
+func Setup(store datastore.DataStore, auth *auth.AuthController, rm services.Resource, pp integrations.PaymentProvider, occupancy services.Occupancy, log services.Logger) API {
 	c := config.Get()
 
 	userServer := NewUserServer(store, c)
+	members := member.New(store, rm, pp, log)
 
 	return API{
 		db: store,
@@ -47,12 +59,22 @@ func Setup(store datastore.DataStore, auth *auth.AuthController, rm services.Res
 			resourcemanager: rm,
 			logger:          log,
 		},
-		VersionServer: &VersionServer{NewInMemoryVersionStore()},
-		ReportsServer: &ReportsServer{report.Report{Store: store}, log},
-		MemberServer:  &MemberServer{rm, member.New(store, rm, pp, log), auth.AuthStrategy},
-		UserServer:    &userServer,
-		AuthStrategy:  auth.AuthStrategy,
-		JWTKeeper:     auth.JWTSecretsKeeper,
-		logger:        log,
+		CertificationServer: &CertificationServer{store, log},
+		AuditServer:         &AuditServer{store, log},
+		RoleServer:          &RoleServer{store, log},
+		APIKeyServer:        &APIKeyServer{store, log},
+		SessionServer:       &SessionServer{store, log},
+		LockoutServer:       &LockoutServer{auth, store, log},
+		AccessEventServer:   &AccessEventServer{store, log, rm.AccessEvents()},
+		OccupancyServer:     &OccupancyServer{occupancy, store, log},
+		VersionServer:       &VersionServer{NewInMemoryVersionStore()},
+		ReportsServer:       &ReportsServer{report.Report{Store: store}, log},
+		MemberServer:        &MemberServer{rm, members, auth.AuthStrategy},
+		UserServer:          &userServer,
+		V2MemberServer:      v2.NewMemberServer(members),
+		V2ResourceServer:    v2.NewResourceServer(rm, store, log),
+		AuthStrategy:        auth.AuthStrategy,
+		JWTKeeper:           auth.JWTSecretsKeeper,
+		logger:              log,
 	}
 }
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	auditActionDeviceApprove = "resource.device.approve"
+	auditActionDeviceDismiss = "resource.device.dismiss"
+)
+
+// GetPendingDevices responds with the devices that have announced themselves and are waiting to be approved
+func (rs resourceAPI) GetPendingDevices(w http.ResponseWriter, req *http.Request) {
+	devices, err := rs.db.GetPendingDevices()
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error getting pending devices")
+		return
+	}
+
+	ok(w, devices)
+}
+
+// ApproveDevice names a pending device and registers it as a resource
+func (rs resourceAPI) ApproveDevice(w http.ResponseWriter, req *http.Request) {
+	mac, err := models.NormalizeMAC(mux.Vars(req)["mac"])
+	if err != nil {
+		badRequest(w, "invalid mac address")
+		return
+	}
+
+	var approval models.ApproveDeviceRequest
+	if err := json.NewDecoder(req.Body).Decode(&approval); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(strings.TrimSpace(approval.Name)) == 0 {
+		preconditionFailed(w, "name is required")
+		return
+	}
+
+	d, err := rs.db.GetDevice(mac)
+	if err != nil {
+		notFound(w, "device not found")
+		return
+	}
+
+	if len(d.ResourceID) > 0 {
+		preconditionFailed(w, "device has already been approved")
+		return
+	}
+
+	if _, err := rs.db.GetResourceByName(strings.TrimSpace(approval.Name)); err == nil {
+		preconditionFailed(w, "a resource with that name already exists")
+		return
+	}
+
+	r, err := rs.resourcemanager.ApproveDevice(mac, approval)
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error approving device")
+		return
+	}
+
+	logAudit(rs.db, rs.logger, requestActor(req), auditActionDeviceApprove, r.Name, "device "+mac)
+
+	ok(w, r)
+}
+
+// DismissDevice forgets a pending device
+//
+//	if the device announces itself again it will show up as pending again
+func (rs resourceAPI) DismissDevice(w http.ResponseWriter, req *http.Request) {
+	mac, err := models.NormalizeMAC(mux.Vars(req)["mac"])
+	if err != nil {
+		badRequest(w, "invalid mac address")
+		return
+	}
+
+	if err := rs.db.DeleteDevice(mac); err != nil {
+		notFound(w, "device not found")
+		return
+	}
+
+	logAudit(rs.db, rs.logger, requestActor(req), auditActionDeviceDismiss, mac, "")
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+func TestApproveDevice(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.AnnounceDevice(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:01", Model: "esp-rfid", Address: "10.0.0.5"})
+	store.AnnounceDevice(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:02", Model: "esp-rfid", Address: "10.0.0.6"})
+	store.SetDeviceResource("aa:bb:cc:dd:ee:02", "approved already")
+	in_memory.Resources["taken"] = models.Resource{ID: "taken", Name: "taken"}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "taken")
+		delete(in_memory.Resources, "laser cutter")
+	})
+
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	server := resourceAPI{db: store, resourcemanager: rm, logger: logrus.New()}
+
+	tests := []struct {
+		TestName           string
+		mac                string
+		request            models.ApproveDeviceRequest
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "should reject a bad mac address",
+			mac:                "not a mac",
+			request:            models.ApproveDeviceRequest{Name: "laser cutter"},
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			TestName:           "should require a name",
+			mac:                "aa:bb:cc:dd:ee:01",
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should fail for an unknown device",
+			mac:                "aa:bb:cc:dd:ee:ff",
+			request:            models.ApproveDeviceRequest{Name: "laser cutter"},
+			expectedHTTPStatus: http.StatusNotFound,
+		},
+		{
+			TestName:           "should not approve a device twice",
+			mac:                "aa:bb:cc:dd:ee:02",
+			request:            models.ApproveDeviceRequest{Name: "laser cutter"},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should not reuse a resource name",
+			mac:                "aa:bb:cc:dd:ee:01",
+			request:            models.ApproveDeviceRequest{Name: "taken"},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should approve a pending device",
+			mac:                "AA-BB-CC-DD-EE-01",
+			request:            models.ApproveDeviceRequest{Name: "laser cutter"},
+			expectedHTTPStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			reqBody, _ := json.Marshal(tt.request)
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/devices/"+tt.mac+"/approve", bytes.NewReader(reqBody))
+			request = mux.SetURLVars(request, map[string]string{"mac": tt.mac})
+			response := httptest.NewRecorder()
+
+			server.ApproveDevice(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+
+	pending, _ := store.GetPendingDevices()
+	if len(pending) != 0 {
+		t.Errorf("expected no pending devices, got %+v", pending)
+	}
+
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Target != "laser cutter" {
+		t.Errorf("expected the approval to be audited, got %+v", store.AuditLog)
+	}
+}
+
+func TestDismissDevice(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.AnnounceDevice(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:01"})
+
+	server := resourceAPI{db: store, logger: logrus.New()}
+
+	for _, expected := range []int{http.StatusOK, http.StatusNotFound} {
+		request, _ := http.NewRequest(http.MethodDelete, "/api/resource/devices/aa:bb:cc:dd:ee:01", nil)
+		request = mux.SetURLVars(request, map[string]string{"mac": "aa:bb:cc:dd:ee:01"})
+		response := httptest.NewRecorder()
+
+		server.DismissDevice(response, request)
+
+		assertStatus(t, response.Code, expected)
+	}
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/slack"
+)
+
+// slackResponse is the body slack expects in reply to a slash command
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// DoorMode http handlers for lockdown and hold-open
+func (rs resourceAPI) DoorMode(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		rs.getDoorModes(w, req)
+	}
+
+	if req.Method == http.MethodPut {
+		rs.putDoorMode(w, req)
+	}
+}
+
+func (rs resourceAPI) getDoorModes(w http.ResponseWriter, req *http.Request) {
+	modes, err := rs.db.GetDoorModes()
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, modes)
+}
+
+func (rs resourceAPI) putDoorMode(w http.ResponseWriter, req *http.Request) {
+	var mode models.DoorMode
+
+	err := json.NewDecoder(req.Body).Decode(&mode)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if !mode.Mode.Valid() {
+		preconditionFailed(w, "mode must be one of normal, lockdown or holdopen")
+		return
+	}
+
+	if err := rs.setDoorMode(mode, requestActor(req)); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// setDoorMode applies a mode and records who set it
+func (rs resourceAPI) setDoorMode(mode models.DoorMode, actor string) error {
+	target := "all resources"
+	if len(mode.ResourceID) > 0 {
+		r, err := rs.db.GetResourceByID(mode.ResourceID)
+		if err != nil {
+			return err
+		}
+		target = r.Name
+	}
+
+	mode.SetBy = actor
+	if err := rs.resourcemanager.SetDoorMode(mode); err != nil {
+		return err
+	}
+
+	rs.logger.Infof("%s set %s to %s", actor, target, mode.Mode)
+	logAudit(rs.db, rs.logger, actor, models.AuditActionDoorMode, target, strings.TrimSpace(fmt.Sprintf("%s %s", mode.Mode, mode.Reason)))
+
+	return nil
+}
+
+// SlackDoorMode handles the door slash command from slack
+//
+//	/door status
+//	/door lockdown|holdopen|normal [resource name]
+func (rs resourceAPI) SlackDoorMode(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if err := slack.VerifyRequest(rs.config.SlackSigningSecret, req.Header, body, time.Now()); err != nil {
+		rs.logger.Errorf("rejected slack command: %s", err)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if !contains(strings.Split(rs.config.SlackDoorModeUsers, ","), form.Get("user_id")) {
+		ok(w, slackResponse{ResponseType: "ephemeral", Text: "you aren't allowed to change door modes"})
+		return
+	}
+
+	args := strings.Fields(form.Get("text"))
+	if len(args) == 0 || args[0] == "status" {
+		ok(w, slackResponse{ResponseType: "ephemeral", Text: rs.describeDoorModes()})
+		return
+	}
+
+	mode := models.DoorMode{
+		Mode:   models.DoorModeName(args[0]),
+		Reason: "set from slack",
+	}
+
+	if !mode.Mode.Valid() {
+		ok(w, slackResponse{ResponseType: "ephemeral", Text: "usage: /door status | lockdown|holdopen|normal [resource]"})
+		return
+	}
+
+	target := "all resources"
+	if len(args) > 1 {
+		target = strings.Join(args[1:], " ")
+		r, err := rs.db.GetResourceByName(target)
+		if err != nil {
+			ok(w, slackResponse{ResponseType: "ephemeral", Text: fmt.Sprintf("unknown resource: %s", target)})
+			return
+		}
+		mode.ResourceID = r.ID
+	}
+
+	if err := rs.setDoorMode(mode, "slack:"+form.Get("user_name")); err != nil {
+		ok(w, slackResponse{ResponseType: "ephemeral", Text: err.Error()})
+		return
+	}
+
+	ok(w, slackResponse{ResponseType: "in_channel", Text: fmt.Sprintf("%s set %s to %s", form.Get("user_name"), target, mode.Mode)})
+}
+
+func (rs resourceAPI) describeDoorModes() string {
+	modes, err := rs.db.GetDoorModes()
+	if err != nil {
+		return err.Error()
+	}
+
+	if len(modes) == 0 {
+		return "all doors are in normal mode"
+	}
+
+	var lines []string
+	for _, m := range modes {
+		target := "all resources"
+		if r, err := rs.db.GetResourceByID(m.ResourceID); err == nil && len(m.ResourceID) > 0 {
+			target = r.Name
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (set by %s)", target, m.Mode, m.SetBy))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if strings.TrimSpace(s) == item && len(item) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+	"github.com/HackRVA/memberserver/pkg/slack"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func newSlackCommandRequest(secret string, userID string, text string) *http.Request {
+	body := url.Values{
+		"command":   {"/door"},
+		"user_id":   {userID},
+		"user_name": {"someone"},
+		"text":      {text},
+	}.Encode()
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, _ := http.NewRequest(http.MethodPost, "/api/slack/door", strings.NewReader(body))
+	request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	request.Header.Set("X-Slack-Signature", slack.Sign(secret, timestamp, []byte(body)))
+	return request
+}
+
+func TestSlackDoorMode(t *testing.T) {
+	tests := []struct {
+		TestName           string
+		request            *http.Request
+		expectedHTTPStatus int
+		expectedMode       models.DoorModeName
+	}{
+		{
+			TestName:           "should lock down every door",
+			request:            newSlackCommandRequest("secret", "U123", "lockdown"),
+			expectedHTTPStatus: http.StatusOK,
+			expectedMode:       models.DoorModeLockdown,
+		},
+		{
+			TestName:           "should ignore users that aren't allowed",
+			request:            newSlackCommandRequest("secret", "U999", "lockdown"),
+			expectedHTTPStatus: http.StatusOK,
+			expectedMode:       models.DoorModeNormal,
+		},
+		{
+			TestName:           "should reject unsigned requests",
+			request:            newSlackCommandRequest("wrong", "U123", "lockdown"),
+			expectedHTTPStatus: http.StatusUnauthorized,
+			expectedMode:       models.DoorModeNormal,
+		},
+		{
+			TestName:           "should ignore unknown modes",
+			request:            newSlackCommandRequest("secret", "U123", "party"),
+			expectedHTTPStatus: http.StatusOK,
+			expectedMode:       models.DoorModeNormal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{}
+			server := resourceAPI{
+				db:              store,
+				config:          config.Config{SlackSigningSecret: "secret", SlackDoorModeUsers: "U123, U456"},
+				resourcemanager: resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New()),
+				logger:          logrus.New(),
+			}
+			response := httptest.NewRecorder()
+
+			server.SlackDoorMode(response, tt.request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			modes, _ := store.GetDoorModes()
+			if got := models.EffectiveDoorMode(modes, ""); got != tt.expectedMode {
+				t.Errorf("got mode %s want %s", got, tt.expectedMode)
+			}
+
+			if tt.expectedMode != models.DoorModeNormal && len(store.AuditLog) != 1 {
+				t.Errorf("expected the change to be audited")
+			}
+		})
+	}
+}
+
+func TestOpenLockedDownResource(t *testing.T) {
+	in_memory.Resources["frontdoor"] = models.Resource{ID: "frontdoor", Name: "frontdoor"}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "frontdoor")
+	})
+
+	tests := []struct {
+		TestName           string
+		user               auth.Info
+		mode               models.DoorModeName
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "hosts can open a door in normal mode",
+			user:               auth.NewDefaultUser("host@test.com", "host@test.com", []string{"member", "area-host"}, nil),
+			mode:               models.DoorModeNormal,
+			expectedHTTPStatus: http.StatusOK,
+		},
+		{
+			TestName:           "hosts can't open a locked down door",
+			user:               auth.NewDefaultUser("host@test.com", "host@test.com", []string{"member", "area-host"}, nil),
+			mode:               models.DoorModeLockdown,
+			expectedHTTPStatus: http.StatusConflict,
+		},
+		{
+			TestName:           "scoped api keys can't open a locked down door",
+			user:               auth.NewDefaultUser("kiosk-key", "kiosk-key", rbac.ScopeGroups([]string{string(rbac.OpenResources)}, []string{"frontdoor"}), nil),
+			mode:               models.DoorModeLockdown,
+			expectedHTTPStatus: http.StatusConflict,
+		},
+		{
+			TestName:           "admins can open a locked down door",
+			user:               auth.NewDefaultUser("admin@test.com", "admin@test.com", []string{"admin"}, nil),
+			mode:               models.DoorModeLockdown,
+			expectedHTTPStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{}
+			store.SetDoorMode(models.DoorMode{ResourceID: "frontdoor", Mode: tt.mode})
+			server := resourceAPI{
+				db:              store,
+				resourcemanager: resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New()),
+				logger:          logrus.New(),
+			}
+
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/frontdoor/open", nil)
+			request = mux.SetURLVars(request, map[string]string{"id": "frontdoor"})
+			response := httptest.NewRecorder()
+
+			server.Open(response, auth.RequestWithUser(tt.user, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+}
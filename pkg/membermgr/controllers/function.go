@@ -18,6 +18,10 @@ func preconditionFailed(writer http.ResponseWriter, validateMessage string) {
 	http.Error(writer, errors.New(validateMessage).Error(), http.StatusPreconditionFailed)
 }
 
+func conflict(writer http.ResponseWriter, errorMessage string) {
+	http.Error(writer, errors.New(errorMessage).Error(), http.StatusConflict)
+}
+
 func notFound(writer http.ResponseWriter, errorMessage string) {
 	http.Error(writer, errors.New(errorMessage).Error(), http.StatusNotFound)
 }
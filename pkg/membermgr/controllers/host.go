@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+// ResourceHosts gets or replaces the hosts of a resource
+func (rs resourceAPI) ResourceHosts(w http.ResponseWriter, req *http.Request) {
+	r, err := rs.db.GetResourceByID(mux.Vars(req)["id"])
+	if err != nil {
+		notFound(w, "resource not found")
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateHosts(w, req, r.ID, func(emails []string) error {
+			return rs.db.SetResourceHosts(r.ID, emails)
+		}, models.AuditActionResourceHosts, r.Name)
+		return
+	}
+
+	hosts, err := rs.db.GetResourceHosts(r.ID)
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error getting resource hosts")
+		return
+	}
+
+	ok(w, models.ResourceHosts{ID: r.ID, Emails: hosts})
+}
+
+// ResourceGroupHosts gets or replaces the hosts of a resource group
+//
+//	hosting a group means hosting every resource in it
+func (rs resourceAPI) ResourceGroupHosts(w http.ResponseWriter, req *http.Request) {
+	g, err := rs.db.GetResourceGroup(mux.Vars(req)["id"])
+	if err != nil {
+		notFound(w, "resource group not found")
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateHosts(w, req, g.ID, func(emails []string) error {
+			return rs.db.SetResourceGroupHosts(g.ID, emails)
+		}, models.AuditActionResourceGroupHosts, g.Name)
+		return
+	}
+
+	hosts, err := rs.db.GetResourceGroupHosts(g.ID)
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error getting resource group hosts")
+		return
+	}
+
+	ok(w, models.ResourceHosts{ID: g.ID, Emails: hosts})
+}
+
+// GetSelfHostedResources responds with the resources the current user hosts
+func (rs resourceAPI) GetSelfHostedResources(w http.ResponseWriter, req *http.Request) {
+	resources, err := rs.db.GetHostedResources(requestActor(req))
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error getting hosted resources")
+		return
+	}
+
+	ok(w, resources)
+}
+
+func (rs resourceAPI) updateHosts(w http.ResponseWriter, req *http.Request, id string, set func(emails []string) error, action string, target string) {
+	var update models.UpdateResourceHostsRequest
+	if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	emails := []string{}
+	for _, email := range update.Emails {
+		email = strings.ToLower(strings.TrimSpace(email))
+		if len(email) == 0 || contains(emails, email) {
+			continue
+		}
+		if _, err := rs.db.GetMemberByEmail(email); err != nil {
+			preconditionFailed(w, fmt.Sprintf("%s is not a member", email))
+			return
+		}
+		emails = append(emails, email)
+	}
+
+	if err := set(emails); err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error setting hosts")
+		return
+	}
+
+	logAudit(rs.db, rs.logger, requestActor(req), action, target, strings.Join(emails, ","))
+
+	ok(w, models.ResourceHosts{ID: id, Emails: emails})
+}
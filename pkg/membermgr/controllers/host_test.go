@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func TestUpdateResourceHosts(t *testing.T) {
+	in_memory.Resources["lathe"] = models.Resource{ID: "lathe", Name: "lathe"}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "lathe")
+	})
+
+	tests := []struct {
+		TestName           string
+		resourceID         string
+		emails             []string
+		expectedHTTPStatus int
+		expectedHosts      []string
+		expectedAuditLogs  int
+	}{
+		{
+			TestName:           "should set the hosts of a resource",
+			resourceID:         "lathe",
+			emails:             []string{" Host@test.com", "host@test.com"},
+			expectedHTTPStatus: http.StatusOK,
+			expectedHosts:      []string{"host@test.com"},
+			expectedAuditLogs:  1,
+		},
+		{
+			TestName:           "should only let members host",
+			resourceID:         "lathe",
+			emails:             []string{"nobody@test.com"},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+			expectedHosts:      []string{},
+		},
+		{
+			TestName:           "should fail for an unknown resource",
+			resourceID:         "unknown",
+			emails:             []string{"host@test.com"},
+			expectedHTTPStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{
+				Members: map[string]models.Member{
+					"host@test.com": {Name: "host", Email: "host@test.com"},
+				},
+			}
+			server := resourceAPI{db: store, logger: logrus.New()}
+
+			reqBody, _ := json.Marshal(models.UpdateResourceHostsRequest{Emails: tt.emails})
+			request, _ := http.NewRequest(http.MethodPut, "/api/resource/"+tt.resourceID+"/hosts", bytes.NewReader(reqBody))
+			request = mux.SetURLVars(request, map[string]string{"id": tt.resourceID})
+			response := httptest.NewRecorder()
+
+			server.ResourceHosts(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			if tt.expectedHosts != nil {
+				hosts, _ := store.GetResourceHosts(tt.resourceID)
+				if !reflect.DeepEqual(hosts, tt.expectedHosts) {
+					t.Errorf("expected hosts %v, got %v", tt.expectedHosts, hosts)
+				}
+			}
+
+			entries, _ := store.GetAuditEvents(defaultAuditLimit, 0)
+			if len(entries) != tt.expectedAuditLogs {
+				t.Errorf("expected %d audit entries, got %d", tt.expectedAuditLogs, len(entries))
+			}
+		})
+	}
+}
+
+func TestGetSelfHostedResources(t *testing.T) {
+	in_memory.Resources["lathe"] = models.Resource{ID: "lathe", Name: "lathe"}
+	in_memory.Resources["mill"] = models.Resource{ID: "mill", Name: "mill"}
+	in_memory.Resources["laser"] = models.Resource{ID: "laser", Name: "laser"}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "lathe")
+		delete(in_memory.Resources, "mill")
+		delete(in_memory.Resources, "laser")
+	})
+
+	store := &in_memory.In_memory{
+		ResourceGroups: []models.ResourceGroup{{ID: "1", Name: "metal shop", ResourceIDs: []string{"mill"}}},
+	}
+	store.SetResourceHosts("lathe", []string{"host@test.com"})
+	store.SetResourceGroupHosts("1", []string{"host@test.com"})
+	server := resourceAPI{db: store, logger: logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/member/self/hosted", nil)
+	response := httptest.NewRecorder()
+
+	host := auth.NewDefaultUser("host@test.com", "host@test.com", []string{"member", "area-host"}, nil)
+	server.GetSelfHostedResources(response, auth.RequestWithUser(host, request))
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var resources []models.Resource
+	json.NewDecoder(response.Body).Decode(&resources)
+
+	names := []string{}
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+
+	if !reflect.DeepEqual(names, []string{"lathe", "mill"}) {
+		t.Errorf("expected the hosted resource and the group's resource, got %v", names)
+	}
+}
+
+func TestScopedRouteRejectsAnotherResource(t *testing.T) {
+	store := &in_memory.In_memory{}
+	server := resourceAPI{db: store, logger: logrus.New()}
+
+	reqBody, _ := json.Marshal(models.MembersResourceRelation{ID: "frontdoor", Emails: []string{"guest@test.com"}})
+	request, _ := http.NewRequest(http.MethodPost, "/api/resource/lathe/member/bulk", bytes.NewReader(reqBody))
+	request = mux.SetURLVars(request, map[string]string{"id": "lathe"})
+	response := httptest.NewRecorder()
+
+	server.AddMultipleMembersToResource(response, request)
+
+	assertStatus(t, response.Code, http.StatusPreconditionFailed)
+
+	entries, _ := store.GetAuditEvents(defaultAuditLimit, 0)
+	if len(entries) != 0 {
+		t.Errorf("expected nothing to be granted, got %+v", entries)
+	}
+}
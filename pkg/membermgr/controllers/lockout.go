@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+// LoginLocks are the accounts locked after too many failed logins
+type LoginLocks interface {
+	Lockout(email string) (models.Lockout, error)
+	Unlock(email string) error
+}
+
+type LockoutServer struct {
+	locks  LoginLocks
+	store  datastore.DataStore
+	logger Logger
+}
+
+// GetLockout responds with whether a member's account is locked after failed logins
+func (ls *LockoutServer) GetLockout(w http.ResponseWriter, req *http.Request) {
+	lockout, err := ls.locks.Lockout(strings.ToLower(mux.Vars(req)["email"]))
+	if err != nil {
+		ls.logger.Error(err)
+		internalServerError(w, "error getting lockout")
+		return
+	}
+
+	ok(w, lockout)
+}
+
+// Unlock lets a locked member sign in again before the lockout is over
+func (ls *LockoutServer) Unlock(w http.ResponseWriter, req *http.Request) {
+	email := strings.ToLower(mux.Vars(req)["email"])
+
+	if err := ls.locks.Unlock(email); err != nil {
+		ls.logger.Error(err)
+		internalServerError(w, "error unlocking account")
+		return
+	}
+
+	logAudit(ls.store, ls.logger, requestActor(req), models.AuditActionUnlock, email, "")
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	guardian "github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+// stubLocks locks accounts by email
+type stubLocks map[string]bool
+
+func (l stubLocks) Lockout(email string) (models.Lockout, error) {
+	return models.Lockout{Email: email, Locked: l[email]}, nil
+}
+
+func (l stubLocks) Unlock(email string) error {
+	delete(l, email)
+	return nil
+}
+
+func lockoutRequest(handler http.HandlerFunc, method string, email string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, "/api/member/email/"+email+"/lockout", nil)
+	request = mux.SetURLVars(request, map[string]string{"email": email})
+	admin := guardian.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+
+	response := httptest.NewRecorder()
+	handler(response, guardian.RequestWithUser(admin, request))
+	return response
+}
+
+func TestUnlock(t *testing.T) {
+	store := &in_memory.In_memory{}
+	locks := stubLocks{"member@test.com": true}
+	server := &LockoutServer{locks, store, logrus.New()}
+
+	response := lockoutRequest(server.GetLockout, http.MethodGet, "Member@test.com")
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var lockout models.Lockout
+	json.NewDecoder(response.Body).Decode(&lockout)
+	if !lockout.Locked {
+		t.Errorf("expected the account to be locked, got %+v", lockout)
+	}
+
+	response = lockoutRequest(server.Unlock, http.MethodDelete, "Member@test.com")
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if locks["member@test.com"] {
+		t.Error("expected the account to be unlocked")
+	}
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Action != models.AuditActionUnlock || store.AuditLog[0].Target != "member@test.com" {
+		t.Errorf("expected unlocking to be audited, got %+v", store.AuditLog)
+	}
+}
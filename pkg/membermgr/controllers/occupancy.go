@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type OccupancyServer struct {
+	occupancy services.Occupancy
+	store     datastore.DataStore
+	logger    Logger
+}
+
+// GetOccupancy responds with how many members are in the space
+func (oc *OccupancyServer) GetOccupancy(w http.ResponseWriter, req *http.Request) {
+	ok(w, oc.occupancy.Occupancy())
+}
+
+// GetWhosHere responds with the members in the space that share their presence
+func (oc *OccupancyServer) GetWhosHere(w http.ResponseWriter, req *http.Request) {
+	members, err := oc.occupancy.WhosHere()
+	if err != nil {
+		oc.logger.Error(err)
+		internalServerError(w, "error getting who's here")
+		return
+	}
+
+	ok(w, members)
+}
+
+// PresenceSharing gets or sets whether the logged in member shows up in the who's here list
+func (oc *OccupancyServer) PresenceSharing(w http.ResponseWriter, req *http.Request) {
+	email := auth.User(req).GetUserName()
+
+	if req.Method == http.MethodPut {
+		var update models.PresenceSharingRequest
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			badRequest(w, err.Error())
+			return
+		}
+
+		if err := oc.store.SetPresenceSharing(email, update.Share); err != nil {
+			notFound(w, err.Error())
+			return
+		}
+
+		ok(w, update)
+		return
+	}
+
+	member, err := oc.store.GetMemberByEmail(email)
+	if err != nil {
+		notFound(w, "error getting member by email")
+		return
+	}
+
+	sharing, err := oc.store.GetPresenceSharing()
+	if err != nil {
+		oc.logger.Error(err)
+		internalServerError(w, "error getting presence sharing")
+		return
+	}
+
+	ok(w, models.PresenceSharingRequest{Share: contains(sharing, member.ID)})
+}
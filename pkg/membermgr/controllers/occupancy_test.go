@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+type stubOccupancy struct {
+	present []models.PresentMember
+}
+
+func (s stubOccupancy) Occupancy() models.Occupancy {
+	return models.Occupancy{Count: 3, Keyholders: 1}
+}
+
+func (s stubOccupancy) WhosHere() ([]models.PresentMember, error) {
+	return s.present, nil
+}
+
+func TestPresenceSharing(t *testing.T) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {ID: "1", Name: "member", Email: "member@test.com"},
+		},
+	}
+	server := &OccupancyServer{stubOccupancy{}, store, logrus.New()}
+	member := auth.NewDefaultUser("member@test.com", "member@test.com", nil, nil)
+
+	getSharing := func() bool {
+		request, _ := http.NewRequest(http.MethodGet, "/api/member/self/presence", nil)
+		response := httptest.NewRecorder()
+		server.PresenceSharing(response, auth.RequestWithUser(member, request))
+		assertStatus(t, response.Code, http.StatusOK)
+
+		var sharing models.PresenceSharingRequest
+		json.NewDecoder(response.Body).Decode(&sharing)
+		return sharing.Share
+	}
+
+	if getSharing() {
+		t.Fatal("members shouldn't share their presence until they opt in")
+	}
+
+	reqBody, _ := json.Marshal(models.PresenceSharingRequest{Share: true})
+	request, _ := http.NewRequest(http.MethodPut, "/api/member/self/presence", bytes.NewReader(reqBody))
+	response := httptest.NewRecorder()
+	server.PresenceSharing(response, auth.RequestWithUser(member, request))
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if !getSharing() {
+		t.Error("expected the member to share their presence after opting in")
+	}
+}
+
+func TestGetOccupancy(t *testing.T) {
+	server := &OccupancyServer{stubOccupancy{present: []models.PresentMember{{Name: "member"}}}, &in_memory.In_memory{}, logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/occupancy", nil)
+	response := httptest.NewRecorder()
+	server.GetOccupancy(response, request)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var o models.Occupancy
+	json.NewDecoder(response.Body).Decode(&o)
+	if o.Count != 3 || o.Keyholders != 1 {
+		t.Errorf("unexpected occupancy: %+v", o)
+	}
+
+	request, _ = http.NewRequest(http.MethodGet, "/api/occupancy/members", nil)
+	response = httptest.NewRecorder()
+	server.GetWhosHere(response, request)
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var here []models.PresentMember
+	json.NewDecoder(response.Body).Decode(&here)
+	if len(here) != 1 || here[0].Name != "member" {
+		t.Errorf("unexpected members: %+v", here)
+	}
+}
@@ -0,0 +1,186 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/schedule"
+)
+
+const (
+	// calendarHorizon is how far ahead the calendar feed lists open windows
+	calendarHorizon = 90 * 24 * time.Hour
+	icsTimeLayout   = "20060102T150405Z"
+)
+
+// OpenWindow http handlers for scheduled open windows
+func (rs resourceAPI) OpenWindow(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		rs.getOpenWindows(w, req)
+	}
+
+	if req.Method == http.MethodPost {
+		rs.addOpenWindow(w, req)
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateOpenWindow(w, req)
+	}
+
+	if req.Method == http.MethodDelete {
+		rs.deleteOpenWindow(w, req)
+	}
+}
+
+func (rs resourceAPI) getOpenWindows(w http.ResponseWriter, req *http.Request) {
+	windows, err := rs.db.GetOpenWindows(req.URL.Query().Get("resourceID"))
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, windows)
+}
+
+func (rs resourceAPI) addOpenWindow(w http.ResponseWriter, req *http.Request) {
+	var window models.OpenWindow
+
+	err := json.NewDecoder(req.Body).Decode(&window)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if err := validateOpenWindow(window); err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	window, err = rs.db.AddOpenWindow(window)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, window)
+}
+
+func (rs resourceAPI) updateOpenWindow(w http.ResponseWriter, req *http.Request) {
+	var window models.OpenWindow
+
+	err := json.NewDecoder(req.Body).Decode(&window)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if err := validateOpenWindow(window); err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	window, err = rs.db.UpdateOpenWindow(window)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, window)
+}
+
+func (rs resourceAPI) deleteOpenWindow(w http.ResponseWriter, req *http.Request) {
+	var deleteRequest models.OpenWindowDeleteRequest
+
+	err := json.NewDecoder(req.Body).Decode(&deleteRequest)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	err = rs.db.DeleteOpenWindow(deleteRequest.ID)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+func validateOpenWindow(window models.OpenWindow) error {
+	if len(window.ResourceID) == 0 {
+		return errors.New("resourceID is required")
+	}
+
+	if len(strings.TrimSpace(window.Title)) == 0 {
+		return errors.New("title is required")
+	}
+
+	if !window.End.After(window.Start) {
+		return errors.New("end must be after start")
+	}
+
+	_, err := schedule.ParseRRule(window.RRule)
+	return err
+}
+
+// OpenWindowCalendar is an iCalendar feed of upcoming open windows
+func (rs resourceAPI) OpenWindowCalendar(w http.ResponseWriter, req *http.Request) {
+	windows, err := rs.db.GetOpenWindows(req.URL.Query().Get("resourceID"))
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	resourceNames := map[string]string{}
+	for _, r := range rs.db.GetResources() {
+		resourceNames[r.ID] = r.Name
+	}
+
+	now := time.Now()
+
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:-//HackRVA//memberserver//EN")
+	writeICSLine(&b, "X-WR-CALNAME:Open Doors")
+
+	for _, window := range windows {
+		rule, err := schedule.ParseRRule(window.RRule)
+		if err != nil {
+			rs.logger.Errorf("invalid recurrence rule on open window %s: %s", window.Title, err)
+			continue
+		}
+
+		for _, o := range schedule.Occurrences(window.Start, window.End, rule, now, now.Add(calendarHorizon)) {
+			writeICSLine(&b, "BEGIN:VEVENT")
+			writeICSLine(&b, fmt.Sprintf("UID:%s-%d@memberserver", window.ID, o.Start.Unix()))
+			writeICSLine(&b, "DTSTAMP:"+now.UTC().Format(icsTimeLayout))
+			writeICSLine(&b, "DTSTART:"+o.Start.UTC().Format(icsTimeLayout))
+			writeICSLine(&b, "DTEND:"+o.End.UTC().Format(icsTimeLayout))
+			writeICSLine(&b, "SUMMARY:"+escapeICS(window.Title))
+			writeICSLine(&b, "LOCATION:"+escapeICS(resourceNames[window.ResourceID]))
+			writeICSLine(&b, "END:VEVENT")
+		}
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func writeICSLine(b *strings.Builder, line string) {
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+func escapeICS(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAddOpenWindow(t *testing.T) {
+	start := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		TestName           string
+		window             models.OpenWindow
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "should add a weekly open house",
+			window:             models.OpenWindow{ResourceID: "1", Title: "Open House", Start: start, End: start.Add(2 * time.Hour), RRule: "FREQ=WEEKLY;BYDAY=TU", HoldOpen: true},
+			expectedHTTPStatus: http.StatusOK,
+		},
+		{
+			TestName:           "should require the window to end after it starts",
+			window:             models.OpenWindow{ResourceID: "1", Title: "Open House", Start: start, End: start},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should reject an unsupported recurrence rule",
+			window:             models.OpenWindow{ResourceID: "1", Title: "Open House", Start: start, End: start.Add(time.Hour), RRule: "FREQ=YEARLY"},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should require a resource",
+			window:             models.OpenWindow{Title: "Open House", Start: start, End: start.Add(time.Hour)},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			server := resourceAPI{db: &in_memory.In_memory{}, logger: logrus.New()}
+
+			reqBody, _ := json.Marshal(tt.window)
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/openwindow", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			server.OpenWindow(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+}
+
+func TestOpenWindowCalendar(t *testing.T) {
+	store := &in_memory.In_memory{}
+	start := time.Now().Add(time.Hour)
+	store.AddOpenWindow(models.OpenWindow{ResourceID: "1", Title: "Woodworking, Intro", Start: start, End: start.Add(time.Hour), RRule: "FREQ=DAILY;COUNT=3"})
+	server := resourceAPI{db: store, logger: logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/calendar/openwindows.ics", nil)
+	response := httptest.NewRecorder()
+
+	server.OpenWindowCalendar(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	body := response.Body.String()
+	if got := strings.Count(body, "BEGIN:VEVENT"); got != 3 {
+		t.Errorf("expected 3 events, got %d", got)
+	}
+
+	if !strings.Contains(body, `SUMMARY:Woodworking\, Intro`) {
+		t.Errorf("expected the title to be escaped: %s", body)
+	}
+}
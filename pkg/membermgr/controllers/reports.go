@@ -2,12 +2,19 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/report"
 )
 
+const (
+	defaultUnknownFobDays  = 30
+	defaultUnknownFobLimit = 20
+	maxUnknownFobLimit     = 500
+)
+
 type ReportsServer struct {
 	service report.ReportService
 	Logger  Logger
@@ -74,3 +81,29 @@ func (r *ReportsServer) GetMemberChurn(w http.ResponseWriter, req *http.Request)
 		Churn: churn,
 	})
 }
+
+// GetUnknownFobs responds with the fobs that have the most unknown swipes over the last `days` days
+func (r *ReportsServer) GetUnknownFobs(w http.ResponseWriter, req *http.Request) {
+	days, err := strconv.Atoi(req.URL.Query().Get("days"))
+	if err != nil || days <= 0 {
+		days = defaultUnknownFobDays
+	}
+
+	limit, err := strconv.Atoi(req.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultUnknownFobLimit
+	}
+
+	if limit > maxUnknownFobLimit {
+		limit = maxUnknownFobLimit
+	}
+
+	fobs, err := r.service.GetUnknownFobs(time.Now().AddDate(0, 0, -days), limit)
+	if err != nil {
+		r.Logger.Error(err)
+		internalServerError(w, "error getting unknown fobs")
+		return
+	}
+
+	ok(w, fobs)
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/report"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestGetUnknownFobs(t *testing.T) {
+	store := &in_memory.In_memory{}
+	now := time.Now()
+	swipes := []models.LogMessage{
+		{IsKnown: "false", RFID: "aaa", Door: "frontdoor", EventTime: now.Add(-time.Hour).Unix()},
+		{IsKnown: "false", RFID: "bbb", Door: "frontdoor", EventTime: now.Add(-2 * time.Hour).Unix()},
+		{IsKnown: "false", RFID: "bbb", Door: "backdoor", EventTime: now.Add(-3 * time.Hour).Unix()},
+		{IsKnown: "true", RFID: "ccc", Door: "frontdoor", EventTime: now.Add(-time.Hour).Unix()},
+		{IsKnown: "false", RFID: "ddd", Door: "frontdoor", EventTime: now.AddDate(0, 0, -60).Unix()},
+	}
+	for _, swipe := range swipes {
+		store.LogAccessEvent(swipe)
+	}
+
+	server := &ReportsServer{report.Report{Store: store}, logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/reports/unknown-fobs?days=30", nil)
+	response := httptest.NewRecorder()
+
+	server.GetUnknownFobs(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var fobs []models.UnknownFob
+	json.NewDecoder(response.Body).Decode(&fobs)
+
+	if len(fobs) != 2 {
+		t.Fatalf("expected 2 unknown fobs, got %d", len(fobs))
+	}
+
+	if fobs[0].RFID != "bbb" || fobs[0].Attempts != 2 || len(fobs[0].Doors) != 2 {
+		t.Errorf("expected the most tried fob first, got %+v", fobs[0])
+	}
+}
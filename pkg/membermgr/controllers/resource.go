@@ -2,9 +2,15 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
 )
 
 // Resource http handlers for resources
@@ -36,7 +42,12 @@ func (rs resourceAPI) update(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	r, err := rs.db.UpdateResource(updateResourceReq)
+	if len(updateResourceReq.Driver) > 0 && !models.ValidDriver(updateResourceReq.Driver) {
+		preconditionFailed(w, "unknown driver")
+		return
+	}
+
+	r, err := rs.resourcemanager.UpdateResource(updateResourceReq)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -55,7 +66,7 @@ func (rs resourceAPI) delete(w http.ResponseWriter, req *http.Request) {
 	}
 	rs.logger.Printf("attempting to delete %s", deleteResourceReq.ID)
 
-	err = rs.db.DeleteResource(deleteResourceReq.ID)
+	err = rs.resourcemanager.DeleteResource(deleteResourceReq.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -76,21 +87,60 @@ func (rs resourceAPI) AddMultipleMembersToResource(w http.ResponseWriter, req *h
 		return
 	}
 
-	resource, err := rs.db.AddMultipleMembersToResource(membersResource.Emails, membersResource.ID)
-	for _, email := range membersResource.Emails {
-		member, _ := rs.db.GetMemberByEmail(email)
-		rs.logger.Info("pushing member to resource", member.Email, member.Resources)
-		rs.resourcemanager.PushOne(member)
+	membersResource.ID, err = routeResourceID(req, membersResource.ID)
+	if err != nil {
+		preconditionFailed(w, err.Error())
+		return
 	}
 
+	if membersResource.ValidUntil != nil {
+		if membersResource.ValidUntil.Before(time.Now()) {
+			preconditionFailed(w, "validUntil must be in the future")
+			return
+		}
+
+		if membersResource.ValidFrom != nil && !membersResource.ValidUntil.After(*membersResource.ValidFrom) {
+			preconditionFailed(w, "validUntil must be after validFrom")
+			return
+		}
+	}
+
+	resource, err := rs.db.AddTemporaryMembersToResource(membersResource.Emails, membersResource.ID, membersResource.ValidFrom, membersResource.ValidUntil)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	for _, email := range membersResource.Emails {
+		logAudit(rs.db, rs.logger, requestActor(req), models.AuditActionGrant, email, grantDetail(membersResource))
+	}
+
+	// grants that start later are pushed by the scheduler when they begin
+	if membersResource.ValidFrom != nil && membersResource.ValidFrom.After(time.Now()) {
+		ok(w, resource)
+		return
+	}
+
+	for _, email := range membersResource.Emails {
+		member, _ := rs.db.GetMemberByEmail(email)
+		rs.logger.Info("pushing member to resource", member.Email, member.Resources)
+		rs.resourcemanager.PushOne(member)
+	}
+
 	ok(w, resource)
 }
 
+func grantDetail(grant models.MembersResourceRelation) string {
+	detail := "resource " + grant.ID
+	if grant.ValidFrom != nil {
+		detail += " from " + grant.ValidFrom.Format(time.RFC3339)
+	}
+	if grant.ValidUntil != nil {
+		detail += " until " + grant.ValidUntil.Format(time.RFC3339)
+	}
+	return detail
+}
+
 func (rs resourceAPI) RemoveMember(w http.ResponseWriter, req *http.Request) {
 	var update models.MemberResourceRelationUpdateRequest
 
@@ -100,6 +150,12 @@ func (rs resourceAPI) RemoveMember(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	update.ID, err = routeResourceID(req, update.ID)
+	if err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
 	err = rs.db.RemoveUserFromResource(update.Email, update.ID)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -128,7 +184,12 @@ func (rs resourceAPI) Register(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	r, err := rs.db.RegisterResource(register.Name, register.Address, register.IsDefault)
+	if len(register.Driver) > 0 && !models.ValidDriver(register.Driver) {
+		preconditionFailed(w, "unknown driver")
+		return
+	}
+
+	r, err := rs.resourcemanager.RegisterResource(register.Name, register.Address, register.IsDefault, register.Driver)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -158,9 +219,12 @@ func (rs resourceAPI) Status(w http.ResponseWriter, req *http.Request) {
 		// statusMap[r.Name] = resourcemanager.StatusGood
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	// j, _ := json.Marshal(statusMap)
 	// w.Write(j)
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
 }
 
 func (rs resourceAPI) UpdateResourceACL(w http.ResponseWriter, req *http.Request) {
@@ -171,19 +235,28 @@ func (rs resourceAPI) UpdateResourceACL(w http.ResponseWriter, req *http.Request
 	})
 }
 
+// Open sends an open command to a resource
+//
+//	only callers that can open every resource get through a lockdown,
+//	hosts and scoped api keys get a conflict until it's lifted
 func (rs resourceAPI) Open(w http.ResponseWriter, req *http.Request) {
-	var openResourceRequest models.OpenResourceRequest
-
-	err := json.NewDecoder(req.Body).Decode(&openResourceRequest)
+	resource, err := rs.resourceToOpen(req)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	resource, err := rs.db.GetResourceByName(openResourceRequest.Name)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	if user := auth.User(req); user == nil || !rbac.Allowed(user.GetGroups(), rbac.OpenResources) {
+		modes, err := rs.db.GetDoorModes()
+		if err != nil {
+			internalServerError(w, err.Error())
+			return
+		}
+
+		if models.EffectiveDoorMode(modes, resource.ID) == models.DoorModeLockdown {
+			conflict(w, resource.Name+" is locked down")
+			return
+		}
 	}
 
 	rs.resourcemanager.Open(resource)
@@ -193,6 +266,67 @@ func (rs resourceAPI) Open(w http.ResponseWriter, req *http.Request) {
 	})
 }
 
+// PostAccessEvent records a swipe from a device or bridge that doesn't publish to mqtt
+//
+//	it's handled the same as an access event from mqtt
+func (rs resourceAPI) PostAccessEvent(w http.ResponseWriter, req *http.Request) {
+	var event models.LogMessage
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(event.Door) == 0 || len(event.RFID) == 0 {
+		preconditionFailed(w, "door and uid are required")
+		return
+	}
+
+	if event.EventTime == 0 {
+		event.EventTime = time.Now().Unix()
+	}
+
+	rs.resourcemanager.OnAccessEventHandler(event)
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// resourceToOpen is the resource in the route, or the one named in the body
+func (rs resourceAPI) resourceToOpen(req *http.Request) (models.Resource, error) {
+	if id, ok := mux.Vars(req)["id"]; ok {
+		return rs.db.GetResourceByID(id)
+	}
+
+	var openResourceRequest models.OpenResourceRequest
+	if err := json.NewDecoder(req.Body).Decode(&openResourceRequest); err != nil {
+		return models.Resource{}, err
+	}
+
+	if len(openResourceRequest.ResourceID) > 0 {
+		return rs.db.GetResourceByID(openResourceRequest.ResourceID)
+	}
+
+	return rs.db.GetResourceByName(openResourceRequest.Name)
+}
+
+// routeResourceID is the resource a request is about
+//
+//	on routes scoped to a resource the route's id wins, a different id in the body is rejected
+//	so a host can't use their own resource's route to change another resource
+func routeResourceID(req *http.Request, bodyID string) (string, error) {
+	id, ok := mux.Vars(req)["id"]
+	if !ok {
+		return bodyID, nil
+	}
+
+	if len(bodyID) > 0 && bodyID != id {
+		return "", errors.New("resourceID doesn't match the route")
+	}
+
+	return id, nil
+}
+
 func (rs resourceAPI) DeleteResourceACL(w http.ResponseWriter, req *http.Request) {
 	rs.resourcemanager.DeleteResourceACL()
 
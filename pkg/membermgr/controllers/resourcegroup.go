@@ -0,0 +1,215 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	auditActionGroupGrant          = "resource.group.grant"
+	auditActionGroupRevoke         = "resource.group.revoke"
+	auditActionGroupAddResource    = "resource.group.resource.add"
+	auditActionGroupRemoveResource = "resource.group.resource.remove"
+)
+
+// ResourceGroup http handlers for resource groups
+func (rs resourceAPI) ResourceGroup(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		rs.getGroups(w, req)
+	}
+
+	if req.Method == http.MethodPost {
+		rs.addGroup(w, req)
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateGroup(w, req)
+	}
+
+	if req.Method == http.MethodDelete {
+		rs.deleteGroup(w, req)
+	}
+}
+
+func (rs resourceAPI) getGroups(w http.ResponseWriter, req *http.Request) {
+	groups, err := rs.db.GetResourceGroups()
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, groups)
+}
+
+func (rs resourceAPI) addGroup(w http.ResponseWriter, req *http.Request) {
+	var g models.ResourceGroup
+
+	err := json.NewDecoder(req.Body).Decode(&g)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(strings.TrimSpace(g.Name)) == 0 {
+		preconditionFailed(w, "name is required")
+		return
+	}
+
+	group, err := rs.db.AddResourceGroup(g)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, group)
+}
+
+func (rs resourceAPI) updateGroup(w http.ResponseWriter, req *http.Request) {
+	var g models.ResourceGroup
+
+	err := json.NewDecoder(req.Body).Decode(&g)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(strings.TrimSpace(g.Name)) == 0 {
+		preconditionFailed(w, "name is required")
+		return
+	}
+
+	group, err := rs.db.UpdateResourceGroup(g)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, group)
+}
+
+func (rs resourceAPI) deleteGroup(w http.ResponseWriter, req *http.Request) {
+	var deleteRequest models.ResourceGroupDeleteRequest
+
+	err := json.NewDecoder(req.Body).Decode(&deleteRequest)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	err = rs.db.DeleteResourceGroup(deleteRequest.ID)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// GetResourceGroupMembers returns the members that were granted access to a group
+func (rs resourceAPI) GetResourceGroupMembers(w http.ResponseWriter, req *http.Request) {
+	members, err := rs.db.GetResourceGroupMembers(mux.Vars(req)["id"])
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, members)
+}
+
+// AddResourceGroupMembers grants members access to every resource in a group
+func (rs resourceAPI) AddResourceGroupMembers(w http.ResponseWriter, req *http.Request) {
+	var request models.ResourceGroupMembersRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(request.GroupID) == 0 || len(request.Emails) == 0 {
+		preconditionFailed(w, "groupID and emails are required")
+		return
+	}
+
+	relations, err := rs.resourcemanager.AddGroupMembers(request.GroupID, request.Emails)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	for _, email := range request.Emails {
+		logAudit(rs.db, rs.logger, requestActor(req), auditActionGroupGrant, email, "resource group "+request.GroupID)
+	}
+
+	ok(w, relations)
+}
+
+// RemoveResourceGroupMember revokes a member's access to the resources in a group
+func (rs resourceAPI) RemoveResourceGroupMember(w http.ResponseWriter, req *http.Request) {
+	var request models.ResourceGroupMemberRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(request.GroupID) == 0 || len(request.Email) == 0 {
+		preconditionFailed(w, "groupID and email are required")
+		return
+	}
+
+	err = rs.resourcemanager.RemoveGroupMember(request.GroupID, request.Email)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	logAudit(rs.db, rs.logger, requestActor(req), auditActionGroupRevoke, request.Email, "resource group "+request.GroupID)
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// ResourceGroupResource adds or removes a resource from a group
+func (rs resourceAPI) ResourceGroupResource(w http.ResponseWriter, req *http.Request) {
+	var request models.ResourceGroupResourceRequest
+
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if len(request.GroupID) == 0 || len(request.ResourceID) == 0 {
+		preconditionFailed(w, "groupID and resourceID are required")
+		return
+	}
+
+	action := auditActionGroupAddResource
+	if req.Method == http.MethodDelete {
+		action = auditActionGroupRemoveResource
+		err = rs.resourcemanager.RemoveGroupResource(request.GroupID, request.ResourceID)
+	} else {
+		err = rs.resourcemanager.AddGroupResource(request.GroupID, request.ResourceID)
+	}
+
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	logAudit(rs.db, rs.logger, requestActor(req), action, request.ResourceID, "resource group "+request.GroupID)
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestResourceGroupMembers(t *testing.T) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {ID: "1", Name: "member", Email: "member@test.com"},
+		},
+	}
+	group, _ := store.AddResourceGroup(models.ResourceGroup{Name: "building entry"})
+	store.AddResourceToResourceGroup(group.ID, "front door")
+	store.AddResourceToResourceGroup(group.ID, "back door")
+
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	server := resourceAPI{db: store, resourcemanager: rm, logger: logrus.New()}
+
+	tests := []struct {
+		TestName           string
+		request            models.ResourceGroupMembersRequest
+		expectedHTTPStatus int
+		expectedRelations  int
+	}{
+		{
+			TestName:           "should require a group",
+			request:            models.ResourceGroupMembersRequest{Emails: []string{"member@test.com"}},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should fail for an unknown group",
+			request:            models.ResourceGroupMembersRequest{GroupID: "nope", Emails: []string{"member@test.com"}},
+			expectedHTTPStatus: http.StatusBadRequest,
+		},
+		{
+			TestName:           "should grant access to every resource in the group",
+			request:            models.ResourceGroupMembersRequest{GroupID: group.ID, Emails: []string{"member@test.com"}},
+			expectedHTTPStatus: http.StatusOK,
+			expectedRelations:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			reqBody, _ := json.Marshal(tt.request)
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/group/member/bulk", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			server.AddResourceGroupMembers(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			if tt.expectedHTTPStatus != http.StatusOK {
+				return
+			}
+
+			var relations []models.MemberResourceRelation
+			json.NewDecoder(response.Body).Decode(&relations)
+			if len(relations) != tt.expectedRelations {
+				t.Errorf("expected %d relations, got %d", tt.expectedRelations, len(relations))
+			}
+		})
+	}
+
+	members, _ := store.GetResourceGroupMembers(group.ID)
+	if len(members) != 1 || members[0].Email != "member@test.com" {
+		t.Errorf("expected member@test.com to be in the group, got %v", members)
+	}
+}
+
+func TestAddResourceGroup(t *testing.T) {
+	tests := []struct {
+		TestName           string
+		group              models.ResourceGroup
+		expectedHTTPStatus int
+	}{
+		{
+			TestName:           "should add a group",
+			group:              models.ResourceGroup{Name: "metal shop"},
+			expectedHTTPStatus: http.StatusOK,
+		},
+		{
+			TestName:           "should require a name",
+			group:              models.ResourceGroup{Name: " "},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			server := resourceAPI{db: &in_memory.In_memory{}, logger: logrus.New()}
+
+			reqBody, _ := json.Marshal(tt.group)
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/group", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			server.ResourceGroup(response, request)
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+		})
+	}
+}
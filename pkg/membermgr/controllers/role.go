@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+type RoleServer struct {
+	store  datastore.DataStore
+	logger Logger
+}
+
+// GetRoles responds with every role and the permissions it grants
+func (rs *RoleServer) GetRoles(w http.ResponseWriter, req *http.Request) {
+	roles := []models.RolePermissions{}
+	for _, role := range models.Roles {
+		permissions := []string{}
+		for _, p := range rbac.Permissions(role) {
+			permissions = append(permissions, string(p))
+		}
+		roles = append(roles, models.RolePermissions{Role: role, Permissions: permissions})
+	}
+
+	ok(w, roles)
+}
+
+// MemberRoles gets or replaces the roles assigned to a member
+func (rs *RoleServer) MemberRoles(w http.ResponseWriter, req *http.Request) {
+	email := strings.ToLower(mux.Vars(req)["email"])
+	if _, err := rs.store.GetMemberByEmail(email); err != nil {
+		notFound(w, "member not found")
+		return
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateMemberRoles(w, req, email)
+		return
+	}
+
+	rs.getMemberRoles(w, email)
+}
+
+func (rs *RoleServer) getMemberRoles(w http.ResponseWriter, email string) {
+	roles, err := rs.store.GetMemberRoles(email)
+	if err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error getting member roles")
+		return
+	}
+
+	ok(w, models.MemberRoles{Email: email, Roles: roles})
+}
+
+func (rs *RoleServer) updateMemberRoles(w http.ResponseWriter, req *http.Request, email string) {
+	var update models.UpdateMemberRolesRequest
+	if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	roles, err := assignableRoles(update.Roles)
+	if err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	actor := requestActor(req)
+	if strings.EqualFold(actor, email) && !containsRole(roles, models.RoleAdmin) {
+		current, _ := rs.store.GetMemberRoles(email)
+		if containsRole(current, models.RoleAdmin) {
+			preconditionFailed(w, "you can't remove your own admin role")
+			return
+		}
+	}
+
+	if err := rs.store.SetMemberRoles(email, roles); err != nil {
+		rs.logger.Error(err)
+		internalServerError(w, "error setting member roles")
+		return
+	}
+
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+	logAudit(rs.store, rs.logger, actor, models.AuditActionRoles, email, strings.Join(names, ","))
+
+	ok(w, models.MemberRoles{Email: email, Roles: roles})
+}
+
+// ResetTwoFactor removes a member's two-factor enrolment, for when they lose their app and recovery codes
+//
+//	if their roles require two-factor authentication they enrol again at their next login
+func (rs *RoleServer) ResetTwoFactor(w http.ResponseWriter, req *http.Request) {
+	email := strings.ToLower(mux.Vars(req)["email"])
+
+	actor := requestActor(req)
+	if strings.EqualFold(actor, email) {
+		preconditionFailed(w, "you can't reset your own two-factor authentication")
+		return
+	}
+
+	if err := rs.store.DisableTwoFactor(email); err != nil {
+		notFound(w, "user not found")
+		return
+	}
+
+	logAudit(rs.store, rs.logger, actor, models.AuditActionTwoFactorReset, email, "")
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+// assignableRoles validates and dedupes the roles in a request
+//
+//	the member role is dropped, everyone has it
+func assignableRoles(requested []models.Role) ([]models.Role, error) {
+	roles := []models.Role{}
+	for _, role := range requested {
+		if !role.Valid() {
+			return nil, fmt.Errorf("unknown role: %s", role)
+		}
+		if role == models.RoleMember || containsRole(roles, role) {
+			continue
+		}
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles, nil
+}
+
+func containsRole(roles []models.Role, role models.Role) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func TestUpdateMemberRoles(t *testing.T) {
+	tests := []struct {
+		TestName           string
+		email              string
+		roles              []models.Role
+		expectedHTTPStatus int
+		expectedRoles      []models.Role
+		expectedAuditLogs  int
+	}{
+		{
+			TestName:           "should assign roles and record the change",
+			email:              "member@test.com",
+			roles:              []models.Role{models.RoleTreasurer, models.RoleBoard, models.RoleMember, models.RoleBoard},
+			expectedHTTPStatus: http.StatusOK,
+			expectedRoles:      []models.Role{models.RoleBoard, models.RoleTreasurer},
+			expectedAuditLogs:  1,
+		},
+		{
+			TestName:           "should reject a role that doesn't exist",
+			email:              "member@test.com",
+			roles:              []models.Role{"superuser"},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+		},
+		{
+			TestName:           "should not assign roles to someone who isn't a member",
+			email:              "nobody@test.com",
+			roles:              []models.Role{models.RoleBoard},
+			expectedHTTPStatus: http.StatusNotFound,
+		},
+		{
+			TestName:           "should not let an admin remove their own admin role",
+			email:              "admin@test.com",
+			roles:              []models.Role{models.RoleBoard},
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+			expectedRoles:      []models.Role{models.RoleAdmin},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{
+				Members: map[string]models.Member{
+					"admin@test.com":  {Name: "admin", Email: "admin@test.com"},
+					"member@test.com": {Name: "member", Email: "member@test.com"},
+				},
+			}
+			store.SetMemberRoles("admin@test.com", []models.Role{models.RoleAdmin})
+			server := &RoleServer{store, logrus.New()}
+
+			reqBody, _ := json.Marshal(models.UpdateMemberRolesRequest{Roles: tt.roles})
+			request, _ := http.NewRequest(http.MethodPut, "/api/member/email/"+tt.email+"/roles", bytes.NewReader(reqBody))
+			request = mux.SetURLVars(request, map[string]string{"email": tt.email})
+			response := httptest.NewRecorder()
+
+			admin := auth.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+			server.MemberRoles(response, auth.RequestWithUser(admin, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			if tt.expectedRoles != nil {
+				roles, _ := store.GetMemberRoles(tt.email)
+				if !reflect.DeepEqual(roles, tt.expectedRoles) {
+					t.Errorf("expected roles %v, got %v", tt.expectedRoles, roles)
+				}
+			}
+
+			entries, _ := store.GetAuditEvents(defaultAuditLimit, 0)
+			if len(entries) != tt.expectedAuditLogs {
+				t.Fatalf("expected %d audit entries, got %d", tt.expectedAuditLogs, len(entries))
+			}
+
+			for _, e := range entries {
+				if e.Actor != "admin@test.com" || e.Action != models.AuditActionRoles || e.Target != tt.email {
+					t.Errorf("unexpected audit entry: %+v", e)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRoles(t *testing.T) {
+	server := &RoleServer{&in_memory.In_memory{}, logrus.New()}
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/roles", nil)
+	response := httptest.NewRecorder()
+
+	server.GetRoles(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var roles []models.RolePermissions
+	json.NewDecoder(response.Body).Decode(&roles)
+
+	if len(roles) != len(models.Roles) {
+		t.Fatalf("expected %d roles, got %d", len(models.Roles), len(roles))
+	}
+
+	if roles[0].Role != models.RoleAdmin || len(roles[0].Permissions) == 0 {
+		t.Errorf("expected admin first with permissions, got %+v", roles[0])
+	}
+}
+
+func TestResetTwoFactor(t *testing.T) {
+	tests := []struct {
+		TestName           string
+		email              string
+		expectedHTTPStatus int
+		expectedEnabled    bool
+		expectedAuditLogs  int
+	}{
+		{
+			TestName:           "should reset a member's two-factor and record it",
+			email:              "member@test.com",
+			expectedHTTPStatus: http.StatusOK,
+			expectedAuditLogs:  1,
+		},
+		{
+			TestName:           "should not let an admin reset their own",
+			email:              "admin@test.com",
+			expectedHTTPStatus: http.StatusPreconditionFailed,
+			expectedEnabled:    true,
+		},
+		{
+			TestName:           "should not reset someone without an account",
+			email:              "nobody@test.com",
+			expectedHTTPStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{
+				Users: map[string]models.UserAccount{
+					"admin@test.com":  {Email: "admin@test.com", Verified: true},
+					"member@test.com": {Email: "member@test.com", Verified: true},
+				},
+			}
+			for email := range store.Users {
+				store.SetTwoFactorSecret(email, "JBSWY3DPEHPK3PXP")
+				store.EnableTwoFactor(email, 0, []string{"hash"})
+			}
+			server := &RoleServer{store, logrus.New()}
+
+			request, _ := http.NewRequest(http.MethodDelete, "/api/member/email/"+tt.email+"/2fa", nil)
+			request = mux.SetURLVars(request, map[string]string{"email": tt.email})
+			response := httptest.NewRecorder()
+
+			admin := auth.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+			server.ResetTwoFactor(response, auth.RequestWithUser(admin, request))
+
+			assertStatus(t, response.Code, tt.expectedHTTPStatus)
+
+			if tf, err := store.GetTwoFactor(tt.email); err == nil && tf.Enabled != tt.expectedEnabled {
+				t.Errorf("expected enabled to be %t", tt.expectedEnabled)
+			}
+
+			entries, _ := store.GetAuditEvents(defaultAuditLimit, 0)
+			if len(entries) != tt.expectedAuditLogs {
+				t.Fatalf("expected %d audit entries, got %d", tt.expectedAuditLogs, len(entries))
+			}
+		})
+	}
+}
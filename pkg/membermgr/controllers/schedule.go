@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// Schedule http handlers for resource access schedules
+func (rs resourceAPI) Schedule(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodGet {
+		rs.getSchedules(w, req)
+	}
+
+	if req.Method == http.MethodPost {
+		rs.addSchedule(w, req)
+	}
+
+	if req.Method == http.MethodPut {
+		rs.updateSchedule(w, req)
+	}
+
+	if req.Method == http.MethodDelete {
+		rs.deleteSchedule(w, req)
+	}
+}
+
+func (rs resourceAPI) getSchedules(w http.ResponseWriter, req *http.Request) {
+	schedules, err := rs.db.GetAccessSchedules(req.URL.Query().Get("resourceID"))
+	if err != nil {
+		internalServerError(w, err.Error())
+		return
+	}
+
+	ok(w, schedules)
+}
+
+func (rs resourceAPI) addSchedule(w http.ResponseWriter, req *http.Request) {
+	var s models.AccessSchedule
+
+	err := json.NewDecoder(req.Body).Decode(&s)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if err := s.Validate(); err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	schedule, err := rs.db.AddAccessSchedule(s)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	rs.pushSchedule(schedule.ResourceID)
+
+	ok(w, schedule)
+}
+
+func (rs resourceAPI) updateSchedule(w http.ResponseWriter, req *http.Request) {
+	var s models.AccessSchedule
+
+	err := json.NewDecoder(req.Body).Decode(&s)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	if err := s.Validate(); err != nil {
+		preconditionFailed(w, err.Error())
+		return
+	}
+
+	existing, err := rs.db.GetAccessSchedule(s.ID)
+	if err != nil {
+		notFound(w, err.Error())
+		return
+	}
+
+	schedule, err := rs.db.UpdateAccessSchedule(s)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	// a schedule moved to another resource has to be taken off the old one too
+	if existing.ResourceID != schedule.ResourceID {
+		rs.pushSchedule(existing.ResourceID)
+	}
+	rs.pushSchedule(schedule.ResourceID)
+
+	ok(w, schedule)
+}
+
+func (rs resourceAPI) deleteSchedule(w http.ResponseWriter, req *http.Request) {
+	var deleteRequest models.AccessScheduleDeleteRequest
+
+	err := json.NewDecoder(req.Body).Decode(&deleteRequest)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	schedule, err := rs.db.GetAccessSchedule(deleteRequest.ID)
+	if err != nil {
+		notFound(w, err.Error())
+		return
+	}
+
+	err = rs.db.DeleteAccessSchedule(schedule.ID)
+	if err != nil {
+		badRequest(w, err.Error())
+		return
+	}
+
+	rs.pushSchedule(schedule.ResourceID)
+
+	ok(w, models.EndpointSuccess{
+		Ack: true,
+	})
+}
+
+// pushSchedule sends the new schedule to the resource if it is able to enforce it
+//
+//	only the resource the schedule belongs to, or belonged to, is pushed
+func (rs resourceAPI) pushSchedule(resourceID string) {
+	resource, err := rs.db.GetResourceByID(resourceID)
+	if err != nil {
+		rs.logger.Errorf("error getting resource to push schedule: %s", err)
+		return
+	}
+
+	if !resource.SupportsTimeWindows {
+		return
+	}
+
+	go rs.resourcemanager.PushResource(resource)
+}
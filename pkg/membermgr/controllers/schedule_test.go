@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// pushRecorder notes which resources are sent their access list instead of talking to them
+type pushRecorder struct {
+	*resourcemanager.ResourceManager
+	pushed chan string
+}
+
+func (r pushRecorder) PushResource(resource models.Resource) {
+	r.pushed <- resource.ID
+}
+
+// waitForPushes collects the resources pushed in the background, in order of their IDs
+func (r pushRecorder) waitForPushes(t *testing.T, count int) []string {
+	t.Helper()
+	pushed := []string{}
+	for len(pushed) < count {
+		select {
+		case id := <-r.pushed:
+			pushed = append(pushed, id)
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d pushes, got %v", count, pushed)
+		}
+	}
+	sort.Strings(pushed)
+	return pushed
+}
+
+func TestUpdateScheduleMovesResource(t *testing.T) {
+	in_memory.Resources["lathe"] = models.Resource{ID: "lathe", Name: "lathe", SupportsTimeWindows: true}
+	in_memory.Resources["mill"] = models.Resource{ID: "mill", Name: "mill", SupportsTimeWindows: true}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "lathe")
+		delete(in_memory.Resources, "mill")
+	})
+
+	tests := []struct {
+		TestName       string
+		resourceID     string
+		expectedPushed []string
+	}{
+		{
+			TestName:       "should push the resource the schedule belongs to",
+			resourceID:     "lathe",
+			expectedPushed: []string{"lathe"},
+		},
+		{
+			TestName:       "should push the old and new resource when the schedule moves",
+			resourceID:     "mill",
+			expectedPushed: []string{"lathe", "mill"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			store := &in_memory.In_memory{}
+			schedule, _ := store.AddAccessSchedule(models.AccessSchedule{ResourceID: "lathe", Weekdays: []int{1}, StartTime: "09:00", EndTime: "17:00"})
+			resources := pushRecorder{
+				ResourceManager: resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New()),
+				pushed:          make(chan string, 2),
+			}
+			server := resourceAPI{db: store, resourcemanager: resources, logger: logrus.New()}
+
+			schedule.ResourceID = tt.resourceID
+			reqBody, _ := json.Marshal(schedule)
+			request, _ := http.NewRequest(http.MethodPut, "/api/resource/schedule", bytes.NewReader(reqBody))
+			response := httptest.NewRecorder()
+
+			server.Schedule(response, request)
+
+			assertStatus(t, response.Code, http.StatusOK)
+
+			if pushed := resources.waitForPushes(t, len(tt.expectedPushed)); !reflect.DeepEqual(pushed, tt.expectedPushed) {
+				t.Errorf("expected %v to be pushed, got %v", tt.expectedPushed, pushed)
+			}
+		})
+	}
+}
+
+func TestDeleteSchedulePushesResource(t *testing.T) {
+	in_memory.Resources["lathe"] = models.Resource{ID: "lathe", Name: "lathe", SupportsTimeWindows: true}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "lathe")
+	})
+
+	store := &in_memory.In_memory{}
+	schedule, _ := store.AddAccessSchedule(models.AccessSchedule{ResourceID: "lathe", Weekdays: []int{1}, StartTime: "09:00", EndTime: "17:00"})
+	resources := pushRecorder{
+		ResourceManager: resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New()),
+		pushed:          make(chan string, 1),
+	}
+	server := resourceAPI{db: store, resourcemanager: resources, logger: logrus.New()}
+
+	reqBody, _ := json.Marshal(models.AccessScheduleDeleteRequest{ID: schedule.ID})
+	request, _ := http.NewRequest(http.MethodDelete, "/api/resource/schedule", bytes.NewReader(reqBody))
+	response := httptest.NewRecorder()
+
+	server.Schedule(response, request)
+
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if pushed := resources.waitForPushes(t, 1); !reflect.DeepEqual(pushed, []string{"lathe"}) {
+		t.Errorf("expected the lathe to be pushed, got %v", pushed)
+	}
+
+	if schedules, _ := store.GetAccessSchedules(""); len(schedules) != 0 {
+		t.Errorf("expected the schedule to be deleted, got %+v", schedules)
+	}
+
+	request, _ = http.NewRequest(http.MethodDelete, "/api/resource/schedule", bytes.NewReader(reqBody))
+	response = httptest.NewRecorder()
+
+	server.Schedule(response, request)
+
+	assertStatus(t, response.Code, http.StatusNotFound)
+}
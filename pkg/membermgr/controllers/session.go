@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+type SessionServer struct {
+	store  datastore.DataStore
+	logger Logger
+}
+
+// GetSessions responds with the places a member is signed in
+func (ss *SessionServer) GetSessions(w http.ResponseWriter, req *http.Request) {
+	sessions, err := ss.store.GetSessions(strings.ToLower(mux.Vars(req)["email"]))
+	if err != nil {
+		ss.logger.Error(err)
+		internalServerError(w, "error getting sessions")
+		return
+	}
+
+	ok(w, sessions)
+}
+
+// RevokeSessions signs a member out everywhere
+func (ss *SessionServer) RevokeSessions(w http.ResponseWriter, req *http.Request) {
+	email := strings.ToLower(mux.Vars(req)["email"])
+
+	revoked, err := ss.store.RevokeSessions(email)
+	if err != nil {
+		ss.logger.Error(err)
+		internalServerError(w, "error revoking sessions")
+		return
+	}
+
+	logAudit(ss.store, ss.logger, requestActor(req), models.AuditActionSessionRevoke, email, strconv.FormatInt(revoked, 10)+" sessions")
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
+
+// RevokeSession signs a member out of one session
+func (ss *SessionServer) RevokeSession(w http.ResponseWriter, req *http.Request) {
+	email := strings.ToLower(mux.Vars(req)["email"])
+	id := mux.Vars(req)["id"]
+
+	session, err := ss.store.GetSession(id)
+	if err != nil || !strings.EqualFold(session.Email, email) {
+		notFound(w, "session not found")
+		return
+	}
+
+	if err := ss.store.RevokeSession(id); err != nil {
+		ss.logger.Error(err)
+		internalServerError(w, "error revoking session")
+		return
+	}
+
+	logAudit(ss.store, ss.logger, requestActor(req), models.AuditActionSessionRevoke, email, id)
+
+	ok(w, models.EndpointSuccess{Ack: true})
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	guardian "github.com/shaj13/go-guardian/v2/auth"
+	"github.com/sirupsen/logrus"
+)
+
+func newSessionTestServer() (*SessionServer, *in_memory.In_memory) {
+	store := &in_memory.In_memory{}
+	for _, email := range []string{"member@test.com", "member@test.com", "other@test.com"} {
+		store.CreateSession(models.Session{Email: email, ExpiresAt: time.Now().Add(time.Hour)})
+	}
+	return &SessionServer{store, logrus.New()}, store
+}
+
+func sessionRequest(server *SessionServer, handler http.HandlerFunc, method string, vars map[string]string) *httptest.ResponseRecorder {
+	request, _ := http.NewRequest(method, "/api/member/email/"+vars["email"]+"/sessions", nil)
+	request = mux.SetURLVars(request, vars)
+	admin := guardian.NewDefaultUser("admin@test.com", "admin@test.com", []string{"member", "admin"}, nil)
+
+	response := httptest.NewRecorder()
+	handler(response, guardian.RequestWithUser(admin, request))
+	return response
+}
+
+func TestGetSessions(t *testing.T) {
+	server, _ := newSessionTestServer()
+
+	response := sessionRequest(server, server.GetSessions, http.MethodGet, map[string]string{"email": "Member@test.com"})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	var sessions []models.Session
+	json.NewDecoder(response.Body).Decode(&sessions)
+	if len(sessions) != 2 {
+		t.Errorf("expected the member's 2 sessions, got %d", len(sessions))
+	}
+}
+
+func TestRevokeSession(t *testing.T) {
+	server, store := newSessionTestServer()
+
+	// a session that belongs to someone else isn't found
+	response := sessionRequest(server, server.RevokeSession, http.MethodDelete, map[string]string{"email": "member@test.com", "id": "3"})
+	assertStatus(t, response.Code, http.StatusNotFound)
+
+	response = sessionRequest(server, server.RevokeSession, http.MethodDelete, map[string]string{"email": "member@test.com", "id": "1"})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	sessions, _ := store.GetSessions("member@test.com")
+	if len(sessions) != 1 || sessions[0].ID != "2" {
+		t.Errorf("expected only the revoked session to end, got %+v", sessions)
+	}
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Action != models.AuditActionSessionRevoke || store.AuditLog[0].Target != "member@test.com" {
+		t.Errorf("expected revoking the session to be audited, got %+v", store.AuditLog)
+	}
+}
+
+func TestRevokeSessions(t *testing.T) {
+	server, store := newSessionTestServer()
+
+	response := sessionRequest(server, server.RevokeSessions, http.MethodDelete, map[string]string{"email": "member@test.com"})
+	assertStatus(t, response.Code, http.StatusOK)
+
+	if sessions, _ := store.GetSessions("member@test.com"); len(sessions) != 0 {
+		t.Errorf("expected the member to be signed out everywhere, got %+v", sessions)
+	}
+	if sessions, _ := store.GetSessions("other@test.com"); len(sessions) != 1 {
+		t.Error("expected other members to stay signed in")
+	}
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Detail != "2 sessions" {
+		t.Errorf("expected revoking the sessions to be audited, got %+v", store.AuditLog)
+	}
+}
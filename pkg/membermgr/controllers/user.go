@@ -5,6 +5,7 @@ import (
 
 	config "github.com/HackRVA/memberserver/configs"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 
 	"github.com/shaj13/go-guardian/v2/auth"
@@ -41,3 +42,21 @@ func (us *UserServer) GetUser(w http.ResponseWriter, r *http.Request) {
 
 	ok(w, userProfile)
 }
+
+// GetUserPermissions responds with what the current logged in user's roles allow
+//
+//	the dashboard shows and hides pages with these instead of looking at role names
+func (us *UserServer) GetUserPermissions(w http.ResponseWriter, r *http.Request) {
+	u := auth.User(r)
+	if u == nil {
+		http.Error(w, "user not found", http.StatusUnauthorized)
+		return
+	}
+
+	permissions := []string{}
+	for _, p := range rbac.Granted(u.GetGroups()) {
+		permissions = append(permissions, string(p))
+	}
+
+	ok(w, models.UserPermissions{Permissions: permissions})
+}
@@ -53,3 +53,31 @@ func newGetUserRequest() *http.Request {
 	req, _ := http.NewRequest(http.MethodGet, "/api/user", nil)
 	return req
 }
+
+func TestGetUserPermissions(t *testing.T) {
+	c, _ := config.Load()
+	server := NewUserServer(&testMemberStore, c)
+
+	tests := []struct {
+		TestName         string
+		groups           []string
+		expectedResponse string
+	}{
+		{"members have no permissions", []string{"member"}, "{\"permissions\":[]}"},
+		{"permissions come from the roles", []string{"member", "treasurer"}, "{\"permissions\":[\"members.credit\",\"members.view\",\"reports.view\"]}"},
+		{"a group named after a role that doesn't exist grants nothing", []string{"admins"}, "{\"permissions\":[]}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "/api/user/permissions", nil)
+			response := httptest.NewRecorder()
+
+			authInfo := auth.NewDefaultUser("test", "test", tt.groups, nil)
+			server.GetUserPermissions(response, auth.RequestWithUser(authInfo, request))
+
+			assertStatus(t, response.Code, http.StatusOK)
+			assertResponseBody(t, response.Body.String(), tt.expectedResponse)
+		})
+	}
+}
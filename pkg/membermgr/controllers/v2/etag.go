@@ -0,0 +1,43 @@
+package v2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// etag is a strong validator for the fields of v that can be changed
+func etag(v interface{}) string {
+	b, _ := json.Marshal(v)
+	sum := sha256.Sum256(b)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// ifMatch checks that an update was made from the current version
+//
+//	updates have to send If-Match, so changes made from an old copy aren't saved over newer ones
+//	the update is then only made at the version that was checked, see changed
+func ifMatch(w http.ResponseWriter, req *http.Request, current string) bool {
+	header := req.Header.Get("If-Match")
+	if len(header) == 0 {
+		problem(w, req, http.StatusPreconditionRequired, "send the ETag you read in If-Match")
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	changed(w, req)
+	return false
+}
+
+// changed responds to an update that was refused because of a change made since the ETag was checked
+func changed(w http.ResponseWriter, req *http.Request) {
+	problem(w, req, http.StatusPreconditionFailed, "it has changed since you read it, read it again and retry")
+}
@@ -0,0 +1,222 @@
+package v2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type MemberServer struct {
+	members services.Member
+}
+
+func NewMemberServer(members services.Member) *MemberServer {
+	return &MemberServer{members}
+}
+
+// memberETag covers the fields of a member that are changed through the API
+//
+//	a member's level follows their payments and their resources follow grants, neither is an edit to the member
+func memberETag(m models.Member) string {
+	return etag([]interface{}{m.Email, m.Name, m.RFID, m.SubscriptionID})
+}
+
+func memberLocation(email string) string {
+	return Prefix + "members/" + url.PathEscape(email)
+}
+
+// GetMembers responds with a page of members sorted by email
+//
+//	q searches names, emails, fobs and subscriptions
+//	active=true only has members with a paid up level, active=false only inactive ones
+func (ms *MemberServer) GetMembers(w http.ResponseWriter, req *http.Request) {
+	page, err := readPage(req)
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	active := req.URL.Query().Get("active")
+	if len(active) > 0 && active != "true" && active != "false" {
+		problem(w, req, http.StatusBadRequest, "active must be true or false")
+		return
+	}
+
+	query := models.MemberQuery{Search: req.URL.Query().Get("q"), After: page.after, Limit: page.limit + 1}
+	if len(active) > 0 {
+		isActive := active == "true"
+		query.Active = &isActive
+	}
+
+	members, total, err := ms.members.GetPage(query)
+	if err != nil {
+		problem(w, req, http.StatusInternalServerError, "error reading members")
+		return
+	}
+
+	items, next := nextPage(members, func(m models.Member) string { return strings.ToLower(m.Email) }, page)
+	ok(w, models.MemberPage{Items: items, Total: total, NextCursor: next})
+}
+
+// GetMember responds with a member, its ETag is needed to change it
+func (ms *MemberServer) GetMember(w http.ResponseWriter, req *http.Request) {
+	member, found := ms.member(w, req)
+	if !found {
+		return
+	}
+
+	w.Header().Set("ETag", memberETag(member))
+	ok(w, member)
+}
+
+// CreateMember adds a member
+func (ms *MemberServer) CreateMember(w http.ResponseWriter, req *http.Request) {
+	var newMember models.Member
+	if err := json.NewDecoder(req.Body).Decode(&newMember); err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if !govalidator.IsEmail(newMember.Email) {
+		problem(w, req, http.StatusUnprocessableEntity, "email must be a valid email address")
+		return
+	}
+	if len(newMember.Name) == 0 {
+		problem(w, req, http.StatusUnprocessableEntity, "name is required")
+		return
+	}
+	if _, err := ms.members.GetByEmail(newMember.Email); err == nil {
+		problem(w, req, http.StatusConflict, "there is already a member with that email")
+		return
+	}
+
+	member, err := ms.members.Add(models.Member{
+		Name:           newMember.Name,
+		Email:          newMember.Email,
+		RFID:           newMember.RFID,
+		SubscriptionID: newMember.SubscriptionID,
+	})
+	if err != nil {
+		problem(w, req, http.StatusInternalServerError, "error adding member")
+		return
+	}
+
+	w.Header().Set("ETag", memberETag(member))
+	created(w, memberLocation(member.Email), member)
+}
+
+// UpdateMember changes a member's name and subscription
+func (ms *MemberServer) UpdateMember(w http.ResponseWriter, req *http.Request) {
+	member, found := ms.member(w, req)
+	if !found || !ifMatch(w, req, memberETag(member)) {
+		return
+	}
+
+	var change models.MemberChange
+	if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(change.Name) == 0 {
+		problem(w, req, http.StatusUnprocessableEntity, "name is required")
+		return
+	}
+
+	err := ms.members.UpdateAtVersion(models.Member{
+		Email:          member.Email,
+		Name:           change.Name,
+		SubscriptionID: change.SubscriptionID,
+	}, member.Version)
+	if errors.Is(err, datastore.ErrVersionChanged) {
+		changed(w, req)
+		return
+	}
+	if err != nil {
+		problem(w, req, http.StatusInternalServerError, "error updating member")
+		return
+	}
+
+	ms.respondWithMember(w, req, member.Email)
+}
+
+// AssignRFID gives a member a new fob
+func (ms *MemberServer) AssignRFID(w http.ResponseWriter, req *http.Request) {
+	member, found := ms.member(w, req)
+	if !found || !ifMatch(w, req, memberETag(member)) {
+		return
+	}
+
+	ms.assignRFID(w, req, member.Email, member.Version)
+}
+
+// GetSelf responds with the signed in member
+func (ms *MemberServer) GetSelf(w http.ResponseWriter, req *http.Request) {
+	ms.respondWithMember(w, req, auth.User(req).GetUserName())
+}
+
+// AssignSelfRFID lets the signed in member register their own fob
+func (ms *MemberServer) AssignSelfRFID(w http.ResponseWriter, req *http.Request) {
+	ms.assignRFID(w, req, auth.User(req).GetUserName(), datastore.AnyVersion)
+}
+
+// GetTiers responds with the membership levels
+func (ms *MemberServer) GetTiers(w http.ResponseWriter, req *http.Request) {
+	ok(w, ms.members.GetTiers())
+}
+
+// assignRFID gives the member a new fob, unless they've changed since they were at version
+func (ms *MemberServer) assignRFID(w http.ResponseWriter, req *http.Request, email string, version int) {
+	var change models.RFIDChange
+	if err := json.NewDecoder(req.Body).Decode(&change); err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(change.RFID) == 0 {
+		problem(w, req, http.StatusUnprocessableEntity, "rfid is required")
+		return
+	}
+
+	_, err := ms.members.AssignRFIDAtVersion(email, change.RFID, version)
+	if errors.Is(err, datastore.ErrVersionChanged) {
+		changed(w, req)
+		return
+	}
+	if err != nil {
+		problem(w, req, http.StatusInternalServerError, "error assigning rfid")
+		return
+	}
+
+	ms.respondWithMember(w, req, email)
+}
+
+// member is the member named in the route, it responds with 404 when there isn't one
+func (ms *MemberServer) member(w http.ResponseWriter, req *http.Request) (models.Member, bool) {
+	member, err := ms.members.GetByEmail(mux.Vars(req)["email"])
+	if err != nil {
+		problem(w, req, http.StatusNotFound, "member not found")
+		return member, false
+	}
+	return member, true
+}
+
+// respondWithMember reads a member back after a change, so the response has the new ETag
+func (ms *MemberServer) respondWithMember(w http.ResponseWriter, req *http.Request, email string) {
+	member, err := ms.members.GetByEmail(email)
+	if err != nil {
+		problem(w, req, http.StatusNotFound, "member not found")
+		return
+	}
+
+	w.Header().Set("ETag", memberETag(member))
+	ok(w, member)
+}
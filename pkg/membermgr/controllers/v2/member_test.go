@@ -0,0 +1,266 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+)
+
+// stubMembers keeps members in a map, and pages through them with the in memory store
+type stubMembers map[string]models.Member
+
+func (s stubMembers) Add(m models.Member) (models.Member, error) {
+	s[m.Email] = m
+	return m, nil
+}
+func (s stubMembers) Get() []models.Member {
+	members := []models.Member{}
+	for _, m := range s {
+		members = append(members, m)
+	}
+	return members
+}
+func (s stubMembers) GetMembersWithLimit(limit int, offset int, active bool) []models.Member {
+	return s.Get()
+}
+func (s stubMembers) GetPage(query models.MemberQuery) ([]models.Member, int, error) {
+	return (&in_memory.In_memory{Members: s}).GetMemberPage(query)
+}
+func (s stubMembers) GetByEmail(email string) (models.Member, error) {
+	m, ok := s[email]
+	if !ok {
+		return m, errors.New("not found")
+	}
+	return m, nil
+}
+func (s stubMembers) Update(update models.Member) error {
+	return s.UpdateAtVersion(update, datastore.AnyVersion)
+}
+func (s stubMembers) UpdateAtVersion(update models.Member, version int) error {
+	return (&in_memory.In_memory{Members: s}).UpdateMemberAtVersion(update, version)
+}
+func (s stubMembers) AssignRFID(email string, rfid string) (models.Member, error) {
+	return s.AssignRFIDAtVersion(email, rfid, datastore.AnyVersion)
+}
+func (s stubMembers) AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error) {
+	return (&in_memory.In_memory{Members: s}).AssignRFIDAtVersion(email, rfid, version)
+}
+func (s stubMembers) GetTiers() []models.Tier                              { return []models.Tier{} }
+func (s stubMembers) FindNonMembersOnSlack() []string                      { return nil }
+func (s stubMembers) GetActiveMembersWithoutSubscription() []models.Member { return nil }
+func (s stubMembers) GetMemberFromSubscription(subscriptionID string) (models.Member, error) {
+	return models.Member{}, nil
+}
+func (s stubMembers) CheckStatus(subscriptionID string) (models.Member, error) {
+	return models.Member{}, nil
+}
+func (s stubMembers) SetLevel(memberID string, level models.MemberLevel) error { return nil }
+
+func testMembers() stubMembers {
+	return stubMembers{
+		"carol@test.com": {ID: "3", Name: "Carol", Email: "carol@test.com", Level: uint8(models.Standard), Version: 1},
+		"alice@test.com": {ID: "1", Name: "Alice", Email: "alice@test.com", Level: uint8(models.Standard), Version: 1},
+		"bob@test.com":   {ID: "2", Name: "Bob", Email: "bob@test.com", Level: uint8(models.Inactive), Version: 1},
+	}
+}
+
+func TestGetMembers(t *testing.T) {
+	server := NewMemberServer(testMembers())
+
+	get := func(query string) models.MemberPage {
+		t.Helper()
+		rr := httptest.NewRecorder()
+		server.GetMembers(rr, httptest.NewRequest("GET", "/api/v2/members"+query, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rr.Code, rr.Body.String())
+		}
+		var page models.MemberPage
+		json.NewDecoder(rr.Body).Decode(&page)
+		return page
+	}
+
+	first := get("?limit=2")
+	if first.Total != 3 || len(first.Items) != 2 || first.Items[0].Email != "alice@test.com" || first.NextCursor == "" {
+		t.Fatalf("first page = %+v", first)
+	}
+
+	second := get("?limit=2&cursor=" + first.NextCursor)
+	if second.Total != 3 || len(second.Items) != 1 || second.Items[0].Email != "carol@test.com" || second.NextCursor != "" {
+		t.Fatalf("second page = %+v", second)
+	}
+
+	active := get("?active=true")
+	if active.Total != 2 {
+		t.Errorf("active total = %d, want 2", active.Total)
+	}
+
+	inactive := get("?active=false&q=bob")
+	if inactive.Total != 1 || inactive.Items[0].Email != "bob@test.com" {
+		t.Errorf("inactive = %+v", inactive)
+	}
+
+	rr := httptest.NewRecorder()
+	server.GetMembers(rr, httptest.NewRequest("GET", "/api/v2/members?active=maybe", nil))
+	if rr.Code != http.StatusBadRequest || rr.Header().Get("Content-Type") != problemContentType {
+		t.Errorf("bad filter = %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+}
+
+func memberRequest(method string, email string, body interface{}, ifMatch string) *http.Request {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, memberLocation(email), bytes.NewReader(b))
+	if len(ifMatch) > 0 {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	return mux.SetURLVars(req, map[string]string{"email": email})
+}
+
+func TestUpdateMember(t *testing.T) {
+	server := NewMemberServer(testMembers())
+
+	rr := httptest.NewRecorder()
+	server.GetMember(rr, memberRequest("GET", "alice@test.com", nil, ""))
+	tag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || len(tag) == 0 {
+		t.Fatalf("get = %d, etag %q", rr.Code, tag)
+	}
+
+	change := models.MemberChange{Name: "Alice Smith"}
+
+	tests := []struct {
+		name       string
+		ifMatch    string
+		wantStatus int
+	}{
+		{"needs If-Match", "", http.StatusPreconditionRequired},
+		{"stale ETag", `"stale"`, http.StatusPreconditionFailed},
+		{"current ETag", tag, http.StatusOK},
+		{"ETag from before the update", tag, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.UpdateMember(rr, memberRequest("PUT", "alice@test.com", change, tt.ifMatch))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var updated models.Member
+			json.NewDecoder(rr.Body).Decode(&updated)
+			if updated.Name != "Alice Smith" {
+				t.Errorf("name = %q", updated.Name)
+			}
+			if newTag := rr.Header().Get("ETag"); newTag == tag || len(newTag) == 0 {
+				t.Errorf("etag wasn't changed: %q", newTag)
+			}
+		})
+	}
+
+	rr = httptest.NewRecorder()
+	server.UpdateMember(rr, memberRequest("PUT", "nobody@test.com", change, "*"))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("missing member = %d, want 404", rr.Code)
+	}
+}
+
+func TestCreateMember(t *testing.T) {
+	server := NewMemberServer(testMembers())
+
+	tests := []struct {
+		name       string
+		member     models.Member
+		wantStatus int
+	}{
+		{"new member", models.Member{Name: "Dave", Email: "dave@test.com"}, http.StatusCreated},
+		{"existing email", models.Member{Name: "Alice", Email: "alice@test.com"}, http.StatusConflict},
+		{"bad email", models.Member{Name: "Erin", Email: "erin"}, http.StatusUnprocessableEntity},
+		{"no name", models.Member{Email: "frank@test.com"}, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, _ := json.Marshal(tt.member)
+			rr := httptest.NewRecorder()
+			server.CreateMember(rr, httptest.NewRequest("POST", "/api/v2/members", bytes.NewReader(b)))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantStatus == http.StatusCreated && rr.Header().Get("Location") != "/api/v2/members/dave@test.com" {
+				t.Errorf("location = %q", rr.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+// racingMembers lets another change land right after each member is read
+type racingMembers struct {
+	stubMembers
+}
+
+func (r racingMembers) GetByEmail(email string) (models.Member, error) {
+	m, err := r.stubMembers.GetByEmail(email)
+	if err == nil {
+		other := m
+		other.Name = "Changed Elsewhere"
+		other.Version++
+		r.stubMembers[email] = other
+	}
+	return m, err
+}
+
+func TestUpdateMemberChangedAfterCheck(t *testing.T) {
+	members := racingMembers{testMembers()}
+	server := NewMemberServer(members)
+	tag := memberETag(members.stubMembers["alice@test.com"])
+
+	rr := httptest.NewRecorder()
+	server.UpdateMember(rr, memberRequest("PUT", "alice@test.com", models.MemberChange{Name: "Alice Smith"}, tag))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("update = %d, want 412: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	server.AssignRFID(rr, memberRequest("PUT", "alice@test.com", models.RFIDChange{RFID: "1234"}, "*"))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("assign rfid = %d, want 412: %s", rr.Code, rr.Body.String())
+	}
+
+	if m := members.stubMembers["alice@test.com"]; m.Name != "Changed Elsewhere" || len(m.RFID) > 0 {
+		t.Errorf("member = %+v, the other change was overwritten", m)
+	}
+}
+
+func TestMemberETagIgnoresLevel(t *testing.T) {
+	members := testMembers()
+	server := NewMemberServer(members)
+
+	rr := httptest.NewRecorder()
+	server.GetMember(rr, memberRequest("GET", "alice@test.com", nil, ""))
+	tag := rr.Header().Get("ETag")
+
+	// payments move the level along, that doesn't make an edit stale
+	alice := members["alice@test.com"]
+	alice.Level = uint8(models.Inactive)
+	members["alice@test.com"] = alice
+
+	rr = httptest.NewRecorder()
+	server.UpdateMember(rr, memberRequest("PUT", "alice@test.com", models.MemberChange{Name: "Alice Smith"}, tag))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+}
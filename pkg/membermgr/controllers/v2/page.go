@@ -0,0 +1,56 @@
+package v2
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+// pageRequest is the page a list request asks for
+type pageRequest struct {
+	limit int
+	// after is the sort key of the last item on the previous page
+	after string
+}
+
+// readPage reads the limit and cursor query parameters
+func readPage(req *http.Request) (pageRequest, error) {
+	page := pageRequest{limit: defaultPageSize}
+
+	if limit := req.URL.Query().Get("limit"); len(limit) > 0 {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > maxPageSize {
+			return page, errors.New("limit must be a number from 1 to " + strconv.Itoa(maxPageSize))
+		}
+		page.limit = n
+	}
+
+	if cursor := req.URL.Query().Get("cursor"); len(cursor) > 0 {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return page, errors.New("cursor isn't one from a previous page")
+		}
+		page.after = string(after)
+	}
+
+	return page, nil
+}
+
+// nextPage trims items read with one more than the limit to the page, and returns the cursor for the next one
+//
+//	the cursor is the key of the last item, so items added or removed
+//	between requests don't shift the pages the way an offset would
+func nextPage[T any](items []T, key func(T) string, page pageRequest) ([]T, string) {
+	if len(items) <= page.limit {
+		return items, ""
+	}
+
+	items = items[:page.limit]
+	return items, base64.RawURLEncoding.EncodeToString([]byte(key(items[len(items)-1])))
+}
@@ -0,0 +1,81 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func TestNextPage(t *testing.T) {
+	key := func(s string) string { return s }
+
+	// pages are read with one more item than the limit, to know if there's another
+	first, cursor := nextPage([]string{"a", "b", "c"}, key, pageRequest{limit: 2})
+	if len(first) != 2 || first[0] != "a" || first[1] != "b" || len(cursor) == 0 {
+		t.Fatalf("first page = %v, cursor %q", first, cursor)
+	}
+
+	req := httptest.NewRequest("GET", "/api/v2/members?limit=2&cursor="+cursor, nil)
+	page, err := readPage(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.after != "b" {
+		t.Fatalf("after = %q, want the key of the last item", page.after)
+	}
+
+	last, cursor := nextPage([]string{"c", "d"}, key, page)
+	if len(last) != 2 || last[0] != "c" || last[1] != "d" || len(cursor) != 0 {
+		t.Fatalf("last page = %v, cursor %q", last, cursor)
+	}
+}
+
+func TestGetMembersKeyset(t *testing.T) {
+	members := testMembers()
+	server := NewMemberServer(members)
+
+	rr := httptest.NewRecorder()
+	server.GetMembers(rr, httptest.NewRequest("GET", "/api/v2/members?limit=1", nil))
+	var first models.MemberPage
+	json.NewDecoder(rr.Body).Decode(&first)
+
+	// a member removed from an earlier page doesn't shift the next one
+	delete(members, "alice@test.com")
+
+	rr = httptest.NewRecorder()
+	server.GetMembers(rr, httptest.NewRequest("GET", "/api/v2/members?limit=1&cursor="+first.NextCursor, nil))
+	var second models.MemberPage
+	json.NewDecoder(rr.Body).Decode(&second)
+	if len(second.Items) != 1 || second.Items[0].Email != "bob@test.com" || second.Total != 2 {
+		t.Fatalf("second page = %+v", second)
+	}
+}
+
+func TestReadPage(t *testing.T) {
+	tests := []struct {
+		query   string
+		limit   int
+		wantErr bool
+	}{
+		{"", defaultPageSize, false},
+		{"?limit=10", 10, false},
+		{"?limit=0", 0, true},
+		{"?limit=501", 0, true},
+		{"?limit=ten", 0, true},
+		{"?cursor=not*base64", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			page, err := readPage(httptest.NewRequest("GET", "/api/v2/members"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && page.limit != tt.limit {
+				t.Errorf("limit = %d, want %d", page.limit, tt.limit)
+			}
+		})
+	}
+}
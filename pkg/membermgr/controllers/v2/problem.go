@@ -0,0 +1,95 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// Prefix is where the v2 routes live
+const Prefix = "/api/v2/"
+
+const problemContentType = "application/problem+json"
+
+// problem writes an error as problem details
+func problem(w http.ResponseWriter, req *http.Request, status int, detail string) {
+	w.Header().Set("Content-Type", problemContentType)
+	w.Header().Del("ETag")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: req.URL.Path,
+	})
+}
+
+func ok(w http.ResponseWriter, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+func created(w http.ResponseWriter, location string, result interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", location)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(result)
+}
+
+// Problems is middleware that turns the plain text errors from middleware shared with v1,
+// like a failed login or a missing permission, into problem details on v2 routes
+func Problems(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !strings.HasPrefix(req.URL.Path, Prefix) {
+			next.ServeHTTP(w, req)
+			return
+		}
+
+		pw := &problemWriter{ResponseWriter: w}
+		next.ServeHTTP(pw, req)
+
+		if pw.status != 0 {
+			problem(w, req, pw.status, strings.TrimSpace(pw.body.String()))
+		}
+	})
+}
+
+// problemWriter holds back plain text errors so they can be rewritten
+type problemWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	// status is set when the response is an error that needs rewriting
+	status int
+	body   bytes.Buffer
+}
+
+func (pw *problemWriter) WriteHeader(status int) {
+	if pw.wroteHeader {
+		return
+	}
+	pw.wroteHeader = true
+
+	contentType := pw.Header().Get("Content-Type")
+	if status >= http.StatusBadRequest && !strings.HasPrefix(contentType, problemContentType) {
+		pw.status = status
+		pw.Header().Del("Content-Type")
+		pw.Header().Del("X-Content-Type-Options")
+		return
+	}
+
+	pw.ResponseWriter.WriteHeader(status)
+}
+
+func (pw *problemWriter) Write(b []byte) (int, error) {
+	if !pw.wroteHeader {
+		pw.WriteHeader(http.StatusOK)
+	}
+	if pw.status != 0 {
+		return pw.body.Write(b)
+	}
+	return pw.ResponseWriter.Write(b)
+}
@@ -0,0 +1,62 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func TestProblems(t *testing.T) {
+	forbidden := Problems(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.Error(w, "not allowed", http.StatusForbidden)
+	}))
+
+	t.Run("v2 errors are problem details", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		forbidden.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v2/members", nil))
+
+		if rr.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusForbidden)
+		}
+		if got := rr.Header().Get("Content-Type"); got != problemContentType {
+			t.Fatalf("content type = %q", got)
+		}
+
+		var p models.Problem
+		if err := json.NewDecoder(rr.Body).Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		want := models.Problem{Type: "about:blank", Title: "Forbidden", Status: http.StatusForbidden, Detail: "not allowed", Instance: "/api/v2/members"}
+		if p != want {
+			t.Errorf("problem = %+v, want %+v", p, want)
+		}
+	})
+
+	t.Run("v1 errors are left alone", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		forbidden.ServeHTTP(rr, httptest.NewRequest("GET", "/api/member", nil))
+
+		if rr.Body.String() != "not allowed\n" {
+			t.Errorf("body = %q", rr.Body.String())
+		}
+	})
+
+	t.Run("problems from v2 handlers pass through", func(t *testing.T) {
+		handler := Problems(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			problem(w, req, http.StatusNotFound, "member not found")
+		}))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, httptest.NewRequest("GET", "/api/v2/members/nobody", nil))
+
+		var p models.Problem
+		if err := json.NewDecoder(rr.Body).Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		if p.Status != http.StatusNotFound || p.Detail != "member not found" {
+			t.Errorf("problem = %+v", p)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package v2
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+)
+
+type ResourceServer struct {
+	resources services.Resource
+	store     datastore.DataStore
+	logger    services.Logger
+}
+
+func NewResourceServer(resources services.Resource, store datastore.DataStore, logger services.Logger) *ResourceServer {
+	return &ResourceServer{resources, store, logger}
+}
+
+// resourceETag covers the fields of a resource that are changed through the API
+//
+//	heartbeats from the device would otherwise change it every few minutes
+func resourceETag(r models.Resource) string {
+	r.LastHeartBeat = time.Time{}
+	return etag(r)
+}
+
+func resourceLocation(id string) string {
+	return Prefix + "resources/" + url.PathEscape(id)
+}
+
+// GetResources responds with a page of resources sorted by name, q searches names and addresses
+func (rs *ResourceServer) GetResources(w http.ResponseWriter, req *http.Request) {
+	page, err := readPage(req)
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// names aren't unique, the id breaks ties so the cursor lands on one resource
+	query := models.ResourceQuery{Search: req.URL.Query().Get("q"), Limit: page.limit + 1}
+	query.AfterName, query.AfterID, _ = strings.Cut(page.after, "\x00")
+
+	resources, total, err := rs.store.GetResourcePage(query)
+	if err != nil {
+		problem(w, req, http.StatusInternalServerError, "error reading resources")
+		return
+	}
+
+	items, next := nextPage(resources, func(r models.Resource) string { return strings.ToLower(r.Name) + "\x00" + r.ID }, page)
+	ok(w, models.ResourcePage{Items: items, Total: total, NextCursor: next})
+}
+
+// GetResource responds with a resource, its ETag is needed to change it
+func (rs *ResourceServer) GetResource(w http.ResponseWriter, req *http.Request) {
+	resource, found := rs.resource(w, req)
+	if !found {
+		return
+	}
+
+	w.Header().Set("ETag", resourceETag(resource))
+	ok(w, resource)
+}
+
+// CreateResource registers a resource
+func (rs *ResourceServer) CreateResource(w http.ResponseWriter, req *http.Request) {
+	register, valid := readResource(w, req)
+	if !valid {
+		return
+	}
+
+	r, err := rs.resources.RegisterResource(register.Name, register.Address, register.IsDefault, register.Driver)
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", resourceETag(r))
+	created(w, resourceLocation(r.ID), r)
+}
+
+// UpdateResource changes a resource's name, address, default access and driver
+func (rs *ResourceServer) UpdateResource(w http.ResponseWriter, req *http.Request) {
+	resource, found := rs.resource(w, req)
+	if !found || !ifMatch(w, req, resourceETag(resource)) {
+		return
+	}
+
+	change, valid := readResource(w, req)
+	if !valid {
+		return
+	}
+
+	resource.Name = change.Name
+	resource.Address = change.Address
+	resource.IsDefault = change.IsDefault
+	if len(change.Driver) > 0 {
+		resource.Driver = change.Driver
+	}
+
+	updated, err := rs.resources.UpdateResourceAtVersion(resource, resource.Version)
+	if errors.Is(err, datastore.ErrVersionChanged) {
+		changed(w, req)
+		return
+	}
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("ETag", resourceETag(*updated))
+	ok(w, updated)
+}
+
+// DeleteResource removes a resource
+func (rs *ResourceServer) DeleteResource(w http.ResponseWriter, req *http.Request) {
+	resource, found := rs.resource(w, req)
+	if !found || !ifMatch(w, req, resourceETag(resource)) {
+		return
+	}
+
+	rs.logger.Printf("attempting to delete %s", resource.ID)
+	err := rs.resources.DeleteResourceAtVersion(resource.ID, resource.Version)
+	if errors.Is(err, datastore.ErrVersionChanged) {
+		changed(w, req)
+		return
+	}
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PushACLs sends every resource its access list
+func (rs *ResourceServer) PushACLs(w http.ResponseWriter, req *http.Request) {
+	rs.resources.UpdateResources()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteACLs clears the access list on every resource
+func (rs *ResourceServer) DeleteACLs(w http.ResponseWriter, req *http.Request) {
+	rs.resources.DeleteResourceACL()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GrantAccess lets a member use a resource, optionally only between validFrom and validUntil
+func (rs *ResourceServer) GrantAccess(w http.ResponseWriter, req *http.Request) {
+	resource, found := rs.resource(w, req)
+	if !found {
+		return
+	}
+	member, err := rs.store.GetMemberByEmail(mux.Vars(req)["email"])
+	if err != nil {
+		problem(w, req, http.StatusNotFound, "member not found")
+		return
+	}
+
+	grant := models.ResourceGrant{}
+	if req.ContentLength != 0 {
+		if err := json.NewDecoder(req.Body).Decode(&grant); err != nil {
+			problem(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	if grant.ValidUntil != nil {
+		if grant.ValidUntil.Before(time.Now()) {
+			problem(w, req, http.StatusUnprocessableEntity, "validUntil must be in the future")
+			return
+		}
+		if grant.ValidFrom != nil && !grant.ValidUntil.After(*grant.ValidFrom) {
+			problem(w, req, http.StatusUnprocessableEntity, "validUntil must be after validFrom")
+			return
+		}
+	}
+
+	relations, err := rs.store.AddTemporaryMembersToResource([]string{member.Email}, resource.ID, grant.ValidFrom, grant.ValidUntil)
+	if err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rs.audit(req, models.AuditActionGrant, member.Email, grantDetail(resource.ID, grant))
+
+	// grants that start later are pushed by the scheduler when they begin
+	if grant.ValidFrom == nil || !grant.ValidFrom.After(time.Now()) {
+		member, _ = rs.store.GetMemberByEmail(member.Email)
+		rs.resources.PushOne(member)
+	}
+
+	ok(w, relations)
+}
+
+// RevokeAccess stops a member using a resource
+//
+//	only that resource is updated, the member's fob is taken off it and then anyone
+//	who still has access, like the member through a group, is pushed to it again
+func (rs *ResourceServer) RevokeAccess(w http.ResponseWriter, req *http.Request) {
+	resource, found := rs.resource(w, req)
+	if !found {
+		return
+	}
+	email := mux.Vars(req)["email"]
+
+	if err := rs.store.RemoveUserFromResource(email, resource.ID); err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rs.audit(req, models.AuditActionRevoke, email, "resource "+resource.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+
+	if member, err := rs.store.GetMemberByEmail(email); err == nil {
+		rs.resources.RemoveMember(models.MemberAccess{
+			Email:           member.Email,
+			ResourceID:      resource.ID,
+			ResourceAddress: resource.Address,
+			ResourceName:    resource.Name,
+			Name:            member.Name,
+			RFID:            member.RFID,
+		})
+	}
+	rs.resources.PushResource(resource)
+}
+
+// resource is the resource named in the route, it responds with 404 when there isn't one
+func (rs *ResourceServer) resource(w http.ResponseWriter, req *http.Request) (models.Resource, bool) {
+	resource, err := rs.store.GetResourceByID(mux.Vars(req)["id"])
+	if err != nil {
+		problem(w, req, http.StatusNotFound, "resource not found")
+		return resource, false
+	}
+	return resource, true
+}
+
+func readResource(w http.ResponseWriter, req *http.Request) (models.RegisterResourceRequest, bool) {
+	var resource models.RegisterResourceRequest
+	if err := json.NewDecoder(req.Body).Decode(&resource); err != nil {
+		problem(w, req, http.StatusBadRequest, err.Error())
+		return resource, false
+	}
+
+	if len(resource.Name) == 0 {
+		problem(w, req, http.StatusUnprocessableEntity, "name is required")
+		return resource, false
+	}
+	if len(resource.Driver) > 0 && !models.ValidDriver(resource.Driver) {
+		problem(w, req, http.StatusUnprocessableEntity, "unknown driver")
+		return resource, false
+	}
+
+	return resource, true
+}
+
+func grantDetail(resourceID string, grant models.ResourceGrant) string {
+	detail := "resource " + resourceID
+	if grant.ValidFrom != nil {
+		detail += " from " + grant.ValidFrom.Format(time.RFC3339)
+	}
+	if grant.ValidUntil != nil {
+		detail += " until " + grant.ValidUntil.Format(time.RFC3339)
+	}
+	return detail
+}
+
+// audit records an action in the audit log
+func (rs *ResourceServer) audit(req *http.Request, action string, target string, detail string) {
+	actor := "unknown"
+	if user := auth.User(req); user != nil {
+		actor = user.GetUserName()
+	}
+
+	err := rs.store.LogAuditEvent(models.AuditEntry{
+		Actor:  actor,
+		Action: action,
+		Target: target,
+		Detail: detail,
+	})
+	if err != nil {
+		rs.logger.Errorf("error writing audit log: %s", err)
+	}
+}
@@ -0,0 +1,198 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+type slackNotifier struct{}
+
+func (s slackNotifier) Send(msg string) {}
+
+func resourceRequest(method string, id string, body interface{}, ifMatch string) *http.Request {
+	b, _ := json.Marshal(body)
+	req := httptest.NewRequest(method, resourceLocation(id), bytes.NewReader(b))
+	if len(ifMatch) > 0 {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	return mux.SetURLVars(req, map[string]string{"id": id, "email": "member@test.com"})
+}
+
+func TestUpdateResource(t *testing.T) {
+	in_memory.Resources["v2 door"] = models.Resource{ID: "v2 door", Name: "v2 door", Address: "10.0.0.7", Driver: models.DriverESPRFID}
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "v2 door")
+		delete(in_memory.Resources, "v2 front door")
+	})
+
+	store := &in_memory.In_memory{}
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	server := NewResourceServer(rm, store, logrus.New())
+
+	rr := httptest.NewRecorder()
+	server.GetResource(rr, resourceRequest("GET", "v2 door", nil, ""))
+	tag := rr.Header().Get("ETag")
+	if rr.Code != http.StatusOK || len(tag) == 0 {
+		t.Fatalf("get = %d, etag %q", rr.Code, tag)
+	}
+
+	// a heartbeat isn't an edit
+	door := in_memory.Resources["v2 door"]
+	door.LastHeartBeat = time.Now()
+	in_memory.Resources["v2 door"] = door
+
+	change := models.RegisterResourceRequest{Name: "v2 front door", Address: "10.0.0.8"}
+
+	tests := []struct {
+		name       string
+		change     models.RegisterResourceRequest
+		ifMatch    string
+		wantStatus int
+	}{
+		{"needs If-Match", change, "", http.StatusPreconditionRequired},
+		{"stale ETag", change, `"stale"`, http.StatusPreconditionFailed},
+		{"unknown driver", models.RegisterResourceRequest{Name: "v2 door", Driver: "telnet"}, tag, http.StatusUnprocessableEntity},
+		{"current ETag", change, tag, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.UpdateResource(rr, resourceRequest("PUT", "v2 door", tt.change, tt.ifMatch))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+
+	if r := in_memory.Resources["v2 front door"]; r.Address != "10.0.0.8" || r.Driver != models.DriverESPRFID {
+		t.Errorf("resource = %+v", r)
+	}
+}
+
+func TestGrantAccess(t *testing.T) {
+	in_memory.Resources["v2 lathe"] = models.Resource{ID: "v2 lathe", Name: "v2 lathe"}
+	t.Cleanup(func() { delete(in_memory.Resources, "v2 lathe") })
+
+	store := &in_memory.In_memory{Members: map[string]models.Member{"member@test.com": {Email: "member@test.com"}}}
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	server := NewResourceServer(rm, store, logrus.New())
+
+	past := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name       string
+		id         string
+		grant      models.ResourceGrant
+		wantStatus int
+	}{
+		{"missing resource", "v2 nothing", models.ResourceGrant{}, http.StatusNotFound},
+		{"already expired", "v2 lathe", models.ResourceGrant{ValidUntil: &past}, http.StatusUnprocessableEntity},
+		{"grant", "v2 lathe", models.ResourceGrant{}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			server.GrantAccess(rr, resourceRequest("PUT", tt.id, tt.grant, ""))
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+
+	if len(store.AuditLog) != 1 || store.AuditLog[0].Action != models.AuditActionGrant {
+		t.Errorf("audit log = %+v", store.AuditLog)
+	}
+}
+
+// racingStore lets another change land right after each resource is read
+type racingStore struct {
+	*in_memory.In_memory
+}
+
+func (s racingStore) GetResourceByID(id string) (models.Resource, error) {
+	r, err := s.In_memory.GetResourceByID(id)
+	if err == nil {
+		other := r
+		other.IsDefault = !other.IsDefault
+		s.In_memory.UpdateResource(other)
+	}
+	return r, err
+}
+
+func TestResourceChangedAfterCheck(t *testing.T) {
+	in_memory.Resources["v2 racing door"] = models.Resource{ID: "v2 racing door", Name: "v2 racing door", Address: "10.0.0.7", Driver: models.DriverESPRFID, Version: 1}
+	t.Cleanup(func() { delete(in_memory.Resources, "v2 racing door") })
+
+	store := &in_memory.In_memory{}
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	server := NewResourceServer(rm, racingStore{store}, logrus.New())
+
+	rr := httptest.NewRecorder()
+	server.UpdateResource(rr, resourceRequest("PUT", "v2 racing door", models.RegisterResourceRequest{Name: "v2 racing door", Address: "10.0.0.8"}, "*"))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("update = %d, want 412: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = httptest.NewRecorder()
+	server.DeleteResource(rr, resourceRequest("DELETE", "v2 racing door", nil, "*"))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("delete = %d, want 412: %s", rr.Code, rr.Body.String())
+	}
+
+	if r, ok := in_memory.Resources["v2 racing door"]; !ok || r.Address != "10.0.0.7" {
+		t.Errorf("resource = %+v, want it left as the other changes made it", r)
+	}
+}
+
+// recordingResources notes which devices are updated instead of talking to them
+type recordingResources struct {
+	*resourcemanager.ResourceManager
+	removed    []string
+	pushed     []string
+	updatedAll bool
+}
+
+func (r *recordingResources) RemoveMember(access models.MemberAccess) {
+	r.removed = append(r.removed, access.ResourceID+" "+access.RFID)
+}
+func (r *recordingResources) PushResource(resource models.Resource) {
+	r.pushed = append(r.pushed, resource.ID)
+}
+func (r *recordingResources) UpdateResources() { r.updatedAll = true }
+
+func TestRevokeAccess(t *testing.T) {
+	in_memory.Resources["v2 drill"] = models.Resource{ID: "v2 drill", Name: "v2 drill"}
+	t.Cleanup(func() { delete(in_memory.Resources, "v2 drill") })
+
+	store := &in_memory.In_memory{Members: map[string]models.Member{"member@test.com": {Email: "member@test.com", RFID: "1234"}}}
+	resources := &recordingResources{ResourceManager: resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())}
+	server := NewResourceServer(resources, store, logrus.New())
+
+	rr := httptest.NewRecorder()
+	server.RevokeAccess(rr, resourceRequest("DELETE", "v2 drill", nil, ""))
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204: %s", rr.Code, rr.Body.String())
+	}
+
+	if len(resources.removed) != 1 || resources.removed[0] != "v2 drill 1234" {
+		t.Errorf("removed = %v, want the member's fob taken off the drill", resources.removed)
+	}
+	if len(resources.pushed) != 1 || resources.pushed[0] != "v2 drill" || resources.updatedAll {
+		t.Errorf("pushed = %v, every resource = %v, want only the drill", resources.pushed, resources.updatedAll)
+	}
+}
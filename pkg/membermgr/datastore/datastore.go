@@ -1,23 +1,64 @@
 package datastore
 
 import (
+	"errors"
 	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 )
 
+// ErrAPIKeyNameTaken is returned when an API key is created with the name of another key
+var ErrAPIKeyNameTaken = errors.New("there is already an api key with that name")
+
+// ErrVersionChanged is returned when a change is made at a version that isn't the current one
+var ErrVersionChanged = errors.New("it has changed since it was read")
+
+// ErrUserNotFound is returned when there is no user with that email
+var ErrUserNotFound = errors.New("user not found")
+
+// AnyVersion makes a change at whatever the current version is
+const AnyVersion = 0
+
 type (
 	DataStore interface {
 		AccessEvent
 		MemberStore
 		ResourceStore
+		ResourceGroupStore
+		DoorModeStore
+		OpenWindowStore
+		ScheduleStore
+		CertificationStore
 		CommunicationStore
 		UserStore
 		ReportStore
+		AuditStore
+		OccupancyStore
+		DeviceStore
+		RoleStore
+		HostStore
+		TwoFactorStore
+		APIKeyStore
+		SessionStore
+		RateLimitStore
 	}
 
 	AccessEvent interface {
 		LogAccessEvent(event models.LogMessage) error
+		GetAccessEvents(filter models.AccessEventFilter) (models.AccessEventPage, error)
+		CountAccessBetweenHours(memberID string, startHour int, endHour int, before time.Time) (int, error)
+		RollupAccessEvents(before time.Time) error
+		DeleteAccessEvents(before time.Time) (int64, error)
+		PseudonymiseAccessEvents(before time.Time, key string) (int64, error)
+	}
+
+	DeviceStore interface {
+		AnnounceDevice(announcement models.DeviceAnnouncement) (models.Device, error)
+		GetPendingDevices() ([]models.Device, error)
+		GetDevice(mac string) (models.Device, error)
+		GetResourceDevice(resourceID string) (models.Device, error)
+		ApproveDevice(mac string, name string, isDefault bool) (models.Resource, error)
+		DeleteDevice(mac string) error
 	}
 
 	MemberStore interface {
@@ -32,6 +73,11 @@ type (
 		ProcessMember(newMember models.Member) error
 		GetMemberByRFID(rfid string) (models.Member, error)
 		UpdateMember(update models.Member) error
+		// UpdateMemberAtVersion only updates the member while it's at version, see models.Member.Version
+		UpdateMemberAtVersion(update models.Member, version int) error
+		AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error)
+		// GetMemberPage is a page of the members that match, and how many match across every page
+		GetMemberPage(query models.MemberQuery) ([]models.Member, int, error)
 		UpdateMemberBySubscriptionID(subscriptionID string, update models.Member) error
 		SetMemberLevel(memberId string, level models.MemberLevel) error
 		ApplyMemberCredits()
@@ -43,18 +89,71 @@ type (
 		GetResources() []models.Resource
 		GetResourceByID(ID string) (models.Resource, error)
 		GetResourceByName(resourceName string) (models.Resource, error)
-		RegisterResource(name string, address string, isDefault bool) (models.Resource, error)
+		RegisterResource(name string, address string, isDefault bool, driver string) (models.Resource, error)
 		UpdateResource(res models.Resource) (*models.Resource, error)
 		DeleteResource(id string) error
+		// UpdateResourceAtVersion only updates the resource while it's at version, see models.Resource.Version
+		UpdateResourceAtVersion(res models.Resource, version int) (*models.Resource, error)
+		DeleteResourceAtVersion(id string, version int) error
+		// GetResourcePage is a page of the resources that match, and how many match across every page
+		GetResourcePage(query models.ResourceQuery) ([]models.Resource, int, error)
 		AddMultipleMembersToResource(emails []string, resourceID string) ([]models.MemberResourceRelation, error)
+		AddTemporaryMembersToResource(emails []string, resourceID string, validFrom *time.Time, validUntil *time.Time) ([]models.MemberResourceRelation, error)
 		AddUserToDefaultResources(email string) ([]models.MemberResourceRelation, error)
 		GetMemberResourceRelation(m models.Member, r models.Resource) (models.MemberResourceRelation, error)
 		RemoveUserFromResource(email string, resourceID string) error
 		GetResourceACL(r models.Resource) ([]string, error)
-		GetResourceACLWithMemberInfo(r models.Resource) ([]models.Member, error)
+		GetResourceACLWithMemberInfo(r models.Resource) ([]models.MemberAccess, error)
 		GetMembersAccess(m models.Member) ([]models.MemberAccess, error)
 		GetInactiveMembersByResource() ([]models.MemberAccess, error)
 		GetActiveMembersByResource() ([]models.MemberAccess, error)
+		GetExpiredResourceGrants() ([]models.MemberAccess, error)
+		GetActiveScheduledResourceGrants() ([]models.MemberAccess, error)
+	}
+
+	ResourceGroupStore interface {
+		GetResourceGroups() ([]models.ResourceGroup, error)
+		GetResourceGroup(id string) (models.ResourceGroup, error)
+		AddResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error)
+		UpdateResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error)
+		DeleteResourceGroup(id string) error
+		GetResourceGroupMembers(groupID string) ([]models.Member, error)
+		AddMembersToResourceGroup(emails []string, groupID string) ([]models.MemberResourceRelation, error)
+		RemoveMemberFromResourceGroup(email string, groupID string) ([]models.MemberAccess, error)
+		AddResourceToResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error)
+		RemoveResourceFromResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error)
+	}
+
+	DoorModeStore interface {
+		GetDoorModes() ([]models.DoorMode, error)
+		SetDoorMode(mode models.DoorMode) error
+	}
+
+	OpenWindowStore interface {
+		GetOpenWindows(resourceID string) ([]models.OpenWindow, error)
+		AddOpenWindow(w models.OpenWindow) (models.OpenWindow, error)
+		UpdateOpenWindow(w models.OpenWindow) (models.OpenWindow, error)
+		DeleteOpenWindow(id string) error
+	}
+
+	ScheduleStore interface {
+		GetAccessSchedules(resourceID string) ([]models.AccessSchedule, error)
+		GetAccessSchedule(id string) (models.AccessSchedule, error)
+		AddAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error)
+		UpdateAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error)
+		DeleteAccessSchedule(id string) error
+	}
+
+	CertificationStore interface {
+		GetCertifications() ([]models.Certification, error)
+		AddCertification(c models.Certification) (models.Certification, error)
+		UpdateCertification(c models.Certification) (models.Certification, error)
+		DeleteCertification(id string) error
+		RecordTraining(certificationID string, memberEmail string, trainerEmail string, trainedAt time.Time) (models.MemberCertification, error)
+		GetMemberCertifications(email string) ([]models.MemberCertification, error)
+		GetResourceCertifications(resourceID string) ([]models.Certification, error)
+		SetResourceCertifications(resourceID string, certificationIDs []string) error
+		GetExpiredCertifiedAccess() ([]models.ExpiredCertificationAccess, error)
 	}
 
 	CommunicationStore interface {
@@ -68,6 +167,42 @@ type (
 		GetUser(email string) (models.UserResponse, error)
 		UserSignin(email string, password string) error
 		RegisterUser(creds models.Credentials) error
+		GetUserAccount(email string) (models.UserAccount, error)
+		VerifyUser(email string) error
+		UpdatePassword(creds models.Credentials) error
+	}
+
+	TwoFactorStore interface {
+		GetTwoFactor(email string) (models.TwoFactor, error)
+		SetTwoFactorSecret(email string, secret string) error
+		ApproveTwoFactorEnrollment(email string) error
+		EnableTwoFactor(email string, step int64, recoveryCodeHashes []string) error
+		SetRecoveryCodes(email string, recoveryCodeHashes []string) error
+		DisableTwoFactor(email string) error
+		UseTOTPStep(email string, step int64) (bool, error)
+		UseRecoveryCode(email string, recoveryCodeHash string) (bool, error)
+	}
+
+	APIKeyStore interface {
+		CreateAPIKey(key models.APIKey, keyHash string) (models.APIKey, error)
+		GetAPIKeys() ([]models.APIKey, error)
+		GetAPIKeyByHash(keyHash string) (models.APIKey, error)
+		RevokeAPIKey(id string) (models.APIKey, error)
+	}
+
+	SessionStore interface {
+		CreateSession(session models.Session) (models.Session, error)
+		GetSession(id string) (models.Session, error)
+		GetSessions(email string) ([]models.Session, error)
+		RotateSession(id string, refreshHash string, newRefreshHash string, expiresAt time.Time) (bool, error)
+		RevokeSession(id string) error
+		RevokeSessions(email string) (int64, error)
+	}
+
+	RateLimitStore interface {
+		HitRateLimit(key string, window time.Duration) (int, time.Time, error)
+		GetRateLimit(key string) (int, time.Time, error)
+		ResetRateLimit(key string) error
 	}
 
 	ReportStore interface {
@@ -76,5 +211,32 @@ type (
 		GetMemberCountByMonth(month time.Time) (models.MemberCount, error)
 		GetAccessStats(date time.Time, resourceName string) ([]models.AccessStats, error)
 		GetMemberChurn() (int, error)
+		GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error)
+	}
+
+	OccupancyStore interface {
+		GetPresenceSharing() ([]string, error)
+		SetPresenceSharing(email string, share bool) error
+		GetCertifiedMemberIDs(certificationName string) ([]string, error)
+	}
+
+	RoleStore interface {
+		GetMemberRoles(email string) ([]models.Role, error)
+		SetMemberRoles(email string, roles []models.Role) error
+		GetMembersWithRole(role models.Role) ([]models.Member, error)
+	}
+
+	HostStore interface {
+		GetResourceHosts(resourceID string) ([]string, error)
+		SetResourceHosts(resourceID string, emails []string) error
+		GetResourceGroupHosts(groupID string) ([]string, error)
+		SetResourceGroupHosts(groupID string, emails []string) error
+		GetHostedResources(email string) ([]models.Resource, error)
+		IsResourceHost(email string, resourceID string) (bool, error)
+	}
+
+	AuditStore interface {
+		LogAuditEvent(entry models.AuditEntry) error
+		GetAuditEvents(limit int, offset int) ([]models.AuditEntry, error)
 	}
 )
@@ -24,7 +24,7 @@ func (db *DatabaseStore) LogAccessEvent(logMsg models.LogMessage) error {
 	t := time.Unix(logMsg.EventTime, 0)
 	t.Format(timeLayout)
 
-	commandTag, err := dbPool.Exec(context.Background(), memberDbMethod.insertEvent(), logMsg.Type, t.Format(timeLayout), logMsg.IsKnown, logMsg.Username, logMsg.RFID, logMsg.Door)
+	commandTag, err := dbPool.Exec(context.Background(), memberDbMethod.insertEvent(), logMsg.Type, t.Format(timeLayout), logMsg.IsKnown, logMsg.Username, logMsg.RFID, logMsg.Door, logMsg.MemberID, logMsg.Access)
 	if err != nil {
 		return fmt.Errorf("error insterting event to DB: %v", err)
 	}
@@ -35,3 +35,119 @@ func (db *DatabaseStore) LogAccessEvent(logMsg models.LogMessage) error {
 
 	return nil
 }
+
+// GetAccessEvents returns a page of the access log, most recent first
+func (db *DatabaseStore) GetAccessEvents(filter models.AccessEventFilter) (models.AccessEventPage, error) {
+	page := models.AccessEventPage{Events: []models.AccessEvent{}}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return page, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	query, args := accessEventDbMethod.getAccessEvents(filter)
+
+	rows, err := dbPool.Query(db.ctx, query, args...)
+	if err != nil {
+		return page, fmt.Errorf("error getting access events: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.AccessEvent
+		if err := rows.Scan(&e.ID, &e.Type, &e.EventTime, &e.IsKnown, &e.Username, &e.RFID, &e.Door, &e.MemberID, &e.Pseudonymised, &e.Access); err != nil {
+			return page, err
+		}
+		e.EventTime = localEventTime(e.EventTime)
+		page.Events = append(page.Events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return page, err
+	}
+
+	if filter.Limit > 0 && len(page.Events) == filter.Limit {
+		page.NextCursor = models.CursorFor(page.Events[len(page.Events)-1]).Encode()
+	}
+
+	return page, nil
+}
+
+// CountAccessBetweenHours counts a member's swipes before a time that happened between two hours of the day
+//
+//	the hours wrap past midnight when startHour is after endHour
+func (db *DatabaseStore) CountAccessBetweenHours(memberID string, startHour int, endHour int, before time.Time) (int, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return 0, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var count int
+	err = dbPool.QueryRow(db.ctx, accessEventDbMethod.countAccessBetweenHours(startHour, endHour), memberID, before.Local(), startHour, endHour).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting access events: %v", err)
+	}
+
+	return count, nil
+}
+
+// RollupAccessEvents adds the events before a time to the daily per-resource aggregates
+func (db *DatabaseStore) RollupAccessEvents(before time.Time) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	if _, err := dbPool.Exec(db.ctx, accessEventDbMethod.rollupAccessEvents(), before.Local()); err != nil {
+		return fmt.Errorf("error rolling up access events: %v", err)
+	}
+
+	return nil
+}
+
+// DeleteAccessEvents deletes the events before a time and returns how many were deleted
+func (db *DatabaseStore) DeleteAccessEvents(before time.Time) (int64, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return 0, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, accessEventDbMethod.deleteAccessEvents(), before.Local())
+	if err != nil {
+		return 0, fmt.Errorf("error deleting access events: %v", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// PseudonymiseAccessEvents strips the member from the events before a time and returns how many were changed
+func (db *DatabaseStore) PseudonymiseAccessEvents(before time.Time, key string) (int64, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return 0, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, accessEventDbMethod.pseudonymiseAccessEvents(), before.Local(), key)
+	if err != nil {
+		return 0, fmt.Errorf("error pseudonymising access events: %v", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// localEventTime reads an event_time back as the server's local time,
+// since it's stored without a time zone
+func localEventTime(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
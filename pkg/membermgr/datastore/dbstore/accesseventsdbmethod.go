@@ -1,8 +1,122 @@
 package dbstore
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+var accessEventDbMethod AccessEventDatabaseMethod
+
+// AccessEventDatabaseMethod -- method container that holds the extension methods to query the access log
+type AccessEventDatabaseMethod struct{}
+
 func (member *MemberDatabaseMethod) insertEvent() string {
 	return `INSERT INTO membership.access_events(
-		type, event_time, is_known, username, rfid, door)
-		VALUES ($1, $2, $3, $4, $5, $6);;
+		type, event_time, is_known, username, rfid, door, member_id, access)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, '')::uuid, $8);
 	`
 }
+
+// countAccessBetweenHours counts a member's swipes in a time of day that may wrap past midnight
+func (AccessEventDatabaseMethod) countAccessBetweenHours(startHour int, endHour int) string {
+	hours := "EXTRACT(HOUR FROM event_time) >= $3 AND EXTRACT(HOUR FROM event_time) < $4"
+	if startHour > endHour {
+		hours = "(EXTRACT(HOUR FROM event_time) >= $3 OR EXTRACT(HOUR FROM event_time) < $4)"
+	}
+
+	return `SELECT COUNT(*)
+	FROM membership.access_events
+	WHERE member_id = $1 AND is_known = true AND event_time < $2 AND ` + hours + `;`
+}
+
+// getAccessEvents builds the query for a page of the access log, most recent first
+//
+//	event_time has no time zone and holds the server's local time,
+//	so times are passed in as local wall clock times
+func (AccessEventDatabaseMethod) getAccessEvents(filter models.AccessEventFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(filter.MemberID) > 0 {
+		where("member_id = $%d", filter.MemberID)
+	}
+	if len(filter.RFID) > 0 {
+		where("rfid = $%d", filter.RFID)
+	}
+	if len(filter.Door) > 0 {
+		where("door = $%d", filter.Door)
+	}
+	if filter.IsKnown != nil {
+		where("is_known = $%d", *filter.IsKnown)
+	}
+	if filter.Pseudonymised != nil {
+		where("pseudonymised = $%d", *filter.Pseudonymised)
+	}
+	if filter.From != nil {
+		where("event_time >= $%d", filter.From.Local())
+	}
+	if filter.To != nil {
+		where("event_time < $%d", filter.To.Local())
+	}
+	if filter.After != nil {
+		args = append(args, filter.After.EventTime.Local(), filter.After.ID)
+		conditions = append(conditions, fmt.Sprintf("(event_time, id) < ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	query := `SELECT id, type, event_time, is_known, username, rfid, door, COALESCE(member_id::text, ''), pseudonymised, access
+	FROM membership.access_events`
+
+	if len(conditions) > 0 {
+		query += "\n\tWHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf("\n\tORDER BY event_time DESC, id DESC\n\tLIMIT $%d;", len(args))
+
+	return query, args
+}
+
+// rollupAccessEvents adds the events before a time that haven't been rolled up to the daily aggregates
+//
+//	events are marked as they're counted, so running it twice doesn't count them twice
+//	and events that arrive late are added to the days that were already rolled up.
+//	Only members' swipes are counted, like getAccessStats
+func (AccessEventDatabaseMethod) rollupAccessEvents() string {
+	return `WITH rolled AS (
+		UPDATE membership.access_events
+		SET rolled_up = true
+		WHERE event_time < $1 AND NOT rolled_up
+		RETURNING event_time, door, is_known
+	)
+	INSERT INTO membership.access_event_daily (day, door, access_count)
+	SELECT event_time::date, door, COUNT(*)
+	FROM rolled
+	WHERE is_known
+	GROUP BY event_time::date, door
+	ON CONFLICT (day, door) DO UPDATE
+	SET access_count = membership.access_event_daily.access_count + EXCLUDED.access_count;`
+}
+
+func (AccessEventDatabaseMethod) deleteAccessEvents() string {
+	return `DELETE FROM membership.access_events
+	WHERE event_time < $1;`
+}
+
+// pseudonymiseAccessEvents drops the member from old events
+//
+//	fobs are replaced by an HMAC-SHA256 of the fob, keyed with $2, so that repeat swipes of the same fob still line up
+func (AccessEventDatabaseMethod) pseudonymiseAccessEvents() string {
+	return `UPDATE membership.access_events
+	SET username = '',
+		rfid = CASE WHEN rfid = '' THEN '' ELSE 'anon-' || left(encode(hmac(rfid, $2, 'sha256'), 'hex'), 16) END,
+		member_id = NULL,
+		pseudonymised = true
+	WHERE event_time < $1 AND pseudonymised = false;`
+}
@@ -0,0 +1,133 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// uniqueViolation is the postgres error code for a duplicate key
+const uniqueViolation = "23505"
+
+// CreateAPIKey stores a new API key, only the hash of the key itself is stored
+func (db *DatabaseStore) CreateAPIKey(key models.APIKey, keyHash string) (models.APIKey, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.APIKey{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	var id string
+	err = tx.QueryRow(db.ctx, apiKeyDbMethod.insertAPIKey(), key.Name, key.Prefix, keyHash, key.Scopes, key.CreatedBy, key.ExpiresAt).Scan(&id)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "api_keys_name_key" {
+		return models.APIKey{}, datastore.ErrAPIKeyNameTaken
+	}
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("error inserting api key: %v", err)
+	}
+
+	if len(key.ResourceIDs) > 0 {
+		if _, err := tx.Exec(db.ctx, apiKeyDbMethod.insertAPIKeyResources(), id, key.ResourceIDs); err != nil {
+			return models.APIKey{}, fmt.Errorf("error setting api key resources: %v", err)
+		}
+	}
+
+	created, err := scanAPIKey(tx.QueryRow(db.ctx, apiKeyDbMethod.getAPIKeyByID(), id))
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("getAPIKeyByID failed: %v", err)
+	}
+
+	return created, tx.Commit(db.ctx)
+}
+
+// GetAPIKeys returns every API key, including revoked and expired ones
+func (db *DatabaseStore) GetAPIKeys() ([]models.APIKey, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	keys := []models.APIKey{}
+
+	rows, err := dbPool.Query(db.ctx, apiKeyDbMethod.getAPIKeys())
+	if err != nil {
+		return keys, fmt.Errorf("getAPIKeys failed: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// GetAPIKeyByHash finds the key a request was signed with and records that it was used
+func (db *DatabaseStore) GetAPIKeyByHash(keyHash string) (models.APIKey, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.APIKey{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	key, err := scanAPIKey(dbPool.QueryRow(db.ctx, apiKeyDbMethod.getAPIKeyByHash(), keyHash))
+	if err != nil {
+		return key, fmt.Errorf("getAPIKeyByHash failed: %v", err)
+	}
+
+	return key, nil
+}
+
+// RevokeAPIKey stops a key working, the key is kept so its history can still be seen
+func (db *DatabaseStore) RevokeAPIKey(id string) (models.APIKey, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.APIKey{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tag, err := dbPool.Exec(db.ctx, apiKeyDbMethod.revokeAPIKey(), id)
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("revokeAPIKey failed: %v", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.APIKey{}, fmt.Errorf("revokeAPIKey failed: api key not found")
+	}
+
+	key, err := scanAPIKey(dbPool.QueryRow(db.ctx, apiKeyDbMethod.getAPIKeyByID(), id))
+	if err != nil {
+		return key, fmt.Errorf("getAPIKeyByID failed: %v", err)
+	}
+
+	return key, nil
+}
+
+func scanAPIKey(row pgx.Row) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.ResourceIDs, &key.CreatedBy, &key.CreatedAt, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt)
+	return key, err
+}
@@ -0,0 +1,57 @@
+package dbstore
+
+var apiKeyDbMethod APIKeyDatabaseMethod
+
+// APIKeyDatabaseMethod -- method container that holds the extension methods to query API keys
+type APIKeyDatabaseMethod struct{}
+
+const apiKeyColumns = `id::text, name, prefix, scopes,
+	ARRAY(SELECT resource_id::text FROM membership.api_key_resources WHERE api_key_id = membership.api_keys.id ORDER BY resource_id),
+	created_by, created_at, expires_at, last_used_at, revoked_at`
+
+func (APIKeyDatabaseMethod) getAPIKeys() string {
+	return `SELECT ` + apiKeyColumns + `
+	FROM membership.api_keys
+	ORDER BY created_at DESC;`
+}
+
+func (APIKeyDatabaseMethod) getAPIKeyByID() string {
+	return `SELECT ` + apiKeyColumns + `
+	FROM membership.api_keys
+	WHERE id = $1;`
+}
+
+// getAPIKeyByHash finds a key and records that it was used, in one statement since it runs on every request
+//
+//	last_used_at is only written once a minute, and the key is returned as it was before the write
+func (APIKeyDatabaseMethod) getAPIKeyByHash() string {
+	return `WITH touched AS (
+		UPDATE membership.api_keys
+		SET last_used_at = now()
+		WHERE key_hash = $1 AND revoked_at IS NULL
+			AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+	)
+	SELECT ` + apiKeyColumns + `
+	FROM membership.api_keys
+	WHERE key_hash = $1;`
+}
+
+func (APIKeyDatabaseMethod) insertAPIKey() string {
+	return `INSERT INTO membership.api_keys(
+		name, prefix, key_hash, scopes, created_by, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id::text;`
+}
+
+func (APIKeyDatabaseMethod) insertAPIKeyResources() string {
+	return `INSERT INTO membership.api_key_resources(api_key_id, resource_id)
+	SELECT $1, unnest($2::text[])::uuid
+	ON CONFLICT DO NOTHING;`
+}
+
+// revokeAPIKey keeps the time a key was first revoked
+func (APIKeyDatabaseMethod) revokeAPIKey() string {
+	return `UPDATE membership.api_keys
+	SET revoked_at = COALESCE(revoked_at, now())
+	WHERE id = $1;`
+}
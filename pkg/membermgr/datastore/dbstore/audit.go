@@ -0,0 +1,61 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// LogAuditEvent records a change to the audit log
+func (db *DatabaseStore) LogAuditEvent(entry models.AuditEntry) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, auditDbMethod.insertAuditEntry(), entry.Actor, entry.Action, entry.Target, entry.Detail)
+	if err != nil {
+		return fmt.Errorf("error inserting audit entry: %v", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("no row affected")
+	}
+
+	return nil
+}
+
+// GetAuditEvents returns the audit log, most recent first
+func (db *DatabaseStore) GetAuditEvents(limit int, offset int) ([]models.AuditEntry, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var entries []models.AuditEntry
+
+	rows, err := dbPool.Query(db.ctx, auditDbMethod.getAuditEntries(), limit, offset)
+	if err != nil {
+		return entries, fmt.Errorf("error getting audit log: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &e.Actor, &e.Action, &e.Target, &e.Detail); err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, nil
+}
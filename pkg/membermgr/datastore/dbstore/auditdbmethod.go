@@ -0,0 +1,19 @@
+package dbstore
+
+var auditDbMethod AuditDatabaseMethod
+
+// AuditDatabaseMethod -- method container that holds the extension methods to query the audit log
+type AuditDatabaseMethod struct{}
+
+func (AuditDatabaseMethod) insertAuditEntry() string {
+	return `INSERT INTO membership.audit_log(
+		actor, action, target, detail)
+		VALUES ($1, $2, $3, $4);`
+}
+
+func (AuditDatabaseMethod) getAuditEntries() string {
+	return `SELECT id, created_at, actor, action, target, detail
+	FROM membership.audit_log
+	ORDER BY created_at DESC, id DESC
+	LIMIT $1 OFFSET $2;`
+}
@@ -0,0 +1,295 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetCertifications returns every certification
+func (db *DatabaseStore) GetCertifications() ([]models.Certification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var certifications []models.Certification
+
+	rows, err := dbPool.Query(db.ctx, certificationDbMethod.getCertifications())
+	if err != nil {
+		return certifications, fmt.Errorf("getCertifications failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Certification
+		err = rows.Scan(&c.ID, &c.Name, &c.Description, &c.ValidForDays)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		certifications = append(certifications, c)
+	}
+
+	return certifications, nil
+}
+
+// AddCertification stores a new certification
+func (db *DatabaseStore) AddCertification(c models.Certification) (models.Certification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var certification models.Certification
+
+	err = dbPool.QueryRow(db.ctx, certificationDbMethod.insertCertification(), c.Name, c.Description, c.ValidForDays).Scan(&certification.ID, &certification.Name, &certification.Description, &certification.ValidForDays)
+	if err != nil {
+		return certification, fmt.Errorf("error inserting certification: %v", err)
+	}
+
+	return certification, nil
+}
+
+// UpdateCertification updates an existing certification
+func (db *DatabaseStore) UpdateCertification(c models.Certification) (models.Certification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var certification models.Certification
+
+	if len(c.ID) == 0 {
+		return certification, errors.New("invalid certification id")
+	}
+
+	err = dbPool.QueryRow(db.ctx, certificationDbMethod.updateCertification(), c.ID, c.Name, c.Description, c.ValidForDays).Scan(&certification.ID, &certification.Name, &certification.Description, &certification.ValidForDays)
+	if err != nil {
+		return certification, fmt.Errorf("error updating certification: %v", err)
+	}
+
+	return certification, nil
+}
+
+// DeleteCertification removes a certification and every training record for it
+func (db *DatabaseStore) DeleteCertification(id string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, certificationDbMethod.deleteCertification(), id)
+	if err != nil {
+		return fmt.Errorf("deleteCertification failed: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return errors.New("no row affected")
+	}
+
+	return nil
+}
+
+// RecordTraining stores that a trainer certified a member
+//
+//	the expiry is calculated from the certification's renewal period
+func (db *DatabaseStore) RecordTraining(certificationID string, memberEmail string, trainerEmail string, trainedAt time.Time) (models.MemberCertification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var mc models.MemberCertification
+
+	member, err := db.GetMemberByEmail(memberEmail)
+	if err != nil {
+		return mc, err
+	}
+
+	var c models.Certification
+	err = dbPool.QueryRow(db.ctx, certificationDbMethod.getCertificationByID(), certificationID).Scan(&c.ID, &c.Name, &c.Description, &c.ValidForDays)
+	if err != nil {
+		return mc, fmt.Errorf("error getting certification: %v", err)
+	}
+
+	if trainedAt.IsZero() {
+		trainedAt = time.Now()
+	}
+
+	mc = models.MemberCertification{
+		MemberID:          member.ID,
+		CertificationID:   c.ID,
+		CertificationName: c.Name,
+		TrainerEmail:      trainerEmail,
+		TrainedAt:         trainedAt,
+	}
+
+	if c.ValidForDays > 0 {
+		expiresAt := trainedAt.AddDate(0, 0, c.ValidForDays)
+		mc.ExpiresAt = &expiresAt
+	}
+
+	err = dbPool.QueryRow(db.ctx, certificationDbMethod.insertMemberCertification(), mc.MemberID, mc.CertificationID, mc.TrainerEmail, mc.TrainedAt, mc.ExpiresAt).Scan(&mc.ID)
+	if err != nil {
+		return mc, fmt.Errorf("error recording training: %v", err)
+	}
+
+	return mc, nil
+}
+
+// GetMemberCertifications returns every training a member has received
+func (db *DatabaseStore) GetMemberCertifications(email string) ([]models.MemberCertification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var certifications []models.MemberCertification
+
+	rows, err := dbPool.Query(db.ctx, certificationDbMethod.getMemberCertifications(), email)
+	if err != nil {
+		return certifications, fmt.Errorf("getMemberCertifications failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var mc models.MemberCertification
+		err = rows.Scan(&mc.ID, &mc.MemberID, &mc.CertificationID, &mc.CertificationName, &mc.TrainerEmail, &mc.TrainedAt, &mc.ExpiresAt)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		certifications = append(certifications, mc)
+	}
+
+	return certifications, nil
+}
+
+// GetResourceCertifications returns the certifications a resource requires
+func (db *DatabaseStore) GetResourceCertifications(resourceID string) ([]models.Certification, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var certifications []models.Certification
+
+	rows, err := dbPool.Query(db.ctx, certificationDbMethod.getResourceCertifications(), resourceID)
+	if err != nil {
+		return certifications, fmt.Errorf("getResourceCertifications failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var c models.Certification
+		err = rows.Scan(&c.ID, &c.Name, &c.Description, &c.ValidForDays)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		certifications = append(certifications, c)
+	}
+
+	return certifications, nil
+}
+
+// SetResourceCertifications replaces the certifications a resource requires
+func (db *DatabaseStore) SetResourceCertifications(resourceID string, certificationIDs []string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	_, err = tx.Exec(db.ctx, certificationDbMethod.deleteResourceCertifications(), resourceID)
+	if err != nil {
+		return fmt.Errorf("error clearing resource certifications: %v", err)
+	}
+
+	if len(certificationIDs) > 0 {
+		_, err = tx.Exec(db.ctx, certificationDbMethod.insertResourceCertifications(), resourceID, certificationIDs)
+		if err != nil {
+			return fmt.Errorf("error setting resource certifications: %v", err)
+		}
+	}
+
+	return tx.Commit(db.ctx)
+}
+
+// GetExpiredCertifiedAccess finds members whose certification for a resource has expired
+func (db *DatabaseStore) GetExpiredCertifiedAccess() ([]models.ExpiredCertificationAccess, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var expired []models.ExpiredCertificationAccess
+
+	rows, err := dbPool.Query(db.ctx, certificationDbMethod.getExpiredCertifiedAccess())
+	if err != nil {
+		return expired, fmt.Errorf("getExpiredCertifiedAccess failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var e models.ExpiredCertificationAccess
+		err = rows.Scan(&e.Email, &e.ResourceID, &e.ResourceAddress, &e.ResourceName, &e.Name, &e.RFID, &e.Level, &e.CertificationName)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		expired = append(expired, e)
+	}
+
+	return expired, nil
+}
+
+// missingCertifications returns the names of the certifications a resource requires that the member doesn't hold
+func (db *DatabaseStore) missingCertifications(dbPool *pgxpool.Pool, memberID string, resourceID string) ([]string, error) {
+	var missing []string
+
+	rows, err := dbPool.Query(db.ctx, certificationDbMethod.getMissingCertifications(), memberID, resourceID)
+	if err != nil {
+		return missing, fmt.Errorf("error checking certifications: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return missing, err
+		}
+		missing = append(missing, name)
+	}
+
+	return missing, nil
+}
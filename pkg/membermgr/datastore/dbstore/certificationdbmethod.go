@@ -0,0 +1,122 @@
+package dbstore
+
+var certificationDbMethod CertificationDatabaseMethod
+
+// CertificationDatabaseMethod -- method container that holds the extension methods to query the certification tables
+type CertificationDatabaseMethod struct{}
+
+func (CertificationDatabaseMethod) getCertifications() string {
+	return `SELECT id, name, description, valid_for_days
+	FROM membership.certifications
+	ORDER BY name;`
+}
+
+func (CertificationDatabaseMethod) getCertificationByID() string {
+	return `SELECT id, name, description, valid_for_days
+	FROM membership.certifications
+	WHERE id = $1;`
+}
+
+func (CertificationDatabaseMethod) insertCertification() string {
+	return `INSERT INTO membership.certifications(
+		name, description, valid_for_days)
+		VALUES ($1, $2, $3)
+		RETURNING id, name, description, valid_for_days;`
+}
+
+func (CertificationDatabaseMethod) updateCertification() string {
+	return `UPDATE membership.certifications
+	SET name=$2, description=$3, valid_for_days=$4
+	WHERE id=$1
+	RETURNING id, name, description, valid_for_days;`
+}
+
+func (CertificationDatabaseMethod) deleteCertification() string {
+	return `DELETE FROM membership.certifications
+	WHERE id = $1;`
+}
+
+func (CertificationDatabaseMethod) insertMemberCertification() string {
+	return `INSERT INTO membership.member_certifications(
+		member_id, certification_id, trainer_email, trained_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;`
+}
+
+func (CertificationDatabaseMethod) getMemberCertifications() string {
+	return `SELECT mc.id, mc.member_id, mc.certification_id, c.name, mc.trainer_email, mc.trained_at, mc.expires_at
+	FROM membership.member_certifications mc
+	JOIN membership.certifications c
+	ON c.id = mc.certification_id
+	JOIN membership.members m
+	ON m.id = mc.member_id
+	WHERE LOWER(m.email) = LOWER($1)
+	ORDER BY mc.trained_at DESC;`
+}
+
+func (CertificationDatabaseMethod) getResourceCertifications() string {
+	return `SELECT c.id, c.name, c.description, c.valid_for_days
+	FROM membership.resource_certifications rc
+	JOIN membership.certifications c
+	ON c.id = rc.certification_id
+	WHERE rc.resource_id = $1
+	ORDER BY c.name;`
+}
+
+func (CertificationDatabaseMethod) deleteResourceCertifications() string {
+	return `DELETE FROM membership.resource_certifications
+	WHERE resource_id = $1;`
+}
+
+func (CertificationDatabaseMethod) insertResourceCertifications() string {
+	return `INSERT INTO membership.resource_certifications(resource_id, certification_id)
+	SELECT $1, unnest($2::uuid[])
+	ON CONFLICT DO NOTHING;`
+}
+
+// getMissingCertifications returns the names of the certifications a resource requires
+// that a member does not currently hold
+func (CertificationDatabaseMethod) getMissingCertifications() string {
+	return `SELECT c.name
+	FROM membership.resource_certifications rc
+	JOIN membership.certifications c
+	ON c.id = rc.certification_id
+	WHERE rc.resource_id = $2
+	AND NOT EXISTS (
+		SELECT 1 FROM membership.member_certifications mc
+		WHERE mc.member_id = $1
+		AND mc.certification_id = rc.certification_id
+		AND (mc.expires_at IS NULL OR mc.expires_at > NOW())
+	)
+	ORDER BY c.name;`
+}
+
+// getExpiredCertifiedAccess finds members that still have access to a resource
+// even though every certification they held for it has expired
+//
+//	there's one row per member and resource, with the lapsed certifications' names joined together
+func (CertificationDatabaseMethod) getExpiredCertifiedAccess() string {
+	return `SELECT m.email, r.id, r.device_identifier, r.description, m.name, COALESCE(m.rfid, ''), m.member_tier_id, string_agg(DISTINCT c.name, ', ' ORDER BY c.name)
+	FROM membership.member_resource mr
+	JOIN membership.members m
+	ON m.id = mr.member_id
+	JOIN membership.resources r
+	ON r.id = mr.resource_id
+	JOIN membership.resource_certifications rc
+	ON rc.resource_id = mr.resource_id
+	JOIN membership.certifications c
+	ON c.id = rc.certification_id
+	WHERE EXISTS (
+		SELECT 1 FROM membership.member_certifications mc
+		WHERE mc.member_id = mr.member_id
+		AND mc.certification_id = rc.certification_id
+		AND mc.expires_at <= NOW()
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM membership.member_certifications mc
+		WHERE mc.member_id = mr.member_id
+		AND mc.certification_id = rc.certification_id
+		AND (mc.expires_at IS NULL OR mc.expires_at > NOW())
+	)
+	GROUP BY m.email, r.id, r.device_identifier, r.description, m.name, m.rfid, m.member_tier_id;`
+}
@@ -2,6 +2,7 @@ package dbstore
 
 import (
 	"context"
+	"strings"
 
 	config "github.com/HackRVA/memberserver/configs"
 )
@@ -21,3 +22,22 @@ func Setup() (*DatabaseStore, error) {
 
 	return db, nil
 }
+
+// searchPattern is an ILIKE pattern for text that has the letters of search in order,
+// or empty when there's nothing to search for
+func searchPattern(search string) string {
+	if len(search) == 0 {
+		return ""
+	}
+
+	var pattern strings.Builder
+	pattern.WriteString("%")
+	for _, r := range search {
+		if r == '%' || r == '_' || r == '\\' {
+			pattern.WriteRune('\\')
+		}
+		pattern.WriteRune(r)
+		pattern.WriteString("%")
+	}
+	return pattern.String()
+}
@@ -0,0 +1,177 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// AnnounceDevice records a device that announced itself on the discovery topic
+func (db *DatabaseStore) AnnounceDevice(announcement models.DeviceAnnouncement) (models.Device, error) {
+	var d models.Device
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return d, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, deviceDbMethod.announceDevice(), announcement.MAC, announcement.Model, announcement.Firmware, announcement.Address).
+		Scan(&d.MAC, &d.Model, &d.Firmware, &d.Address, &d.ResourceID, &d.FirstSeen, &d.LastSeen)
+	if err != nil {
+		return d, fmt.Errorf("announceDevice failed: %v", err)
+	}
+
+	return d, nil
+}
+
+// GetPendingDevices returns the devices that are waiting to be approved
+func (db *DatabaseStore) GetPendingDevices() ([]models.Device, error) {
+	devices := []models.Device{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return devices, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, deviceDbMethod.getPendingDevices())
+	if err != nil {
+		return devices, fmt.Errorf("getPendingDevices failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var d models.Device
+		err = rows.Scan(&d.MAC, &d.Model, &d.Firmware, &d.Address, &d.ResourceID, &d.FirstSeen, &d.LastSeen)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		devices = append(devices, d)
+	}
+
+	return devices, nil
+}
+
+// GetDevice looks up a device by its mac address
+func (db *DatabaseStore) GetDevice(mac string) (models.Device, error) {
+	var d models.Device
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return d, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, deviceDbMethod.getDevice(), mac).
+		Scan(&d.MAC, &d.Model, &d.Firmware, &d.Address, &d.ResourceID, &d.FirstSeen, &d.LastSeen)
+	if err != nil {
+		return d, fmt.Errorf("getDevice failed: %v", err)
+	}
+
+	return d, nil
+}
+
+// GetResourceDevice looks up the device a resource was approved from
+//
+//	resources that weren't discovered get an empty device
+func (db *DatabaseStore) GetResourceDevice(resourceID string) (models.Device, error) {
+	var d models.Device
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return d, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, deviceDbMethod.getResourceDevice(), resourceID).
+		Scan(&d.MAC, &d.Model, &d.Firmware, &d.Address, &d.ResourceID, &d.FirstSeen, &d.LastSeen)
+	if err == pgx.ErrNoRows {
+		return models.Device{}, nil
+	}
+	if err != nil {
+		return d, fmt.Errorf("getResourceDevice failed: %v", err)
+	}
+
+	return d, nil
+}
+
+// ApproveDevice registers a pending device as a resource
+//
+//	the resource is only added if the device is still pending, so a device can't be approved twice
+func (db *DatabaseStore) ApproveDevice(mac string, name string, isDefault bool) (models.Resource, error) {
+	r := models.Resource{Name: name, IsDefault: isDefault, Driver: models.DriverESPRFID}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return r, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return r, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	var d models.Device
+	err = tx.QueryRow(db.ctx, deviceDbMethod.getDevice(), mac).
+		Scan(&d.MAC, &d.Model, &d.Firmware, &d.Address, &d.ResourceID, &d.FirstSeen, &d.LastSeen)
+	if err != nil {
+		return r, fmt.Errorf("getDevice failed: %v", err)
+	}
+	r.Address = d.Address
+
+	err = tx.QueryRow(db.ctx, resourceDbMethod.insertResource(), r.Name, r.Address, r.IsDefault, r.Driver).Scan(&r.ID, &r.Driver)
+	if err != nil {
+		return r, fmt.Errorf("error inserting resource: %s", err.Error())
+	}
+
+	commandTag, err := tx.Exec(db.ctx, deviceDbMethod.approveDevice(), mac, r.ID)
+	if err != nil {
+		return r, fmt.Errorf("approveDevice failed: %v", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return r, fmt.Errorf("device %s has already been approved", mac)
+	}
+
+	if err := tx.Commit(db.ctx); err != nil {
+		return r, fmt.Errorf("error committing transaction: %v", err)
+	}
+
+	return r, nil
+}
+
+// DeleteDevice forgets a device, if it announces itself again it will be pending again
+func (db *DatabaseStore) DeleteDevice(mac string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, deviceDbMethod.deleteDevice(), mac)
+	if err != nil {
+		return fmt.Errorf("deleteDevice failed: %v", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("device not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package dbstore
+
+var deviceDbMethod DeviceDatabaseMethod
+
+// DeviceDatabaseMethod -- method container that holds the extension methods to query the devices table
+type DeviceDatabaseMethod struct{}
+
+// announceDevice adds a device the first time it's seen and keeps its details up to date after that
+func (DeviceDatabaseMethod) announceDevice() string {
+	return `INSERT INTO membership.devices(
+		mac_address, model, firmware, address)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (mac_address) DO UPDATE
+		SET model = EXCLUDED.model, firmware = EXCLUDED.firmware, address = EXCLUDED.address, last_seen = now()
+		RETURNING mac_address, model, firmware, address, COALESCE(resource_id::text, ''), first_seen, last_seen;`
+}
+
+func (DeviceDatabaseMethod) getPendingDevices() string {
+	return `SELECT mac_address, model, firmware, address, '', first_seen, last_seen
+	FROM membership.devices
+	WHERE resource_id IS NULL
+	ORDER BY last_seen DESC;`
+}
+
+func (DeviceDatabaseMethod) getDevice() string {
+	return `SELECT mac_address, model, firmware, address, COALESCE(resource_id::text, ''), first_seen, last_seen
+	FROM membership.devices
+	WHERE mac_address = $1;`
+}
+
+func (DeviceDatabaseMethod) getResourceDevice() string {
+	return `SELECT mac_address, model, firmware, address, COALESCE(resource_id::text, ''), first_seen, last_seen
+	FROM membership.devices
+	WHERE resource_id = $1;`
+}
+
+// approveDevice links a pending device to the resource it was approved as
+func (DeviceDatabaseMethod) approveDevice() string {
+	return `UPDATE membership.devices
+	SET resource_id = $2
+	WHERE mac_address = $1 AND resource_id IS NULL;`
+}
+
+func (DeviceDatabaseMethod) deleteDevice() string {
+	return `DELETE FROM membership.devices
+	WHERE mac_address = $1;`
+}
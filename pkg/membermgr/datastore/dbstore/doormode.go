@@ -0,0 +1,68 @@
+package dbstore
+
+import (
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetDoorModes returns the lockdowns and hold-opens that are in effect
+func (db *DatabaseStore) GetDoorModes() ([]models.DoorMode, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var modes []models.DoorMode
+
+	rows, err := dbPool.Query(db.ctx, doorModeDbMethod.getDoorModes())
+	if err != nil {
+		return modes, fmt.Errorf("getDoorModes failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.DoorMode
+		if err := rows.Scan(&m.ResourceID, &m.Mode, &m.Reason, &m.SetBy, &m.SetAt); err != nil {
+			return modes, err
+		}
+		modes = append(modes, m)
+	}
+
+	return modes, nil
+}
+
+// SetDoorMode stores the mode for a resource, or for every resource if ResourceID is empty
+//
+//	setting a resource back to normal removes its mode
+func (db *DatabaseStore) SetDoorMode(mode models.DoorMode) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var resourceID *string
+	if len(mode.ResourceID) > 0 {
+		resourceID = &mode.ResourceID
+	}
+
+	if mode.Mode == models.DoorModeNormal {
+		_, err = dbPool.Exec(db.ctx, doorModeDbMethod.deleteDoorMode(), resourceID)
+	} else {
+		_, err = dbPool.Exec(db.ctx, doorModeDbMethod.upsertDoorMode(), resourceID, string(mode.Mode), mode.Reason, mode.SetBy)
+	}
+
+	if err != nil {
+		return fmt.Errorf("error setting door mode: %v", err)
+	}
+
+	return nil
+}
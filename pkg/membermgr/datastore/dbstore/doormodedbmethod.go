@@ -0,0 +1,25 @@
+package dbstore
+
+var doorModeDbMethod DoorModeDatabaseMethod
+
+// DoorModeDatabaseMethod -- method container that holds the extension methods to query the door_modes table
+type DoorModeDatabaseMethod struct{}
+
+func (DoorModeDatabaseMethod) getDoorModes() string {
+	return `SELECT COALESCE(resource_id::text, ''), mode, reason, set_by, set_at
+	FROM membership.door_modes
+	ORDER BY set_at;`
+}
+
+func (DoorModeDatabaseMethod) upsertDoorMode() string {
+	return `INSERT INTO membership.door_modes(
+		resource_id, mode, reason, set_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT ((COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::uuid)))
+		DO UPDATE SET mode = EXCLUDED.mode, reason = EXCLUDED.reason, set_by = EXCLUDED.set_by, set_at = NOW();`
+}
+
+func (DoorModeDatabaseMethod) deleteDoorMode() string {
+	return `DELETE FROM membership.door_modes
+	WHERE resource_id IS NOT DISTINCT FROM $1;`
+}
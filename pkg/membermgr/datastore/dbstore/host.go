@@ -0,0 +1,133 @@
+package dbstore
+
+import (
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetResourceHosts returns the emails of the members who host a resource
+func (db *DatabaseStore) GetResourceHosts(resourceID string) ([]string, error) {
+	return db.getHosts(hostDbMethod.getResourceHosts(), resourceID)
+}
+
+// SetResourceHosts replaces the hosts of a resource
+func (db *DatabaseStore) SetResourceHosts(resourceID string, emails []string) error {
+	return db.setHosts(hostDbMethod.deleteResourceHosts(), hostDbMethod.insertResourceHosts(), resourceID, emails)
+}
+
+// GetResourceGroupHosts returns the emails of the members who host a resource group
+func (db *DatabaseStore) GetResourceGroupHosts(groupID string) ([]string, error) {
+	return db.getHosts(hostDbMethod.getResourceGroupHosts(), groupID)
+}
+
+// SetResourceGroupHosts replaces the hosts of a resource group
+func (db *DatabaseStore) SetResourceGroupHosts(groupID string, emails []string) error {
+	return db.setHosts(hostDbMethod.deleteResourceGroupHosts(), hostDbMethod.insertResourceGroupHosts(), groupID, emails)
+}
+
+// GetHostedResources returns the resources a member hosts, directly or through a resource group
+func (db *DatabaseStore) GetHostedResources(email string) ([]models.Resource, error) {
+	resources := []models.Resource{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return resources, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, hostDbMethod.getHostedResources(), email)
+	if err != nil {
+		return resources, fmt.Errorf("getHostedResources failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var r models.Resource
+		if err := rows.Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver); err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// IsResourceHost reports whether a member hosts a resource, directly or through a resource group
+func (db *DatabaseStore) IsResourceHost(email string, resourceID string) (bool, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return false, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var isHost bool
+	if err := dbPool.QueryRow(db.ctx, hostDbMethod.isResourceHost(), email, resourceID).Scan(&isHost); err != nil {
+		return false, fmt.Errorf("isResourceHost failed: %v", err)
+	}
+
+	return isHost, nil
+}
+
+func (db *DatabaseStore) getHosts(query string, id string) ([]string, error) {
+	emails := []string{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return emails, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, query, id)
+	if err != nil {
+		return emails, fmt.Errorf("getHosts failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		emails = append(emails, email)
+	}
+
+	return emails, nil
+}
+
+func (db *DatabaseStore) setHosts(deleteQuery string, insertQuery string, id string, emails []string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	if _, err := tx.Exec(db.ctx, deleteQuery, id); err != nil {
+		return fmt.Errorf("error clearing hosts: %v", err)
+	}
+
+	if len(emails) > 0 {
+		if _, err := tx.Exec(db.ctx, insertQuery, id, emails); err != nil {
+			return fmt.Errorf("error setting hosts: %v", err)
+		}
+	}
+
+	return tx.Commit(db.ctx)
+}
@@ -0,0 +1,68 @@
+package dbstore
+
+var hostDbMethod HostDatabaseMethod
+
+// HostDatabaseMethod -- method container that holds the extension methods to query the resource host tables
+type HostDatabaseMethod struct{}
+
+func (HostDatabaseMethod) getResourceHosts() string {
+	return `SELECT email
+	FROM membership.resource_hosts
+	JOIN membership.members ON membership.members.id = membership.resource_hosts.member_id
+	WHERE resource_id = $1
+	ORDER BY email;`
+}
+
+func (HostDatabaseMethod) deleteResourceHosts() string {
+	return `DELETE FROM membership.resource_hosts WHERE resource_id = $1;`
+}
+
+func (HostDatabaseMethod) insertResourceHosts() string {
+	return `INSERT INTO membership.resource_hosts(member_id, resource_id)
+	SELECT id, $1 FROM membership.members WHERE email = ANY($2::citext[])
+	ON CONFLICT DO NOTHING;`
+}
+
+func (HostDatabaseMethod) getResourceGroupHosts() string {
+	return `SELECT email
+	FROM membership.resource_group_hosts
+	JOIN membership.members ON membership.members.id = membership.resource_group_hosts.member_id
+	WHERE group_id = $1
+	ORDER BY email;`
+}
+
+func (HostDatabaseMethod) deleteResourceGroupHosts() string {
+	return `DELETE FROM membership.resource_group_hosts WHERE group_id = $1;`
+}
+
+func (HostDatabaseMethod) insertResourceGroupHosts() string {
+	return `INSERT INTO membership.resource_group_hosts(member_id, group_id)
+	SELECT id, $1 FROM membership.members WHERE email = ANY($2::citext[])
+	ON CONFLICT DO NOTHING;`
+}
+
+// hostedResourceIDs is every resource a member hosts, directly or through a resource group
+const hostedResourceIDs = `SELECT resource_id
+	FROM membership.resource_hosts
+	JOIN membership.members ON membership.members.id = membership.resource_hosts.member_id
+	WHERE email = $1
+	UNION
+	SELECT resource_id
+	FROM membership.resource_group_hosts
+	JOIN membership.members ON membership.members.id = membership.resource_group_hosts.member_id
+	JOIN membership.resource_group_resources ON membership.resource_group_resources.group_id = membership.resource_group_hosts.group_id
+	WHERE email = $1`
+
+func (HostDatabaseMethod) getHostedResources() string {
+	return `SELECT id, description, device_identifier, is_default, supports_time_windows, driver
+	FROM membership.resources
+	WHERE id IN (` + hostedResourceIDs + `)
+	ORDER BY description;`
+}
+
+func (HostDatabaseMethod) isResourceHost() string {
+	return `SELECT EXISTS (
+		SELECT 1 FROM (` + hostedResourceIDs + `) hosted
+		WHERE hosted.resource_id::text = $2
+	);`
+}
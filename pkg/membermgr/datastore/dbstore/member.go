@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/logger"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
@@ -123,6 +124,56 @@ func (db *DatabaseStore) GetMembers() []models.Member {
 	return members
 }
 
+// GetMemberPage reads one page of members, sorted by email
+func (db *DatabaseStore) GetMemberPage(query models.MemberQuery) ([]models.Member, int, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	pattern := searchPattern(query.Search)
+
+	var total int
+	if err := dbPool.QueryRow(db.ctx, memberDbMethod.countMemberPage(), pattern, query.Active).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("GetMemberPage failed: %w", err)
+	}
+
+	rows, err := dbPool.Query(db.ctx, memberDbMethod.getMemberPage(), pattern, query.Active, query.After, query.Limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetMemberPage failed: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.Member{}
+	resourceMemo := make(map[string]models.MemberResource)
+
+	for rows.Next() {
+		var rIDs []string
+		var member models.Member
+		if err := rows.Scan(&member.ID, &member.Name, &member.Email, &member.RFID, &member.Level, &rIDs, &member.SubscriptionID, &member.Version); err != nil {
+			return nil, 0, fmt.Errorf("GetMemberPage failed: %w", err)
+		}
+
+		for _, rID := range rIDs {
+			if _, exist := resourceMemo[rID]; !exist {
+				resource, err := db.GetResourceByID(rID)
+				if err != nil {
+					logger.Errorf("error getting resource by id in memberResource lookup: %s %s\n", err.Error(), rID)
+					continue
+				}
+				resourceMemo[rID] = models.MemberResource{ResourceID: resource.ID, Name: resource.Name}
+			}
+
+			member.Resources = append(member.Resources, resourceMemo[rID])
+		}
+
+		members = append(members, member)
+	}
+
+	return members, total, rows.Err()
+}
+
 func (db *DatabaseStore) getMemberBySubscriptionID(subscriptionID string) (models.Member, error) {
 	for _, m := range db.GetMembers() {
 		if m.SubscriptionID == subscriptionID {
@@ -144,7 +195,7 @@ func (db *DatabaseStore) GetMemberByEmail(memberEmail string) (models.Member, er
 	var member models.Member
 	var rIDs []string
 
-	err = dbPool.QueryRow(context.Background(), memberDbMethod.getMemberByEmail(), memberEmail).Scan(&member.ID, &member.Name, &member.Email, &member.RFID, &member.Level, &rIDs)
+	err = dbPool.QueryRow(context.Background(), memberDbMethod.getMemberByEmail(), memberEmail).Scan(&member.ID, &member.Name, &member.Email, &member.RFID, &member.Level, &rIDs, &member.Version)
 	if err == pgx.ErrNoRows {
 		return member, err
 	}
@@ -201,6 +252,10 @@ func (db *DatabaseStore) GetMemberByRFID(rfid string) (models.Member, error) {
 }
 
 func (db *DatabaseStore) AssignRFID(email string, rfid string) (models.Member, error) {
+	return db.AssignRFIDAtVersion(email, rfid, datastore.AnyVersion)
+}
+
+func (db *DatabaseStore) AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
@@ -213,7 +268,10 @@ func (db *DatabaseStore) AssignRFID(email string, rfid string) (models.Member, e
 		return member, err
 	}
 
-	err = dbPool.QueryRow(context.Background(), memberDbMethod.setMemberRFIDTag(), email, encodeRFID(rfid)).Scan(&member.RFID)
+	err = dbPool.QueryRow(context.Background(), memberDbMethod.setMemberRFIDTag(), email, encodeRFID(rfid), version).Scan(&member.RFID)
+	if err == pgx.ErrNoRows && version != datastore.AnyVersion {
+		return member, datastore.ErrVersionChanged
+	}
 	if err != nil {
 		return member, fmt.Errorf("AssignRFID failed: %v", err)
 	}
@@ -222,6 +280,10 @@ func (db *DatabaseStore) AssignRFID(email string, rfid string) (models.Member, e
 }
 
 func (db *DatabaseStore) UpdateMember(update models.Member) error {
+	return db.UpdateMemberAtVersion(update, datastore.AnyVersion)
+}
+
+func (db *DatabaseStore) UpdateMemberAtVersion(update models.Member, version int) error {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
@@ -239,11 +301,14 @@ func (db *DatabaseStore) UpdateMember(update models.Member) error {
 		subID = update.SubscriptionID
 	}
 
-	commandTag, err := dbPool.Exec(context.Background(), memberDbMethod.updateMemberByEmail(), update.Name, subID, member.Email)
+	commandTag, err := dbPool.Exec(context.Background(), memberDbMethod.updateMemberByEmail(), update.Name, subID, member.Email, version)
 	if err != nil {
 		return fmt.Errorf("UpdateMemberByEmail failed: %v", err)
 	}
 
+	if commandTag.RowsAffected() == 0 && version != datastore.AnyVersion {
+		return datastore.ErrVersionChanged
+	}
 	if commandTag.RowsAffected() == 0 {
 		return errors.New("no row affected")
 	}
@@ -63,13 +63,38 @@ func (member *MemberDatabaseMethod) getMemberByEmail() string {
 	LEFT JOIN membership.resources 
 	ON membership.resources.id = membership.member_resource.resource_id
 	WHERE member_id = membership.members.id
-	) as resources
+	) as resources, version
 	FROM membership.members
 	WHERE LOWER(email) = LOWER($1);`
 
 	return getMemberByEmailQuery
 }
 
+// memberPageFilter matches the pattern in $1, or everyone when it's empty,
+// and paid up members when $2 is true, inactive ones when it's false, or everyone when it's null
+const memberPageFilter = `($1 = '' OR name ILIKE $1 OR email ILIKE $1 OR rfid ILIKE $1 OR subscription_id ILIKE $1)
+	AND ($2::boolean IS NULL OR (member_tier_id != 1) = $2::boolean)`
+
+func (member *MemberDatabaseMethod) getMemberPage() string {
+	return `SELECT id, name, email, COALESCE(rfid,'notset'), member_tier_id,
+	ARRAY(
+	SELECT resource_id
+	FROM membership.member_resource
+	WHERE member_id = membership.members.id
+	) as resources, COALESCE(subscription_id,'none'), version
+	FROM membership.members
+	WHERE ` + memberPageFilter + `
+	AND LOWER(email) > $3
+	ORDER BY LOWER(email)
+	LIMIT $4;`
+}
+
+func (member *MemberDatabaseMethod) countMemberPage() string {
+	return `SELECT count(*)
+	FROM membership.members
+	WHERE ` + memberPageFilter + `;`
+}
+
 func (member *MemberDatabaseMethod) getMemberByEmailOrSubscriptionID() string {
 	return `SELECT id, name, LOWER(email), COALESCE(rfid,'notset'), member_tier_id,
 	ARRAY(
@@ -85,7 +110,7 @@ func (member *MemberDatabaseMethod) getMemberByEmailOrSubscriptionID() string {
 }
 
 func (member *MemberDatabaseMethod) updateMemberByEmail() string {
-	return `UPDATE membership.members SET name=$1, subscription_id=$2 WHERE email=$3;`
+	return `UPDATE membership.members SET name=$1, subscription_id=$2 WHERE email=$3 AND ($4 = 0 OR version = $4);`
 }
 
 func (member *MemberDatabaseMethod) updateMemberBySubscriptionID() string {
@@ -110,7 +135,7 @@ func (member *MemberDatabaseMethod) getMemberByRFID() string {
 func (member *MemberDatabaseMethod) setMemberRFIDTag() string {
 	const setMemberRFIDTagQuery = `UPDATE membership.members
 	SET rfid=$2
-	WHERE email=$1
+	WHERE email=$1 AND ($3 = 0 OR version = $3)
 	RETURNING rfid;`
 
 	return setMemberRFIDTagQuery
@@ -0,0 +1,68 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetPresenceSharing returns the IDs of the members that show up in the who's here list
+func (db *DatabaseStore) GetPresenceSharing() ([]string, error) {
+	return db.queryMemberIDs(occupancyDbMethod.getPresenceSharing())
+}
+
+// SetPresenceSharing sets whether a member shows up in the who's here list
+func (db *DatabaseStore) SetPresenceSharing(email string, share bool) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, occupancyDbMethod.setPresenceSharing(), email, share)
+	if err != nil {
+		return fmt.Errorf("error setting presence sharing: %v", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return errors.New("member not found")
+	}
+
+	return nil
+}
+
+// GetCertifiedMemberIDs returns the IDs of the members that hold a valid certification with the given name
+func (db *DatabaseStore) GetCertifiedMemberIDs(certificationName string) ([]string, error) {
+	return db.queryMemberIDs(occupancyDbMethod.getCertifiedMemberIDs(), certificationName)
+}
+
+func (db *DatabaseStore) queryMemberIDs(query string, args ...interface{}) ([]string, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	ids := []string{}
+
+	rows, err := dbPool.Query(db.ctx, query, args...)
+	if err != nil {
+		return ids, fmt.Errorf("error getting members: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,27 @@
+package dbstore
+
+var occupancyDbMethod OccupancyDatabaseMethod
+
+// OccupancyDatabaseMethod -- method container that holds the extension methods for tracking who is in the space
+type OccupancyDatabaseMethod struct{}
+
+func (OccupancyDatabaseMethod) getPresenceSharing() string {
+	return `SELECT id
+	FROM membership.members
+	WHERE share_presence = true;`
+}
+
+func (OccupancyDatabaseMethod) setPresenceSharing() string {
+	return `UPDATE membership.members
+	SET share_presence = $2
+	WHERE LOWER(email) = LOWER($1);`
+}
+
+func (OccupancyDatabaseMethod) getCertifiedMemberIDs() string {
+	return `SELECT DISTINCT mc.member_id
+	FROM membership.member_certifications mc
+	JOIN membership.certifications c
+	ON c.id = mc.certification_id
+	WHERE LOWER(c.name) = LOWER($1)
+	AND (mc.expires_at IS NULL OR mc.expires_at > NOW());`
+}
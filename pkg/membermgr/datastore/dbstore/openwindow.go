@@ -0,0 +1,111 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetOpenWindows returns the scheduled open windows for a resource
+//
+//	if resourceID is empty, every window is returned
+func (db *DatabaseStore) GetOpenWindows(resourceID string) ([]models.OpenWindow, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var windows []models.OpenWindow
+
+	query := openWindowDbMethod.getOpenWindows()
+	args := []interface{}{}
+	if len(resourceID) > 0 {
+		query = openWindowDbMethod.getOpenWindowsByResourceID()
+		args = append(args, resourceID)
+	}
+
+	rows, err := dbPool.Query(db.ctx, query, args...)
+	if err != nil {
+		return windows, fmt.Errorf("getOpenWindows failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var w models.OpenWindow
+		err = rows.Scan(&w.ID, &w.ResourceID, &w.Title, &w.Start, &w.End, &w.RRule, &w.HoldOpen)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		windows = append(windows, w)
+	}
+
+	return windows, nil
+}
+
+// AddOpenWindow stores a new open window
+func (db *DatabaseStore) AddOpenWindow(w models.OpenWindow) (models.OpenWindow, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var window models.OpenWindow
+
+	err = dbPool.QueryRow(db.ctx, openWindowDbMethod.insertOpenWindow(), w.ResourceID, w.Title, w.Start, w.End, w.RRule, w.HoldOpen).Scan(&window.ID, &window.ResourceID, &window.Title, &window.Start, &window.End, &window.RRule, &window.HoldOpen)
+	if err != nil {
+		return window, fmt.Errorf("error inserting open window: %v", err)
+	}
+
+	return window, nil
+}
+
+// UpdateOpenWindow updates an existing open window
+func (db *DatabaseStore) UpdateOpenWindow(w models.OpenWindow) (models.OpenWindow, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var window models.OpenWindow
+
+	if len(w.ID) == 0 {
+		return window, errors.New("invalid open window id")
+	}
+
+	err = dbPool.QueryRow(db.ctx, openWindowDbMethod.updateOpenWindow(), w.ID, w.ResourceID, w.Title, w.Start, w.End, w.RRule, w.HoldOpen).Scan(&window.ID, &window.ResourceID, &window.Title, &window.Start, &window.End, &window.RRule, &window.HoldOpen)
+	if err != nil {
+		return window, fmt.Errorf("error updating open window: %v", err)
+	}
+
+	return window, nil
+}
+
+// DeleteOpenWindow removes an open window
+func (db *DatabaseStore) DeleteOpenWindow(id string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, openWindowDbMethod.deleteOpenWindow(), id)
+	if err != nil {
+		return fmt.Errorf("deleteOpenWindow failed: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return errors.New("no row affected")
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package dbstore
+
+var openWindowDbMethod OpenWindowDatabaseMethod
+
+// OpenWindowDatabaseMethod -- method container that holds the extension methods to query the open_windows table
+type OpenWindowDatabaseMethod struct{}
+
+func (OpenWindowDatabaseMethod) getOpenWindows() string {
+	return `SELECT id, resource_id, title, start_time, end_time, rrule, hold_open
+	FROM membership.open_windows
+	ORDER BY start_time;`
+}
+
+func (OpenWindowDatabaseMethod) getOpenWindowsByResourceID() string {
+	return `SELECT id, resource_id, title, start_time, end_time, rrule, hold_open
+	FROM membership.open_windows
+	WHERE resource_id = $1
+	ORDER BY start_time;`
+}
+
+func (OpenWindowDatabaseMethod) insertOpenWindow() string {
+	return `INSERT INTO membership.open_windows(
+		resource_id, title, start_time, end_time, rrule, hold_open)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, resource_id, title, start_time, end_time, rrule, hold_open;`
+}
+
+func (OpenWindowDatabaseMethod) updateOpenWindow() string {
+	return `UPDATE membership.open_windows
+	SET resource_id=$2, title=$3, start_time=$4, end_time=$5, rrule=$6, hold_open=$7
+	WHERE id=$1
+	RETURNING id, resource_id, title, start_time, end_time, rrule, hold_open;`
+}
+
+func (OpenWindowDatabaseMethod) deleteOpenWindow() string {
+	return `DELETE FROM membership.open_windows
+	WHERE id = $1;`
+}
@@ -0,0 +1,68 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// HitRateLimit counts a hit for key and returns the hits in the window and when it ends
+func (db *DatabaseStore) HitRateLimit(key string, window time.Duration) (int, time.Time, error) {
+	var hits int
+	var resetAt time.Time
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return hits, resetAt, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, rateLimitDbMethod.hitRateLimit(), key, window.Seconds()).Scan(&hits, &resetAt)
+	if err != nil {
+		return hits, resetAt, fmt.Errorf("hitRateLimit failed: %v", err)
+	}
+
+	// ended windows are only replaced when their key is hit again, clear them out now and then
+	if rand.Intn(100) == 0 {
+		if _, err := dbPool.Exec(db.ctx, rateLimitDbMethod.deleteEndedRateLimits()); err != nil {
+			log.Errorf("deleteEndedRateLimits failed: %v", err)
+		}
+	}
+
+	return hits, resetAt, nil
+}
+
+// GetRateLimit returns the hits for key in its current window
+func (db *DatabaseStore) GetRateLimit(key string) (int, time.Time, error) {
+	var hits int
+	var resetAt time.Time
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return hits, resetAt, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, rateLimitDbMethod.getRateLimit(), key).Scan(&hits, &resetAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, time.Time{}, nil
+	}
+	if err != nil {
+		return hits, resetAt, fmt.Errorf("getRateLimit failed: %v", err)
+	}
+
+	return hits, resetAt, nil
+}
+
+// ResetRateLimit forgets the hits for key
+func (db *DatabaseStore) ResetRateLimit(key string) error {
+	_, err := db.execUsed(rateLimitDbMethod.resetRateLimit(), "resetRateLimit", key)
+	return err
+}
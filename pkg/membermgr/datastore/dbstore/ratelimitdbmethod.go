@@ -0,0 +1,32 @@
+package dbstore
+
+var rateLimitDbMethod RateLimitDatabaseMethod
+
+// RateLimitDatabaseMethod -- method container that holds the extension methods to count requests
+type RateLimitDatabaseMethod struct{}
+
+// hitRateLimit starts a new window when the last one has ended
+func (RateLimitDatabaseMethod) hitRateLimit() string {
+	return `INSERT INTO membership.rate_limits(key, hits, reset_at)
+	VALUES ($1, 1, now() + make_interval(secs => $2))
+	ON CONFLICT (key) DO UPDATE SET
+		hits = CASE WHEN rate_limits.reset_at > now() THEN rate_limits.hits + 1 ELSE 1 END,
+		reset_at = CASE WHEN rate_limits.reset_at > now() THEN rate_limits.reset_at ELSE EXCLUDED.reset_at END
+	RETURNING hits, reset_at;`
+}
+
+func (RateLimitDatabaseMethod) getRateLimit() string {
+	return `SELECT hits, reset_at
+	FROM membership.rate_limits
+	WHERE key = $1 AND reset_at > now();`
+}
+
+func (RateLimitDatabaseMethod) resetRateLimit() string {
+	return `DELETE FROM membership.rate_limits
+	WHERE key = $1;`
+}
+
+func (RateLimitDatabaseMethod) deleteEndedRateLimits() string {
+	return `DELETE FROM membership.rate_limits
+	WHERE reset_at <= now();`
+}
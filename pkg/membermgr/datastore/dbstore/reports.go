@@ -84,7 +84,12 @@ func (db *DatabaseStore) GetAccessStats(date time.Time, resourceName string) ([]
 	}
 	defer dbPool.Close()
 
-	rows, err := dbPool.Query(db.ctx, reportsDbMethod.getAccessStats(date, resourceName))
+	var day interface{}
+	if !date.IsZero() {
+		day = date.Local()
+	}
+
+	rows, err := dbPool.Query(db.ctx, reportsDbMethod.getAccessStats(), day, resourceName)
 	if err != nil {
 		log.Errorf("error getting member counts: %v", err)
 		return stats, err
@@ -105,6 +110,37 @@ func (db *DatabaseStore) GetAccessStats(date time.Time, resourceName string) ([]
 	return stats, nil
 }
 
+// GetUnknownFobs returns the fobs with the most unknown swipes since a time
+func (db *DatabaseStore) GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error) {
+	fobs := []models.UnknownFob{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fobs, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, reportsDbMethod.getUnknownFobs(), since.Local(), limit)
+	if err != nil {
+		return fobs, fmt.Errorf("error getting unknown fobs: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var f models.UnknownFob
+		if err := rows.Scan(&f.RFID, &f.Attempts, &f.Doors, &f.FirstSeen, &f.LastSeen); err != nil {
+			return fobs, err
+		}
+		f.FirstSeen = localEventTime(f.FirstSeen)
+		f.LastSeen = localEventTime(f.LastSeen)
+		fobs = append(fobs, f)
+	}
+
+	return fobs, rows.Err()
+}
+
 func (db *DatabaseStore) GetMemberChurn() (int, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
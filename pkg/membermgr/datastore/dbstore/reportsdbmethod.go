@@ -1,10 +1,5 @@
 package dbstore
 
-import (
-	"fmt"
-	"time"
-)
-
 // ReportsDatabaseMethod -- method container that holds the extension methods to query the members, credit, and resource tables
 type ReportsDatabaseMethod struct{}
 
@@ -55,25 +50,32 @@ func (ReportsDatabaseMethod) getMemberChurn() string {
 `
 }
 
-func (ReportsDatabaseMethod) getAccessStats(day time.Time, resourceName string) string {
-	var dayFilter string
-	var resourceFilter string
-
-	if len(resourceName) > 0 {
-		resourceFilter = fmt.Sprintf("WHERE door = '%s'", resourceName)
-	}
-
-	if !day.IsZero() {
-		dayFilter = fmt.Sprintf("WHERE date_trunc('day', event_time) = date_trunc('day', %s)", day)
-		if len(resourceName) > 0 {
-			resourceFilter = fmt.Sprintf("AND door = '%s'", resourceName)
-		}
-	}
-
-	return fmt.Sprintf(`SELECT date_trunc('day', event_time) as day, door as resource, COUNT(*)
+func (ReportsDatabaseMethod) getUnknownFobs() string {
+	return `SELECT rfid, COUNT(*) as attempts, array_agg(DISTINCT door), MIN(event_time), MAX(event_time)
 	FROM membership.access_events
-	%s
-	%s
-	GROUP BY date_trunc('day', event_time), door
-	ORDER BY day;`, dayFilter, resourceFilter)
+	WHERE is_known = false AND event_time >= $1
+	GROUP BY rfid
+	ORDER BY attempts DESC, MAX(event_time) DESC
+	LIMIT $2;`
+}
+
+// getAccessStats counts members' access per day and resource
+//
+//	events that the retention policy has rolled up come from the daily aggregates,
+//	the rest are counted from the raw events. Swipes of unknown fobs aren't usage and aren't counted
+func (ReportsDatabaseMethod) getAccessStats() string {
+	return `SELECT day, resource, SUM(access_count)::bigint
+	FROM (
+		SELECT day::timestamp AS day, door AS resource, access_count::bigint
+		FROM membership.access_event_daily
+		UNION ALL
+		SELECT date_trunc('day', e.event_time) AS day, e.door AS resource, COUNT(*) AS access_count
+		FROM membership.access_events e
+		WHERE e.is_known AND NOT e.rolled_up
+		GROUP BY date_trunc('day', e.event_time), e.door
+	) stats
+	WHERE ($1::timestamp IS NULL OR day = date_trunc('day', $1::timestamp))
+		AND ($2 = '' OR resource = $2)
+	GROUP BY day, resource
+	ORDER BY day;`
 }
@@ -3,8 +3,10 @@ package dbstore
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 
 	log "github.com/sirupsen/logrus"
@@ -49,7 +51,7 @@ func (db *DatabaseStore) GetResources() []models.Resource {
 
 	for rows.Next() {
 		var r models.Resource
-		_ = rows.Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault)
+		_ = rows.Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver)
 
 		r.LastHeartBeat = GetLastHeartbeat(r)
 		resources = append(resources, r)
@@ -68,7 +70,7 @@ func (db *DatabaseStore) GetResourceByID(ID string) (models.Resource, error) {
 
 	var r models.Resource
 
-	err = dbPool.QueryRow(db.ctx, resourceDbMethod.getResourceByID(), ID).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault)
+	err = dbPool.QueryRow(db.ctx, resourceDbMethod.getResourceByID(), ID).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver, &r.Version)
 	if err != nil {
 		return r, fmt.Errorf("getResourceByID failed: %v", err)
 	}
@@ -86,7 +88,7 @@ func (db *DatabaseStore) GetResourceByName(resourceName string) (models.Resource
 
 	var r models.Resource
 
-	err = dbPool.QueryRow(db.ctx, resourceDbMethod.getResourceByName(), resourceName).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault)
+	err = dbPool.QueryRow(db.ctx, resourceDbMethod.getResourceByName(), resourceName).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver)
 	if err != nil {
 		return r, fmt.Errorf("getResourceByName failed: %v", err)
 	}
@@ -95,7 +97,9 @@ func (db *DatabaseStore) GetResourceByName(resourceName string) (models.Resource
 }
 
 // RegisterResource - stores a new resource in the db
-func (db *DatabaseStore) RegisterResource(name string, address string, isDefault bool) (models.Resource, error) {
+//
+//	resources without a driver get the esp-rfid driver
+func (db *DatabaseStore) RegisterResource(name string, address string, isDefault bool, driver string) (models.Resource, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
@@ -107,21 +111,25 @@ func (db *DatabaseStore) RegisterResource(name string, address string, isDefault
 	r.Name = name
 	r.Address = address
 	r.IsDefault = isDefault
+	r.Driver = driver
+	if len(r.Driver) == 0 {
+		r.Driver = models.DriverESPRFID
+	}
 
-	commandTag, err := dbPool.Exec(db.ctx, resourceDbMethod.insertResource(), r.Name, r.Address, r.IsDefault)
+	err = dbPool.QueryRow(db.ctx, resourceDbMethod.insertResource(), r.Name, r.Address, r.IsDefault, r.Driver).Scan(&r.ID, &r.Driver)
 	if err != nil {
 		return *r, fmt.Errorf("error inserting resource: %s", err.Error())
 	}
 
-	if commandTag.RowsAffected() != 1 {
-		return *r, errors.New("no row affected")
-	}
-
 	return *r, nil
 }
 
 // UpdateResource - updates a resource in the db
 func (db *DatabaseStore) UpdateResource(res models.Resource) (*models.Resource, error) {
+	return db.UpdateResourceAtVersion(res, datastore.AnyVersion)
+}
+
+func (db *DatabaseStore) UpdateResourceAtVersion(res models.Resource, version int) (*models.Resource, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
@@ -136,35 +144,90 @@ func (db *DatabaseStore) UpdateResource(res models.Resource) (*models.Resource,
 		return r, errors.New("invalid resourseID of 0")
 	}
 
-	row := dbPool.QueryRow(db.ctx, resourceDbMethod.updateResource(), res.ID, res.Name, res.Address, res.IsDefault).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault)
-	if row == pgx.ErrNoRows {
-		log.Printf("no rows affected %s", row.Error())
+	err = dbPool.QueryRow(db.ctx, resourceDbMethod.updateResource(), res.ID, res.Name, res.Address, res.IsDefault, res.SupportsTimeWindows, res.Driver, version).Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver, &r.Version)
+	if err == pgx.ErrNoRows && version != datastore.AnyVersion {
+		return r, datastore.ErrVersionChanged
+	}
+	if err == pgx.ErrNoRows {
+		log.Printf("no rows affected %s", err.Error())
 		return r, errors.New("no rows affected")
 	}
+	if err != nil {
+		return r, fmt.Errorf("error updating resource: %v", err)
+	}
 
 	return r, nil
 }
 
 // DeleteResource - delete a resource from the db
 func (db *DatabaseStore) DeleteResource(id string) error {
+	return db.DeleteResourceAtVersion(id, datastore.AnyVersion)
+}
+
+// DeleteResourceAtVersion - delete a resource from the db while it's at version
+func (db *DatabaseStore) DeleteResourceAtVersion(id string, version int) error {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
 	}
 	defer dbPool.Close()
 
-	rows, err := dbPool.Query(db.ctx, resourceDbMethod.deleteResource(), id)
+	commandTag, err := dbPool.Exec(db.ctx, resourceDbMethod.deleteResource(), id, version)
 	if err != nil {
 		return fmt.Errorf("deleteResource failed: %v", err)
 	}
 
-	defer rows.Close()
+	if commandTag.RowsAffected() == 0 && version != datastore.AnyVersion {
+		return datastore.ErrVersionChanged
+	}
 
 	return nil
 }
 
+// GetResourcePage reads one page of resources, sorted by name then ID
+func (db *DatabaseStore) GetResourcePage(query models.ResourceQuery) ([]models.Resource, int, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	pattern := searchPattern(query.Search)
+
+	var total int
+	if err := dbPool.QueryRow(db.ctx, resourceDbMethod.countResourcePage(), pattern).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("GetResourcePage failed: %w", err)
+	}
+
+	rows, err := dbPool.Query(db.ctx, resourceDbMethod.getResourcePage(), pattern, query.AfterName, query.AfterID, query.Limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetResourcePage failed: %w", err)
+	}
+	defer rows.Close()
+
+	resources := []models.Resource{}
+	for rows.Next() {
+		var r models.Resource
+		if err := rows.Scan(&r.ID, &r.Name, &r.Address, &r.IsDefault, &r.SupportsTimeWindows, &r.Driver, &r.Version); err != nil {
+			return nil, 0, fmt.Errorf("GetResourcePage failed: %w", err)
+		}
+
+		r.LastHeartBeat = GetLastHeartbeat(r)
+		resources = append(resources, r)
+	}
+
+	return resources, total, rows.Err()
+}
+
 // AddMultipleMembersToResource grant multiple members access to a resource
 func (db *DatabaseStore) AddMultipleMembersToResource(emails []string, resourceID string) ([]models.MemberResourceRelation, error) {
+	return db.AddTemporaryMembersToResource(emails, resourceID, nil, nil)
+}
+
+// AddTemporaryMembersToResource grant multiple members access to a resource between validFrom and validUntil
+//
+//	a nil validFrom starts now and a nil validUntil never expires
+func (db *DatabaseStore) AddTemporaryMembersToResource(emails []string, resourceID string, validFrom *time.Time, validUntil *time.Time) ([]models.MemberResourceRelation, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
@@ -179,6 +242,8 @@ func (db *DatabaseStore) AddMultipleMembersToResource(emails []string, resourceI
 		return membersResource, err
 	}
 
+	// make sure every member holds the certifications the resource requires before granting anything
+	var members []models.Member
 	for i := 0; i < len(emails); i++ {
 		member, err := db.GetMemberByEmail(emails[i])
 
@@ -186,13 +251,30 @@ func (db *DatabaseStore) AddMultipleMembersToResource(emails []string, resourceI
 			return membersResource, err
 		}
 
+		missing, err := db.missingCertifications(dbPool, member.ID, resource.ID)
+		if err != nil {
+			return membersResource, err
+		}
+
+		if len(missing) > 0 {
+			return membersResource, fmt.Errorf("%s is missing required certification(s) for %s: %s", member.Email, resource.Name, strings.Join(missing, ", "))
+		}
+
+		members = append(members, member)
+	}
+
+	for _, member := range members {
 		var memberResource models.MemberResourceRelation
 		memberResource.MemberID = member.ID
 		memberResource.ResourceID = resource.ID
 
-		row := dbPool.QueryRow(db.ctx, resourceDbMethod.insertMemberResource(), memberResource.MemberID, memberResource.ResourceID).Scan(&memberResource.ID, &memberResource.MemberID, &memberResource.ResourceID)
-		if row == pgx.ErrNoRows {
-			return membersResource, errors.New("no rows affected")
+		err := dbPool.QueryRow(db.ctx, resourceDbMethod.insertMemberResource(), memberResource.MemberID, memberResource.ResourceID, validFrom, validUntil, nil).Scan(&memberResource.ID, &memberResource.MemberID, &memberResource.ResourceID, &memberResource.ValidFrom, &memberResource.ValidUntil)
+		if err == pgx.ErrNoRows {
+			// the member already has access that doesn't expire
+			memberResource, err = db.GetMemberResourceRelation(member, resource)
+		}
+		if err != nil {
+			return membersResource, err
 		}
 
 		membersResource = append(membersResource, memberResource)
@@ -243,7 +325,7 @@ func (db *DatabaseStore) GetMemberResourceRelation(m models.Member, r models.Res
 
 	mr := models.MemberResourceRelation{}
 
-	row := dbPool.QueryRow(db.ctx, resourceDbMethod.getMemberResource(), m.ID, r.ID).Scan(&mr.ID, &mr.MemberID, &mr.ResourceID)
+	row := dbPool.QueryRow(db.ctx, resourceDbMethod.getMemberResource(), m.ID, r.ID).Scan(&mr.ID, &mr.MemberID, &mr.ResourceID, &mr.ValidFrom, &mr.ValidUntil)
 	if row == pgx.ErrNoRows {
 		return mr, errors.New("no rows affected")
 	}
@@ -314,14 +396,14 @@ func (db *DatabaseStore) GetResourceACL(r models.Resource) ([]string, error) {
 }
 
 // GetResourceACLWithMemberInfo returns a list of members that have access to that Resource
-func (db *DatabaseStore) GetResourceACLWithMemberInfo(r models.Resource) ([]models.Member, error) {
+func (db *DatabaseStore) GetResourceACLWithMemberInfo(r models.Resource) ([]models.MemberAccess, error) {
 	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
 	if err != nil {
 		log.Printf("got error: %v\n", err)
 	}
 	defer dbPool.Close()
 
-	var accessList []models.Member
+	var accessList []models.MemberAccess
 
 	rows, err := dbPool.Query(db.ctx, resourceDbMethod.getResourceACLByResourceIDQueryWithMemberInfo(), r.ID)
 	if err != nil {
@@ -331,9 +413,13 @@ func (db *DatabaseStore) GetResourceACLWithMemberInfo(r models.Resource) ([]mode
 	defer rows.Close()
 
 	for rows.Next() {
-		var member models.Member
+		member := models.MemberAccess{
+			ResourceID:      r.ID,
+			ResourceAddress: r.Address,
+			ResourceName:    r.Name,
+		}
 
-		rows.Scan(&member.ID, &member.Name, &member.RFID)
+		rows.Scan(&member.Email, &member.Name, &member.RFID, &member.Level, &member.ValidUntil)
 
 		accessList = append(accessList, member)
 	}
@@ -363,7 +449,7 @@ func (db *DatabaseStore) GetMembersAccess(m models.Member) ([]models.MemberAcces
 	for rows.Next() {
 		var resourceUpdate models.MemberAccess
 
-		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID)
+		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceID, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID, &resourceUpdate.Level, &resourceUpdate.ValidUntil)
 
 		memberAccess = append(memberAccess, resourceUpdate)
 	}
@@ -390,7 +476,7 @@ func (db *DatabaseStore) GetInactiveMembersByResource() ([]models.MemberAccess,
 	for rows.Next() {
 		var resourceUpdate models.MemberAccess
 
-		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID)
+		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceID, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID, &resourceUpdate.Level)
 
 		memberAccess = append(memberAccess, resourceUpdate)
 	}
@@ -417,10 +503,50 @@ func (db *DatabaseStore) GetActiveMembersByResource() ([]models.MemberAccess, er
 	for rows.Next() {
 		var resourceUpdate models.MemberAccess
 
-		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID)
+		rows.Scan(&resourceUpdate.Email, &resourceUpdate.ResourceID, &resourceUpdate.ResourceAddress, &resourceUpdate.ResourceName, &resourceUpdate.Name, &resourceUpdate.RFID, &resourceUpdate.Level, &resourceUpdate.ValidUntil)
 
 		memberAccess = append(memberAccess, resourceUpdate)
 	}
 
 	return memberAccess, nil
 }
+
+// GetExpiredResourceGrants returns temporary grants that have run out
+func (db *DatabaseStore) GetExpiredResourceGrants() ([]models.MemberAccess, error) {
+	return db.queryResourceGrants(resourceDbMethod.getExpiredResourceGrants())
+}
+
+// GetActiveScheduledResourceGrants returns grants that were made to start later and are in effect now
+func (db *DatabaseStore) GetActiveScheduledResourceGrants() ([]models.MemberAccess, error) {
+	return db.queryResourceGrants(resourceDbMethod.getActiveScheduledResourceGrants())
+}
+
+func (db *DatabaseStore) queryResourceGrants(query string, args ...interface{}) ([]models.MemberAccess, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var memberAccess []models.MemberAccess
+
+	rows, err := dbPool.Query(db.ctx, query, args...)
+	if err != nil {
+		return memberAccess, fmt.Errorf("error getting resource grants: %s", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var grant models.MemberAccess
+
+		err = rows.Scan(&grant.Email, &grant.ResourceID, &grant.ResourceAddress, &grant.ResourceName, &grant.Name, &grant.RFID, &grant.Level, &grant.ValidUntil)
+		if err != nil {
+			return memberAccess, err
+		}
+
+		memberAccess = append(memberAccess, grant)
+	}
+
+	return memberAccess, nil
+}
@@ -4,38 +4,56 @@ package dbstore
 type ResourceDatabaseMethod struct{}
 
 func (resource *ResourceDatabaseMethod) getResource() string {
-	return `SELECT id, description, device_identifier, is_default 
+	return `SELECT id, description, device_identifier, is_default, supports_time_windows, driver
 	FROM membership.resources
 	ORDER BY description;`
 }
 
 func (resource *ResourceDatabaseMethod) insertResource() string {
 	return `INSERT INTO membership.resources(
-		description, device_identifier, is_default)
-		VALUES ($1, $2, $3)
-		RETURNING *;`
+		description, device_identifier, is_default, driver)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, driver;`
 }
 
 func (resource *ResourceDatabaseMethod) updateResource() string {
 	return `UPDATE membership.resources
-	SET description=$2, device_identifier=$3, is_default=$4
-	WHERE id=$1
-	RETURNING *;`
+	SET description=$2, device_identifier=$3, is_default=$4, supports_time_windows=$5, driver=COALESCE(NULLIF($6, ''), driver)
+	WHERE id=$1 AND ($7 = 0 OR version = $7)
+	RETURNING id, description, device_identifier, is_default, supports_time_windows, driver, version;`
 }
 
 func (resource *ResourceDatabaseMethod) deleteResource() string {
 	return `DELETE FROM membership.resources
-	WHERE id = $1;`
+	WHERE id = $1 AND ($2 = 0 OR version = $2);`
+}
+
+// resourcePageFilter matches the pattern in $1, or every resource when it's empty
+const resourcePageFilter = `($1 = '' OR description ILIKE $1 OR device_identifier ILIKE $1)`
+
+func (resource *ResourceDatabaseMethod) getResourcePage() string {
+	return `SELECT id, description, device_identifier, is_default, supports_time_windows, driver, version
+	FROM membership.resources
+	WHERE ` + resourcePageFilter + `
+	AND (LOWER(description), id::text) > ($2, $3)
+	ORDER BY LOWER(description), id::text
+	LIMIT $4;`
+}
+
+func (resource *ResourceDatabaseMethod) countResourcePage() string {
+	return `SELECT count(*)
+	FROM membership.resources
+	WHERE ` + resourcePageFilter + `;`
 }
 
 func (resource *ResourceDatabaseMethod) getResourceByName() string {
-	return `SELECT id, description, device_identifier, is_default
+	return `SELECT id, description, device_identifier, is_default, supports_time_windows, driver
 	FROM membership.resources
 	WHERE description = $1;`
 }
 
 func (resource *ResourceDatabaseMethod) getResourceByID() string {
-	return `SELECT id, description, device_identifier, is_default
+	return `SELECT id, description, device_identifier, is_default, supports_time_windows, driver, version
 	FROM membership.resources
 	WHERE id = $1;`
 }
@@ -48,42 +66,58 @@ func (resource *ResourceDatabaseMethod) getResourceACLByResourceID() string {
 	ON membership.member_resource.member_id = membership.members.id
 	WHERE resource_id = $1
 	AND rfid is not NULL
-	AND member_tier_id != 1;`
+	AND member_tier_id != 1
+	AND (valid_from IS NULL OR valid_from <= NOW())
+	AND (valid_until IS NULL OR valid_until > NOW());`
 }
 
+// getResourceACLByResourceIDQueryWithMemberInfo has one row per member
+//
+//	a member can hold more than one grant for a resource, access only expires if every one of them does
 func (resource *ResourceDatabaseMethod) getResourceACLByResourceIDQueryWithMemberInfo() string {
-	return `SELECT member_id, name, rfid
+	return `SELECT email, name, rfid, member_tier_id,
+	CASE WHEN bool_or(valid_until IS NULL) THEN NULL ELSE MAX(valid_until) END
 	FROM membership.member_resource
 	LEFT JOIN membership.members
 	ON membership.member_resource.member_id = membership.members.id
 	WHERE resource_id = $1
 	AND rfid is not NULL
-	AND member_tier_id != 1;`
+	AND member_tier_id != 1
+	AND (valid_from IS NULL OR valid_from <= NOW())
+	AND (valid_until IS NULL OR valid_until > NOW())
+	GROUP BY member_id, email, name, rfid, member_tier_id;`
 }
 
+// getResourceACLByEmail has one row per resource, like getResourceACLByResourceIDQueryWithMemberInfo
 func (resource *ResourceDatabaseMethod) getResourceACLByEmail() string {
-	return `SELECT email, device_identifier, description, name, rfid
+	return `SELECT email, resource_id, device_identifier, description, name, rfid, member_tier_id,
+	CASE WHEN bool_or(valid_until IS NULL) THEN NULL ELSE MAX(valid_until) END
 	FROM membership.member_resource
 	LEFT JOIN membership.members
 	ON membership.member_resource.member_id = membership.members.id
 	LEFT JOIN membership.resources
 	ON membership.member_resource.resource_id = membership.resources.id 
-	WHERE rfid is not NULL and email = $1;`
+	WHERE rfid is not NULL and email = $1
+	AND (valid_from IS NULL OR valid_from <= NOW())
+	AND (valid_until IS NULL OR valid_until > NOW())
+	GROUP BY email, resource_id, device_identifier, description, name, rfid, member_tier_id;`
 }
 
 func (resource *ResourceDatabaseMethod) getActiveMembersResourceACL() string {
-	return `SELECT email, device_identifier, description, name, rfid
+	return `SELECT email, resource_id, device_identifier, description, name, rfid, member_tier_id, valid_until
 	FROM membership.member_resource
  	LEFT JOIN membership.members
  	ON membership.member_resource.member_id = membership.members.id
 	LEFT JOIN membership.resources
 	ON membership.resources.id = membership.member_resource.resource_id
 	WHERE member_tier_id != 1
-	AND rfid is not null;`
+	AND rfid is not null
+	AND (valid_from IS NULL OR valid_from <= NOW())
+	AND (valid_until IS NULL OR valid_until > NOW());`
 }
 
 func (resource *ResourceDatabaseMethod) getInactiveMembersResourceACL() string {
-	return `SELECT email, device_identifier, description, name, rfid
+	return `SELECT email, resource_id, device_identifier, description, name, rfid, member_tier_id
 	FROM membership.member_resource
  	LEFT JOIN membership.members
  	ON membership.member_resource.member_id = membership.members.id
@@ -95,7 +129,7 @@ func (resource *ResourceDatabaseMethod) getInactiveMembersResourceACL() string {
 }
 
 func (resource *ResourceDatabaseMethod) getMemberResource() string {
-	const getMemberResourceQuery = `SELECT id, member_id, resource_id
+	const getMemberResourceQuery = `SELECT id, member_id, resource_id, valid_from, valid_until
 	FROM membership.member_resource
 	WHERE member_id = $1 AND resource_id = $2;`
 
@@ -103,19 +137,52 @@ func (resource *ResourceDatabaseMethod) getMemberResource() string {
 }
 
 func (resource *ResourceDatabaseMethod) insertMemberResource() string {
+	// a new grant replaces a temporary one, but never downgrades access that doesn't expire.
+	// group_id is null for direct grants, a direct grant that doesn't expire takes over one that came from a group
+	// so it isn't revoked along with the group
 	return `INSERT INTO membership.member_resource(
-		member_id, resource_id)
-		VALUES ($1, $2)
-		RETURNING *;`
+		member_id, resource_id, valid_from, valid_until, group_id)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT ON CONSTRAINT unique_relationship
+		DO UPDATE SET valid_from = EXCLUDED.valid_from, valid_until = EXCLUDED.valid_until, group_id = EXCLUDED.group_id
+		WHERE membership.member_resource.valid_until IS NOT NULL
+		OR (membership.member_resource.group_id IS NOT NULL AND EXCLUDED.group_id IS NULL AND EXCLUDED.valid_until IS NULL)
+		RETURNING id, member_id, resource_id, valid_from, valid_until;`
 }
 
 func (resource *ResourceDatabaseMethod) insertMemberDefaultResource() string {
 	return `INSERT INTO membership.member_resource(member_id, resource_id)
 	VALUES($1, unnest( ARRAY(SELECT resources.id FROM membership.resources AS resources WHERE resources.is_default IS TRUE)))
-	RETURNING *;`
+	RETURNING id, member_id, resource_id;`
 }
 
 func (resource *ResourceDatabaseMethod) removeMemberResource() string {
 	return `DELETE FROM membership.member_resource
 	WHERE member_id = $1 AND resource_id = $2;`
 }
+
+func (resource *ResourceDatabaseMethod) getExpiredResourceGrants() string {
+	return `SELECT email, resource_id, device_identifier, description, name, rfid, member_tier_id, valid_until
+	FROM membership.member_resource
+	LEFT JOIN membership.members
+	ON membership.member_resource.member_id = membership.members.id
+	LEFT JOIN membership.resources
+	ON membership.resources.id = membership.member_resource.resource_id
+	WHERE valid_until IS NOT NULL
+	AND valid_until <= NOW();`
+}
+
+// getActiveScheduledResourceGrants finds grants that were made to start later and have started
+func (resource *ResourceDatabaseMethod) getActiveScheduledResourceGrants() string {
+	return `SELECT email, resource_id, device_identifier, description, name, rfid, member_tier_id, valid_until
+	FROM membership.member_resource
+	LEFT JOIN membership.members
+	ON membership.member_resource.member_id = membership.members.id
+	LEFT JOIN membership.resources
+	ON membership.resources.id = membership.member_resource.resource_id
+	WHERE valid_from IS NOT NULL
+	AND valid_from <= NOW()
+	AND (valid_until IS NULL OR valid_until > NOW())
+	AND member_tier_id != 1
+	AND rfid is not null;`
+}
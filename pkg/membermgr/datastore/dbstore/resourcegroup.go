@@ -0,0 +1,387 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetResourceGroups returns every resource group along with the resources in it
+func (db *DatabaseStore) GetResourceGroups() ([]models.ResourceGroup, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var groups []models.ResourceGroup
+
+	rows, err := dbPool.Query(db.ctx, resourceGroupDbMethod.getResourceGroups())
+	if err != nil {
+		return groups, fmt.Errorf("getResourceGroups failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var g models.ResourceGroup
+		err = rows.Scan(&g.ID, &g.Name, &g.Description, &g.ResourceIDs)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		groups = append(groups, g)
+	}
+
+	return groups, nil
+}
+
+// GetResourceGroup returns a single resource group
+func (db *DatabaseStore) GetResourceGroup(id string) (models.ResourceGroup, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	return db.getResourceGroup(dbPool, id)
+}
+
+func (db *DatabaseStore) getResourceGroup(dbPool *pgxpool.Pool, id string) (models.ResourceGroup, error) {
+	var g models.ResourceGroup
+
+	err := dbPool.QueryRow(db.ctx, resourceGroupDbMethod.getResourceGroupByID(), id).Scan(&g.ID, &g.Name, &g.Description, &g.ResourceIDs)
+	if err == pgx.ErrNoRows {
+		return g, errors.New("resource group not found")
+	}
+	if err != nil {
+		return g, fmt.Errorf("error getting resource group: %v", err)
+	}
+
+	return g, nil
+}
+
+// AddResourceGroup stores a new resource group
+func (db *DatabaseStore) AddResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var group models.ResourceGroup
+
+	err = dbPool.QueryRow(db.ctx, resourceGroupDbMethod.insertResourceGroup(), g.Name, g.Description).Scan(&group.ID, &group.Name, &group.Description)
+	if err != nil {
+		return group, fmt.Errorf("error inserting resource group: %v", err)
+	}
+
+	group.ResourceIDs = []string{}
+
+	return group, nil
+}
+
+// UpdateResourceGroup updates a resource group's name and description
+func (db *DatabaseStore) UpdateResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	if len(g.ID) == 0 {
+		return models.ResourceGroup{}, errors.New("invalid resource group id")
+	}
+
+	_, err = dbPool.Exec(db.ctx, resourceGroupDbMethod.updateResourceGroup(), g.ID, g.Name, g.Description)
+	if err != nil {
+		return models.ResourceGroup{}, fmt.Errorf("error updating resource group: %v", err)
+	}
+
+	return db.getResourceGroup(dbPool, g.ID)
+}
+
+// DeleteResourceGroup removes a resource group
+//
+//	members keep the access they were granted through the group
+func (db *DatabaseStore) DeleteResourceGroup(id string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, resourceGroupDbMethod.deleteResourceGroup(), id)
+	if err != nil {
+		return fmt.Errorf("error deleting resource group: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return errors.New("no row affected")
+	}
+
+	return nil
+}
+
+// GetResourceGroupMembers returns the members that were granted access to a group
+func (db *DatabaseStore) GetResourceGroupMembers(groupID string) ([]models.Member, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	return db.getResourceGroupMembers(dbPool, groupID)
+}
+
+func (db *DatabaseStore) getResourceGroupMembers(dbPool *pgxpool.Pool, groupID string) ([]models.Member, error) {
+	var members []models.Member
+
+	rows, err := dbPool.Query(db.ctx, resourceGroupDbMethod.getResourceGroupMembers(), groupID)
+	if err != nil {
+		return members, fmt.Errorf("error getting resource group members: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.RFID, &m.Level); err != nil {
+			return members, err
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
+
+// AddMembersToResourceGroup grants multiple members access to every resource in a group
+//
+//	either every member is granted every resource or nothing is granted
+func (db *DatabaseStore) AddMembersToResourceGroup(emails []string, groupID string) ([]models.MemberResourceRelation, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var membersResource []models.MemberResourceRelation
+
+	group, err := db.getResourceGroup(dbPool, groupID)
+	if err != nil {
+		return membersResource, err
+	}
+
+	// check every resource up front so we don't grant half of a group
+	var members []models.Member
+	for _, email := range emails {
+		member, err := db.GetMemberByEmail(email)
+		if err != nil {
+			return membersResource, err
+		}
+		members = append(members, member)
+
+		for _, resourceID := range group.ResourceIDs {
+			missing, err := db.missingCertifications(dbPool, member.ID, resourceID)
+			if err != nil {
+				return membersResource, err
+			}
+
+			if len(missing) > 0 {
+				return membersResource, fmt.Errorf("%s is missing required certification(s) for %s: %s", member.Email, group.Name, strings.Join(missing, ", "))
+			}
+		}
+	}
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return membersResource, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	_, err = tx.Exec(db.ctx, resourceGroupDbMethod.insertResourceGroupMembers(), emails, group.ID)
+	if err != nil {
+		return membersResource, fmt.Errorf("error adding members to resource group: %v", err)
+	}
+
+	for _, resourceID := range group.ResourceIDs {
+		for _, member := range members {
+			relation := models.MemberResourceRelation{MemberID: member.ID, ResourceID: resourceID}
+			err = tx.QueryRow(db.ctx, resourceDbMethod.insertMemberResource(), member.ID, resourceID, nil, nil, group.ID).Scan(&relation.ID, &relation.MemberID, &relation.ResourceID, &relation.ValidFrom, &relation.ValidUntil)
+			if err != nil && err != pgx.ErrNoRows {
+				return nil, fmt.Errorf("error granting %s access to %s: %v", member.Email, group.Name, err)
+			}
+			// no rows means the member already has access that doesn't expire
+			membersResource = append(membersResource, relation)
+		}
+	}
+
+	return membersResource, tx.Commit(db.ctx)
+}
+
+// RemoveMemberFromResourceGroup removes a member from a group and returns the access that was revoked
+//
+//	access to a resource that another of the member's groups covers is kept
+func (db *DatabaseStore) RemoveMemberFromResourceGroup(email string, groupID string) ([]models.MemberAccess, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	member, err := db.GetMemberByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	revoked, err := db.queryRevokedAccess(tx, resourceGroupDbMethod.revokeMemberGroupAccess(), member.ID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	commandTag, err := tx.Exec(db.ctx, resourceGroupDbMethod.deleteResourceGroupMember(), member.ID, groupID)
+	if err != nil {
+		return nil, fmt.Errorf("error removing member from resource group: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return nil, errors.New("member is not in the resource group")
+	}
+
+	return revoked, tx.Commit(db.ctx)
+}
+
+// AddResourceToResourceGroup adds a resource to a group and backfills its access list with the group's members
+//
+//	members that are missing a certification the resource requires are skipped
+func (db *DatabaseStore) AddResourceToResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var granted []models.MemberAccess
+
+	resource, err := db.GetResourceByID(resourceID)
+	if err != nil {
+		return granted, err
+	}
+
+	members, err := db.getResourceGroupMembers(dbPool, groupID)
+	if err != nil {
+		return granted, err
+	}
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return granted, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	_, err = tx.Exec(db.ctx, resourceGroupDbMethod.insertResourceGroupResource(), groupID, resource.ID)
+	if err != nil {
+		return granted, fmt.Errorf("error adding resource to resource group: %v", err)
+	}
+
+	for _, member := range members {
+		missing, err := db.missingCertifications(dbPool, member.ID, resource.ID)
+		if err != nil {
+			return granted, err
+		}
+
+		if len(missing) > 0 {
+			log.Infof("not granting %s access to %s: missing certification(s) %s", member.Email, resource.Name, strings.Join(missing, ", "))
+			continue
+		}
+
+		var relation models.MemberResourceRelation
+		err = tx.QueryRow(db.ctx, resourceDbMethod.insertMemberResource(), member.ID, resource.ID, nil, nil, groupID).Scan(&relation.ID, &relation.MemberID, &relation.ResourceID, &relation.ValidFrom, &relation.ValidUntil)
+		if err == pgx.ErrNoRows {
+			// the member already has access that doesn't expire
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error granting %s access to %s: %v", member.Email, resource.Name, err)
+		}
+
+		granted = append(granted, models.MemberAccess{
+			Email:           member.Email,
+			ResourceID:      resource.ID,
+			ResourceAddress: resource.Address,
+			ResourceName:    resource.Name,
+			Name:            member.Name,
+			RFID:            member.RFID,
+			Level:           member.Level,
+		})
+	}
+
+	return granted, tx.Commit(db.ctx)
+}
+
+// RemoveResourceFromResourceGroup removes a resource from a group and returns the access that was revoked
+//
+//	access that another of the member's groups covers is kept
+func (db *DatabaseStore) RemoveResourceFromResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	revoked, err := db.queryRevokedAccess(tx, resourceGroupDbMethod.revokeResourceGroupAccess(), groupID, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	commandTag, err := tx.Exec(db.ctx, resourceGroupDbMethod.deleteResourceGroupResource(), groupID, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("error removing resource from resource group: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return nil, errors.New("resource is not in the resource group")
+	}
+
+	return revoked, tx.Commit(db.ctx)
+}
+
+func (db *DatabaseStore) queryRevokedAccess(tx pgx.Tx, query string, args ...interface{}) ([]models.MemberAccess, error) {
+	var revoked []models.MemberAccess
+
+	rows, err := tx.Query(db.ctx, query, args...)
+	if err != nil {
+		return revoked, fmt.Errorf("error revoking resource group access: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var access models.MemberAccess
+		if err := rows.Scan(&access.Email, &access.ResourceID, &access.ResourceAddress, &access.ResourceName, &access.Name, &access.RFID, &access.Level); err != nil {
+			return revoked, err
+		}
+		revoked = append(revoked, access)
+	}
+
+	return revoked, rows.Err()
+}
@@ -0,0 +1,132 @@
+package dbstore
+
+var resourceGroupDbMethod ResourceGroupDatabaseMethod
+
+// ResourceGroupDatabaseMethod -- method container that holds the extension methods to query the resource group tables
+type ResourceGroupDatabaseMethod struct{}
+
+func (ResourceGroupDatabaseMethod) getResourceGroups() string {
+	return `SELECT g.id, g.name, g.description,
+	COALESCE(array_agg(rg.resource_id::text) FILTER (WHERE rg.resource_id IS NOT NULL), '{}')
+	FROM membership.resource_groups g
+	LEFT JOIN membership.resource_group_resources rg
+	ON rg.group_id = g.id
+	GROUP BY g.id
+	ORDER BY g.name;`
+}
+
+func (ResourceGroupDatabaseMethod) getResourceGroupByID() string {
+	return `SELECT g.id, g.name, g.description,
+	COALESCE(array_agg(rg.resource_id::text) FILTER (WHERE rg.resource_id IS NOT NULL), '{}')
+	FROM membership.resource_groups g
+	LEFT JOIN membership.resource_group_resources rg
+	ON rg.group_id = g.id
+	WHERE g.id = $1
+	GROUP BY g.id;`
+}
+
+func (ResourceGroupDatabaseMethod) insertResourceGroup() string {
+	return `INSERT INTO membership.resource_groups(
+		name, description)
+		VALUES ($1, $2)
+		RETURNING id, name, description;`
+}
+
+func (ResourceGroupDatabaseMethod) updateResourceGroup() string {
+	return `UPDATE membership.resource_groups
+	SET name=$2, description=$3
+	WHERE id=$1
+	RETURNING id, name, description;`
+}
+
+func (ResourceGroupDatabaseMethod) deleteResourceGroup() string {
+	return `DELETE FROM membership.resource_groups
+	WHERE id = $1;`
+}
+
+func (ResourceGroupDatabaseMethod) getResourceGroupMembers() string {
+	return `SELECT m.id, m.name, m.email, COALESCE(m.rfid, ''), m.member_tier_id
+	FROM membership.member_resource_groups mg
+	INNER JOIN membership.members m
+	ON m.id = mg.member_id
+	WHERE mg.group_id = $1
+	ORDER BY m.name;`
+}
+
+func (ResourceGroupDatabaseMethod) insertResourceGroupMembers() string {
+	return `INSERT INTO membership.member_resource_groups(member_id, group_id)
+	SELECT id, $2 FROM membership.members WHERE email = ANY($1::text[])
+	ON CONFLICT DO NOTHING;`
+}
+
+func (ResourceGroupDatabaseMethod) deleteResourceGroupMember() string {
+	return `DELETE FROM membership.member_resource_groups
+	WHERE member_id = $1 AND group_id = $2;`
+}
+
+func (ResourceGroupDatabaseMethod) insertResourceGroupResource() string {
+	return `INSERT INTO membership.resource_group_resources(group_id, resource_id)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING;`
+}
+
+func (ResourceGroupDatabaseMethod) deleteResourceGroupResource() string {
+	return `DELETE FROM membership.resource_group_resources
+	WHERE group_id = $1 AND resource_id = $2;`
+}
+
+// revokeMemberGroupAccess removes a member's access to the resources in a group
+//
+//	unless another of the member's groups still covers the resource
+//	access that was granted directly is kept
+func (ResourceGroupDatabaseMethod) revokeMemberGroupAccess() string {
+	return `WITH revoked AS (
+		DELETE FROM membership.member_resource mr
+		USING membership.resource_group_resources rg
+		WHERE rg.group_id = $2
+		AND mr.resource_id = rg.resource_id
+		AND mr.member_id = $1
+		AND mr.group_id IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM membership.member_resource_groups mg
+			INNER JOIN membership.resource_group_resources rg2
+			ON rg2.group_id = mg.group_id
+			WHERE mg.member_id = $1
+			AND mg.group_id != $2
+			AND rg2.resource_id = mr.resource_id)
+		RETURNING mr.member_id, mr.resource_id)
+	SELECT m.email, r.id, r.device_identifier, r.description, m.name, COALESCE(m.rfid, ''), m.member_tier_id
+	FROM revoked
+	INNER JOIN membership.members m
+	ON m.id = revoked.member_id
+	INNER JOIN membership.resources r
+	ON r.id = revoked.resource_id;`
+}
+
+// revokeResourceGroupAccess removes access to a resource for every member of a group
+//
+//	unless another of the member's groups still covers the resource
+//	access that was granted directly is kept
+func (ResourceGroupDatabaseMethod) revokeResourceGroupAccess() string {
+	return `WITH revoked AS (
+		DELETE FROM membership.member_resource mr
+		USING membership.member_resource_groups mg
+		WHERE mg.group_id = $1
+		AND mr.member_id = mg.member_id
+		AND mr.resource_id = $2
+		AND mr.group_id IS NOT NULL
+		AND NOT EXISTS (
+			SELECT 1 FROM membership.member_resource_groups mg2
+			INNER JOIN membership.resource_group_resources rg2
+			ON rg2.group_id = mg2.group_id
+			WHERE mg2.member_id = mr.member_id
+			AND mg2.group_id != $1
+			AND rg2.resource_id = $2)
+		RETURNING mr.member_id, mr.resource_id)
+	SELECT m.email, r.id, r.device_identifier, r.description, m.name, COALESCE(m.rfid, ''), m.member_tier_id
+	FROM revoked
+	INNER JOIN membership.members m
+	ON m.id = revoked.member_id
+	INNER JOIN membership.resources r
+	ON r.id = revoked.resource_id;`
+}
@@ -0,0 +1,110 @@
+package dbstore
+
+import (
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetMemberRoles returns the roles assigned to a member
+//
+//	every member also holds models.RoleMember, it isn't stored
+func (db *DatabaseStore) GetMemberRoles(email string) ([]models.Role, error) {
+	roles := []models.Role{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return roles, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, roleDbMethod.getMemberRoles(), email)
+	if err != nil {
+		return roles, fmt.Errorf("getMemberRoles failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var role models.Role
+		if err := rows.Scan(&role); err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// SetMemberRoles replaces the roles assigned to a member
+func (db *DatabaseStore) SetMemberRoles(email string, roles []models.Role) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var memberID string
+	if err := dbPool.QueryRow(db.ctx, roleDbMethod.getMemberID(), email).Scan(&memberID); err != nil {
+		return fmt.Errorf("error getting member: %v", err)
+	}
+
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	if _, err := tx.Exec(db.ctx, roleDbMethod.deleteMemberRoles(), memberID, names); err != nil {
+		return fmt.Errorf("error clearing member roles: %v", err)
+	}
+
+	if len(names) > 0 {
+		if _, err := tx.Exec(db.ctx, roleDbMethod.insertMemberRoles(), memberID, names); err != nil {
+			return fmt.Errorf("error setting member roles: %v", err)
+		}
+	}
+
+	return tx.Commit(db.ctx)
+}
+
+// GetMembersWithRole returns the members a role has been assigned to
+func (db *DatabaseStore) GetMembersWithRole(role models.Role) ([]models.Member, error) {
+	members := []models.Member{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return members, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	rows, err := dbPool.Query(db.ctx, roleDbMethod.getMembersWithRole(), string(role))
+	if err != nil {
+		return members, fmt.Errorf("getMembersWithRole failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var m models.Member
+		if err := rows.Scan(&m.ID, &m.Name, &m.Email, &m.RFID, &m.Level); err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		members = append(members, m)
+	}
+
+	return members, nil
+}
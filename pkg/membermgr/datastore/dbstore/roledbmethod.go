@@ -0,0 +1,39 @@
+package dbstore
+
+var roleDbMethod RoleDatabaseMethod
+
+// RoleDatabaseMethod -- method container that holds the extension methods to query the role tables
+type RoleDatabaseMethod struct{}
+
+func (RoleDatabaseMethod) getMemberID() string {
+	return `SELECT id FROM membership.members WHERE email = $1;`
+}
+
+func (RoleDatabaseMethod) getMemberRoles() string {
+	return `SELECT role
+	FROM membership.member_roles
+	JOIN membership.members ON membership.members.id = membership.member_roles.member_id
+	WHERE email = $1
+	ORDER BY role;`
+}
+
+// deleteMemberRoles removes the roles that aren't in $2
+//
+//	roles that stay aren't touched, changing a role signs the member out of their sessions
+func (RoleDatabaseMethod) deleteMemberRoles() string {
+	return `DELETE FROM membership.member_roles WHERE member_id = $1 AND role <> ALL($2::text[]);`
+}
+
+func (RoleDatabaseMethod) insertMemberRoles() string {
+	return `INSERT INTO membership.member_roles(member_id, role)
+	SELECT $1, unnest($2::text[])
+	ON CONFLICT DO NOTHING;`
+}
+
+func (RoleDatabaseMethod) getMembersWithRole() string {
+	return `SELECT id, name, email, COALESCE(rfid, ''), member_tier_id
+	FROM membership.member_roles
+	JOIN membership.members ON membership.members.id = membership.member_roles.member_id
+	WHERE role = $1
+	ORDER BY name;`
+}
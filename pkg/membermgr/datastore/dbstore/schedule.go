@@ -0,0 +1,134 @@
+package dbstore
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetAccessSchedules returns the schedules attached to a resource
+//
+//	if resourceID is empty, every schedule is returned
+func (db *DatabaseStore) GetAccessSchedules(resourceID string) ([]models.AccessSchedule, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var schedules []models.AccessSchedule
+
+	query := scheduleDbMethod.getAccessSchedules()
+	args := []interface{}{}
+	if len(resourceID) > 0 {
+		query = scheduleDbMethod.getAccessSchedulesByResourceID()
+		args = append(args, resourceID)
+	}
+
+	rows, err := dbPool.Query(db.ctx, query, args...)
+	if err != nil {
+		return schedules, fmt.Errorf("getAccessSchedules failed: %v", err)
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var s models.AccessSchedule
+		err = rows.Scan(&s.ID, &s.ResourceID, &s.TierID, &s.Weekdays, &s.StartTime, &s.EndTime)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		schedules = append(schedules, s)
+	}
+
+	return schedules, nil
+}
+
+// GetAccessSchedule returns one schedule
+func (db *DatabaseStore) GetAccessSchedule(id string) (models.AccessSchedule, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.AccessSchedule{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	var s models.AccessSchedule
+
+	err = dbPool.QueryRow(db.ctx, scheduleDbMethod.getAccessScheduleByID(), id).Scan(&s.ID, &s.ResourceID, &s.TierID, &s.Weekdays, &s.StartTime, &s.EndTime)
+	if err == pgx.ErrNoRows {
+		return s, errors.New("access schedule not found")
+	}
+	if err != nil {
+		return s, fmt.Errorf("getAccessSchedule failed: %v", err)
+	}
+
+	return s, nil
+}
+
+// AddAccessSchedule stores a new schedule
+func (db *DatabaseStore) AddAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var schedule models.AccessSchedule
+
+	err = dbPool.QueryRow(db.ctx, scheduleDbMethod.insertAccessSchedule(), s.ResourceID, s.TierID, s.Weekdays, s.StartTime, s.EndTime).Scan(&schedule.ID, &schedule.ResourceID, &schedule.TierID, &schedule.Weekdays, &schedule.StartTime, &schedule.EndTime)
+	if err != nil {
+		return schedule, fmt.Errorf("error inserting access schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+// UpdateAccessSchedule updates an existing schedule
+func (db *DatabaseStore) UpdateAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	var schedule models.AccessSchedule
+
+	if len(s.ID) == 0 {
+		return schedule, errors.New("invalid schedule id")
+	}
+
+	err = dbPool.QueryRow(db.ctx, scheduleDbMethod.updateAccessSchedule(), s.ID, s.ResourceID, s.TierID, s.Weekdays, s.StartTime, s.EndTime).Scan(&schedule.ID, &schedule.ResourceID, &schedule.TierID, &schedule.Weekdays, &schedule.StartTime, &schedule.EndTime)
+	if err != nil {
+		return schedule, fmt.Errorf("error updating access schedule: %v", err)
+	}
+
+	return schedule, nil
+}
+
+// DeleteAccessSchedule removes a schedule
+func (db *DatabaseStore) DeleteAccessSchedule(id string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+	}
+	defer dbPool.Close()
+
+	commandTag, err := dbPool.Exec(db.ctx, scheduleDbMethod.deleteAccessSchedule(), id)
+	if err != nil {
+		return fmt.Errorf("deleteAccessSchedule failed: %v", err)
+	}
+
+	if commandTag.RowsAffected() != 1 {
+		return errors.New("no row affected")
+	}
+
+	return nil
+}
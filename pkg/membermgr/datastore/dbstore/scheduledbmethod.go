@@ -0,0 +1,44 @@
+package dbstore
+
+var scheduleDbMethod ScheduleDatabaseMethod
+
+// ScheduleDatabaseMethod -- method container that holds the extension methods to query the access_schedules table
+type ScheduleDatabaseMethod struct{}
+
+func (ScheduleDatabaseMethod) getAccessSchedules() string {
+	return `SELECT id, resource_id, member_tier_id, weekdays, start_time, end_time
+	FROM membership.access_schedules
+	ORDER BY resource_id, member_tier_id, start_time;`
+}
+
+func (ScheduleDatabaseMethod) getAccessSchedulesByResourceID() string {
+	return `SELECT id, resource_id, member_tier_id, weekdays, start_time, end_time
+	FROM membership.access_schedules
+	WHERE resource_id = $1
+	ORDER BY member_tier_id, start_time;`
+}
+
+func (ScheduleDatabaseMethod) getAccessScheduleByID() string {
+	return `SELECT id, resource_id, member_tier_id, weekdays, start_time, end_time
+	FROM membership.access_schedules
+	WHERE id = $1;`
+}
+
+func (ScheduleDatabaseMethod) insertAccessSchedule() string {
+	return `INSERT INTO membership.access_schedules(
+		resource_id, member_tier_id, weekdays, start_time, end_time)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, resource_id, member_tier_id, weekdays, start_time, end_time;`
+}
+
+func (ScheduleDatabaseMethod) updateAccessSchedule() string {
+	return `UPDATE membership.access_schedules
+	SET resource_id=$2, member_tier_id=$3, weekdays=$4, start_time=$5, end_time=$6
+	WHERE id=$1
+	RETURNING id, resource_id, member_tier_id, weekdays, start_time, end_time;`
+}
+
+func (ScheduleDatabaseMethod) deleteAccessSchedule() string {
+	return `DELETE FROM membership.access_schedules
+	WHERE id = $1;`
+}
@@ -0,0 +1,117 @@
+package dbstore
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// CreateSession stores a new session, session.RefreshHash is the hash of its first refresh token
+func (db *DatabaseStore) CreateSession(session models.Session) (models.Session, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.Session{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	created, err := scanSession(dbPool.QueryRow(db.ctx, sessionDbMethod.insertSession(), session.Email, session.RefreshHash, session.UserAgent, session.IPAddress, session.ExpiresAt))
+	if err != nil {
+		return created, fmt.Errorf("error inserting session: %v", err)
+	}
+
+	return created, nil
+}
+
+// GetSession returns a session, including revoked and expired ones
+func (db *DatabaseStore) GetSession(id string) (models.Session, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return models.Session{}, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	session, err := scanSession(dbPool.QueryRow(db.ctx, sessionDbMethod.getSession(), id))
+	if err != nil {
+		return session, fmt.Errorf("getSession failed: %v", err)
+	}
+
+	return session, nil
+}
+
+// GetSessions returns a user's active sessions, most recently used first
+func (db *DatabaseStore) GetSessions(email string) ([]models.Session, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return nil, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	sessions := []models.Session{}
+
+	rows, err := dbPool.Query(db.ctx, sessionDbMethod.getSessions(), email)
+	if err != nil {
+		return sessions, fmt.Errorf("getSessions failed: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			log.Errorf("error scanning row: %s", err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+// RotateSession replaces a session's refresh token and extends it
+//
+//	it returns false if refreshHash isn't the session's current token, or the session has ended
+func (db *DatabaseStore) RotateSession(id string, refreshHash string, newRefreshHash string, expiresAt time.Time) (bool, error) {
+	return db.execUsed(sessionDbMethod.rotateSession(), "rotateSession", id, refreshHash, newRefreshHash, expiresAt)
+}
+
+// RevokeSession ends a session
+func (db *DatabaseStore) RevokeSession(id string) error {
+	changed, err := db.execUsed(sessionDbMethod.revokeSession(), "revokeSession", id)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("revokeSession failed: session not found")
+	}
+	return nil
+}
+
+// RevokeSessions ends every session a user has, it returns how many there were
+func (db *DatabaseStore) RevokeSessions(email string) (int64, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return 0, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tag, err := dbPool.Exec(db.ctx, sessionDbMethod.revokeSessions(), email)
+	if err != nil {
+		return 0, fmt.Errorf("revokeSessions failed: %v", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanSession(row pgx.Row) (models.Session, error) {
+	var s models.Session
+	err := row.Scan(&s.ID, &s.Email, &s.UserAgent, &s.IPAddress, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt, &s.RevokedAt, &s.RefreshHash, &s.PreviousHash, &s.RotatedAt)
+	return s, err
+}
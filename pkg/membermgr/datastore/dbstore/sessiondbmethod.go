@@ -0,0 +1,49 @@
+package dbstore
+
+var sessionDbMethod SessionDatabaseMethod
+
+// SessionDatabaseMethod -- method container that holds the extension methods to query sessions
+type SessionDatabaseMethod struct{}
+
+const sessionColumns = `id::text, email, user_agent, ip_address, created_at, last_used_at, expires_at, revoked_at,
+	refresh_hash, COALESCE(previous_hash, ''), rotated_at`
+
+func (SessionDatabaseMethod) insertSession() string {
+	return `INSERT INTO membership.sessions(
+		email, refresh_hash, user_agent, ip_address, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + sessionColumns + `;`
+}
+
+func (SessionDatabaseMethod) getSession() string {
+	return `SELECT ` + sessionColumns + `
+	FROM membership.sessions
+	WHERE id = $1;`
+}
+
+// getSessions only returns sessions that can still be used
+func (SessionDatabaseMethod) getSessions() string {
+	return `SELECT ` + sessionColumns + `
+	FROM membership.sessions
+	WHERE lower(email) = lower($1) AND revoked_at IS NULL AND expires_at > now()
+	ORDER BY last_used_at DESC;`
+}
+
+// rotateSession only replaces the refresh token it was given, so a token can't be rotated twice
+func (SessionDatabaseMethod) rotateSession() string {
+	return `UPDATE membership.sessions
+	SET previous_hash = refresh_hash, refresh_hash = $3, rotated_at = now(), last_used_at = now(), expires_at = $4
+	WHERE id = $1 AND refresh_hash = $2 AND revoked_at IS NULL AND expires_at > now();`
+}
+
+func (SessionDatabaseMethod) revokeSession() string {
+	return `UPDATE membership.sessions
+	SET revoked_at = COALESCE(revoked_at, now())
+	WHERE id = $1;`
+}
+
+func (SessionDatabaseMethod) revokeSessions() string {
+	return `UPDATE membership.sessions
+	SET revoked_at = now()
+	WHERE lower(email) = lower($1) AND revoked_at IS NULL;`
+}
@@ -0,0 +1,157 @@
+package dbstore
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+// GetTwoFactor returns a user's two-factor enrolment
+func (db *DatabaseStore) GetTwoFactor(email string) (models.TwoFactor, error) {
+	tf := models.TwoFactor{Email: strings.ToLower(email)}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return tf, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	err = dbPool.QueryRow(db.ctx, twoFactorDbMethod.getTwoFactor(), tf.Email).Scan(&tf.Secret, &tf.Enabled, &tf.EnrollmentApproved, &tf.LastStep, &tf.RecoveryCodesLeft)
+	if err == pgx.ErrNoRows {
+		return tf, datastore.ErrUserNotFound
+	}
+	if err != nil {
+		return tf, fmt.Errorf("getTwoFactor failed: %v", err)
+	}
+
+	return tf, nil
+}
+
+// SetTwoFactorSecret starts enrolling a user, it fails if they are already enrolled
+func (db *DatabaseStore) SetTwoFactorSecret(email string, secret string) error {
+	return db.execOne(twoFactorDbMethod.setTwoFactorSecret(), "setTwoFactorSecret", strings.ToLower(email), secret)
+}
+
+// ApproveTwoFactorEnrollment lets a user who has to enrol start enrolling
+func (db *DatabaseStore) ApproveTwoFactorEnrollment(email string) error {
+	return db.execOne(twoFactorDbMethod.approveTwoFactorEnrollment(), "approveTwoFactorEnrollment", strings.ToLower(email))
+}
+
+// EnableTwoFactor finishes enrolling a user and replaces their recovery codes
+//
+//	step is the time step of the code they confirmed with
+func (db *DatabaseStore) EnableTwoFactor(email string, step int64, recoveryCodeHashes []string) error {
+	email = strings.ToLower(email)
+	return db.withRecoveryCodes(email, recoveryCodeHashes, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(db.ctx, twoFactorDbMethod.enableTwoFactor(), email, step)
+		if err != nil {
+			return fmt.Errorf("enableTwoFactor failed: %v", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("enableTwoFactor failed: user hasn't started enrolling")
+		}
+		return nil
+	})
+}
+
+// SetRecoveryCodes replaces a user's recovery codes
+func (db *DatabaseStore) SetRecoveryCodes(email string, recoveryCodeHashes []string) error {
+	return db.withRecoveryCodes(strings.ToLower(email), recoveryCodeHashes, func(pgx.Tx) error { return nil })
+}
+
+// DisableTwoFactor removes a user's enrolment and recovery codes
+//
+//	a user who has to enrol again has to confirm it from their email again
+func (db *DatabaseStore) DisableTwoFactor(email string) error {
+	email = strings.ToLower(email)
+	return db.withRecoveryCodes(email, nil, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(db.ctx, twoFactorDbMethod.disableTwoFactor(), email)
+		if err != nil {
+			return fmt.Errorf("disableTwoFactor failed: %v", err)
+		}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("disableTwoFactor failed: user not found")
+		}
+		return nil
+	})
+}
+
+// UseTOTPStep records that a code for step was used
+//
+//	it returns false if a code for that step or a later one was already used
+func (db *DatabaseStore) UseTOTPStep(email string, step int64) (bool, error) {
+	return db.execUsed(twoFactorDbMethod.useTOTPStep(), "useTOTPStep", strings.ToLower(email), step)
+}
+
+// UseRecoveryCode removes a recovery code, it returns false if the user doesn't have it
+func (db *DatabaseStore) UseRecoveryCode(email string, recoveryCodeHash string) (bool, error) {
+	return db.execUsed(twoFactorDbMethod.useRecoveryCode(), "useRecoveryCode", strings.ToLower(email), recoveryCodeHash)
+}
+
+// withRecoveryCodes runs update and replaces the user's recovery codes in one transaction
+func (db *DatabaseStore) withRecoveryCodes(email string, recoveryCodeHashes []string, update func(tx pgx.Tx) error) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tx, err := dbPool.Begin(db.ctx)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+	defer tx.Rollback(db.ctx)
+
+	if err := update(tx); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(db.ctx, twoFactorDbMethod.deleteRecoveryCodes(), email); err != nil {
+		return fmt.Errorf("error clearing recovery codes: %v", err)
+	}
+
+	if len(recoveryCodeHashes) > 0 {
+		if _, err := tx.Exec(db.ctx, twoFactorDbMethod.insertRecoveryCodes(), email, recoveryCodeHashes); err != nil {
+			return fmt.Errorf("error setting recovery codes: %v", err)
+		}
+	}
+
+	return tx.Commit(db.ctx)
+}
+
+// execOne runs a statement that has to change exactly one row
+func (db *DatabaseStore) execOne(query string, name string, args ...interface{}) error {
+	changed, err := db.execUsed(query, name, args...)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return fmt.Errorf("%s failed: no matching user", name)
+	}
+	return nil
+}
+
+// execUsed runs a statement and reports whether it changed a row
+func (db *DatabaseStore) execUsed(query string, name string, args ...interface{}) (bool, error) {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return false, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tag, err := dbPool.Exec(db.ctx, query, args...)
+	if err != nil {
+		return false, fmt.Errorf("%s failed: %v", name, err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
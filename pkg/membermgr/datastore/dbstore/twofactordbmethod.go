@@ -0,0 +1,59 @@
+package dbstore
+
+var twoFactorDbMethod TwoFactorDatabaseMethod
+
+// TwoFactorDatabaseMethod -- method container that holds the extension methods to query two-factor enrolments
+type TwoFactorDatabaseMethod struct{}
+
+func (TwoFactorDatabaseMethod) getTwoFactor() string {
+	return `SELECT COALESCE(totp_secret, ''), totp_enabled, totp_enrollment_approved, totp_last_step,
+		(SELECT count(*) FROM membership.user_recovery_codes WHERE membership.user_recovery_codes.email = membership.users.email)
+	FROM membership.users
+	WHERE email = $1;`
+}
+
+// setTwoFactorSecret starts enrolling, it never replaces an enabled enrolment
+func (TwoFactorDatabaseMethod) setTwoFactorSecret() string {
+	return `UPDATE membership.users
+	SET totp_secret = $2, totp_last_step = 0
+	WHERE email = $1 AND totp_enabled = false;`
+}
+
+func (TwoFactorDatabaseMethod) approveTwoFactorEnrollment() string {
+	return `UPDATE membership.users
+	SET totp_enrollment_approved = true
+	WHERE email = $1;`
+}
+
+func (TwoFactorDatabaseMethod) enableTwoFactor() string {
+	return `UPDATE membership.users
+	SET totp_enabled = true, totp_last_step = $2
+	WHERE email = $1 AND totp_secret IS NOT NULL;`
+}
+
+func (TwoFactorDatabaseMethod) disableTwoFactor() string {
+	return `UPDATE membership.users
+	SET totp_secret = NULL, totp_enabled = false, totp_enrollment_approved = false, totp_last_step = 0
+	WHERE email = $1;`
+}
+
+// useTOTPStep only accepts steps after the last one used
+func (TwoFactorDatabaseMethod) useTOTPStep() string {
+	return `UPDATE membership.users
+	SET totp_last_step = $2
+	WHERE email = $1 AND totp_enabled = true AND totp_last_step < $2;`
+}
+
+func (TwoFactorDatabaseMethod) deleteRecoveryCodes() string {
+	return `DELETE FROM membership.user_recovery_codes WHERE email = $1;`
+}
+
+func (TwoFactorDatabaseMethod) insertRecoveryCodes() string {
+	return `INSERT INTO membership.user_recovery_codes(email, code_hash)
+	SELECT $1, unnest($2::text[])
+	ON CONFLICT DO NOTHING;`
+}
+
+func (TwoFactorDatabaseMethod) useRecoveryCode() string {
+	return `DELETE FROM membership.user_recovery_codes WHERE email = $1 AND code_hash = $2;`
+}
@@ -21,14 +21,14 @@ func (db *DatabaseStore) RegisterUser(creds models.Credentials) error {
 	}
 	defer dbPool.Close()
 
-	if len(creds.Password) == 0 {
-		return fmt.Errorf("not a valid password")
-	}
-
 	if len(creds.Email) == 0 {
 		return fmt.Errorf("not a valid email")
 	}
 
+	if err := models.CheckPasswordPolicy(creds.Password, creds.Email); err != nil {
+		return err
+	}
+
 	// require the user to be a member
 	_, err = db.GetMemberByEmail(creds.Email)
 	if err != nil {
@@ -43,12 +43,15 @@ func (db *DatabaseStore) RegisterUser(creds models.Credentials) error {
 	}
 
 	// Next, insert the email, along with the hashed password into the database
-	rows, err := dbPool.Query(context.Background(), userDbMethod.registerUser(), creds.Email, string(hashedPassword))
+	// the user can't sign in until they verify their email
+	tag, err := dbPool.Exec(context.Background(), userDbMethod.registerUser(), creds.Email, string(hashedPassword))
 	if err != nil {
 		return fmt.Errorf("registerUser failed: %s", err)
 	}
 
-	defer rows.Close()
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("user is already registered")
+	}
 
 	return nil
 }
@@ -65,11 +68,16 @@ func (db *DatabaseStore) UserSignin(email string, password string) error {
 	storedCreds := &models.Credentials{}
 
 	// Get the existing entry present in the database for the given user
-	row := dbPool.QueryRow(context.Background(), userDbMethod.getUserPassword(), strings.ToLower(email)).Scan(&storedCreds.Password)
+	var verified bool
+	row := dbPool.QueryRow(context.Background(), userDbMethod.getUserPassword(), strings.ToLower(email)).Scan(&storedCreds.Password, &verified)
 	if row == pgx.ErrNoRows {
 		return fmt.Errorf("Unauthorized")
 	}
 
+	if !verified {
+		return fmt.Errorf("unauthorized: email has not been verified")
+	}
+
 	// Compare the stored hashed password, with the hashed version of the password that was received
 	if err := bcrypt.CompareHashAndPassword([]byte(storedCreds.Password), []byte(password)); err != nil {
 		// If the two passwords don't match, return a 401 status
@@ -95,3 +103,74 @@ func (db *DatabaseStore) GetUser(email string) (models.UserResponse, error) {
 	}
 	return userResponse, nil
 }
+
+// GetUserAccount returns a user's stored login
+func (db *DatabaseStore) GetUserAccount(email string) (models.UserAccount, error) {
+	account := models.UserAccount{}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return account, fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	account.Email = strings.ToLower(email)
+	err = dbPool.QueryRow(db.ctx, userDbMethod.getUserPassword(), account.Email).Scan(&account.PasswordHash, &account.Verified)
+	if err != nil {
+		return account, fmt.Errorf("getUserAccount failed: %v", err)
+	}
+
+	return account, nil
+}
+
+// VerifyUser marks a user's email as verified so they can sign in
+func (db *DatabaseStore) VerifyUser(email string) error {
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tag, err := dbPool.Exec(db.ctx, userDbMethod.verifyUser(), strings.ToLower(email))
+	if err != nil {
+		return fmt.Errorf("verifyUser failed: %v", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("verifyUser failed: user not found")
+	}
+
+	return nil
+}
+
+// UpdatePassword sets a new password for a registered user
+func (db *DatabaseStore) UpdatePassword(creds models.Credentials) error {
+	if err := models.CheckPasswordPolicy(creds.Password, creds.Email); err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(creds.Password), 8)
+	if err != nil {
+		return err
+	}
+
+	dbPool, err := pgxpool.Connect(db.ctx, db.connectionString)
+	if err != nil {
+		log.Printf("got error: %v\n", err)
+		return fmt.Errorf("error connecting to DB: %v", err)
+	}
+	defer dbPool.Close()
+
+	tag, err := dbPool.Exec(db.ctx, userDbMethod.updatePassword(), strings.ToLower(creds.Email), string(hashedPassword))
+	if err != nil {
+		return fmt.Errorf("updatePassword failed: %v", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("updatePassword failed: user not found")
+	}
+
+	return nil
+}
@@ -5,20 +5,32 @@ var userDbMethod UserDatabaseMethod
 // UserDatabaseMethod -- method container that holds the extension methods to query the user table
 type UserDatabaseMethod struct{}
 
+// registerUser replaces the password of a user who hasn't verified their email yet,
+// so someone who mistyped can sign up again, but never touches a verified user
 func (user *UserDatabaseMethod) registerUser() string {
 	const registerUserQuery = `INSERT INTO membership.users(
-		email, password)
-		VALUES ($1, $2);`
+		email, password, verified)
+		VALUES ($1, $2, false)
+		ON CONFLICT (email) DO UPDATE SET password = EXCLUDED.password
+		WHERE membership.users.verified = false;`
 
 	return registerUserQuery
 }
 
 func (user *UserDatabaseMethod) getUserPassword() string {
-	const getUserPasswordQuery = `SELECT password from membership.users where email=$1`
+	const getUserPasswordQuery = `SELECT password, verified from membership.users where email=$1`
 
 	return getUserPasswordQuery
 }
 
+func (user *UserDatabaseMethod) verifyUser() string {
+	return `UPDATE membership.users SET verified = true WHERE email = $1;`
+}
+
+func (user *UserDatabaseMethod) updatePassword() string {
+	return `UPDATE membership.users SET password = $2 WHERE email = $1;`
+}
+
 func (user *UserDatabaseMethod) getUser() string {
 	const getUserQuery = `SELECT email from membership.users where email=$1`
 
@@ -0,0 +1,81 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) CreateAPIKey(key models.APIKey, keyHash string) (models.APIKey, error) {
+	for _, existing := range i.APIKeys {
+		if existing.Name == key.Name {
+			return models.APIKey{}, datastore.ErrAPIKeyNameTaken
+		}
+	}
+
+	if i.APIKeyHashes == nil {
+		i.APIKeyHashes = map[string]string{}
+	}
+
+	key.ID = strconv.Itoa(len(i.APIKeys) + 1)
+	key.CreatedAt = time.Now()
+	i.APIKeys = append(i.APIKeys, key)
+	i.APIKeyHashes[keyHash] = key.ID
+	return key, nil
+}
+
+func (i *In_memory) GetAPIKeys() ([]models.APIKey, error) {
+	return append([]models.APIKey{}, i.APIKeys...), nil
+}
+
+func (i *In_memory) GetAPIKeyByHash(keyHash string) (models.APIKey, error) {
+	id, ok := i.APIKeyHashes[keyHash]
+	if !ok {
+		return models.APIKey{}, errors.New("api key not found")
+	}
+
+	key, err := i.apiKey(id)
+	if err != nil || key.RevokedAt != nil {
+		return key, err
+	}
+
+	now := time.Now()
+	touched := key
+	touched.LastUsedAt = &now
+	i.setAPIKey(touched)
+	return key, nil
+}
+
+func (i *In_memory) RevokeAPIKey(id string) (models.APIKey, error) {
+	key, err := i.apiKey(id)
+	if err != nil {
+		return key, err
+	}
+
+	if key.RevokedAt == nil {
+		now := time.Now()
+		key.RevokedAt = &now
+		i.setAPIKey(key)
+	}
+	return key, nil
+}
+
+func (i *In_memory) apiKey(id string) (models.APIKey, error) {
+	for _, key := range i.APIKeys {
+		if key.ID == id {
+			return key, nil
+		}
+	}
+	return models.APIKey{}, errors.New("api key not found")
+}
+
+func (i *In_memory) setAPIKey(key models.APIKey) {
+	for n, existing := range i.APIKeys {
+		if existing.ID == key.ID {
+			i.APIKeys[n] = key
+		}
+	}
+}
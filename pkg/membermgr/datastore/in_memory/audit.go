@@ -0,0 +1,22 @@
+package in_memory
+
+import (
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) LogAuditEvent(entry models.AuditEntry) error {
+	entry.ID = int64(len(i.AuditLog) + 1)
+	entry.CreatedAt = time.Now()
+	i.AuditLog = append(i.AuditLog, entry)
+	return nil
+}
+
+func (i *In_memory) GetAuditEvents(limit int, offset int) ([]models.AuditEntry, error) {
+	entries := []models.AuditEntry{}
+	for idx := len(i.AuditLog) - 1 - offset; idx >= 0 && len(entries) < limit; idx-- {
+		entries = append(entries, i.AuditLog[idx])
+	}
+	return entries, nil
+}
@@ -0,0 +1,110 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetCertifications() ([]models.Certification, error) {
+	return i.Certifications, nil
+}
+
+func (i *In_memory) AddCertification(c models.Certification) (models.Certification, error) {
+	c.ID = strconv.Itoa(len(i.Certifications) + 1)
+	i.Certifications = append(i.Certifications, c)
+	return c, nil
+}
+
+func (i *In_memory) UpdateCertification(c models.Certification) (models.Certification, error) {
+	for idx, existing := range i.Certifications {
+		if existing.ID != c.ID {
+			continue
+		}
+		i.Certifications[idx] = c
+		return c, nil
+	}
+	return models.Certification{}, errors.New("not found")
+}
+
+func (i *In_memory) DeleteCertification(id string) error {
+	for idx, existing := range i.Certifications {
+		if existing.ID != id {
+			continue
+		}
+		i.Certifications = append(i.Certifications[:idx], i.Certifications[idx+1:]...)
+		return nil
+	}
+	return errors.New("not found")
+}
+
+func (i *In_memory) RecordTraining(certificationID string, memberEmail string, trainerEmail string, trainedAt time.Time) (models.MemberCertification, error) {
+	member, err := i.GetMemberByEmail(memberEmail)
+	if err != nil {
+		return models.MemberCertification{}, err
+	}
+
+	for _, c := range i.Certifications {
+		if c.ID != certificationID {
+			continue
+		}
+
+		mc := models.MemberCertification{
+			ID:                strconv.Itoa(len(i.MemberCertifications) + 1),
+			MemberID:          member.ID,
+			CertificationID:   c.ID,
+			CertificationName: c.Name,
+			TrainerEmail:      trainerEmail,
+			TrainedAt:         trainedAt,
+		}
+		if c.ValidForDays > 0 {
+			expiresAt := trainedAt.AddDate(0, 0, c.ValidForDays)
+			mc.ExpiresAt = &expiresAt
+		}
+
+		i.MemberCertifications = append(i.MemberCertifications, mc)
+		return mc, nil
+	}
+	return models.MemberCertification{}, errors.New("certification not found")
+}
+
+func (i *In_memory) GetMemberCertifications(email string) ([]models.MemberCertification, error) {
+	member, err := i.GetMemberByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	certifications := []models.MemberCertification{}
+	for _, mc := range i.MemberCertifications {
+		if mc.MemberID == member.ID {
+			certifications = append(certifications, mc)
+		}
+	}
+	return certifications, nil
+}
+
+func (i *In_memory) GetResourceCertifications(resourceID string) ([]models.Certification, error) {
+	certifications := []models.Certification{}
+	for _, id := range i.ResourceCertifications[resourceID] {
+		for _, c := range i.Certifications {
+			if c.ID == id {
+				certifications = append(certifications, c)
+			}
+		}
+	}
+	return certifications, nil
+}
+
+func (i *In_memory) SetResourceCertifications(resourceID string, certificationIDs []string) error {
+	if i.ResourceCertifications == nil {
+		i.ResourceCertifications = map[string][]string{}
+	}
+	i.ResourceCertifications[resourceID] = certificationIDs
+	return nil
+}
+
+func (i *In_memory) GetExpiredCertifiedAccess() ([]models.ExpiredCertificationAccess, error) {
+	return []models.ExpiredCertificationAccess{}, nil
+}
@@ -0,0 +1,102 @@
+package in_memory
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (store *In_memory) AnnounceDevice(announcement models.DeviceAnnouncement) (models.Device, error) {
+	if store.Devices == nil {
+		store.Devices = map[string]models.Device{}
+	}
+
+	now := time.Now()
+	d, ok := store.Devices[announcement.MAC]
+	if !ok {
+		d = models.Device{MAC: announcement.MAC, FirstSeen: now}
+	}
+
+	d.Model = announcement.Model
+	d.Firmware = announcement.Firmware
+	d.Address = announcement.Address
+	d.LastSeen = now
+	if !ok {
+		d.LastSeen = d.FirstSeen
+	}
+
+	store.Devices[d.MAC] = d
+	return d, nil
+}
+
+func (store *In_memory) GetPendingDevices() ([]models.Device, error) {
+	devices := []models.Device{}
+	for _, d := range store.Devices {
+		if len(d.ResourceID) == 0 {
+			devices = append(devices, d)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].LastSeen.After(devices[j].LastSeen)
+	})
+	return devices, nil
+}
+
+func (store *In_memory) GetDevice(mac string) (models.Device, error) {
+	d, ok := store.Devices[mac]
+	if !ok {
+		return d, errors.New("device not found")
+	}
+	return d, nil
+}
+
+func (store *In_memory) SetDeviceResource(mac string, resourceID string) error {
+	d, ok := store.Devices[mac]
+	if !ok {
+		return errors.New("device not found")
+	}
+
+	d.ResourceID = resourceID
+	store.Devices[mac] = d
+	return nil
+}
+
+func (store *In_memory) GetResourceDevice(resourceID string) (models.Device, error) {
+	for _, d := range store.Devices {
+		if len(resourceID) > 0 && d.ResourceID == resourceID {
+			return d, nil
+		}
+	}
+	return models.Device{}, nil
+}
+
+func (store *In_memory) ApproveDevice(mac string, name string, isDefault bool) (models.Resource, error) {
+	d, ok := store.Devices[mac]
+	if !ok {
+		return models.Resource{}, errors.New("device not found")
+	}
+
+	if len(d.ResourceID) > 0 {
+		return models.Resource{}, fmt.Errorf("device %s has already been approved", mac)
+	}
+
+	r, err := store.RegisterResource(name, d.Address, isDefault, models.DriverESPRFID)
+	if err != nil {
+		return r, err
+	}
+
+	return r, store.SetDeviceResource(mac, r.ID)
+}
+
+func (store *In_memory) DeleteDevice(mac string) error {
+	if _, ok := store.Devices[mac]; !ok {
+		return errors.New("device not found")
+	}
+
+	delete(store.Devices, mac)
+	return nil
+}
@@ -0,0 +1,28 @@
+package in_memory
+
+import (
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetDoorModes() ([]models.DoorMode, error) {
+	return append([]models.DoorMode{}, i.DoorModes...), nil
+}
+
+func (i *In_memory) SetDoorMode(mode models.DoorMode) error {
+	modes := []models.DoorMode{}
+	for _, m := range i.DoorModes {
+		if m.ResourceID != mode.ResourceID {
+			modes = append(modes, m)
+		}
+	}
+
+	if mode.Mode != models.DoorModeNormal {
+		mode.SetAt = time.Now()
+		modes = append(modes, mode)
+	}
+
+	i.DoorModes = modes
+	return nil
+}
@@ -1,7 +1,166 @@
 package in_memory
 
-import "github.com/HackRVA/memberserver/pkg/membermgr/models"
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
 
 func (store *In_memory) LogAccessEvent(event models.LogMessage) error {
+	isKnown, _ := strconv.ParseBool(event.IsKnown)
+	store.AccessEvents = append(store.AccessEvents, models.AccessEvent{
+		ID:        fmt.Sprintf("%08d", len(store.AccessEvents)+1),
+		Type:      event.Type,
+		EventTime: time.Unix(event.EventTime, 0),
+		IsKnown:   isKnown,
+		Username:  event.Username,
+		RFID:      event.RFID,
+		Door:      event.Door,
+		Access:    event.Access,
+		MemberID:  event.MemberID,
+	})
+	return nil
+}
+
+func (store *In_memory) GetAccessEvents(filter models.AccessEventFilter) (models.AccessEventPage, error) {
+	events := make([]models.AccessEvent, len(store.AccessEvents))
+	copy(events, store.AccessEvents)
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].EventTime.Equal(events[j].EventTime) {
+			return events[i].ID > events[j].ID
+		}
+		return events[i].EventTime.After(events[j].EventTime)
+	})
+
+	page := models.AccessEventPage{Events: []models.AccessEvent{}}
+	for _, e := range events {
+		if filter.Limit > 0 && len(page.Events) == filter.Limit {
+			break
+		}
+		if len(filter.MemberID) > 0 && e.MemberID != filter.MemberID {
+			continue
+		}
+		if len(filter.RFID) > 0 && e.RFID != filter.RFID {
+			continue
+		}
+		if len(filter.Door) > 0 && e.Door != filter.Door {
+			continue
+		}
+		if filter.IsKnown != nil && e.IsKnown != *filter.IsKnown {
+			continue
+		}
+		if filter.Pseudonymised != nil && e.Pseudonymised != *filter.Pseudonymised {
+			continue
+		}
+		if filter.From != nil && e.EventTime.Before(*filter.From) {
+			continue
+		}
+		if filter.To != nil && !e.EventTime.Before(*filter.To) {
+			continue
+		}
+		if filter.After != nil && !filter.After.Before(e) {
+			continue
+		}
+		page.Events = append(page.Events, e)
+	}
+
+	if filter.Limit > 0 && len(page.Events) == filter.Limit {
+		page.NextCursor = models.CursorFor(page.Events[len(page.Events)-1]).Encode()
+	}
+
+	return page, nil
+}
+
+func (store *In_memory) CountAccessBetweenHours(memberID string, startHour int, endHour int, before time.Time) (int, error) {
+	count := 0
+	for _, e := range store.AccessEvents {
+		if e.MemberID != memberID || !e.IsKnown || !e.EventTime.Before(before) {
+			continue
+		}
+
+		hour := e.EventTime.Hour()
+		if startHour > endHour && (hour >= startHour || hour < endHour) {
+			count++
+		} else if startHour <= endHour && hour >= startHour && hour < endHour {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (store *In_memory) RollupAccessEvents(before time.Time) error {
+	daily := map[string]int{}
+	for i, s := range store.AccessEventDaily {
+		daily[dailyKey(s.Date, s.ResourceName)] = i
+	}
+
+	for i, e := range store.AccessEvents {
+		if e.RolledUp || !e.EventTime.Before(before) {
+			continue
+		}
+		store.AccessEvents[i].RolledUp = true
+
+		if !e.IsKnown {
+			continue
+		}
+
+		day := startOfDay(e.EventTime)
+		key := dailyKey(day, e.Door)
+		if _, ok := daily[key]; !ok {
+			daily[key] = len(store.AccessEventDaily)
+			store.AccessEventDaily = append(store.AccessEventDaily, models.AccessStats{Date: day, ResourceName: e.Door})
+		}
+		store.AccessEventDaily[daily[key]].AccessCount++
+	}
 	return nil
 }
+
+func (store *In_memory) DeleteAccessEvents(before time.Time) (int64, error) {
+	var kept []models.AccessEvent
+	for _, e := range store.AccessEvents {
+		if e.EventTime.Before(before) {
+			continue
+		}
+		kept = append(kept, e)
+	}
+
+	deleted := int64(len(store.AccessEvents) - len(kept))
+	store.AccessEvents = kept
+	return deleted, nil
+}
+
+func (store *In_memory) PseudonymiseAccessEvents(before time.Time, key string) (int64, error) {
+	var changed int64
+	for i, e := range store.AccessEvents {
+		if e.Pseudonymised || !e.EventTime.Before(before) {
+			continue
+		}
+
+		if len(e.RFID) > 0 {
+			mac := hmac.New(sha256.New, []byte(key))
+			mac.Write([]byte(e.RFID))
+			e.RFID = "anon-" + hex.EncodeToString(mac.Sum(nil))[:16]
+		}
+		e.Username = ""
+		e.MemberID = ""
+		e.Pseudonymised = true
+
+		store.AccessEvents[i] = e
+		changed++
+	}
+	return changed, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func dailyKey(day time.Time, door string) string {
+	return day.Format("2006-01-02") + "|" + door
+}
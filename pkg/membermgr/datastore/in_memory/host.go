@@ -0,0 +1,70 @@
+package in_memory
+
+import (
+	"sort"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (store *In_memory) GetResourceHosts(resourceID string) ([]string, error) {
+	return append([]string{}, store.ResourceHosts[resourceID]...), nil
+}
+
+func (store *In_memory) SetResourceHosts(resourceID string, emails []string) error {
+	if store.ResourceHosts == nil {
+		store.ResourceHosts = map[string][]string{}
+	}
+	store.ResourceHosts[resourceID] = append([]string{}, emails...)
+	return nil
+}
+
+func (store *In_memory) GetResourceGroupHosts(groupID string) ([]string, error) {
+	return append([]string{}, store.ResourceGroupHosts[groupID]...), nil
+}
+
+func (store *In_memory) SetResourceGroupHosts(groupID string, emails []string) error {
+	if store.ResourceGroupHosts == nil {
+		store.ResourceGroupHosts = map[string][]string{}
+	}
+	store.ResourceGroupHosts[groupID] = append([]string{}, emails...)
+	return nil
+}
+
+func (store *In_memory) GetHostedResources(email string) ([]models.Resource, error) {
+	resources := []models.Resource{}
+	for _, id := range store.hostedResourceIDs(email) {
+		if r, err := store.GetResourceByID(id); err == nil {
+			resources = append(resources, r)
+		}
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].Name < resources[j].Name })
+	return resources, nil
+}
+
+func (store *In_memory) IsResourceHost(email string, resourceID string) (bool, error) {
+	for _, id := range store.hostedResourceIDs(email) {
+		if id == resourceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// hostedResourceIDs is every resource a member hosts, directly or through a resource group
+func (store *In_memory) hostedResourceIDs(email string) []string {
+	ids := []string{}
+	for resourceID, hosts := range store.ResourceHosts {
+		if containsString(hosts, email) {
+			ids = append(ids, resourceID)
+		}
+	}
+
+	for _, g := range store.ResourceGroups {
+		if containsString(store.ResourceGroupHosts[g.ID], email) {
+			ids = append(ids, g.ResourceIDs...)
+		}
+	}
+
+	return ids
+}
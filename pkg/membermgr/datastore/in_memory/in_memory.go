@@ -6,8 +6,31 @@ import (
 )
 
 type In_memory struct {
-	Members map[string]models.Member
-	Tiers   []models.Tier
+	Members                map[string]models.Member
+	Tiers                  []models.Tier
+	Schedules              []models.AccessSchedule
+	Certifications         []models.Certification
+	MemberCertifications   []models.MemberCertification
+	ResourceCertifications map[string][]string
+	AuditLog               []models.AuditEntry
+	ResourceGroups         []models.ResourceGroup
+	ResourceGroupMembers   map[string][]string
+	DoorModes              []models.DoorMode
+	OpenWindows            []models.OpenWindow
+	AccessEvents           []models.AccessEvent
+	AccessEventDaily       []models.AccessStats
+	PresenceSharing        map[string]bool
+	Devices                map[string]models.Device
+	MemberRoles            map[string][]models.Role
+	Users                  map[string]models.UserAccount
+	ResourceHosts          map[string][]string
+	ResourceGroupHosts     map[string][]string
+	TwoFactor              map[string]models.TwoFactor
+	RecoveryCodes          map[string][]string
+	APIKeys                []models.APIKey
+	APIKeyHashes           map[string]string
+	Sessions               []models.Session
+	rateLimits             map[string]rateLimit
 }
 
 func Setup() (*In_memory, error) {
@@ -3,8 +3,12 @@ package in_memory
 import (
 	"errors"
 	"sort"
+	"strings"
 
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
 )
 
 func (i *In_memory) allocMembers() {
@@ -38,10 +42,19 @@ func (i *In_memory) GetMemberByEmail(email string) (models.Member, error) {
 }
 
 func (i *In_memory) GetMemberByRFID(rfid string) (models.Member, error) {
-	return models.Member{}, nil
+	for _, m := range i.Members {
+		if len(rfid) > 0 && m.RFID == rfid {
+			return m, nil
+		}
+	}
+	return models.Member{}, errors.New("error getting member by rfid: not found")
 }
 
 func (i *In_memory) AssignRFID(email string, rfid string) (models.Member, error) {
+	return i.AssignRFIDAtVersion(email, rfid, datastore.AnyVersion)
+}
+
+func (i *In_memory) AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error) {
 	if len(rfid) == 0 {
 		return models.Member{}, errors.New("not a valid rfid")
 	}
@@ -50,12 +63,19 @@ func (i *In_memory) AssignRFID(email string, rfid string) (models.Member, error)
 		if member.Email != email {
 			continue
 		}
+		if version != datastore.AnyVersion && member.Version != version {
+			return member, datastore.ErrVersionChanged
+		}
 		return member, nil
 	}
 	return models.Member{}, errors.New("user not found")
 }
 
 func (i *In_memory) UpdateMember(update models.Member) error {
+	return i.UpdateMemberAtVersion(update, datastore.AnyVersion)
+}
+
+func (i *In_memory) UpdateMemberAtVersion(update models.Member, version int) error {
 	if len(update.Name) == 0 {
 		return errors.New("fullname is required")
 	}
@@ -64,13 +84,66 @@ func (i *In_memory) UpdateMember(update models.Member) error {
 		return errors.New("email is required")
 	}
 
-	if _, ok := i.Members[update.Email]; !ok {
+	member, ok := i.Members[update.Email]
+	if !ok {
 		return errors.New("not found")
 	}
+	if version != datastore.AnyVersion && member.Version != version {
+		return datastore.ErrVersionChanged
+	}
+
+	subscriptionID := member.SubscriptionID
+	if len(update.SubscriptionID) > 0 {
+		subscriptionID = update.SubscriptionID
+	}
+
+	if member.Name != update.Name || member.SubscriptionID != subscriptionID {
+		member.Name = update.Name
+		member.SubscriptionID = subscriptionID
+		member.Version++
+		i.Members[update.Email] = member
+	}
 
 	return nil
 }
 
+// GetMemberPage sorts and filters the members the same way the database does
+func (i *In_memory) GetMemberPage(query models.MemberQuery) ([]models.Member, int, error) {
+	search := strings.ToLower(query.Search)
+	matching := []models.Member{}
+	for _, member := range i.Members {
+		if query.Active != nil && (models.MemberLevel(member.Level) != models.Inactive) != *query.Active {
+			continue
+		}
+		if len(search) > 0 && !matchesMember(search, member) {
+			continue
+		}
+		matching = append(matching, member)
+	}
+
+	sort.Slice(matching, func(a, b int) bool {
+		return strings.ToLower(matching[a].Email) < strings.ToLower(matching[b].Email)
+	})
+
+	page := []models.Member{}
+	for _, member := range matching {
+		if strings.ToLower(member.Email) > query.After && len(page) < query.Limit {
+			page = append(page, member)
+		}
+	}
+
+	return page, len(matching), nil
+}
+
+func matchesMember(search string, member models.Member) bool {
+	for _, field := range []string{member.Name, member.Email, member.RFID, member.SubscriptionID} {
+		if fuzzy.Match(search, strings.ToLower(field)) {
+			return true
+		}
+	}
+	return false
+}
+
 func (i *In_memory) UpdateMemberBySubscriptionID(subscriptionID string, update models.Member) error {
 	for _, m := range i.Members {
 		if m.SubscriptionID != update.SubscriptionID {
@@ -90,6 +163,9 @@ func (i *In_memory) AddNewMember(newMember models.Member) (models.Member, error)
 	if newMember.ID == "" {
 		newMember.ID = string(rune(len(i.Members)))
 	}
+	if newMember.Version == datastore.AnyVersion {
+		newMember.Version = 1
+	}
 	i.Members[newMember.Email] = newMember
 	return newMember, nil
 }
@@ -119,6 +195,11 @@ func (i *In_memory) SetMemberLevel(memberId string, level models.MemberLevel) er
 		update := member
 		update.Level = uint8(level)
 
+		// the database does this with a trigger
+		if member.Level != update.Level {
+			i.RevokeSessions(member.Email)
+		}
+
 		delete(i.Members, update.Email)
 
 		i.Members[update.Email] = update
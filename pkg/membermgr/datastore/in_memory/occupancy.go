@@ -0,0 +1,40 @@
+package in_memory
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+func (i *In_memory) GetPresenceSharing() ([]string, error) {
+	ids := []string{}
+	for id, share := range i.PresenceSharing {
+		if share {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (i *In_memory) SetPresenceSharing(email string, share bool) error {
+	member, err := i.GetMemberByEmail(email)
+	if err != nil {
+		return errors.New("member not found")
+	}
+
+	if i.PresenceSharing == nil {
+		i.PresenceSharing = map[string]bool{}
+	}
+	i.PresenceSharing[member.ID] = share
+	return nil
+}
+
+func (i *In_memory) GetCertifiedMemberIDs(certificationName string) ([]string, error) {
+	ids := []string{}
+	for _, mc := range i.MemberCertifications {
+		if strings.EqualFold(mc.CertificationName, certificationName) && mc.IsValid(time.Now()) && !containsString(ids, mc.MemberID) {
+			ids = append(ids, mc.MemberID)
+		}
+	}
+	return ids, nil
+}
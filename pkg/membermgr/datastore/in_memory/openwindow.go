@@ -0,0 +1,47 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetOpenWindows(resourceID string) ([]models.OpenWindow, error) {
+	windows := []models.OpenWindow{}
+	for _, w := range i.OpenWindows {
+		if len(resourceID) > 0 && w.ResourceID != resourceID {
+			continue
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+func (i *In_memory) AddOpenWindow(w models.OpenWindow) (models.OpenWindow, error) {
+	w.ID = strconv.Itoa(len(i.OpenWindows) + 1)
+	i.OpenWindows = append(i.OpenWindows, w)
+	return w, nil
+}
+
+func (i *In_memory) UpdateOpenWindow(w models.OpenWindow) (models.OpenWindow, error) {
+	for idx, existing := range i.OpenWindows {
+		if existing.ID != w.ID {
+			continue
+		}
+		i.OpenWindows[idx] = w
+		return w, nil
+	}
+	return models.OpenWindow{}, errors.New("not found")
+}
+
+func (i *In_memory) DeleteOpenWindow(id string) error {
+	for idx, existing := range i.OpenWindows {
+		if existing.ID != id {
+			continue
+		}
+		i.OpenWindows = append(i.OpenWindows[:idx], i.OpenWindows[idx+1:]...)
+		return nil
+	}
+	return errors.New("not found")
+}
@@ -0,0 +1,36 @@
+package in_memory
+
+import "time"
+
+type rateLimit struct {
+	hits    int
+	resetAt time.Time
+}
+
+func (i *In_memory) HitRateLimit(key string, window time.Duration) (int, time.Time, error) {
+	if i.rateLimits == nil {
+		i.rateLimits = map[string]rateLimit{}
+	}
+
+	limit := i.rateLimits[key]
+	if !time.Now().Before(limit.resetAt) {
+		limit = rateLimit{resetAt: time.Now().Add(window)}
+	}
+	limit.hits++
+	i.rateLimits[key] = limit
+
+	return limit.hits, limit.resetAt, nil
+}
+
+func (i *In_memory) GetRateLimit(key string) (int, time.Time, error) {
+	limit, ok := i.rateLimits[key]
+	if !ok || !time.Now().Before(limit.resetAt) {
+		return 0, time.Time{}, nil
+	}
+	return limit.hits, limit.resetAt, nil
+}
+
+func (i *In_memory) ResetRateLimit(key string) error {
+	delete(i.rateLimits, key)
+	return nil
+}
@@ -1,6 +1,7 @@
 package in_memory
 
 import (
+	"sort"
 	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
@@ -14,8 +15,96 @@ func (i *In_memory) GetMemberCountByMonth(month time.Time) (models.MemberCount,
 	return models.MemberCount{}, nil
 }
 func (i *In_memory) GetAccessStats(date time.Time, resourceName string) ([]models.AccessStats, error) {
-	return []models.AccessStats{}, nil
+	stats := []models.AccessStats{}
+	counts := map[string]int{}
+	for _, s := range i.AccessEventDaily {
+		counts[dailyKey(s.Date, s.ResourceName)] = len(stats)
+		stats = append(stats, s)
+	}
+
+	for _, e := range i.AccessEvents {
+		if !e.IsKnown || e.RolledUp {
+			continue
+		}
+
+		day := startOfDay(e.EventTime)
+		key := dailyKey(day, e.Door)
+		if _, ok := counts[key]; !ok {
+			counts[key] = len(stats)
+			stats = append(stats, models.AccessStats{Date: day, ResourceName: e.Door})
+		}
+		stats[counts[key]].AccessCount++
+	}
+
+	filtered := []models.AccessStats{}
+	for _, s := range stats {
+		if !date.IsZero() && !s.Date.Equal(startOfDay(date)) {
+			continue
+		}
+		if len(resourceName) > 0 && s.ResourceName != resourceName {
+			continue
+		}
+		filtered = append(filtered, s)
+	}
+
+	sort.SliceStable(filtered, func(a, b int) bool {
+		return filtered[a].Date.Before(filtered[b].Date)
+	})
+	return filtered, nil
 }
 func (i *In_memory) GetMemberChurn() (int, error) {
 	return 0, nil
 }
+
+func (i *In_memory) GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error) {
+	byRFID := map[string]*models.UnknownFob{}
+	for _, e := range i.AccessEvents {
+		if e.IsKnown || e.EventTime.Before(since) {
+			continue
+		}
+
+		f, ok := byRFID[e.RFID]
+		if !ok {
+			f = &models.UnknownFob{RFID: e.RFID, FirstSeen: e.EventTime, LastSeen: e.EventTime}
+			byRFID[e.RFID] = f
+		}
+
+		f.Attempts++
+		if e.EventTime.Before(f.FirstSeen) {
+			f.FirstSeen = e.EventTime
+		}
+		if e.EventTime.After(f.LastSeen) {
+			f.LastSeen = e.EventTime
+		}
+		if !containsString(f.Doors, e.Door) {
+			f.Doors = append(f.Doors, e.Door)
+		}
+	}
+
+	fobs := []models.UnknownFob{}
+	for _, f := range byRFID {
+		fobs = append(fobs, *f)
+	}
+
+	sort.Slice(fobs, func(a, b int) bool {
+		if fobs[a].Attempts == fobs[b].Attempts {
+			return fobs[a].LastSeen.After(fobs[b].LastSeen)
+		}
+		return fobs[a].Attempts > fobs[b].Attempts
+	})
+
+	if limit > 0 && len(fobs) > limit {
+		fobs = fobs[:limit]
+	}
+
+	return fobs, nil
+}
+
+func containsString(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,6 +1,17 @@
 package in_memory
 
-import "github.com/HackRVA/memberserver/pkg/membermgr/models"
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
 
 var Resources = map[string]models.Resource{}
 
@@ -16,10 +27,10 @@ func (store *In_memory) GetResourceACL(models.Resource) ([]string, error) {
 	return []string{}, nil
 }
 
-func (store *In_memory) GetResourceACLWithMemberInfo(models.Resource) ([]models.Member, error) {
-	return []models.Member{{
-		ID:   "123",
-		Name: "test",
+func (store *In_memory) GetResourceACLWithMemberInfo(models.Resource) ([]models.MemberAccess, error) {
+	return []models.MemberAccess{{
+		Email: "test@test.com",
+		Name:  "test",
 	}}, nil
 }
 
@@ -33,31 +44,135 @@ func (store *In_memory) GetActiveMembersByResource() ([]models.MemberAccess, err
 	return []models.MemberAccess{}, nil
 }
 
-func (store *In_memory) RegisterResource(name string, address string, isDefault bool) (models.Resource, error) {
+func (store *In_memory) RegisterResource(name string, address string, isDefault bool, driver string) (models.Resource, error) {
+	if len(driver) == 0 {
+		driver = models.DriverESPRFID
+	}
+
 	Resources[name] = models.Resource{
+		ID:        name,
 		Name:      name,
 		Address:   address,
 		IsDefault: isDefault,
+		Driver:    driver,
+		Version:   1,
 	}
 
 	return Resources[name], nil
 }
 
 func (store *In_memory) GetResourceByID(ID string) (models.Resource, error) {
-	return models.Resource{}, nil
+	for _, r := range Resources {
+		if r.ID == ID {
+			return r, nil
+		}
+	}
+	return models.Resource{}, errors.New("resource not found")
 }
 func (store *In_memory) GetResourceByName(resourceName string) (models.Resource, error) {
-	return models.Resource{}, nil
+	r, ok := Resources[resourceName]
+	if !ok {
+		return r, errors.New("resource not found")
+	}
+	return r, nil
 }
 func (store *In_memory) UpdateResource(res models.Resource) (*models.Resource, error) {
-	return &models.Resource{}, nil
+	return store.UpdateResourceAtVersion(res, datastore.AnyVersion)
+}
+func (store *In_memory) UpdateResourceAtVersion(res models.Resource, version int) (*models.Resource, error) {
+	previous, err := store.GetResourceByID(res.ID)
+	if err != nil {
+		return nil, err
+	}
+	if version != datastore.AnyVersion && previous.Version != version {
+		return nil, datastore.ErrVersionChanged
+	}
+
+	if len(res.Driver) == 0 {
+		res.Driver = models.DriverESPRFID
+	}
+
+	// like the database, only edits move the version on
+	res.LastHeartBeat = previous.LastHeartBeat
+	res.Version = previous.Version
+	if res != previous {
+		res.Version++
+	}
+
+	delete(Resources, previous.Name)
+	Resources[res.Name] = res
+	return &res, nil
 }
 func (store *In_memory) DeleteResource(id string) error {
+	return store.DeleteResourceAtVersion(id, datastore.AnyVersion)
+}
+func (store *In_memory) DeleteResourceAtVersion(id string, version int) error {
+	r, err := store.GetResourceByID(id)
+	if err != nil {
+		return err
+	}
+	if version != datastore.AnyVersion && r.Version != version {
+		return datastore.ErrVersionChanged
+	}
+
+	delete(Resources, r.Name)
 	return nil
 }
+
+// GetResourcePage sorts and filters the resources the same way the database does
+func (store *In_memory) GetResourcePage(query models.ResourceQuery) ([]models.Resource, int, error) {
+	search := strings.ToLower(query.Search)
+	matching := []models.Resource{}
+	for _, r := range Resources {
+		if len(search) > 0 && !fuzzy.Match(search, strings.ToLower(r.Name)) && !fuzzy.Match(search, strings.ToLower(r.Address)) {
+			continue
+		}
+		matching = append(matching, r)
+	}
+
+	less := func(name string, id string, afterName string, afterID string) bool {
+		return name < afterName || (name == afterName && id < afterID)
+	}
+	sort.Slice(matching, func(a, b int) bool {
+		return less(strings.ToLower(matching[a].Name), matching[a].ID, strings.ToLower(matching[b].Name), matching[b].ID)
+	})
+
+	page := []models.Resource{}
+	for _, r := range matching {
+		if less(query.AfterName, query.AfterID, strings.ToLower(r.Name), r.ID) && len(page) < query.Limit {
+			page = append(page, r)
+		}
+	}
+
+	return page, len(matching), nil
+}
 func (store *In_memory) AddMultipleMembersToResource(emails []string, resourceID string) ([]models.MemberResourceRelation, error) {
 	return []models.MemberResourceRelation{}, nil
 }
+func (store *In_memory) AddTemporaryMembersToResource(emails []string, resourceID string, validFrom *time.Time, validUntil *time.Time) ([]models.MemberResourceRelation, error) {
+	required, _ := store.GetResourceCertifications(resourceID)
+	if len(required) == 0 {
+		return []models.MemberResourceRelation{}, nil
+	}
+
+	for _, email := range emails {
+		held, err := store.GetMemberCertifications(email)
+		if err != nil {
+			return []models.MemberResourceRelation{}, err
+		}
+
+		if missing := models.MissingCertifications(required, held, time.Now()); len(missing) > 0 {
+			return []models.MemberResourceRelation{}, fmt.Errorf("%s is missing required certification(s) for %s: %s", email, resourceID, strings.Join(missing, ", "))
+		}
+	}
+	return []models.MemberResourceRelation{}, nil
+}
+func (store *In_memory) GetExpiredResourceGrants() ([]models.MemberAccess, error) {
+	return []models.MemberAccess{}, nil
+}
+func (store *In_memory) GetActiveScheduledResourceGrants() ([]models.MemberAccess, error) {
+	return []models.MemberAccess{}, nil
+}
 func (store *In_memory) AddUserToDefaultResources(email string) ([]models.MemberResourceRelation, error) {
 	return []models.MemberResourceRelation{}, nil
 }
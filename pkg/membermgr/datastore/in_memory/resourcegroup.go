@@ -0,0 +1,120 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetResourceGroups() ([]models.ResourceGroup, error) {
+	return append([]models.ResourceGroup{}, i.ResourceGroups...), nil
+}
+
+func (i *In_memory) GetResourceGroup(id string) (models.ResourceGroup, error) {
+	for _, g := range i.ResourceGroups {
+		if g.ID == id {
+			return g, nil
+		}
+	}
+	return models.ResourceGroup{}, errors.New("resource group not found")
+}
+
+func (i *In_memory) AddResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error) {
+	g.ID = strconv.Itoa(len(i.ResourceGroups) + 1)
+	g.ResourceIDs = []string{}
+	i.ResourceGroups = append(i.ResourceGroups, g)
+	return g, nil
+}
+
+func (i *In_memory) UpdateResourceGroup(g models.ResourceGroup) (models.ResourceGroup, error) {
+	for idx, existing := range i.ResourceGroups {
+		if existing.ID != g.ID {
+			continue
+		}
+		existing.Name = g.Name
+		existing.Description = g.Description
+		i.ResourceGroups[idx] = existing
+		return existing, nil
+	}
+	return models.ResourceGroup{}, errors.New("resource group not found")
+}
+
+func (i *In_memory) DeleteResourceGroup(id string) error {
+	for idx, existing := range i.ResourceGroups {
+		if existing.ID == id {
+			i.ResourceGroups = append(i.ResourceGroups[:idx], i.ResourceGroups[idx+1:]...)
+			delete(i.ResourceGroupMembers, id)
+			return nil
+		}
+	}
+	return errors.New("resource group not found")
+}
+
+func (i *In_memory) GetResourceGroupMembers(groupID string) ([]models.Member, error) {
+	members := []models.Member{}
+	for _, email := range i.ResourceGroupMembers[groupID] {
+		members = append(members, i.Members[email])
+	}
+	return members, nil
+}
+
+func (i *In_memory) AddMembersToResourceGroup(emails []string, groupID string) ([]models.MemberResourceRelation, error) {
+	group, err := i.GetResourceGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if i.ResourceGroupMembers == nil {
+		i.ResourceGroupMembers = map[string][]string{}
+	}
+
+	relations := []models.MemberResourceRelation{}
+	for _, email := range emails {
+		member, err := i.GetMemberByEmail(email)
+		if err != nil {
+			return nil, err
+		}
+		i.ResourceGroupMembers[groupID] = append(i.ResourceGroupMembers[groupID], email)
+		for _, resourceID := range group.ResourceIDs {
+			relations = append(relations, models.MemberResourceRelation{MemberID: member.ID, ResourceID: resourceID})
+		}
+	}
+	return relations, nil
+}
+
+func (i *In_memory) RemoveMemberFromResourceGroup(email string, groupID string) ([]models.MemberAccess, error) {
+	members := i.ResourceGroupMembers[groupID]
+	for idx, m := range members {
+		if m == email {
+			i.ResourceGroupMembers[groupID] = append(members[:idx], members[idx+1:]...)
+			return []models.MemberAccess{}, nil
+		}
+	}
+	return nil, errors.New("member is not in the resource group")
+}
+
+func (i *In_memory) AddResourceToResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error) {
+	for idx, g := range i.ResourceGroups {
+		if g.ID == groupID {
+			i.ResourceGroups[idx].ResourceIDs = append(g.ResourceIDs, resourceID)
+			return []models.MemberAccess{}, nil
+		}
+	}
+	return nil, errors.New("resource group not found")
+}
+
+func (i *In_memory) RemoveResourceFromResourceGroup(groupID string, resourceID string) ([]models.MemberAccess, error) {
+	for idx, g := range i.ResourceGroups {
+		if g.ID != groupID {
+			continue
+		}
+		for j, id := range g.ResourceIDs {
+			if id == resourceID {
+				i.ResourceGroups[idx].ResourceIDs = append(g.ResourceIDs[:j], g.ResourceIDs[j+1:]...)
+				return []models.MemberAccess{}, nil
+			}
+		}
+	}
+	return nil, errors.New("resource is not in the resource group")
+}
@@ -0,0 +1,66 @@
+package in_memory
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (store *In_memory) GetMemberRoles(email string) ([]models.Role, error) {
+	roles := append([]models.Role{}, store.MemberRoles[email]...)
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles, nil
+}
+
+func (store *In_memory) SetMemberRoles(email string, roles []models.Role) error {
+	if _, err := store.GetMemberByEmail(email); err != nil {
+		return errors.New("error setting member roles: member not found")
+	}
+
+	if store.MemberRoles == nil {
+		store.MemberRoles = map[string][]models.Role{}
+	}
+
+	// the database does this with a trigger
+	if !sameRoles(store.MemberRoles[email], roles) {
+		store.RevokeSessions(email)
+	}
+
+	store.MemberRoles[email] = append([]models.Role{}, roles...)
+	return nil
+}
+
+func (store *In_memory) GetMembersWithRole(role models.Role) ([]models.Member, error) {
+	members := []models.Member{}
+	for email, roles := range store.MemberRoles {
+		for _, r := range roles {
+			if r != role {
+				continue
+			}
+			if m, err := store.GetMemberByEmail(email); err == nil {
+				members = append(members, m)
+			}
+			break
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].Name < members[j].Name })
+	return members, nil
+}
+
+func sameRoles(a []models.Role, b []models.Role) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, role := range a {
+		found := false
+		for _, other := range b {
+			found = found || role == other
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
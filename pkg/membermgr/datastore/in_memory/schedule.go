@@ -0,0 +1,56 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetAccessSchedules(resourceID string) ([]models.AccessSchedule, error) {
+	schedules := []models.AccessSchedule{}
+	for _, s := range i.Schedules {
+		if len(resourceID) > 0 && s.ResourceID != resourceID {
+			continue
+		}
+		schedules = append(schedules, s)
+	}
+	return schedules, nil
+}
+
+func (i *In_memory) GetAccessSchedule(id string) (models.AccessSchedule, error) {
+	for _, s := range i.Schedules {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return models.AccessSchedule{}, errors.New("not found")
+}
+
+func (i *In_memory) AddAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error) {
+	s.ID = strconv.Itoa(len(i.Schedules) + 1)
+	i.Schedules = append(i.Schedules, s)
+	return s, nil
+}
+
+func (i *In_memory) UpdateAccessSchedule(s models.AccessSchedule) (models.AccessSchedule, error) {
+	for idx, existing := range i.Schedules {
+		if existing.ID != s.ID {
+			continue
+		}
+		i.Schedules[idx] = s
+		return s, nil
+	}
+	return models.AccessSchedule{}, errors.New("not found")
+}
+
+func (i *In_memory) DeleteAccessSchedule(id string) error {
+	for idx, existing := range i.Schedules {
+		if existing.ID != id {
+			continue
+		}
+		i.Schedules = append(i.Schedules[:idx], i.Schedules[idx+1:]...)
+		return nil
+	}
+	return errors.New("not found")
+}
@@ -0,0 +1,82 @@
+package in_memory
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) CreateSession(session models.Session) (models.Session, error) {
+	session.ID = strconv.Itoa(len(i.Sessions) + 1)
+	session.CreatedAt = time.Now()
+	session.LastUsedAt = session.CreatedAt
+	i.Sessions = append(i.Sessions, session)
+	return session, nil
+}
+
+func (i *In_memory) GetSession(id string) (models.Session, error) {
+	for _, session := range i.Sessions {
+		if session.ID == id {
+			return session, nil
+		}
+	}
+	return models.Session{}, errors.New("session not found")
+}
+
+func (i *In_memory) GetSessions(email string) ([]models.Session, error) {
+	sessions := []models.Session{}
+	for n := len(i.Sessions) - 1; n >= 0; n-- {
+		session := i.Sessions[n]
+		if strings.EqualFold(session.Email, email) && session.Active(time.Now()) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (i *In_memory) RotateSession(id string, refreshHash string, newRefreshHash string, expiresAt time.Time) (bool, error) {
+	for n, session := range i.Sessions {
+		if session.ID != id || session.RefreshHash != refreshHash || !session.Active(time.Now()) {
+			continue
+		}
+
+		now := time.Now()
+		session.PreviousHash = session.RefreshHash
+		session.RefreshHash = newRefreshHash
+		session.RotatedAt = &now
+		session.LastUsedAt = now
+		session.ExpiresAt = expiresAt
+		i.Sessions[n] = session
+		return true, nil
+	}
+	return false, nil
+}
+
+func (i *In_memory) RevokeSession(id string) error {
+	for n, session := range i.Sessions {
+		if session.ID != id {
+			continue
+		}
+		if session.RevokedAt == nil {
+			now := time.Now()
+			i.Sessions[n].RevokedAt = &now
+		}
+		return nil
+	}
+	return errors.New("session not found")
+}
+
+func (i *In_memory) RevokeSessions(email string) (int64, error) {
+	var revoked int64
+	now := time.Now()
+	for n, session := range i.Sessions {
+		if strings.EqualFold(session.Email, email) && session.RevokedAt == nil {
+			i.Sessions[n].RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
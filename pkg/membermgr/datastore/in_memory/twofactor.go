@@ -0,0 +1,111 @@
+package in_memory
+
+import (
+	"errors"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func (i *In_memory) GetTwoFactor(email string) (models.TwoFactor, error) {
+	if _, ok := i.Users[email]; !ok {
+		return models.TwoFactor{}, datastore.ErrUserNotFound
+	}
+
+	tf := i.TwoFactor[email]
+	tf.Email = email
+	tf.RecoveryCodesLeft = len(i.RecoveryCodes[email])
+	return tf, nil
+}
+
+func (i *In_memory) SetTwoFactorSecret(email string, secret string) error {
+	tf, err := i.GetTwoFactor(email)
+	if err != nil {
+		return err
+	}
+	if tf.Enabled {
+		return errors.New("error setting two-factor secret: already enabled")
+	}
+
+	tf.Secret = secret
+	tf.LastStep = 0
+	i.setTwoFactor(tf)
+	return nil
+}
+
+func (i *In_memory) ApproveTwoFactorEnrollment(email string) error {
+	tf, err := i.GetTwoFactor(email)
+	if err != nil {
+		return err
+	}
+
+	tf.EnrollmentApproved = true
+	i.setTwoFactor(tf)
+	return nil
+}
+
+func (i *In_memory) EnableTwoFactor(email string, step int64, recoveryCodeHashes []string) error {
+	tf, err := i.GetTwoFactor(email)
+	if err != nil {
+		return err
+	}
+	if len(tf.Secret) == 0 {
+		return errors.New("error enabling two-factor: user hasn't started enrolling")
+	}
+
+	tf.Enabled = true
+	tf.LastStep = step
+	i.setTwoFactor(tf)
+	return i.SetRecoveryCodes(email, recoveryCodeHashes)
+}
+
+func (i *In_memory) SetRecoveryCodes(email string, recoveryCodeHashes []string) error {
+	if i.RecoveryCodes == nil {
+		i.RecoveryCodes = map[string][]string{}
+	}
+	i.RecoveryCodes[email] = append([]string{}, recoveryCodeHashes...)
+	return nil
+}
+
+func (i *In_memory) DisableTwoFactor(email string) error {
+	if _, err := i.GetTwoFactor(email); err != nil {
+		return err
+	}
+
+	delete(i.TwoFactor, email)
+	delete(i.RecoveryCodes, email)
+	return nil
+}
+
+func (i *In_memory) UseTOTPStep(email string, step int64) (bool, error) {
+	tf, err := i.GetTwoFactor(email)
+	if err != nil {
+		return false, err
+	}
+	if !tf.Enabled || step <= tf.LastStep {
+		return false, nil
+	}
+
+	tf.LastStep = step
+	i.setTwoFactor(tf)
+	return true, nil
+}
+
+func (i *In_memory) UseRecoveryCode(email string, recoveryCodeHash string) (bool, error) {
+	codes := i.RecoveryCodes[email]
+	for n, code := range codes {
+		if code == recoveryCodeHash {
+			i.RecoveryCodes[email] = append(codes[:n:n], codes[n+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (i *In_memory) setTwoFactor(tf models.TwoFactor) {
+	if i.TwoFactor == nil {
+		i.TwoFactor = map[string]models.TwoFactor{}
+	}
+	tf.RecoveryCodesLeft = 0
+	i.TwoFactor[tf.Email] = tf
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 func (i *In_memory) GetUser(email string) (models.UserResponse, error) {
@@ -26,12 +28,81 @@ func (i *In_memory) GetUser(email string) (models.UserResponse, error) {
 	return models.UserResponse{}, errors.New("error getting user: not found")
 }
 
+// UserSignin lets anyone in, unless they have registered
+//
+//	registered users need a verified email and their password
 func (i *In_memory) UserSignin(email string, password string) error {
-	return nil
+	account, ok := i.Users[email]
+	if !ok {
+		return nil
+	}
+
+	if !account.Verified {
+		return errors.New("unauthorized: email has not been verified")
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(account.PasswordHash), []byte(password))
 }
+
 func (i *In_memory) RegisterUser(creds models.Credentials) error {
-	if _, ok := i.Members[creds.Email]; ok {
+	if err := models.CheckPasswordPolicy(creds.Password, creds.Email); err != nil {
+		return err
+	}
+
+	if _, err := i.GetMemberByEmail(creds.Email); err != nil {
+		return err
+	}
+
+	if account, ok := i.Users[creds.Email]; ok && account.Verified {
 		return errors.New("error registering user")
 	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(creds.Password), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+
+	if i.Users == nil {
+		i.Users = map[string]models.UserAccount{}
+	}
+	i.Users[creds.Email] = models.UserAccount{Email: creds.Email, PasswordHash: string(hash)}
+	return nil
+}
+
+func (i *In_memory) GetUserAccount(email string) (models.UserAccount, error) {
+	account, ok := i.Users[email]
+	if !ok {
+		return models.UserAccount{}, errors.New("error getting user: not found")
+	}
+	return account, nil
+}
+
+func (i *In_memory) VerifyUser(email string) error {
+	account, ok := i.Users[email]
+	if !ok {
+		return errors.New("error verifying user: not found")
+	}
+	account.Verified = true
+	i.Users[email] = account
+	return nil
+}
+
+func (i *In_memory) UpdatePassword(creds models.Credentials) error {
+	if err := models.CheckPasswordPolicy(creds.Password, creds.Email); err != nil {
+		return err
+	}
+
+	account, ok := i.Users[creds.Email]
+	if !ok {
+		return errors.New("error updating password: not found")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(creds.Password), bcrypt.MinCost)
+	if err != nil {
+		return err
+	}
+
+	account.PasswordHash = string(hash)
+	i.Users[creds.Email] = account
 	return nil
 }
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Counter counts hits per key in fixed windows
+type Counter interface {
+	// Hit records a hit for key and returns the hits in the window and when it ends
+	//
+	//	a new window of length window starts when the last one has ended
+	Hit(key string, window time.Duration) (int, time.Time, error)
+	// Count returns the hits for key in its current window without adding one
+	Count(key string) (int, time.Time, error)
+	// Reset forgets key
+	Reset(key string) error
+}
+
+// Store is a datastore that keeps counters, so limits hold across restarts and between servers
+type Store interface {
+	HitRateLimit(key string, window time.Duration) (int, time.Time, error)
+	GetRateLimit(key string) (int, time.Time, error)
+	ResetRateLimit(key string) error
+}
+
+type window struct {
+	hits    int
+	resetAt time.Time
+}
+
+type memoryCounter struct {
+	mu      sync.Mutex
+	windows map[string]window
+	// sinceCleanup is how many hits there have been since ended windows were removed
+	sinceCleanup int
+}
+
+// cleanupEvery is how many hits pass between removing ended windows
+const cleanupEvery = 1000
+
+// NewMemoryCounter keeps counters in the server's memory
+func NewMemoryCounter() Counter {
+	return &memoryCounter{windows: map[string]window{}}
+}
+
+func (c *memoryCounter) Hit(key string, length time.Duration) (int, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.cleanup(now)
+
+	w := c.windows[key]
+	if !now.Before(w.resetAt) {
+		w = window{resetAt: now.Add(length)}
+	}
+	w.hits++
+	c.windows[key] = w
+
+	return w.hits, w.resetAt, nil
+}
+
+func (c *memoryCounter) Count(key string) (int, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w, ok := c.windows[key]
+	if !ok || !time.Now().Before(w.resetAt) {
+		return 0, time.Time{}, nil
+	}
+	return w.hits, w.resetAt, nil
+}
+
+func (c *memoryCounter) Reset(key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.windows, key)
+	return nil
+}
+
+func (c *memoryCounter) cleanup(now time.Time) {
+	c.sinceCleanup++
+	if c.sinceCleanup < cleanupEvery {
+		return
+	}
+	c.sinceCleanup = 0
+
+	for key, w := range c.windows {
+		if !now.Before(w.resetAt) {
+			delete(c.windows, key)
+		}
+	}
+}
+
+type storeCounter struct {
+	store Store
+}
+
+// NewStoreCounter keeps counters in the datastore
+func NewStoreCounter(store Store) Counter {
+	return storeCounter{store}
+}
+
+func (c storeCounter) Hit(key string, length time.Duration) (int, time.Time, error) {
+	return c.store.HitRateLimit(key, length)
+}
+
+func (c storeCounter) Count(key string) (int, time.Time, error) {
+	return c.store.GetRateLimit(key)
+}
+
+func (c storeCounter) Reset(key string) error {
+	return c.store.ResetRateLimit(key)
+}
@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Limit is how many requests a client can make in a window
+type Limit struct {
+	// Name keeps the counts for different limits apart
+	Name     string
+	Requests int
+	Window   time.Duration
+}
+
+// KeyFunc picks who a request counts against
+type KeyFunc func(r *http.Request) string
+
+// Middleware refuses requests over the limit with 429 Too Many Requests
+//
+//	if the counter fails the request is let through, a broken counter shouldn't take the site down
+func Middleware(counter Counter, limit Limit, key KeyFunc) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits, resetAt, err := counter.Hit(limit.Name+":"+key(r), limit.Window)
+			if err != nil {
+				log.Errorf("error counting %s requests: %s", limit.Name, err)
+			}
+			if err == nil && hits > limit.Requests {
+				TooManyRequests(w, time.Until(resetAt))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TooManyRequests tells the client to wait before trying again
+func TooManyRequests(w http.ResponseWriter, retryAfter time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	http.Error(w, "too many requests, please try again later", http.StatusTooManyRequests)
+}
+
+// Proxies are the reverse proxies whose X-Forwarded-For header is believed
+type Proxies []*net.IPNet
+
+// ParseProxies reads a comma separated list of addresses and CIDR ranges
+func ParseProxies(list string) Proxies {
+	proxies := Proxies{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			log.Errorf("ignoring trusted proxy %q: %s", entry, err)
+			continue
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies
+}
+
+// ClientIP is the address a request came from
+//
+//	requests through a trusted proxy come from the last address in X-Forwarded-For
+//	that isn't another trusted proxy, anything before it could have been made up by the client
+func (p Proxies) ClientIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for n := len(forwarded) - 1; n >= 0 && p.trusted(ip); n-- {
+		if hop := strings.TrimSpace(forwarded[n]); net.ParseIP(hop) != nil {
+			ip = hop
+		}
+	}
+	return ip
+}
+
+func (p Proxies) trusted(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range p {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryCounter(t *testing.T) {
+	counter := NewMemoryCounter()
+
+	for want := 1; want <= 3; want++ {
+		hits, resetAt, _ := counter.Hit("a", time.Minute)
+		if hits != want {
+			t.Errorf("expected %d hits, got %d", want, hits)
+		}
+		if time.Until(resetAt) > time.Minute || time.Until(resetAt) < 59*time.Second {
+			t.Errorf("expected the window to end in a minute, got %s", time.Until(resetAt))
+		}
+	}
+
+	if hits, _, _ := counter.Count("a"); hits != 3 {
+		t.Errorf("expected counting not to add a hit, got %d", hits)
+	}
+	if hits, _, _ := counter.Count("b"); hits != 0 {
+		t.Errorf("expected keys to be counted apart, got %d", hits)
+	}
+
+	counter.Reset("a")
+	if hits, _, _ := counter.Count("a"); hits != 0 {
+		t.Errorf("expected reset to forget the hits, got %d", hits)
+	}
+}
+
+func TestMemoryCounterWindowEnds(t *testing.T) {
+	counter := NewMemoryCounter()
+
+	counter.Hit("a", time.Millisecond)
+	counter.Hit("a", time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+
+	if hits, _, _ := counter.Count("a"); hits != 0 {
+		t.Errorf("expected an ended window not to count, got %d", hits)
+	}
+	if hits, _, _ := counter.Hit("a", time.Minute); hits != 1 {
+		t.Errorf("expected a new window to start, got %d", hits)
+	}
+}
+
+// failingCounter can't count, like a datastore that's down
+type failingCounter struct{}
+
+func (failingCounter) Hit(string, time.Duration) (int, time.Time, error) {
+	return 0, time.Time{}, errors.New("down")
+}
+func (failingCounter) Count(string) (int, time.Time, error) {
+	return 0, time.Time{}, errors.New("down")
+}
+func (failingCounter) Reset(string) error { return errors.New("down") }
+
+func limitedRequest(handler http.Handler, remoteAddr string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(http.MethodPost, "/api/auth/register", nil)
+	request.RemoteAddr = remoteAddr
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, request)
+	return response
+}
+
+func TestMiddleware(t *testing.T) {
+	limit := Limit{Name: "register", Requests: 2, Window: time.Minute}
+	handler := Middleware(NewMemoryCounter(), limit, Proxies{}.ClientIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for n := 0; n < 2; n++ {
+		if code := limitedRequest(handler, "192.0.2.1:1234").Code; code != http.StatusOK {
+			t.Fatalf("expected requests under the limit to go through, got %d", code)
+		}
+	}
+
+	response := limitedRequest(handler, "192.0.2.1:1234")
+	if response.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, response.Code)
+	}
+	if retryAfter := response.Header().Get("Retry-After"); retryAfter != "60" {
+		t.Errorf("expected to be told to retry in 60 seconds, got %q", retryAfter)
+	}
+
+	if code := limitedRequest(handler, "192.0.2.2:1234").Code; code != http.StatusOK {
+		t.Errorf("expected other addresses to have their own limit, got %d", code)
+	}
+}
+
+func TestMiddlewareLetsRequestsThroughWhenCounterFails(t *testing.T) {
+	limit := Limit{Name: "register", Requests: 0, Window: time.Minute}
+	handler := Middleware(failingCounter{}, limit, Proxies{}.ClientIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	if code := limitedRequest(handler, "192.0.2.1:1234").Code; code != http.StatusOK {
+		t.Errorf("expected the request to go through, got %d", code)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies := ParseProxies("10.0.0.1, 172.16.0.0/12, not-an-address")
+
+	tests := []struct {
+		TestName   string
+		remoteAddr string
+		forwarded  []string
+		expected   string
+	}{
+		{
+			TestName:   "should use the connection's address",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			TestName:   "should ignore X-Forwarded-For from a client",
+			remoteAddr: "192.0.2.1:1234",
+			forwarded:  []string{"198.51.100.7"},
+			expected:   "192.0.2.1",
+		},
+		{
+			TestName:   "should believe a trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"198.51.100.7"},
+			expected:   "198.51.100.7",
+		},
+		{
+			TestName:   "should skip addresses made up by the client",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"203.0.113.9, 198.51.100.7"},
+			expected:   "198.51.100.7",
+		},
+		{
+			TestName:   "should go back through a chain of trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			forwarded:  []string{"198.51.100.7", "172.16.4.2"},
+			expected:   "198.51.100.7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.RemoteAddr = tt.remoteAddr
+			for _, forwarded := range tt.forwarded {
+				request.Header.Add("X-Forwarded-For", forwarded)
+			}
+
+			if ip := proxies.ClientIP(request); ip != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, ip)
+			}
+		})
+	}
+}
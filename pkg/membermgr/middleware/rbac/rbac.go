@@ -1,72 +1,336 @@
 package rbac
 
 import (
+	"bytes"
+	"encoding/json"
+	"io"
 	"net/http"
+	"sort"
 	"strings"
 
-	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
 	"github.com/shaj13/go-guardian/v2/auth/strategies/union"
+	log "github.com/sirupsen/logrus"
 )
 
-type UserRole uint
+// Permission is something a role allows its members to do
+type Permission string
 
 const (
-	Admin UserRole = iota + 1
-	User
+	ViewMembers          Permission = "members.view"
+	ManageMembers        Permission = "members.manage"
+	ManageCredits        Permission = "members.credit"
+	ManageRoles          Permission = "members.roles"
+	ManageSessions       Permission = "members.sessions"
+	ManageResources      Permission = "resources.manage"
+	GrantAccess          Permission = "resources.grant"
+	OpenResources        Permission = "resources.open"
+	ManageCertifications Permission = "certifications.manage"
+	RecordTraining       Permission = "certifications.train"
+	ViewReports          Permission = "reports.view"
+	ViewAccessEvents     Permission = "access_events.view"
+	PostAccessEvents     Permission = "access_events.post"
+	ViewAuditLog         Permission = "audit.view"
+	ManageAPIKeys        Permission = "api_keys.manage"
 )
 
+// rolePermissions is what each role allows
+//
+//	admins can do everything, members only have the routes that aren't restricted
+var rolePermissions = map[models.Role][]Permission{
+	models.RoleAdmin: {
+		ViewMembers, ManageMembers, ManageCredits, ManageRoles, ManageSessions,
+		ManageResources, GrantAccess, OpenResources,
+		ManageCertifications, RecordTraining,
+		ViewReports, ViewAccessEvents, PostAccessEvents, ViewAuditLog,
+		ManageAPIKeys,
+	},
+	models.RoleTreasurer: {ViewMembers, ManageCredits, ViewReports},
+	models.RoleBoard:     {ViewMembers, ViewReports, ViewAccessEvents, ViewAuditLog},
+	models.RoleAreaHost:  {RecordTraining},
+	models.RoleMember:    {},
+}
+
+// scopedPermissions is what each role allows on the resources its members host
+var scopedPermissions = map[models.Role][]Permission{
+	models.RoleAreaHost: {GrantAccess, OpenResources},
+}
+
+// APIKeyScopes are the permissions an API key can be given
+var APIKeyScopes = []Permission{ViewMembers, OpenResources, PostAccessEvents}
+
+// resourceScopes are the API key scopes that are only ever given for particular resources
+var resourceScopes = []Permission{OpenResources, PostAccessEvents}
+
+// scopeGroupPrefix marks the groups an API key signs in with, they hold a permission rather than a role
+const scopeGroupPrefix = "scope:"
+
 type AccessControl interface {
-	Restrict(next http.HandlerFunc, allowedRoles []UserRole) http.HandlerFunc
+	Restrict(next http.HandlerFunc, permission Permission) http.HandlerFunc
+	RestrictResource(next http.HandlerFunc, permission Permission, param string) http.HandlerFunc
+	RestrictResourceBody(next http.HandlerFunc, permission Permission, field string) http.HandlerFunc
+	RestrictResourceNameBody(next http.HandlerFunc, permission Permission, field string) http.HandlerFunc
+}
+
+// HostStore knows which resources a member hosts, and finds resources by name
+type HostStore interface {
+	IsResourceHost(email string, resourceID string) (bool, error)
+	GetResourceByName(resourceName string) (models.Resource, error)
 }
 
 type RBAC struct {
 	strategy union.Union
+	hosts    HostStore
 }
 
-func New(strategy union.Union) RBAC {
+func New(strategy union.Union, hosts HostStore) RBAC {
 	return RBAC{
 		strategy: strategy,
+		hosts:    hosts,
 	}
 }
 
-func (ur UserRole) ToString() string {
-	switch ur {
-	case Admin:
-		return "admin"
-	default:
-		return "user"
+// Permissions returns what a role allows
+func Permissions(role models.Role) []Permission {
+	return append([]Permission{}, rolePermissions[role]...)
+}
+
+// ScopedPermissions returns what a role allows on the resources its members host
+func ScopedPermissions(role models.Role) []Permission {
+	return append([]Permission{}, scopedPermissions[role]...)
+}
+
+// APIKeyScope reports whether a permission can be given to an API key
+func APIKeyScope(permission Permission) bool {
+	for _, scope := range APIKeyScopes {
+		if scope == permission {
+			return true
+		}
 	}
+	return false
 }
 
-func contains(slice []string, item string) bool {
-	set := make(map[string]struct{}, len(slice))
-	for _, s := range slice {
-		set[s] = struct{}{}
+// ResourceScope reports whether an API key scope is only given for particular resources
+func ResourceScope(permission Permission) bool {
+	for _, scope := range resourceScopes {
+		if scope == permission {
+			return true
+		}
 	}
+	return false
+}
+
+// ScopeGroups are the groups an API key signs in with
+//
+//	OpenResources and PostAccessEvents are scoped to the resources, every other scope applies everywhere
+func ScopeGroups(scopes []string, resourceIDs []string) []string {
+	groups := []string{}
+	for _, scope := range scopes {
+		if !ResourceScope(Permission(scope)) {
+			groups = append(groups, scopeGroupPrefix+scope)
+			continue
+		}
+		for _, id := range resourceIDs {
+			groups = append(groups, resourceScopeGroup(Permission(scope), id))
+		}
+	}
+	return groups
+}
+
+func resourceScopeGroup(permission Permission, resourceID string) string {
+	return scopeGroupPrefix + string(permission) + ":" + resourceID
+}
 
-	_, ok := set[item]
-	return ok
+// Allowed reports whether any of the roles, or an API key's scopes, grant the permission
+//
+//	unknown roles grant nothing
+func Allowed(roles []string, permission Permission) bool {
+	return granted(rolePermissions, roles, permission) || hasGroup(roles, scopeGroupPrefix+string(permission))
 }
 
-// rbac is middleware that will restrict access based on the roles you pass in
-func (rb RBAC) Restrict(next http.HandlerFunc, allowedRoles []UserRole) http.HandlerFunc {
+// Granted is every permission the roles, or an API key's scopes, grant everywhere
+func Granted(roles []string) []Permission {
+	granted := []Permission{}
+	seen := map[Permission]bool{}
+	for _, permissions := range rolePermissions {
+		for _, p := range permissions {
+			if !seen[p] && Allowed(roles, p) {
+				seen[p] = true
+				granted = append(granted, p)
+			}
+		}
+	}
+
+	sort.Slice(granted, func(i, j int) bool { return granted[i] < granted[j] })
+	return granted
+}
+
+// AllowedOnResource reports whether an API key's scopes grant the permission on the resource
+func AllowedOnResource(groups []string, permission Permission, resourceID string) bool {
+	return hasGroup(groups, resourceScopeGroup(permission, resourceID))
+}
+
+func hasGroup(groups []string, group string) bool {
+	for _, g := range groups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedOnHosted reports whether any of the roles grants the permission on hosted resources
+func AllowedOnHosted(roles []string, permission Permission) bool {
+	return granted(scopedPermissions, roles, permission)
+}
+
+func granted(permissions map[models.Role][]Permission, roles []string, permission Permission) bool {
+	for _, role := range roles {
+		for _, p := range permissions[models.Role(role)] {
+			if p == permission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// user is who made the request
+//
+//	the auth middleware has usually found them already, so they aren't looked up again
+func (rb RBAC) user(r *http.Request) (auth.Info, error) {
+	if user := auth.User(r); user != nil {
+		return user, nil
+	}
+
+	_, user, err := rb.strategy.AuthenticateRequest(r)
+	return user, err
+}
+
+// Restrict is middleware that only lets users whose roles grant the permission through
+func (rb RBAC) Restrict(next http.HandlerFunc, permission Permission) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := rb.user(r)
+		if err != nil || !Allowed(user.GetGroups(), permission) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RestrictResource is middleware for routes about a single resource
+//
+//	param names the route parameter holding the resource's ID
+//	users whose roles grant the permission everywhere are let through,
+//	so are API keys scoped to that resource,
+//	and users whose roles grant it on hosted resources if they host that resource
+func (rb RBAC) RestrictResource(next http.HandlerFunc, permission Permission, param string) http.HandlerFunc {
+	return rb.restrictResource(next, permission, func(r *http.Request) string {
+		return mux.Vars(r)[param]
+	})
+}
+
+// RestrictResourceBody is RestrictResource for routes that take the resource's ID in their json body
+//
+//	field names the body's field holding the resource's ID
+func (rb RBAC) RestrictResourceBody(next http.HandlerFunc, permission Permission, field string) http.HandlerFunc {
+	return rb.restrictResource(next, permission, func(r *http.Request) string {
+		return bodyField(r, field)
+	})
+}
+
+// RestrictResourceNameBody is RestrictResourceBody for routes whose json body names the resource rather than giving its ID
+//
+//	field names the body's field holding the resource's name
+func (rb RBAC) RestrictResourceNameBody(next http.HandlerFunc, permission Permission, field string) http.HandlerFunc {
+	return rb.restrictResource(next, permission, func(r *http.Request) string {
+		name := bodyField(r, field)
+		if len(name) == 0 {
+			return ""
+		}
+
+		resource, err := rb.hosts.GetResourceByName(name)
+		if err != nil {
+			return ""
+		}
+		return resource.ID
+	})
+}
+
+// bodyField reads a string field from a json body and puts the body back for the handler
+//
+//	handlers decode the body into structs, which match keys whatever their case and keep the last one,
+//	so a key that only differs from field in case could name another resource than the one checked here,
+//	bodies with one are treated as not having the field
+func bodyField(r *http.Request, field string) string {
+	if r.Body == nil {
+		return ""
+	}
+
+	b, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return ""
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return ""
+	}
+
+	var value string
+	for key, raw := range fields {
+		if !strings.EqualFold(key, field) {
+			continue
+		}
+		if key != field {
+			return ""
+		}
+		json.Unmarshal(raw, &value)
+	}
+	return value
+}
+
+func (rb RBAC) restrictResource(next http.HandlerFunc, permission Permission, resourceIDOf func(r *http.Request) string) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		conf, _ := config.Load()
-		if strings.Contains(conf.AlwaysAdmin, "true") {
+		user, err := rb.user(r)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		if Allowed(user.GetGroups(), permission) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		_, user, _ := rb.strategy.AuthenticateRequest(r)
+		resourceID := resourceIDOf(r)
+		if len(resourceID) > 0 && AllowedOnResource(user.GetGroups(), permission, resourceID) {
+			next.ServeHTTP(w, r)
+			return
+		}
 
-		for _, role := range allowedRoles {
-			if contains(user.GetGroups(), role.ToString()) {
-				next.ServeHTTP(w, r)
-				return
-			}
+		if len(resourceID) == 0 || !AllowedOnHosted(user.GetGroups(), permission) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		isHost, err := rb.hosts.IsResourceHost(user.GetUserName(), resourceID)
+		if err != nil {
+			log.Errorf("error checking if %s hosts %s: %s", user.GetUserName(), resourceID, err)
+		}
+
+		if !isHost {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
 		}
 
-		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		next.ServeHTTP(w, r)
 	})
 }
@@ -0,0 +1,295 @@
+package rbac
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/union"
+)
+
+// groupsByUser stands in for the roles a user signs in with
+var groupsByUser = map[string][]string{
+	"admin@test.com":     {"member", "admin"},
+	"treasurer@test.com": {"member", "treasurer"},
+	"board@test.com":     {"member", "board"},
+	"member@test.com":    {"member"},
+	"resource@test.com":  {"member", "frontdoor"},
+	"host@test.com":      {"member", "area-host"},
+	"former@test.com":    {"member"},
+	"kiosk-key":          ScopeGroups([]string{string(ViewMembers), string(OpenResources)}, []string{"frontdoor"}),
+	"bridge-key":         ScopeGroups([]string{string(PostAccessEvents)}, []string{"frontdoor"}),
+}
+
+// stubHosts hosts resources by email
+type stubHosts map[string][]string
+
+func (h stubHosts) IsResourceHost(email string, resourceID string) (bool, error) {
+	for _, id := range h[email] {
+		if id == resourceID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetResourceByName knows the test resources, their IDs are their names without spaces
+func (h stubHosts) GetResourceByName(name string) (models.Resource, error) {
+	for _, resource := range []models.Resource{{ID: "frontdoor", Name: "front door"}, {ID: "lathe", Name: "lathe"}} {
+		if resource.Name == name {
+			return resource, nil
+		}
+	}
+	return models.Resource{}, errors.New("resource not found")
+}
+
+func testStrategy() union.Union {
+	return union.New(basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		groups, ok := groupsByUser[userName]
+		if !ok {
+			return nil, errors.New("invalid credentials")
+		}
+		return auth.NewDefaultUser(userName, userName, groups, nil), nil
+	}))
+}
+
+func TestRestrict(t *testing.T) {
+	tests := []struct {
+		TestName     string
+		user         string
+		permission   Permission
+		expectedCode int
+	}{
+		{"admins can do everything", "admin@test.com", ManageRoles, http.StatusOK},
+		{"a treasurer can credit members", "treasurer@test.com", ManageCredits, http.StatusOK},
+		{"a treasurer can't manage resources", "treasurer@test.com", ManageResources, http.StatusForbidden},
+		{"the board can read the audit log", "board@test.com", ViewAuditLog, http.StatusOK},
+		{"the board can't change members", "board@test.com", ManageMembers, http.StatusForbidden},
+		{"members can't view other members", "member@test.com", ViewMembers, http.StatusForbidden},
+		{"groups that aren't roles grant nothing", "resource@test.com", ViewMembers, http.StatusForbidden},
+		{"unauthenticated requests are forbidden", "nobody@test.com", ViewMembers, http.StatusForbidden},
+		{"api keys have their scopes", "kiosk-key", ViewMembers, http.StatusOK},
+		{"api keys don't have other permissions", "bridge-key", ViewMembers, http.StatusForbidden},
+		{"a resource scope doesn't post access events everywhere", "bridge-key", PostAccessEvents, http.StatusForbidden},
+		{"a resource scope doesn't open everything", "kiosk-key", OpenResources, http.StatusForbidden},
+	}
+
+	rb := New(testStrategy(), stubHosts{})
+	next := func(w http.ResponseWriter, r *http.Request) {}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodGet, "/api/member", nil)
+			request.SetBasicAuth(tt.user, "password")
+			response := httptest.NewRecorder()
+
+			rb.Restrict(next, tt.permission)(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+		})
+	}
+}
+
+func TestRestrictResource(t *testing.T) {
+	hosts := stubHosts{
+		"host@test.com":   {"lathe"},
+		"former@test.com": {"lathe"},
+	}
+
+	tests := []struct {
+		TestName     string
+		user         string
+		resourceID   string
+		permission   Permission
+		expectedCode int
+	}{
+		{"admins can grant access to any resource", "admin@test.com", "frontdoor", GrantAccess, http.StatusOK},
+		{"hosts can grant access to their resource", "host@test.com", "lathe", GrantAccess, http.StatusOK},
+		{"hosts can open their resource", "host@test.com", "lathe", OpenResources, http.StatusOK},
+		{"hosts can't grant access to other resources", "host@test.com", "frontdoor", GrantAccess, http.StatusForbidden},
+		{"hosts can't manage their resource", "host@test.com", "lathe", ManageResources, http.StatusForbidden},
+		{"hosting needs the area-host role", "former@test.com", "lathe", GrantAccess, http.StatusForbidden},
+		{"the board can't grant access", "board@test.com", "lathe", GrantAccess, http.StatusForbidden},
+		{"api keys can open their resources", "kiosk-key", "frontdoor", OpenResources, http.StatusOK},
+		{"api keys can't open other resources", "kiosk-key", "lathe", OpenResources, http.StatusForbidden},
+		{"a resource scope only opens", "kiosk-key", "frontdoor", GrantAccess, http.StatusForbidden},
+	}
+
+	rb := New(testStrategy(), hosts)
+	next := func(w http.ResponseWriter, r *http.Request) {}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/"+tt.resourceID+"/member/bulk", nil)
+			request.SetBasicAuth(tt.user, "password")
+			request = mux.SetURLVars(request, map[string]string{"id": tt.resourceID})
+			response := httptest.NewRecorder()
+
+			rb.RestrictResource(next, tt.permission, "id")(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+		})
+	}
+}
+
+func TestRestrictResourceBody(t *testing.T) {
+	hosts := stubHosts{"host@test.com": {"lathe"}}
+
+	tests := []struct {
+		TestName     string
+		user         string
+		body         string
+		expectedCode int
+	}{
+		{"admins can grant access to any resource", "admin@test.com", `{"resourceID": "frontdoor"}`, http.StatusOK},
+		{"hosts can grant access to their resource", "host@test.com", `{"resourceID": "lathe", "emails": ["member@test.com"]}`, http.StatusOK},
+		{"hosts can't grant access to other resources", "host@test.com", `{"resourceID": "frontdoor"}`, http.StatusForbidden},
+		{"hosts have to say which resource", "host@test.com", `{"name": "lathe"}`, http.StatusForbidden},
+		{"hosts can't name another resource in another case", "host@test.com", `{"resourceID": "lathe", "RESOURCEID": "frontdoor"}`, http.StatusForbidden},
+		{"bodies that aren't json are forbidden", "host@test.com", `lathe`, http.StatusForbidden},
+		{"members can't grant access", "member@test.com", `{"resourceID": "lathe"}`, http.StatusForbidden},
+	}
+
+	rb := New(testStrategy(), hosts)
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			var received string
+			next := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				received = string(b)
+			}
+
+			request, _ := http.NewRequest(http.MethodPost, "/api/resource/member/bulk", strings.NewReader(tt.body))
+			request.SetBasicAuth(tt.user, "password")
+			response := httptest.NewRecorder()
+
+			rb.RestrictResourceBody(next, GrantAccess, "resourceID")(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+			if response.Code == http.StatusOK && received != tt.body {
+				t.Errorf("expected the handler to get the body, got %q", received)
+			}
+		})
+	}
+}
+
+func TestRestrictResourceNameBody(t *testing.T) {
+	tests := []struct {
+		TestName     string
+		user         string
+		body         string
+		expectedCode int
+	}{
+		{"admins can post for any door", "admin@test.com", `{"door": "lathe", "uid": "111"}`, http.StatusOK},
+		{"api keys can post for their doors", "bridge-key", `{"door": "front door", "uid": "111"}`, http.StatusOK},
+		{"api keys can't post for other doors", "bridge-key", `{"door": "lathe", "uid": "111"}`, http.StatusForbidden},
+		{"api keys can't post for doors that don't exist", "bridge-key", `{"door": "back door", "uid": "111"}`, http.StatusForbidden},
+		{"api keys have to say which door", "bridge-key", `{"uid": "111"}`, http.StatusForbidden},
+		{"api keys can't name another door in another case", "bridge-key", `{"door": "front door", "DOOR": "lathe", "uid": "111"}`, http.StatusForbidden},
+		{"other scopes can't post", "kiosk-key", `{"door": "front door", "uid": "111"}`, http.StatusForbidden},
+	}
+
+	rb := New(testStrategy(), stubHosts{})
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			var received string
+			next := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				received = string(b)
+			}
+
+			request, _ := http.NewRequest(http.MethodPost, "/api/access-events", strings.NewReader(tt.body))
+			request.SetBasicAuth(tt.user, "password")
+			response := httptest.NewRecorder()
+
+			rb.RestrictResourceNameBody(next, PostAccessEvents, "door")(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+			if response.Code == http.StatusOK && received != tt.body {
+				t.Errorf("expected the handler to get the body, got %q", received)
+			}
+		})
+	}
+}
+
+func TestGranted(t *testing.T) {
+	tests := []struct {
+		TestName string
+		roles    []string
+		expected []Permission
+	}{
+		{"members have no permissions", []string{"member"}, []Permission{}},
+		{"the board's permissions", []string{"member", "board"}, []Permission{ViewAccessEvents, ViewAuditLog, ViewMembers, ViewReports}},
+		{"roles add up", []string{"treasurer", "area-host"}, []Permission{RecordTraining, ManageCredits, ViewMembers, ViewReports}},
+		{"api keys have their scopes", groupsByUser["kiosk-key"], []Permission{ViewMembers}},
+		{"resource scopes aren't granted everywhere", groupsByUser["bridge-key"], []Permission{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			granted := Granted(tt.roles)
+			sort.Slice(tt.expected, func(i, j int) bool { return tt.expected[i] < tt.expected[j] })
+
+			if len(granted) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, granted)
+			}
+			for i := range granted {
+				if granted[i] != tt.expected[i] {
+					t.Fatalf("expected %v, got %v", tt.expected, granted)
+				}
+			}
+		})
+	}
+
+	if len(Granted([]string{"admin"})) != len(rolePermissions[models.RoleAdmin]) {
+		t.Error("expected admins to be granted every permission")
+	}
+}
+
+func TestAdminHasEveryPermission(t *testing.T) {
+	for _, grants := range []map[models.Role][]Permission{rolePermissions, scopedPermissions} {
+		for role, permissions := range grants {
+			for _, p := range permissions {
+				if !Allowed([]string{"admin"}, p) {
+					t.Errorf("%s grants %s but admin doesn't", role, p)
+				}
+			}
+		}
+	}
+}
+
+func TestRestrictUsesTheAuthenticatedUser(t *testing.T) {
+	rb := New(testStrategy(), stubHosts{})
+	next := func(w http.ResponseWriter, r *http.Request) {}
+
+	// the auth middleware already found the user, so there are no credentials to check again
+	request, _ := http.NewRequest(http.MethodGet, "/api/member", nil)
+	request = auth.RequestWithUser(auth.NewDefaultUser("admin@test.com", "admin@test.com", groupsByUser["admin@test.com"], nil), request)
+	response := httptest.NewRecorder()
+
+	rb.Restrict(next, ViewMembers)(response, request)
+
+	if response.Code != http.StatusOK {
+		t.Errorf("expected %d, got %d", http.StatusOK, response.Code)
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// APIKey -- a key that lets a script or integration use the api without a member's password
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the key, so admins can tell keys apart
+	// example: msk_3f9a2c
+	Prefix string `json:"prefix"`
+	// Scopes are the permissions the key has
+	// example: ["members.view", "resources.open"]
+	Scopes []string `json:"scopes"`
+	// ResourceIDs are the resources a key with the resources.open or access_events.post scope can use them on
+	ResourceIDs []string   `json:"resourceIDs"`
+	CreatedBy   string     `json:"createdBy"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Active reports whether the key can still be used
+func (k APIKey) Active(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// CreateAPIKeyRequest -- what an admin sends to create an API key
+type CreateAPIKeyRequest struct {
+	// example: kiosk
+	Name string `json:"name"`
+	// example: ["resources.open"]
+	Scopes []string `json:"scopes"`
+	// ResourceIDs are required with the resources.open and access_events.post scopes
+	ResourceIDs []string `json:"resourceIDs"`
+	// ExpiresAt is optional, keys without it don't expire
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// CreateAPIKeyResponse -- a new API key
+//
+//	Key is only ever in this response, just a hash of it is stored
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key"`
+}
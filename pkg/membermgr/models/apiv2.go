@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// Problem -- an error from /api/v2, as described in RFC 9457
+type Problem struct {
+	// Type is a URI for the kind of problem, about:blank when the status says it all
+	// example: about:blank
+	Type string `json:"type"`
+	// example: Precondition Failed
+	Title string `json:"title"`
+	// example: 412
+	Status int `json:"status"`
+	// example: the member has changed since it was read
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request that had the problem
+	// example: /api/v2/members/member@example.com
+	Instance string `json:"instance,omitempty"`
+}
+
+// MemberQuery -- a page of members to read, sorted by email
+type MemberQuery struct {
+	// Search matches names, emails, fobs and subscriptions that have its letters in order
+	Search string
+	// Active only has members with a paid up level when true, and only inactive ones when false
+	Active *bool
+	// After is the lowercase email of the last member on the previous page
+	After string
+	Limit int
+}
+
+// ResourceQuery -- a page of resources to read, sorted by name then ID
+type ResourceQuery struct {
+	// Search matches names and addresses that have its letters in order
+	Search string
+	// AfterName and AfterID are the lowercase name and the ID of the last resource on the previous page
+	AfterName string
+	AfterID   string
+	Limit     int
+}
+
+// MemberPage -- a page of members
+type MemberPage struct {
+	Items []Member `json:"items"`
+	// Total is how many members match, across every page
+	Total int `json:"total"`
+	// NextCursor fetches the next page, it's empty on the last one
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// ResourcePage -- a page of resources
+type ResourcePage struct {
+	Items []Resource `json:"items"`
+	// Total is how many resources match, across every page
+	Total int `json:"total"`
+	// NextCursor fetches the next page, it's empty on the last one
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// MemberChange -- the fields of a member that can be changed
+type MemberChange struct {
+	// required: true
+	// example: Jane Doe
+	Name string `json:"name"`
+	// example: I-1234567890
+	SubscriptionID string `json:"subscriptionID"`
+}
+
+// RFIDChange -- a new fob for a member
+type RFIDChange struct {
+	// required: true
+	// example: 1234567
+	RFID string `json:"rfid"`
+}
+
+// ResourceGrant -- when a member can use a resource, both ends are optional
+type ResourceGrant struct {
+	ValidFrom  *time.Time `json:"validFrom,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// AuditEntry -- a record of a change to who can access what
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+	// Actor is the user or job that made the change
+	Actor string `json:"actor"`
+	// Action that was taken e.g. grant.expired
+	Action string `json:"action"`
+	// Target of the action, usually a member's email or a resource name
+	Target string `json:"target"`
+	Detail string `json:"detail"`
+}
+
+const (
+	// AuditActorScheduler is the actor for changes made by scheduled jobs
+	AuditActorScheduler = "scheduler"
+
+	AuditActionGrant        = "resource.grant"
+	AuditActionGrantExpired = "resource.grant.expired"
+	AuditActionRevoke       = "resource.revoke"
+	AuditActionDoorMode     = "resource.mode"
+
+	AuditActionAccessEventRetention = "access_events.retention"
+
+	AuditActionRoles              = "member.roles"
+	AuditActionResourceHosts      = "resource.hosts"
+	AuditActionResourceGroupHosts = "resource.group.hosts"
+
+	AuditActionTwoFactorApprove = "member.2fa.approve"
+	AuditActionTwoFactorEnable  = "member.2fa.enable"
+	AuditActionTwoFactorDisable = "member.2fa.disable"
+	AuditActionTwoFactorReset   = "member.2fa.reset"
+
+	AuditActionAPIKeyCreate = "api_key.create"
+	AuditActionAPIKeyRevoke = "api_key.revoke"
+
+	AuditActionSessionRevoke = "member.session.revoke"
+	AuditActionUnlock        = "member.unlock"
+)
@@ -0,0 +1,100 @@
+package models
+
+import "time"
+
+// Certification -- a training that members need before they can use some resources
+type Certification struct {
+	// UniqueID of the certification
+	// example: string
+	ID string `json:"id"`
+	// Name of the certification
+	// required: true
+	// example: Laser Cutter
+	Name string `json:"name"`
+	// Description of the training
+	// required: false
+	// example: string
+	Description string `json:"description"`
+	// ValidForDays is how long a training lasts before it has to be renewed. 0 never expires
+	// required: false
+	// example: 365
+	ValidForDays int `json:"validForDays"`
+}
+
+// MemberCertification -- a record of a member being trained
+type MemberCertification struct {
+	ID                string     `json:"id"`
+	MemberID          string     `json:"memberID"`
+	CertificationID   string     `json:"certificationID"`
+	CertificationName string     `json:"certificationName"`
+	TrainerEmail      string     `json:"trainerEmail"`
+	TrainedAt         time.Time  `json:"trainedAt"`
+	ExpiresAt         *time.Time `json:"expiresAt,omitempty"`
+}
+
+// IsValid reports whether the certification has not expired
+func (mc MemberCertification) IsValid(now time.Time) bool {
+	return mc.ExpiresAt == nil || mc.ExpiresAt.After(now)
+}
+
+// MissingCertifications returns the names of the required certifications
+// that aren't covered by a current training in held
+func MissingCertifications(required []Certification, held []MemberCertification, now time.Time) []string {
+	missing := []string{}
+	for _, c := range required {
+		certified := false
+		for _, mc := range held {
+			if mc.CertificationID == c.ID && mc.IsValid(now) {
+				certified = true
+				break
+			}
+		}
+		if !certified {
+			missing = append(missing, c.Name)
+		}
+	}
+	return missing
+}
+
+// CertificationDeleteRequest - request for deleting a certification
+type CertificationDeleteRequest struct {
+	// UniqueID of the certification
+	// required: true
+	// example: string
+	ID string `json:"id"`
+}
+
+// TrainingRequest -- record that the current user trained some members
+type TrainingRequest struct {
+	// CertificationID the members were trained on
+	// required: true
+	// example: string
+	CertificationID string `json:"certificationID"`
+	// Emails - list of the trained member's email addresses
+	// required: true
+	// example: []
+	Emails []string `json:"emails"`
+	// TrainedAt defaults to now
+	// required: false
+	TrainedAt time.Time `json:"trainedAt"`
+}
+
+// ResourceCertificationRequest -- set the certifications a resource requires
+type ResourceCertificationRequest struct {
+	// ID of the Resource
+	// required: true
+	// example: string
+	ResourceID string `json:"resourceID"`
+	// CertificationIDs - every certification a member needs to use the resource
+	// required: true
+	// example: []
+	CertificationIDs []string `json:"certificationIDs"`
+}
+
+// ExpiredCertificationAccess -- a member that still has access to a resource
+//
+//	after the certification it requires has expired
+type ExpiredCertificationAccess struct {
+	MemberAccess
+	CertificationName string
+}
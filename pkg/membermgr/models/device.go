@@ -0,0 +1,58 @@
+package models
+
+import (
+	"net"
+	"strings"
+	"time"
+)
+
+// DeviceAnnouncement is what a device publishes on the discovery topic when it starts up
+type DeviceAnnouncement struct {
+	// MAC address of the device
+	// required: true
+	MAC string `json:"mac"`
+	// Model of the device
+	Model string `json:"model"`
+	// Firmware version the device is running
+	Firmware string `json:"firmware"`
+	// Address the device can be reached at
+	Address string `json:"address"`
+}
+
+// Device is a device that has announced itself
+//
+//	devices without a ResourceID are waiting for an admin to approve them
+type Device struct {
+	MAC        string    `json:"mac"`
+	Model      string    `json:"model"`
+	Firmware   string    `json:"firmware"`
+	Address    string    `json:"address"`
+	ResourceID string    `json:"resourceID,omitempty"`
+	FirstSeen  time.Time `json:"firstSeen"`
+	LastSeen   time.Time `json:"lastSeen"`
+}
+
+// ApproveDeviceRequest names a pending device and turns it into a resource
+type ApproveDeviceRequest struct {
+	// Name of the resource, the device will use it as its mqtt topic prefix
+	// required: true
+	Name string `json:"name"`
+	// IsDefault - new members get access to default resources
+	IsDefault bool `json:"isDefault"`
+}
+
+// DeviceProvisioning is sent to an approved device to tell it its name
+type DeviceProvisioning struct {
+	Name string `json:"name"`
+}
+
+// NormalizeMAC validates a mac address and writes it the same way every time
+//
+//	devices don't agree on case or separators, so we store them lowercase and colon separated
+func NormalizeMAC(mac string) (string, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil {
+		return "", err
+	}
+	return hw.String(), nil
+}
@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// DoorModeName is the state we want a door to be in
+type DoorModeName string
+
+const (
+	// DoorModeNormal - the door follows its access list
+	DoorModeNormal DoorModeName = "normal"
+	// DoorModeLockdown - only admins can get in
+	DoorModeLockdown DoorModeName = "lockdown"
+	// DoorModeHoldOpen - the door is held unlocked
+	DoorModeHoldOpen DoorModeName = "holdopen"
+)
+
+// Valid reports whether the mode is one we know how to apply
+func (m DoorModeName) Valid() bool {
+	return m == DoorModeNormal || m == DoorModeLockdown || m == DoorModeHoldOpen
+}
+
+// DoorMode -- a lockdown or hold-open that is in effect
+type DoorMode struct {
+	// ResourceID the mode applies to. Empty applies to every resource
+	// example: string
+	ResourceID string `json:"resourceID"`
+	// Mode is one of normal, lockdown or holdopen
+	// required: true
+	// example: lockdown
+	Mode DoorModeName `json:"mode"`
+	// Reason the mode was set
+	// example: security incident
+	Reason string `json:"reason"`
+	// SetBy is who set the mode
+	SetBy string    `json:"setBy"`
+	SetAt time.Time `json:"setAt"`
+}
+
+// EffectiveDoorMode works out which mode applies to a resource
+//
+//	lockdown wins over hold-open, and a global mode can't be overridden by a resource
+func EffectiveDoorMode(modes []DoorMode, resourceID string) DoorModeName {
+	effective := DoorModeNormal
+
+	for _, m := range modes {
+		if len(m.ResourceID) > 0 && m.ResourceID != resourceID {
+			continue
+		}
+
+		if m.Mode == DoorModeLockdown {
+			return DoorModeLockdown
+		}
+
+		if m.Mode == DoorModeHoldOpen {
+			effective = DoorModeHoldOpen
+		}
+	}
+
+	return effective
+}
@@ -0,0 +1,44 @@
+package models
+
+import "testing"
+
+func TestEffectiveDoorMode(t *testing.T) {
+	tests := []struct {
+		TestName string
+		modes    []DoorMode
+		expected DoorModeName
+	}{
+		{
+			TestName: "should be normal without any modes",
+			expected: DoorModeNormal,
+		},
+		{
+			TestName: "should apply a mode for the resource",
+			modes:    []DoorMode{{ResourceID: "frontdoor", Mode: DoorModeHoldOpen}},
+			expected: DoorModeHoldOpen,
+		},
+		{
+			TestName: "should ignore modes for other resources",
+			modes:    []DoorMode{{ResourceID: "backdoor", Mode: DoorModeLockdown}},
+			expected: DoorModeNormal,
+		},
+		{
+			TestName: "should apply a global mode",
+			modes:    []DoorMode{{Mode: DoorModeLockdown}},
+			expected: DoorModeLockdown,
+		},
+		{
+			TestName: "lockdown should win over hold-open",
+			modes:    []DoorMode{{ResourceID: "frontdoor", Mode: DoorModeHoldOpen}, {Mode: DoorModeLockdown}},
+			expected: DoorModeLockdown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			if got := EffectiveDoorMode(tt.modes, "frontdoor"); got != tt.expected {
+				t.Errorf("got %s want %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,5 +1,17 @@
 package models
 
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AccessDenied is what esp-rfid devices report for a swipe they didn't let in
+const AccessDenied = "Denied"
+
 type LogMessage struct {
 	Type      string `json:"type"`
 	EventTime int64  `json:"time"`
@@ -7,4 +19,93 @@ type LogMessage struct {
 	Username  string `json:"username"`
 	RFID      string `json:"uid"`
 	Door      string `json:"door"`
+	// Access is what the device did with the swipe, e.g. Granted or Denied
+	Access string `json:"access"`
+	// MemberID is filled in by the server when the fob belongs to a member
+	MemberID string `json:"-"`
+}
+
+// AccessEvent is a swipe that was recorded in the access log
+type AccessEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	EventTime time.Time `json:"eventTime"`
+	IsKnown   bool      `json:"isKnown"`
+	Username  string    `json:"username"`
+	RFID      string    `json:"rfid"`
+	Door      string    `json:"door"`
+	Access    string    `json:"access,omitempty"`
+	MemberID  string    `json:"memberID,omitempty"`
+	// Pseudonymised events have had the member's name and fob replaced by the retention policy
+	Pseudonymised bool `json:"pseudonymised,omitempty"`
+	// RolledUp events have been counted in the daily aggregates
+	RolledUp bool `json:"-"`
+}
+
+// AccessEventFilter narrows down a query of the access log
+//
+//	empty fields aren't filtered on
+type AccessEventFilter struct {
+	MemberID string
+	RFID     string
+	Door     string
+	IsKnown  *bool
+	// Pseudonymised narrows the query to events that have, or haven't, been pseudonymised
+	Pseudonymised *bool
+	From          *time.Time
+	To            *time.Time
+	After         *AccessEventCursor
+	Limit         int
+}
+
+// AccessEventCursor is the position of the last event on a page
+//
+//	events are ordered most recent first, so the next page starts just before it
+type AccessEventCursor struct {
+	EventTime time.Time
+	ID        string
+}
+
+// AccessEventPage is a page of the access log
+type AccessEventPage struct {
+	Events     []AccessEvent `json:"events"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// CursorFor is the cursor that points at an event
+func CursorFor(e AccessEvent) AccessEventCursor {
+	return AccessEventCursor{EventTime: e.EventTime, ID: e.ID}
+}
+
+// Before reports whether an event sorts after the cursor
+func (c AccessEventCursor) Before(e AccessEvent) bool {
+	if e.EventTime.Equal(c.EventTime) {
+		return e.ID < c.ID
+	}
+	return e.EventTime.Before(c.EventTime)
+}
+
+// Encode the cursor into an opaque string for the api
+func (c AccessEventCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d|%s", c.EventTime.UnixNano(), c.ID)))
+}
+
+// DecodeAccessEventCursor parses a cursor created by Encode
+func DecodeAccessEventCursor(cursor string) (AccessEventCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return AccessEventCursor{}, errors.New("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return AccessEventCursor{}, errors.New("invalid cursor")
+	}
+
+	nanos, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return AccessEventCursor{}, errors.New("invalid cursor")
+	}
+
+	return AccessEventCursor{EventTime: time.Unix(0, nanos).UTC(), ID: parts[1]}, nil
 }
@@ -0,0 +1,21 @@
+package models
+
+// ResourceHosts -- the members who host a resource or resource group
+//
+//	hosts with the area-host role can grant and revoke access to the resources they host
+type ResourceHosts struct {
+	// ID of the resource or resource group
+	// example: string
+	ID string `json:"id"`
+	// Emails of the hosts
+	// example: ["host@example.com"]
+	Emails []string `json:"emails"`
+}
+
+// UpdateResourceHostsRequest replaces the hosts of a resource or resource group
+type UpdateResourceHostsRequest struct {
+	// Emails of the hosts, an empty list removes every host
+	// required: true
+	// example: ["host@example.com"]
+	Emails []string `json:"emails"`
+}
@@ -23,6 +23,8 @@ type Member struct {
 	Level          uint8            `json:"memberLevel"`
 	Resources      []MemberResource `json:"resources"`
 	SubscriptionID string           `json:"subscriptionID"`
+	// Version goes up when a field that's edited through the API changes
+	Version int `json:"-"`
 }
 
 // AssignRFIDRequest -- request to associate an rfid to a member
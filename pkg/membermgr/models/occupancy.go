@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Occupancy is how many members are in the space right now
+type Occupancy struct {
+	Count int `json:"count"`
+	// Keyholders is how many of the members in the space are keyholders
+	Keyholders int       `json:"keyholders"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// PresentMember is a member that is in the space and shares that with other members
+type PresentMember struct {
+	Name      string    `json:"name"`
+	Door      string    `json:"door"`
+	Since     time.Time `json:"since"`
+	Keyholder bool      `json:"keyholder"`
+}
+
+// PresenceSharingRequest -- whether the logged in member shows up in the who's here list
+type PresenceSharingRequest struct {
+	Share bool `json:"share"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// OpenWindow -- a scheduled time when a door is unlocked e.g. for an open house or a class
+type OpenWindow struct {
+	// UniqueID of the window
+	// example: string
+	ID string `json:"id"`
+	// ResourceID to unlock
+	// required: true
+	// example: string
+	ResourceID string `json:"resourceID"`
+	// Title shown in the calendar
+	// required: true
+	// example: Open House
+	Title string `json:"title"`
+	// Start of the first occurrence
+	// required: true
+	Start time.Time `json:"start"`
+	// End of the first occurrence
+	// required: true
+	End time.Time `json:"end"`
+	// RRule is an iCalendar recurrence rule. Leave empty for a window that happens once
+	// example: FREQ=WEEKLY;BYDAY=TU
+	RRule string `json:"rrule"`
+	// HoldOpen holds the door open for the whole window. Otherwise the door is opened once when the window starts
+	// example: true
+	HoldOpen bool `json:"holdOpen"`
+}
+
+// OpenWindowDeleteRequest - request for deleting an open window
+type OpenWindowDeleteRequest struct {
+	// UniqueID of the window
+	// required: true
+	// example: string
+	ID string `json:"id"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MinPasswordLength is the shortest password a user can register with
+	MinPasswordLength = 12
+	// MaxPasswordLength is in bytes, bcrypt ignores anything after the 72nd
+	MaxPasswordLength = 72
+)
+
+// ErrPasswordTooShort is returned for passwords under MinPasswordLength
+var ErrPasswordTooShort = errors.New("password must be longer")
+
+// commonPasswords are rejected no matter how long they are
+var commonPasswords = map[string]struct{}{
+	"123456789012":              {},
+	"1234567890123":             {},
+	"qwertyuiopas":              {},
+	"passwordpassword":          {},
+	"password1234":              {},
+	"iloveyou1234":              {},
+	"hackrvahackrva":            {},
+	"hackrva12345":              {},
+	"letmeinletmein":            {},
+	"administrator":             {},
+	"correcthorsebatterystaple": {},
+}
+
+// CheckPasswordPolicy returns an error describing why a password can't be used
+//
+//	long passwords are preferred over composition rules,
+//	so there are no requirements for digits or symbols
+func CheckPasswordPolicy(password string, email string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+
+	if len(password) > MaxPasswordLength {
+		return fmt.Errorf("password must be at most %d bytes", MaxPasswordLength)
+	}
+
+	lower := strings.ToLower(password)
+	if _, ok := commonPasswords[lower]; ok {
+		return errors.New("password is too common")
+	}
+
+	if first, _ := utf8.DecodeRuneInString(lower); strings.Trim(lower, string(first)) == "" {
+		return errors.New("password can't be one repeated character")
+	}
+
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	if len(local) >= 4 && strings.Contains(lower, local) {
+		return errors.New("password can't contain your email address")
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "testing"
+
+func TestCheckPasswordPolicy(t *testing.T) {
+	tests := []struct {
+		TestName string
+		password string
+		valid    bool
+	}{
+		{
+			TestName: "should allow a long passphrase",
+			password: "correct-battery-horse",
+			valid:    true,
+		},
+		{
+			TestName: "should reject a short password",
+			password: "Sh0rt!",
+		},
+		{
+			TestName: "should count characters not bytes",
+			password: "ééééééééé123",
+			valid:    true,
+		},
+		{
+			TestName: "should reject a password bcrypt would truncate",
+			password: "this passphrase goes on and on and on well past the seventy two byte limit",
+		},
+		{
+			TestName: "should reject a common password regardless of case",
+			password: "PasswordPassword",
+		},
+		{
+			TestName: "should reject one repeated character",
+			password: "aaaaaaaaaaaaaaaa",
+		},
+		{
+			TestName: "should reject the email address",
+			password: "member-s3cret-pw",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			err := CheckPasswordPolicy(tt.password, "member@example.com")
+			if tt.valid && err != nil {
+				t.Errorf("expected password to be allowed: %s", err)
+			}
+			if !tt.valid && err == nil {
+				t.Error("expected password to be rejected")
+			}
+		})
+	}
+}
@@ -45,6 +45,15 @@ type AccessStats struct {
 	ResourceName string    `json:"resourceName"`
 }
 
+// UnknownFob is a fob that was swiped but doesn't belong to anyone
+type UnknownFob struct {
+	RFID      string    `json:"rfid"`
+	Attempts  int       `json:"attempts"`
+	Doors     []string  `json:"doors"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
 type MemberChurn struct {
 	Churn int `json:"churn"`
 }
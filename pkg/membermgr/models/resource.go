@@ -25,6 +25,12 @@ type MembersResourceRelation struct {
 	// required: true
 	// example: []
 	Emails []string `json:"emails"`
+	// ValidFrom - when the grant starts. Leave empty to start now
+	// required: false
+	ValidFrom *time.Time `json:"validFrom,omitempty"`
+	// ValidUntil - when the grant is revoked. Leave empty for access that doesn't expire
+	// required: false
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
 }
 
 // MemberAccess represents that a member has access to a certain resource.
@@ -32,10 +38,13 @@ type MembersResourceRelation struct {
 //	this will get pushed to a device.
 type MemberAccess struct {
 	Email           string
+	ResourceID      string
 	ResourceAddress string
 	ResourceName    string
 	Name            string
 	RFID            string
+	Level           uint8
+	ValidUntil      *time.Time
 }
 
 // ACLUpdateRequest is the json object we send to a resource when pushing an update
@@ -60,6 +69,8 @@ type MemberRequest struct {
 	RFID            string `json:"uid"`
 	AccessType      int    `json:"acctype"`
 	ValidUntil      int    `json:"validuntil"`
+	// Windows is only sent to devices whose firmware supports time windows
+	Windows []TimeWindow `json:"windows,omitempty"`
 }
 
 type MQTTRequest struct {
@@ -90,8 +101,30 @@ type Resource struct {
 	// Default state of the Resource
 	// required: true
 	// example: true
-	IsDefault     bool      `json:"isDefault"`
+	IsDefault bool `json:"isDefault"`
+	// SupportsTimeWindows is set when the device firmware can enforce access schedules itself
+	// required: false
+	// example: false
+	SupportsTimeWindows bool `json:"supportsTimeWindows"`
+	// Driver is the protocol the device speaks, either esp-rfid or http
+	// required: false
+	// example: esp-rfid
+	Driver        string    `json:"driver"`
 	LastHeartBeat time.Time `json:"lastHeartBeat"`
+	// Version goes up when a field that's edited through the API changes
+	Version int `json:"-"`
+}
+
+const (
+	// DriverESPRFID - esp-rfid readers that take commands over mqtt
+	DriverESPRFID = "esp-rfid"
+	// DriverHTTP - devices that take their whole access list over http
+	DriverHTTP = "http"
+)
+
+// ValidDriver is true for the device drivers we know how to talk to
+func ValidDriver(driver string) bool {
+	return driver == DriverESPRFID || driver == DriverHTTP
 }
 
 // ResourceDeleteRequest - request for deleting a resource
@@ -120,6 +153,10 @@ type ResourceRequest struct {
 	// required: true
 	// example: true
 	IsDefault bool `json:"isDefault"`
+	// SupportsTimeWindows is set when the device firmware can enforce access schedules itself
+	// required: false
+	// example: false
+	SupportsTimeWindows bool `json:"supportsTimeWindows"`
 }
 
 // RegisterResourceRequest a resource that can accept an access control list
@@ -136,19 +173,27 @@ type RegisterResourceRequest struct {
 	// required: false
 	// example: true
 	IsDefault bool `json:"isDefault"`
+	// Driver is the protocol the device speaks, defaults to esp-rfid
+	// required: false
+	// example: esp-rfid
+	Driver string `json:"driver"`
 }
 
 // MemberResourceRelation  - a relationship between resources and members
 type MemberResourceRelation struct {
-	ID         string `json:"id"`
-	MemberID   string `json:"memberID"`
-	ResourceID string `json:"resourceID"`
+	ID         string     `json:"id"`
+	MemberID   string     `json:"memberID"`
+	ResourceID string     `json:"resourceID"`
+	ValidFrom  *time.Time `json:"validFrom,omitempty"`
+	ValidUntil *time.Time `json:"validUntil,omitempty"`
 }
 
 // OpenResourceRequest -- request to associate an rfid to a member
 type OpenResourceRequest struct {
 	// Name of the Resource
-	// required: true
 	// example: string
 	Name string `json:"name"`
+	// ResourceID picks the resource instead of its name, hosts need it to open their resource
+	// example: string
+	ResourceID string `json:"resourceID"`
 }
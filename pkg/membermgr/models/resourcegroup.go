@@ -0,0 +1,63 @@
+package models
+
+// ResourceGroup -- a named set of resources that members can be granted access to together
+//
+//	e.g. "building entry" could be the front door and the back door
+type ResourceGroup struct {
+	// UniqueID of the group
+	// example: string
+	ID string `json:"id"`
+	// Name of the group
+	// required: true
+	// example: building entry
+	Name string `json:"name"`
+	// Description of the group
+	// example: front and back doors
+	Description string `json:"description"`
+	// ResourceIDs of the resources in the group
+	ResourceIDs []string `json:"resourceIDs"`
+}
+
+// ResourceGroupDeleteRequest - request for deleting a resource group
+type ResourceGroupDeleteRequest struct {
+	// UniqueID of the group
+	// required: true
+	// example: string
+	ID string `json:"id"`
+}
+
+// ResourceGroupMembersRequest -- grant multiple members access to every resource in a group
+type ResourceGroupMembersRequest struct {
+	// GroupID of the resource group
+	// required: true
+	// example: string
+	GroupID string `json:"groupID"`
+	// Emails - list of member's email address
+	// required: true
+	// example: []
+	Emails []string `json:"emails"`
+}
+
+// ResourceGroupMemberRequest -- remove a member from a group
+type ResourceGroupMemberRequest struct {
+	// GroupID of the resource group
+	// required: true
+	// example: string
+	GroupID string `json:"groupID"`
+	// Email - the member's email address
+	// required: true
+	// example: email
+	Email string `json:"email"`
+}
+
+// ResourceGroupResourceRequest -- add or remove a resource from a group
+type ResourceGroupResourceRequest struct {
+	// GroupID of the resource group
+	// required: true
+	// example: string
+	GroupID string `json:"groupID"`
+	// ResourceID of the resource
+	// required: true
+	// example: string
+	ResourceID string `json:"resourceID"`
+}
@@ -0,0 +1,61 @@
+package models
+
+// Role is a named set of permissions that can be assigned to a member
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"
+	RoleTreasurer Role = "treasurer"
+	RoleBoard     Role = "board"
+	RoleAreaHost  Role = "area-host"
+	// RoleMember is held by everyone who can sign in, it doesn't need to be assigned
+	RoleMember Role = "member"
+)
+
+// Roles lists every role, in the order they are shown to admins
+var Roles = []Role{RoleAdmin, RoleTreasurer, RoleBoard, RoleAreaHost, RoleMember}
+
+// Valid reports whether r is a known role
+func (r Role) Valid() bool {
+	for _, role := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// MemberRoles -- the roles assigned to a member
+type MemberRoles struct {
+	// Email of the member
+	// example: member@example.com
+	Email string `json:"email"`
+	// Roles assigned to the member
+	// example: ["board"]
+	Roles []Role `json:"roles"`
+}
+
+// UpdateMemberRolesRequest replaces the roles assigned to a member
+type UpdateMemberRolesRequest struct {
+	// Roles to assign, an empty list leaves the member with only the member role
+	// required: true
+	// example: ["treasurer", "board"]
+	Roles []Role `json:"roles"`
+}
+
+// RolePermissions -- a role and what it allows
+type RolePermissions struct {
+	// Role name
+	// example: treasurer
+	Role Role `json:"role"`
+	// Permissions the role grants
+	// example: ["members.view", "members.credit"]
+	Permissions []string `json:"permissions"`
+}
+
+// UserPermissions -- what the signed in user is allowed to do
+type UserPermissions struct {
+	// Permissions the user's roles grant
+	// example: ["members.view", "reports.view"]
+	Permissions []string `json:"permissions"`
+}
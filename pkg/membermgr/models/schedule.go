@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AccessSchedule -- a weekly window during which members of a tier may use a resource
+//
+//	if a resource has no schedules for a member's tier, that member has access around the clock
+type AccessSchedule struct {
+	// UniqueID of the schedule
+	// example: string
+	ID string `json:"id"`
+	// ResourceID the schedule is attached to
+	// required: true
+	// example: string
+	ResourceID string `json:"resourceID"`
+	// TierID is the member level the schedule applies to. 0 applies to every level
+	// required: false
+	// example: 3
+	TierID uint8 `json:"tierID"`
+	// Weekdays the window is open. 0 is Sunday
+	// required: true
+	// example: [1, 2, 3, 4, 5]
+	Weekdays []int `json:"weekdays"`
+	// StartTime of the window in 24 hour time
+	// required: true
+	// example: 18:00
+	StartTime string `json:"startTime"`
+	// EndTime of the window in 24 hour time. A window that ends before it starts runs past midnight
+	// required: true
+	// example: 22:00
+	EndTime string `json:"endTime"`
+}
+
+// TimeWindow is the shape of a schedule that we push to devices that support time windows
+type TimeWindow struct {
+	Weekdays []int  `json:"days"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+}
+
+// AccessScheduleDeleteRequest - request for deleting a schedule
+type AccessScheduleDeleteRequest struct {
+	// UniqueID of the schedule
+	// required: true
+	// example: string
+	ID string `json:"id"`
+}
+
+// ParseClock converts "HH:MM" to minutes after midnight.  "24:00" is allowed so a window can run to the end of the day
+func ParseClock(clock string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(clock, "%d:%d", &hour, &minute); err != nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", clock)
+	}
+
+	if hour < 0 || minute < 0 || minute > 59 || hour > 24 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid time %q", clock)
+	}
+
+	return hour*60 + minute, nil
+}
+
+// Validate checks that a schedule is well formed
+func (s AccessSchedule) Validate() error {
+	if len(s.ResourceID) == 0 {
+		return errors.New("resourceID is required")
+	}
+
+	if len(s.Weekdays) == 0 {
+		return errors.New("at least one weekday is required")
+	}
+
+	for _, d := range s.Weekdays {
+		if d < int(time.Sunday) || d > int(time.Saturday) {
+			return fmt.Errorf("invalid weekday: %d", d)
+		}
+	}
+
+	start, err := ParseClock(s.StartTime)
+	if err != nil {
+		return err
+	}
+
+	end, err := ParseClock(s.EndTime)
+	if err != nil {
+		return err
+	}
+
+	if start == end {
+		return errors.New("startTime and endTime can not be the same")
+	}
+
+	return nil
+}
+
+// TimeWindow converts the schedule to the format we send to devices
+func (s AccessSchedule) TimeWindow() TimeWindow {
+	return TimeWindow{
+		Weekdays: s.Weekdays,
+		Start:    s.StartTime,
+		End:      s.EndTime,
+	}
+}
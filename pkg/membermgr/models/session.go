@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Session -- a signed in browser, it's kept going with a refresh token
+type Session struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	// example: Mozilla/5.0 (X11; Linux x86_64)
+	UserAgent  string     `json:"userAgent"`
+	IPAddress  string     `json:"ipAddress"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt time.Time  `json:"lastUsedAt"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+	// RefreshHash is the hash of the current refresh token
+	RefreshHash string `json:"-"`
+	// PreviousHash is the hash of the refresh token the current one replaced
+	PreviousHash string     `json:"-"`
+	RotatedAt    *time.Time `json:"-"`
+}
+
+// Active reports whether the session can still be used
+func (s Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Lockout -- whether an account has been locked after too many failed logins
+type Lockout struct {
+	Email string `json:"email"`
+	// FailedLogins since the last successful one, they're forgotten after the lockout period
+	FailedLogins int        `json:"failedLogins"`
+	Locked       bool       `json:"locked"`
+	LockedUntil  *time.Time `json:"lockedUntil,omitempty"`
+}
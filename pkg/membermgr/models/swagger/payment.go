@@ -1,7 +0,0 @@
-package swagger
-
-// swagger:parameters searchPaymentChartRequest
-type searchPaymentChartRequest struct {
-	// in:query
-	Type string `json:"type"`
-}
@@ -0,0 +1,70 @@
+package models
+
+// TwoFactor -- a user's authenticator app enrolment
+type TwoFactor struct {
+	Email string
+	// Secret is set once the user starts enrolling, it's stored encrypted
+	Secret string
+	// EnrollmentApproved is set when a user who has to enrol confirms it from their email
+	EnrollmentApproved bool
+	// Enabled once the user has confirmed a code from their app
+	Enabled bool
+	// LastStep is the time step of the last code that was accepted
+	LastStep int64
+	// RecoveryCodesLeft is how many unused recovery codes the user has
+	RecoveryCodesLeft int
+}
+
+// TwoFactorStatus -- whether a user has two-factor authentication
+type TwoFactorStatus struct {
+	// Enabled is true once the user has enrolled an authenticator app
+	Enabled bool `json:"enabled"`
+	// Required is true when the user's roles require two-factor authentication
+	Required bool `json:"required"`
+	// RecoveryCodesLeft is how many unused recovery codes the user has
+	// example: 10
+	RecoveryCodesLeft int `json:"recoveryCodesLeft"`
+}
+
+// TwoFactorEnrollment -- what an authenticator app needs to add the account
+type TwoFactorEnrollment struct {
+	// Secret for users who type it in rather than scanning the QR code
+	// example: JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP
+	Secret string `json:"secret"`
+	// URI is encoded in the QR code at /api/auth/2fa/qr
+	// example: otpauth://totp/HackRVA:member@example.com?secret=JBSWY3DPEHPK3PXP&issuer=HackRVA
+	URI string `json:"uri"`
+}
+
+// TwoFactorCodeRequest -- a code from the user's authenticator app
+type TwoFactorCodeRequest struct {
+	// required: true
+	// example: 123456
+	Code string `json:"code"`
+}
+
+// RecoveryCodesResponse -- codes that can each be used once instead of the authenticator app
+//
+//	they are only ever shown once
+type RecoveryCodesResponse struct {
+	// example: ["k3jd9-x8w2q"]
+	Codes []string `json:"codes"`
+}
+
+// LoginRequest -- the second step of a login, for users with two-factor authentication
+type LoginRequest struct {
+	// Code from the user's authenticator app
+	// example: 123456
+	Code string `json:"code"`
+	// RecoveryCode can be used instead of Code
+	// example: k3jd9-x8w2q
+	RecoveryCode string `json:"recoveryCode"`
+}
+
+// TwoFactorChallenge -- returned by a login that needs a second step
+type TwoFactorChallenge struct {
+	// TwoFactorRequired is true when the login needs a code
+	TwoFactorRequired bool `json:"twoFactorRequired"`
+	// SetupRequired is true when the user has to enrol before they can sign in
+	SetupRequired bool `json:"setupRequired"`
+}
@@ -8,6 +8,15 @@ type Claims struct {
 	jwt.StandardClaims
 }
 
+// OIDCProviderResponse -- whether members can sign in with an OpenID Connect provider
+type OIDCProviderResponse struct {
+	// Enabled is true when a provider is configured
+	Enabled bool `json:"enabled"`
+	// Name of the provider to show on the sign in button
+	// example: Google
+	Name string `json:"name"`
+}
+
 // TokenResponse -- for json response of signin
 type TokenResponse struct {
 	// login response to send token string
@@ -35,3 +44,29 @@ type UserResponse struct {
 	Email     string     `json:"email"`
 	Resources []Resource `json:"resources"`
 }
+
+// UserAccount -- a user's stored login
+type UserAccount struct {
+	Email        string `json:"email"`
+	PasswordHash string `json:"-"`
+	// Verified is set once the user follows the link sent to their email
+	Verified bool `json:"verified"`
+}
+
+// ForgotPasswordRequest -- ask for a password reset link
+type ForgotPasswordRequest struct {
+	// Email the user registered with
+	// required: true
+	// example: member@example.com
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest -- set a new password with the token from a reset link
+type ResetPasswordRequest struct {
+	// Token from the reset link
+	// required: true
+	Token string `json:"token"`
+	// Password to set
+	// required: true
+	Password string `json:"password"`
+}
@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Endpoint describes what a route does
+type Endpoint struct {
+	Summary string
+	Tag     string
+	// Query names the query parameters it reads
+	Query []string
+	// Headers names the headers it needs
+	Headers []string
+	// Request is an example of the body it reads, nil when there isn't one
+	Request interface{}
+	// Response is an example of the body it writes, nil when there isn't one
+	Response interface{}
+	// Status is the status of a successful response, 200 when it isn't set
+	Status int
+	// ContentType of the response, application/json when it isn't set
+	ContentType string
+}
+
+// Route is an endpoint the server has at a method and path
+type Route struct {
+	Method string
+	// Path is the route's mux template
+	Path string
+	Endpoint
+	// Security overrides the document's security, an empty list means no sign in is needed
+	Security *[]SecurityRequirement
+	// ErrorContentType is how errors are written, they're plain text when it isn't set
+	ErrorContentType string
+	// Error is an example of an error body, it's a string when it isn't set
+	Error interface{}
+}
+
+var pathParam = regexp.MustCompile(`\{([^}:]+)(:[^}]*)?\}`)
+
+// Build describes the routes, along with every model type they read or write
+func Build(info Info, routes []Route) Document {
+	doc := Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: map[string]*Schema{},
+		},
+	}
+	components := schemas(doc.Components.Schemas)
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	for _, route := range routes {
+		// mux templates can have a pattern, {id:[0-9]+}, openapi only has the name
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		if _, ok := doc.Paths[path]; !ok {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = operation(components, route, path)
+	}
+
+	return doc
+}
+
+func operation(components schemas, route Route, path string) *Operation {
+	op := &Operation{
+		OperationID: operationID(route.Method, path),
+		Summary:     route.Summary,
+		Responses:   map[string]Response{},
+		Security:    route.Security,
+	}
+	if len(route.Tag) > 0 {
+		op.Tags = []string{route.Tag}
+	}
+
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+	}
+	for _, name := range route.Query {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "query", Schema: &Schema{Type: "string"}})
+	}
+	for _, name := range route.Headers {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "header", Required: true, Schema: &Schema{Type: "string"}})
+	}
+
+	if route.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {Schema: components.schemaOf(route.Request)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		contentType := route.ContentType
+		if len(contentType) == 0 {
+			contentType = "application/json"
+		}
+		response.Content = map[string]MediaType{contentType: {Schema: components.schemaOf(route.Response)}}
+	}
+	op.Responses[strconv.Itoa(status)] = response
+
+	errorType, errorBody := "text/plain", &Schema{Type: "string"}
+	if len(route.ErrorContentType) > 0 {
+		errorType = route.ErrorContentType
+	}
+	if route.Error != nil {
+		errorBody = components.schemaOf(route.Error)
+	}
+	op.Responses["default"] = Response{
+		Description: "error",
+		Content:     map[string]MediaType{errorType: {Schema: errorBody}},
+	}
+
+	return op
+}
+
+// operationID names an operation after its method and path, GET /api/member/{id} is getApiMemberById
+func operationID(method string, path string) string {
+	id := strings.ToLower(method)
+	for _, segment := range strings.Split(path, "/") {
+		if match := pathParam.FindStringSubmatch(segment); match != nil {
+			id += "By" + title(match[1])
+			continue
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return !isAlphanumeric(r) }) {
+			id += title(word)
+		}
+	}
+	return id
+}
+
+func title(word string) string {
+	word = strings.Map(func(r rune) rune {
+		if isAlphanumeric(r) {
+			return r
+		}
+		return -1
+	}, word)
+	if len(word) == 0 {
+		return ""
+	}
+	return strings.ToUpper(word[:1]) + word[1:]
+}
+
+func isAlphanumeric(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
+}
@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	type resource struct {
+		Name string `json:"name"`
+	}
+	public := []SecurityRequirement{}
+
+	doc := Build(Info{Title: "test", Version: "1"}, []Route{
+		{Method: http.MethodGet, Path: "/api/resource/{id:[0-9]+}", Endpoint: Endpoint{Summary: "get", Response: resource{}}},
+		{Method: http.MethodDelete, Path: "/api/resource/{id:[0-9]+}", Endpoint: Endpoint{Headers: []string{"If-Match"}, Status: http.StatusNoContent}},
+		{Method: http.MethodPost, Path: "/api/login", Endpoint: Endpoint{Query: []string{"next"}, Request: resource{}}, Security: &public},
+	})
+
+	item, ok := doc.Paths["/api/resource/{id}"]
+	if !ok {
+		t.Fatalf("paths = %v", doc.Paths)
+	}
+
+	get := item["get"]
+	if get.OperationID != "getApiResourceById" {
+		t.Errorf("operationId = %q", get.OperationID)
+	}
+	if len(get.Parameters) != 1 || get.Parameters[0] != (Parameter{Name: "id", In: "path", Required: true, Schema: get.Parameters[0].Schema}) {
+		t.Errorf("parameters = %+v", get.Parameters)
+	}
+	if get.Responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/resource" {
+		t.Errorf("responses = %+v", get.Responses)
+	}
+	if get.Responses["default"].Content["text/plain"].Schema.Type != "string" {
+		t.Errorf("errors should be plain text: %+v", get.Responses["default"])
+	}
+
+	remove := item["delete"]
+	if _, ok := remove.Responses["204"]; !ok || remove.Responses["204"].Content != nil {
+		t.Errorf("responses = %+v", remove.Responses)
+	}
+	if len(remove.Parameters) != 2 || remove.Parameters[1].In != "header" || !remove.Parameters[1].Required {
+		t.Errorf("parameters = %+v", remove.Parameters)
+	}
+
+	login := doc.Paths["/api/login"]["post"]
+	if login.RequestBody == nil || login.Security == nil || len(*login.Security) != 0 {
+		t.Errorf("login = %+v", login)
+	}
+	if login.Parameters[0].In != "query" {
+		t.Errorf("parameters = %+v", login.Parameters)
+	}
+}
+
+func TestOperationID(t *testing.T) {
+	tests := map[string]string{
+		"GET /api/member/email/{email}/roles": "getApiMemberEmailByEmailRoles",
+		"POST /api/auth/2fa/recovery-codes":   "postApiAuth2faRecoveryCodes",
+		"GET /api/calendar/openwindows.ics":   "getApiCalendarOpenwindowsIcs",
+	}
+
+	for route, want := range tests {
+		method, path, _ := strings.Cut(route, " ")
+		if got := operationID(method, path); got != want {
+			t.Errorf("operationID(%s) = %q, want %q", route, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package openapi
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBase struct {
+	ID string `json:"id"`
+}
+
+type testNode struct {
+	testBase
+	Name     string `json:"name,omitempty"`
+	Secret   string `json:"-"`
+	Untagged int64
+	When     *time.Time        `json:"when"`
+	Children []testNode        `json:"children"`
+	Labels   map[string]string `json:"labels"`
+	hidden   bool
+}
+
+func TestSchema(t *testing.T) {
+	components := schemas{}
+
+	ref := components.schemaOf([]testNode{})
+	if ref.Type != "array" || ref.Items.Ref != "#/components/schemas/testNode" {
+		t.Fatalf("schema = %+v", ref)
+	}
+
+	node := components["testNode"]
+	if node == nil {
+		t.Fatal("testNode wasn't added to the components")
+	}
+
+	want := map[string]*Schema{
+		"id":       {Type: "string"},
+		"name":     {Type: "string"},
+		"Untagged": {Type: "integer", Format: "int64"},
+		"when":     {Type: "string", Format: "date-time"},
+		"children": {Type: "array", Items: &Schema{Ref: "#/components/schemas/testNode"}},
+		"labels":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+	}
+	if !reflect.DeepEqual(node.Properties, want) {
+		t.Errorf("properties = %+v, want %+v", node.Properties, want)
+	}
+}
+
+func TestSchemaAnonymousStruct(t *testing.T) {
+	components := schemas{}
+
+	s := components.schemaOf(struct {
+		Text string `json:"text"`
+	}{})
+
+	if s.Type != "object" || s.Properties["text"].Type != "string" || len(components) != 0 {
+		t.Errorf("schema = %+v, components %v", s, components)
+	}
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type AccessEventHTTPHandler interface {
+	GetAccessEvents(w http.ResponseWriter, req *http.Request)
+	GetSelfAccessEvents(w http.ResponseWriter, req *http.Request)
+	StreamAccessEvents(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupAccessEventRoutes(accessEvent AccessEventHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/access-events", accessControl.Restrict(accessEvent.GetAccessEvents, rbac.ViewAccessEvents)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/access-events/stream", accessControl.Restrict(accessEvent.StreamAccessEvents, rbac.ViewAccessEvents)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/self/access-events", accessEvent.GetSelfAccessEvents).Methods(http.MethodGet)
+}
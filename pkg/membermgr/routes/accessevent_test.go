@@ -0,0 +1,65 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/union"
+)
+
+type stubAccessEvents struct{}
+
+func (stubAccessEvents) GetAccessEvents(w http.ResponseWriter, req *http.Request)     {}
+func (stubAccessEvents) GetSelfAccessEvents(w http.ResponseWriter, req *http.Request) {}
+func (stubAccessEvents) StreamAccessEvents(w http.ResponseWriter, req *http.Request)  {}
+
+// TestAccessEventStreamIsRestricted the live stream shows every member's swipes, so it needs the same permission as the access log
+func TestAccessEventStreamIsRestricted(t *testing.T) {
+	roles := map[string][]string{
+		"admin@test.com":  {string(models.RoleAdmin)},
+		"board@test.com":  {string(models.RoleBoard)},
+		"member@test.com": {string(models.RoleMember)},
+	}
+	strategy := union.New(basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		groups, ok := roles[userName]
+		if !ok {
+			return nil, errors.New("invalid credentials")
+		}
+		return auth.NewDefaultUser(userName, userName, groups, nil), nil
+	}))
+
+	r := Router{authedRouter: mux.NewRouter().PathPrefix("/api/").Subrouter()}
+	r.setupAccessEventRoutes(stubAccessEvents{}, rbac.New(strategy, nil))
+
+	tests := []struct {
+		user         string
+		expectedCode int
+	}{
+		{"admin@test.com", http.StatusOK},
+		{"board@test.com", http.StatusOK},
+		{"member@test.com", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.user, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/api/access-events/stream", nil)
+			request.SetBasicAuth(tt.user, "password")
+			response := httptest.NewRecorder()
+
+			r.authedRouter.ServeHTTP(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+		})
+	}
+}
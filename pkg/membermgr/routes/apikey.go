@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+
+	"github.com/gorilla/mux"
+	guardian "github.com/shaj13/go-guardian/v2/auth"
+)
+
+type APIKeyHTTPHandler interface {
+	GetAPIKeys(w http.ResponseWriter, req *http.Request)
+	CreateAPIKey(w http.ResponseWriter, req *http.Request)
+	RevokeAPIKey(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupAPIKeyRoutes(apiKey APIKeyHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/api-keys", accessControl.Restrict(apiKey.GetAPIKeys, rbac.ManageAPIKeys)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/api-keys", accessControl.Restrict(apiKey.CreateAPIKey, rbac.ManageAPIKeys)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/api-keys/{id}", accessControl.Restrict(apiKey.RevokeAPIKey, rbac.ManageAPIKeys)).Methods(http.MethodDelete)
+}
+
+// allowAPIKeys lets API keys use a route, rbac still checks the key's scopes
+//
+//	routes that aren't marked are for people only, so a new route is never open to keys by accident
+func (r Router) allowAPIKeys(route *mux.Route) *mux.Route {
+	r.apiKeyRoutes[route] = true
+	return route
+}
+
+// restrictAPIKeys is middleware that keeps API keys to the routes marked with allowAPIKeys
+func (r Router) restrictAPIKeys(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if auth.IsAPIKey(guardian.User(req)) && !r.apiKeyRoutes[mux.CurrentRoute(req)] {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type AuditHTTPHandler interface {
+	GetAuditLog(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupAuditRoutes(audit AuditHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/audit", accessControl.Restrict(audit.GetAuditLog, rbac.ViewAuditLog)).Methods(http.MethodGet)
+}
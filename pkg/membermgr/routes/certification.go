@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type CertificationHTTPHandler interface {
+	Certification(w http.ResponseWriter, req *http.Request)
+	RecordTraining(w http.ResponseWriter, req *http.Request)
+	GetMemberCertifications(w http.ResponseWriter, req *http.Request)
+	GetSelfCertifications(w http.ResponseWriter, req *http.Request)
+	ResourceCertifications(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupCertificationRoutes(certification CertificationHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/certification", accessControl.Restrict(certification.Certification, rbac.ManageCertifications)).Methods(http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete)
+	r.authedRouter.HandleFunc("/certification/training", accessControl.Restrict(certification.RecordTraining, rbac.RecordTraining)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/certification/member/{email}", accessControl.Restrict(certification.GetMemberCertifications, rbac.RecordTraining)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/self/certifications", certification.GetSelfCertifications).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/resource/certification", accessControl.Restrict(certification.ResourceCertifications, rbac.ManageCertifications)).Methods(http.MethodGet, http.MethodPut)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type DeviceHTTPHandler interface {
+	GetPendingDevices(w http.ResponseWriter, req *http.Request)
+	ApproveDevice(w http.ResponseWriter, req *http.Request)
+	DismissDevice(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupDeviceRoutes(device DeviceHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/resource/devices/pending", accessControl.Restrict(device.GetPendingDevices, rbac.ManageResources)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/resource/devices/{mac}/approve", accessControl.Restrict(device.ApproveDevice, rbac.ManageResources)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/devices/{mac}", accessControl.Restrict(device.DismissDevice, rbac.ManageResources)).Methods(http.MethodDelete)
+}
@@ -0,0 +1,5 @@
+// starts Swagger UI, it's served from here rather than inline so the page's CSP can forbid inline scripts
+window.ui = SwaggerUIBundle({
+  url: "/api/openapi.json",
+  dom_id: "#swagger-ui",
+});
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type DoorModeHTTPHandler interface {
+	DoorMode(w http.ResponseWriter, req *http.Request)
+	SlackDoorMode(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupDoorModeRoutes(doorMode DoorModeHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/resource/mode", accessControl.Restrict(doorMode.DoorMode, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPut)
+	// slack can't log in, so slash commands are verified with the slack signing secret instead
+	r.UnAuthedRouter.HandleFunc("/api/slack/door", doorMode.SlackDoorMode).Methods(http.MethodPost)
+}
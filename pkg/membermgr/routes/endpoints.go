@@ -0,0 +1,164 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/openapi"
+)
+
+var (
+	accessEventQuery = []string{"door", "known", "from", "to", "limit", "cursor", "format"}
+	pageQuery        = []string{"limit", "cursor", "q"}
+	ifMatch          = []string{"If-Match"}
+)
+
+// endpoints documents every route for /api/openapi.json, keyed by method and the route's path template
+//
+//	TestEveryRouteIsDocumented fails when a route is added without an entry here
+var endpoints = map[string]openapi.Endpoint{
+	// auth
+	"POST /api/auth/login":                {Tag: "auth", Summary: "Sign in with basic auth, and a two factor code when it's needed", Request: models.LoginRequest{}, Response: models.TokenResponse{}},
+	"DELETE /api/auth/logout":             {Tag: "auth", Summary: "Sign out and end the session", Response: ""},
+	"POST /api/auth/refresh":              {Tag: "auth", Summary: "Swap the refresh cookie for a new access token", Response: models.TokenResponse{}},
+	"POST /api/auth/register":             {Tag: "auth", Summary: "Make an account, a verification email is sent", Request: models.Credentials{}, Response: models.EndpointSuccess{}},
+	"GET /api/auth/verify":                {Tag: "auth", Summary: "Verify an email address from the link that was emailed", Query: []string{"token"}, Status: http.StatusFound},
+	"POST /api/auth/password/forgot":      {Tag: "auth", Summary: "Email a password reset link", Request: models.ForgotPasswordRequest{}, Response: models.EndpointSuccess{}},
+	"POST /api/auth/password/reset":       {Tag: "auth", Summary: "Set a new password with a reset token", Request: models.ResetPasswordRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/auth/oidc":                  {Tag: "auth", Summary: "The single sign on provider, if there is one", Response: models.OIDCProviderResponse{}},
+	"GET /api/auth/oidc/login":            {Tag: "auth", Summary: "Start a single sign on login", Status: http.StatusFound},
+	"GET /api/auth/oidc/callback":         {Tag: "auth", Summary: "Finish a single sign on login", Query: []string{"code", "state", "error"}, Status: http.StatusFound},
+	"GET /api/auth/2fa":                   {Tag: "auth", Summary: "Whether two factor authentication is on", Response: models.TwoFactorStatus{}},
+	"POST /api/auth/2fa/enroll":           {Tag: "auth", Summary: "Start setting up two factor authentication, or email a link to confirm it first", Response: models.TwoFactorEnrollment{}},
+	"GET /api/auth/2fa/approve":           {Tag: "auth", Summary: "Confirm setting up two factor authentication from the link that was emailed", Query: []string{"token"}, Status: http.StatusFound},
+	"GET /api/auth/2fa/qr":                {Tag: "auth", Summary: "QR code for an authenticator app", ContentType: "image/png", Response: []byte{}},
+	"POST /api/auth/2fa/confirm":          {Tag: "auth", Summary: "Turn on two factor authentication with a code from the app", Request: models.TwoFactorCodeRequest{}, Response: models.RecoveryCodesResponse{}},
+	"POST /api/auth/2fa/recovery-codes":   {Tag: "auth", Summary: "Replace the recovery codes", Request: models.LoginRequest{}, Response: models.RecoveryCodesResponse{}},
+	"POST /api/auth/2fa/disable":          {Tag: "auth", Summary: "Turn off two factor authentication", Request: models.LoginRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/user":                       {Tag: "auth", Summary: "The signed in member", Response: models.Member{}},
+	"GET /api/user/permissions":           {Tag: "auth", Summary: "What the signed in member's roles allow", Response: models.UserPermissions{}},
+	"GET /api/version":                    {Tag: "auth", Summary: "The server's version", Response: models.VersionResponse{}},
+	"GET /api/openapi.json":               {Tag: "docs", Summary: "This document", Response: map[string]interface{}{}},
+	"GET /api/docs":                       {Tag: "docs", Summary: "Browse this document", ContentType: "text/html", Response: ""},
+	"GET /api/docs/docs.js":               {Tag: "docs", Summary: "The script that starts the docs page", ContentType: "text/javascript", Response: ""},
+	"GET /api/member/self":                {Tag: "members", Summary: "The signed in member", Response: models.Member{}},
+	"POST /api/member/assignRFID/self":    {Tag: "members", Summary: "Register your own fob", Request: models.AssignRFIDRequest{}, Response: models.Member{}},
+	"GET /api/member/self/access-events":  {Tag: "access events", Summary: "Your swipe history", Query: accessEventQuery, Response: models.AccessEventPage{}},
+	"GET /api/member/self/certifications": {Tag: "certifications", Summary: "Your certifications", Response: []models.MemberCertification{}},
+	"GET /api/member/self/hosted":         {Tag: "resources", Summary: "Resources you host", Response: []models.Resource{}},
+	"GET /api/member/self/presence":       {Tag: "occupancy", Summary: "Whether you're shown in who's here", Response: models.PresenceSharingRequest{}},
+	"PUT /api/member/self/presence":       {Tag: "occupancy", Summary: "Show or hide yourself in who's here", Request: models.PresenceSharingRequest{}, Response: models.PresenceSharingRequest{}},
+
+	// members
+	"GET /api/member":                                {Tag: "members", Summary: "List members, a page at a time when page and count are set", Query: []string{"search", "active", "page", "count"}, Response: []models.Member{}},
+	"POST /api/member/new":                           {Tag: "members", Summary: "Add a member", Request: models.Member{}, Response: models.Member{}},
+	"GET /api/member/email/{email}":                  {Tag: "members", Summary: "Get a member", Response: models.Member{}},
+	"PUT /api/member/email/{email}":                  {Tag: "members", Summary: "Change a member's name and subscription", Request: models.UpdateMemberRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/member/{id}/status":                    {Tag: "members", Summary: "Check a subscription with the payment provider", Response: models.Member{}},
+	"PUT /api/member/{id}/credit":                    {Tag: "members", Summary: "Make a member credited or not", Request: models.MemberShipCreditRequest{}, Response: models.InfoResponse{}},
+	"POST /api/member/assignRFID":                    {Tag: "members", Summary: "Give a member a fob", Request: models.AssignRFIDRequest{}, Response: models.Member{}},
+	"GET /api/member/tier":                           {Tag: "members", Summary: "Membership levels", Response: []models.Tier{}},
+	"GET /api/member/slack/nonmembers":               {Tag: "members", Summary: "Slack users who aren't members", ContentType: "text/csv", Response: ""},
+	"GET /api/roles":                                 {Tag: "roles", Summary: "Roles and their permissions", Response: []models.RolePermissions{}},
+	"GET /api/member/email/{email}/roles":            {Tag: "roles", Summary: "A member's roles", Response: models.MemberRoles{}},
+	"PUT /api/member/email/{email}/roles":            {Tag: "roles", Summary: "Set a member's roles", Request: models.UpdateMemberRolesRequest{}, Response: models.MemberRoles{}},
+	"DELETE /api/member/email/{email}/2fa":           {Tag: "roles", Summary: "Turn off a member's two factor authentication", Response: models.EndpointSuccess{}},
+	"GET /api/member/email/{email}/sessions":         {Tag: "sessions", Summary: "A member's sessions", Response: []models.Session{}},
+	"DELETE /api/member/email/{email}/sessions":      {Tag: "sessions", Summary: "Sign a member out everywhere", Response: models.EndpointSuccess{}},
+	"DELETE /api/member/email/{email}/sessions/{id}": {Tag: "sessions", Summary: "End one of a member's sessions", Response: models.EndpointSuccess{}},
+	"GET /api/member/email/{email}/lockout":          {Tag: "sessions", Summary: "A member's failed logins and lockout", Response: models.Lockout{}},
+	"DELETE /api/member/email/{email}/lockout":       {Tag: "sessions", Summary: "Unlock a member", Response: models.EndpointSuccess{}},
+	"GET /api/api-keys":                              {Tag: "api keys", Summary: "List API keys", Response: []models.APIKey{}},
+	"POST /api/api-keys":                             {Tag: "api keys", Summary: "Make an API key, the key is only shown once", Request: models.CreateAPIKeyRequest{}, Response: models.CreateAPIKeyResponse{}},
+	"DELETE /api/api-keys/{id}":                      {Tag: "api keys", Summary: "Revoke an API key", Response: models.APIKey{}},
+	"GET /api/audit":                                 {Tag: "audit", Summary: "The audit log, newest first", Query: []string{"limit", "offset"}, Response: []models.AuditEntry{}},
+
+	// resources
+	"GET /api/resource":                   {Tag: "resources", Summary: "List resources", Response: []models.Resource{}},
+	"PUT /api/resource":                   {Tag: "resources", Summary: "Change a resource", Request: models.Resource{}, Response: models.Resource{}},
+	"DELETE /api/resource":                {Tag: "resources", Summary: "Remove a resource", Request: models.ResourceDeleteRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/resource/status":            {Tag: "resources", Summary: "Ask every resource to report its status", Response: models.EndpointSuccess{}},
+	"POST /api/resource/register":         {Tag: "resources", Summary: "Register a resource", Request: models.RegisterResourceRequest{}, Response: models.Resource{}},
+	"POST /api/resource/member/bulk":      {Tag: "resources", Summary: "Grant members access to a resource", Request: models.MembersResourceRelation{}, Response: []models.MemberResourceRelation{}},
+	"DELETE /api/resource/member":         {Tag: "resources", Summary: "Revoke a member's access to a resource", Request: models.MemberResourceRelationUpdateRequest{}, Response: models.EndpointSuccess{}},
+	"POST /api/resource/updateacls":       {Tag: "resources", Summary: "Push every resource its access list", Response: models.EndpointSuccess{}},
+	"DELETE /api/resource/deleteacls":     {Tag: "resources", Summary: "Clear every resource's access list", Response: models.EndpointSuccess{}},
+	"POST /api/resource/open":             {Tag: "resources", Summary: "Open a resource by name", Request: models.OpenResourceRequest{}, Response: models.EndpointSuccess{}},
+	"POST /api/resource/{id}/open":        {Tag: "resources", Summary: "Open a resource", Response: models.EndpointSuccess{}},
+	"POST /api/resource/{id}/member/bulk": {Tag: "resources", Summary: "Grant members access to a resource you host", Request: models.MembersResourceRelation{}, Response: []models.MemberResourceRelation{}},
+	"DELETE /api/resource/{id}/member":    {Tag: "resources", Summary: "Revoke a member's access to a resource you host", Request: models.MemberResourceRelationUpdateRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/resource/{id}/hosts":        {Tag: "resources", Summary: "A resource's hosts", Response: models.ResourceHosts{}},
+	"PUT /api/resource/{id}/hosts":        {Tag: "resources", Summary: "Set a resource's hosts", Request: models.UpdateResourceHostsRequest{}, Response: models.ResourceHosts{}},
+	"POST /api/access-events":             {Tag: "access events", Summary: "Record a swipe from a device that doesn't use mqtt", Request: models.LogMessage{}, Response: models.EndpointSuccess{}},
+	"GET /api/access-events":              {Tag: "access events", Summary: "Search swipes", Query: append([]string{"rfid", "member"}, accessEventQuery...), Response: models.AccessEventPage{}},
+	"GET /api/access-events/stream":       {Tag: "access events", Summary: "Swipes as they happen, over server sent events or a websocket", Query: []string{"door"}, ContentType: "text/event-stream", Response: models.AccessEvent{}},
+	"GET /api/resource/schedule":          {Tag: "resources", Summary: "Access schedules", Query: []string{"resourceID"}, Response: []models.AccessSchedule{}},
+	"POST /api/resource/schedule":         {Tag: "resources", Summary: "Add an access schedule", Request: models.AccessSchedule{}, Response: models.AccessSchedule{}},
+	"PUT /api/resource/schedule":          {Tag: "resources", Summary: "Change an access schedule", Request: models.AccessSchedule{}, Response: models.AccessSchedule{}},
+	"DELETE /api/resource/schedule":       {Tag: "resources", Summary: "Remove an access schedule", Request: models.AccessScheduleDeleteRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/resource/openwindow":        {Tag: "resources", Summary: "Open windows", Query: []string{"resourceID"}, Response: []models.OpenWindow{}},
+	"POST /api/resource/openwindow":       {Tag: "resources", Summary: "Add an open window", Request: models.OpenWindow{}, Response: models.OpenWindow{}},
+	"PUT /api/resource/openwindow":        {Tag: "resources", Summary: "Change an open window", Request: models.OpenWindow{}, Response: models.OpenWindow{}},
+	"DELETE /api/resource/openwindow":     {Tag: "resources", Summary: "Remove an open window", Request: models.OpenWindowDeleteRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/calendar/openwindows.ics":   {Tag: "resources", Summary: "Open windows as a calendar", Query: []string{"resourceID"}, ContentType: "text/calendar", Response: ""},
+	"GET /api/resource/mode":              {Tag: "resources", Summary: "Door modes", Response: []models.DoorMode{}},
+	"PUT /api/resource/mode":              {Tag: "resources", Summary: "Lock down, hold open or return a door to normal", Request: models.DoorMode{}, Response: models.EndpointSuccess{}},
+	"POST /api/slack/door": {Tag: "resources", Summary: "The /door slack command, signed by slack", Response: struct {
+		ResponseType string `json:"response_type"`
+		Text         string `json:"text"`
+	}{}},
+	"GET /api/resource/devices/pending":        {Tag: "devices", Summary: "Devices waiting to be approved", Response: []models.Device{}},
+	"POST /api/resource/devices/{mac}/approve": {Tag: "devices", Summary: "Approve a device as a resource", Request: models.ApproveDeviceRequest{}, Response: models.Resource{}},
+	"DELETE /api/resource/devices/{mac}":       {Tag: "devices", Summary: "Dismiss a device", Response: models.EndpointSuccess{}},
+
+	// resource groups
+	"GET /api/resource/group":              {Tag: "resource groups", Summary: "List resource groups", Response: []models.ResourceGroup{}},
+	"POST /api/resource/group":             {Tag: "resource groups", Summary: "Add a resource group", Request: models.ResourceGroup{}, Response: models.ResourceGroup{}},
+	"PUT /api/resource/group":              {Tag: "resource groups", Summary: "Change a resource group", Request: models.ResourceGroup{}, Response: models.ResourceGroup{}},
+	"DELETE /api/resource/group":           {Tag: "resource groups", Summary: "Remove a resource group", Request: models.ResourceGroupDeleteRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/resource/group/{id}/members": {Tag: "resource groups", Summary: "A resource group's members", Response: []models.Member{}},
+	"POST /api/resource/group/member/bulk": {Tag: "resource groups", Summary: "Add members to a resource group", Request: models.ResourceGroupMembersRequest{}, Response: []models.MemberResourceRelation{}},
+	"DELETE /api/resource/group/member":    {Tag: "resource groups", Summary: "Remove a member from a resource group", Request: models.ResourceGroupMemberRequest{}, Response: models.EndpointSuccess{}},
+	"POST /api/resource/group/resource":    {Tag: "resource groups", Summary: "Add a resource to a resource group", Request: models.ResourceGroupResourceRequest{}, Response: models.EndpointSuccess{}},
+	"DELETE /api/resource/group/resource":  {Tag: "resource groups", Summary: "Remove a resource from a resource group", Request: models.ResourceGroupResourceRequest{}, Response: models.EndpointSuccess{}},
+	"GET /api/resource/group/{id}/hosts":   {Tag: "resource groups", Summary: "A resource group's hosts", Response: models.ResourceHosts{}},
+	"PUT /api/resource/group/{id}/hosts":   {Tag: "resource groups", Summary: "Set a resource group's hosts", Request: models.UpdateResourceHostsRequest{}, Response: models.ResourceHosts{}},
+
+	// certifications
+	"GET /api/certification":                {Tag: "certifications", Summary: "List certifications", Response: []models.Certification{}},
+	"POST /api/certification":               {Tag: "certifications", Summary: "Add a certification", Request: models.Certification{}, Response: models.Certification{}},
+	"PUT /api/certification":                {Tag: "certifications", Summary: "Change a certification", Request: models.Certification{}, Response: models.Certification{}},
+	"DELETE /api/certification":             {Tag: "certifications", Summary: "Remove a certification", Request: models.CertificationDeleteRequest{}, Response: models.EndpointSuccess{}},
+	"POST /api/certification/training":      {Tag: "certifications", Summary: "Record that members were trained", Request: models.TrainingRequest{}, Response: []models.MemberCertification{}},
+	"GET /api/certification/member/{email}": {Tag: "certifications", Summary: "A member's certifications", Response: []models.MemberCertification{}},
+	"GET /api/resource/certification":       {Tag: "certifications", Summary: "Certifications a resource needs", Query: []string{"resourceID"}, Response: []models.Certification{}},
+	"PUT /api/resource/certification":       {Tag: "certifications", Summary: "Set the certifications a resource needs", Request: models.ResourceCertificationRequest{}, Response: models.EndpointSuccess{}},
+
+	// occupancy and reports
+	"GET /api/occupancy":                {Tag: "occupancy", Summary: "How many people are in the space", Response: models.Occupancy{}},
+	"GET /api/occupancy/members":        {Tag: "occupancy", Summary: "Who's here, of the members who share it", Response: []models.PresentMember{}},
+	"GET /api/reports/membercounts":     {Tag: "reports", Summary: "Member counts by level", Query: []string{"type", "month"}, Response: []models.ReportChart{}},
+	"GET /api/reports/access":           {Tag: "reports", Summary: "Swipes by hour for a day", Query: []string{"resourceName", "day"}, Response: models.ReportChart{}},
+	"GET /api/reports/churn":            {Tag: "reports", Summary: "Member churn this month", Response: models.MemberChurn{}},
+	"GET /api/reports/unknown-fobs":     {Tag: "reports", Summary: "Fobs that were swiped but aren't assigned", Query: []string{"days", "limit"}, Response: []models.UnknownFob{}},
+	"POST /api/paypal/subscription/new": {Tag: "payments", Summary: "PayPal subscription webhook"},
+
+	// v2
+	"GET /api/v2/members":                           {Tag: "v2 members", Summary: "A page of members, sorted by email", Query: append([]string{"active"}, pageQuery...), Response: models.MemberPage{}},
+	"POST /api/v2/members":                          {Tag: "v2 members", Summary: "Add a member", Request: models.Member{}, Response: models.Member{}, Status: http.StatusCreated},
+	"GET /api/v2/members/{email}":                   {Tag: "v2 members", Summary: "Get a member and its ETag", Response: models.Member{}},
+	"PUT /api/v2/members/{email}":                   {Tag: "v2 members", Summary: "Change a member's name and subscription", Headers: ifMatch, Request: models.MemberChange{}, Response: models.Member{}},
+	"PUT /api/v2/members/{email}/rfid":              {Tag: "v2 members", Summary: "Give a member a fob", Headers: ifMatch, Request: models.RFIDChange{}, Response: models.Member{}},
+	"GET /api/v2/me":                                {Tag: "v2 members", Summary: "The signed in member", Response: models.Member{}},
+	"PUT /api/v2/me/rfid":                           {Tag: "v2 members", Summary: "Register your own fob", Request: models.RFIDChange{}, Response: models.Member{}},
+	"GET /api/v2/tiers":                             {Tag: "v2 members", Summary: "Membership levels", Response: []models.Tier{}},
+	"GET /api/v2/resources":                         {Tag: "v2 resources", Summary: "A page of resources, sorted by name", Query: pageQuery, Response: models.ResourcePage{}},
+	"POST /api/v2/resources":                        {Tag: "v2 resources", Summary: "Register a resource", Request: models.RegisterResourceRequest{}, Response: models.Resource{}, Status: http.StatusCreated},
+	"GET /api/v2/resources/{id}":                    {Tag: "v2 resources", Summary: "Get a resource and its ETag", Response: models.Resource{}},
+	"PUT /api/v2/resources/{id}":                    {Tag: "v2 resources", Summary: "Change a resource", Headers: ifMatch, Request: models.RegisterResourceRequest{}, Response: models.Resource{}},
+	"DELETE /api/v2/resources/{id}":                 {Tag: "v2 resources", Summary: "Remove a resource", Headers: ifMatch, Status: http.StatusNoContent},
+	"PUT /api/v2/resources/{id}/members/{email}":    {Tag: "v2 resources", Summary: "Grant a member access, optionally for a while", Request: models.ResourceGrant{}, Response: []models.MemberResourceRelation{}},
+	"DELETE /api/v2/resources/{id}/members/{email}": {Tag: "v2 resources", Summary: "Revoke a member's access", Status: http.StatusNoContent},
+	"PUT /api/v2/acls":                              {Tag: "v2 resources", Summary: "Push every resource its access list", Status: http.StatusNoContent},
+	"DELETE /api/v2/acls":                           {Tag: "v2 resources", Summary: "Clear every resource's access list", Status: http.StatusNoContent},
+}
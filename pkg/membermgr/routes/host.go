@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type ResourceHostHTTPHandler interface {
+	AddMultipleMembersToResource(w http.ResponseWriter, req *http.Request)
+	RemoveMember(w http.ResponseWriter, req *http.Request)
+	Open(w http.ResponseWriter, req *http.Request)
+	ResourceHosts(w http.ResponseWriter, req *http.Request)
+	ResourceGroupHosts(w http.ResponseWriter, req *http.Request)
+	GetSelfHostedResources(w http.ResponseWriter, req *http.Request)
+}
+
+// setupResourceHostRoutes registers the routes scoped to a single resource
+//
+//	they must come after the other /resource routes so that paths like /resource/group/member aren't read as a resource id
+func (r Router) setupResourceHostRoutes(host ResourceHostHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/resource/{id}/member/bulk", accessControl.RestrictResource(host.AddMultipleMembersToResource, rbac.GrantAccess, "id")).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/{id}/member", accessControl.RestrictResource(host.RemoveMember, rbac.GrantAccess, "id")).Methods(http.MethodDelete)
+	r.allowAPIKeys(r.authedRouter.HandleFunc("/resource/{id}/open", accessControl.RestrictResource(host.Open, rbac.OpenResources, "id")).Methods(http.MethodPost))
+	r.authedRouter.HandleFunc("/resource/{id}/hosts", accessControl.Restrict(host.ResourceHosts, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPut)
+	r.authedRouter.HandleFunc("/resource/group/{id}/hosts", accessControl.Restrict(host.ResourceGroupHosts, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPut)
+	r.authedRouter.HandleFunc("/member/self/hosted", host.GetSelfHostedResources).Methods(http.MethodGet)
+}
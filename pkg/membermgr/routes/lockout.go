@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type LockoutHTTPHandler interface {
+	GetLockout(w http.ResponseWriter, req *http.Request)
+	Unlock(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupLockoutRoutes(lockout LockoutHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/member/email/{email}/lockout", accessControl.Restrict(lockout.GetLockout, rbac.ManageSessions)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/email/{email}/lockout", accessControl.Restrict(lockout.Unlock, rbac.ManageSessions)).Methods(http.MethodDelete)
+}
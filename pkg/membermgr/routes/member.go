@@ -22,14 +22,15 @@ type MemberHTTPHandler interface {
 }
 
 func (r Router) setupMemberRoutes(member MemberHTTPHandler, accessControl rbac.AccessControl) {
-	r.authedRouter.HandleFunc("/member", accessControl.Restrict(member.GetMembersHandler, []rbac.UserRole{rbac.Admin}))
-	r.authedRouter.HandleFunc("/member/new", accessControl.Restrict(member.AddNewMemberHandler, []rbac.UserRole{rbac.Admin}))
+	r.allowAPIKeys(r.authedRouter.HandleFunc("/member", accessControl.Restrict(member.GetMembersHandler, rbac.ViewMembers)))
+	r.authedRouter.HandleFunc("/member/new", accessControl.Restrict(member.AddNewMemberHandler, rbac.ManageMembers))
 	r.authedRouter.HandleFunc("/member/self", member.GetCurrentUserHandler)
-	r.authedRouter.HandleFunc("/member/{id}/status", accessControl.Restrict(member.CheckStatus, []rbac.UserRole{rbac.Admin}))
-	r.authedRouter.HandleFunc("/member/email/{email}", accessControl.Restrict(member.MemberEmailHandler, []rbac.UserRole{rbac.Admin})).Methods(http.MethodGet, http.MethodPut)
-	r.authedRouter.HandleFunc("/member/slack/nonmembers", accessControl.Restrict(member.GetNonMembersOnSlackHandler, []rbac.UserRole{rbac.Admin}))
-	r.authedRouter.HandleFunc("/member/tier", accessControl.Restrict(member.GetTiersHandler, []rbac.UserRole{rbac.Admin}))
+	r.allowAPIKeys(r.authedRouter.HandleFunc("/member/{id}/status", accessControl.Restrict(member.CheckStatus, rbac.ViewMembers)))
+	r.allowAPIKeys(r.authedRouter.HandleFunc("/member/email/{email}", accessControl.Restrict(member.MemberEmailHandler, rbac.ViewMembers)).Methods(http.MethodGet))
+	r.authedRouter.HandleFunc("/member/email/{email}", accessControl.Restrict(member.MemberEmailHandler, rbac.ManageMembers)).Methods(http.MethodPut)
+	r.authedRouter.HandleFunc("/member/slack/nonmembers", accessControl.Restrict(member.GetNonMembersOnSlackHandler, rbac.ViewMembers))
+	r.authedRouter.HandleFunc("/member/tier", accessControl.Restrict(member.GetTiersHandler, rbac.ViewMembers))
 	r.authedRouter.HandleFunc("/member/assignRFID/self", member.AssignRFIDSelfHandler).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/member/assignRFID", accessControl.Restrict(member.AssignRFIDHandler, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/member/{id}/credit", accessControl.Restrict(member.SetCredited, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPut)
+	r.authedRouter.HandleFunc("/member/assignRFID", accessControl.Restrict(member.AssignRFIDHandler, rbac.ManageMembers)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/member/{id}/credit", accessControl.Restrict(member.SetCredited, rbac.ManageCredits)).Methods(http.MethodPut)
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"net/http"
+)
+
+type OccupancyHTTPHandler interface {
+	GetOccupancy(w http.ResponseWriter, req *http.Request)
+	GetWhosHere(w http.ResponseWriter, req *http.Request)
+	PresenceSharing(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupOccupancyRoutes(occupancy OccupancyHTTPHandler) {
+	r.authedRouter.HandleFunc("/occupancy", occupancy.GetOccupancy).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/occupancy/members", occupancy.GetWhosHere).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/self/presence", occupancy.PresenceSharing).Methods(http.MethodGet, http.MethodPut)
+}
@@ -0,0 +1,175 @@
+package routes
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	api "github.com/HackRVA/memberserver/pkg/membermgr/controllers"
+	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	v2 "github.com/HackRVA/memberserver/pkg/membermgr/controllers/v2"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/openapi"
+
+	"github.com/gorilla/mux"
+)
+
+//go:embed docs.html
+var docsPage []byte
+
+//go:embed docs.js
+var docsScript []byte
+
+// swaggerUI is where the docs page loads Swagger UI from, pinned to one version
+const swaggerUI = "https://unpkg.com/swagger-ui-dist@5.17.14/"
+
+// docsPolicy only lets the docs page run our script and the pinned Swagger UI,
+// and only talk to this server
+//
+//	Swagger UI sets inline styles, so those are allowed
+const docsPolicy = "default-src 'none'; " +
+	"script-src 'self' " + swaggerUI + "; " +
+	"style-src 'unsafe-inline' " + swaggerUI + "; " +
+	"img-src 'self' data:; " +
+	"connect-src 'self'; " +
+	"base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+func (r Router) setupOpenAPIRoutes() {
+	r.UnAuthedRouter.HandleFunc("/api/openapi.json", r.serveOpenAPI).Methods(http.MethodGet)
+	r.UnAuthedRouter.HandleFunc("/api/docs", serveDocs).Methods(http.MethodGet)
+	r.UnAuthedRouter.HandleFunc("/api/docs/docs.js", serveDocsScript).Methods(http.MethodGet)
+}
+
+func (r Router) serveOpenAPI(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(r.OpenAPI())
+}
+
+func serveDocs(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", docsPolicy)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(docsPage)
+}
+
+func serveDocsScript(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(docsScript)
+}
+
+// registeredRoute is a route on the router, for one of its methods
+type registeredRoute struct {
+	// method is empty when the route takes any method, the handler sorts it out
+	method string
+	path   string
+	route  *mux.Route
+	authed bool
+}
+
+// registeredRoutes walks the router for every route the API has
+func (r Router) registeredRoutes() []registeredRoute {
+	authed := map[*mux.Route]bool{}
+	r.authedRouter.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		authed[route] = true
+		return nil
+	})
+
+	routes := []registeredRoute{}
+	r.UnAuthedRouter.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		// subrouters don't have a handler of their own, and prefixes, like the UI's, serve files
+		pattern, _ := route.GetPathRegexp()
+		if route.GetHandler() == nil || !strings.HasSuffix(pattern, "$") {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{""}
+		}
+		for _, method := range methods {
+			routes = append(routes, registeredRoute{method, path, route, authed[route]})
+		}
+		return nil
+	})
+
+	return routes
+}
+
+// endpointsFor finds the documentation for a route by method
+func endpointsFor(route registeredRoute) map[string]openapi.Endpoint {
+	found := map[string]openapi.Endpoint{}
+	for key, endpoint := range endpoints {
+		method, path, _ := strings.Cut(key, " ")
+		if path == route.path && (route.method == "" || route.method == method) {
+			found[method] = endpoint
+		}
+	}
+	return found
+}
+
+// OpenAPI describes the routes registered on the router
+func (r Router) OpenAPI() openapi.Document {
+	routes := []openapi.Route{}
+	for _, registered := range r.registeredRoutes() {
+		for method, endpoint := range endpointsFor(registered) {
+			route := openapi.Route{
+				Method:   method,
+				Path:     registered.path,
+				Endpoint: endpoint,
+			}
+
+			switch {
+			case !registered.authed:
+				route.Security = &[]openapi.SecurityRequirement{}
+			case r.apiKeyRoutes[registered.route]:
+				route.Security = &[]openapi.SecurityRequirement{{"bearerAuth": {}}, {"basicAuth": {}}, {"apiKey": {}}}
+			}
+
+			if strings.HasPrefix(registered.path, v2.Prefix) {
+				route.ErrorContentType = "application/problem+json"
+				route.Error = models.Problem{}
+			}
+
+			routes = append(routes, route)
+		}
+	}
+
+	version := api.GitCommit
+	if len(version) == 0 {
+		version = "dev"
+	}
+
+	doc := openapi.Build(openapi.Info{
+		Title:       "memberserver",
+		Description: "Manages members of the makerspace and their access to its resources.",
+		Version:     version,
+	}, routes)
+
+	doc.Components.SecuritySchemes = map[string]openapi.SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "the token from /api/auth/login, sent along with the session cookie it sets"},
+		"basicAuth":  {Type: "http", Scheme: "basic", Description: "email and password"},
+		"apiKey":     {Type: "apiKey", In: "header", Name: auth.APIKeyHeader, Description: "a key from /api/api-keys, only on routes that allow keys"},
+	}
+	doc.Security = []openapi.SecurityRequirement{{"bearerAuth": {}}, {"basicAuth": {}}}
+
+	return doc
+}
+
+// undocumentedRoutes are registered routes without an entry in endpoints
+func (r Router) undocumentedRoutes() []string {
+	missing := []string{}
+	for _, route := range r.registeredRoutes() {
+		if len(endpointsFor(route)) == 0 {
+			missing = append(missing, strings.TrimSpace(route.method+" "+route.path))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
@@ -0,0 +1,121 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	api "github.com/HackRVA/memberserver/pkg/membermgr/controllers"
+	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/openapi"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+	"github.com/HackRVA/memberserver/pkg/mqtt"
+
+	"github.com/sirupsen/logrus"
+)
+
+type slackNotifier struct{}
+
+func (s slackNotifier) Send(msg string) {}
+
+// testRouter is built once, registering routes also registers them on http.DefaultServeMux
+var testRouter = func() Router {
+	store := &in_memory.In_memory{}
+	authController := auth.New(store, nil)
+	rm := resourcemanager.New(mqtt.New(), store, slackNotifier{}, logrus.New())
+	return New(api.Setup(store, authController, rm, nil, nil, logrus.New()), authController, store)
+}()
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	for _, route := range testRouter.undocumentedRoutes() {
+		t.Errorf("%s isn't in the OpenAPI spec, add it to endpoints", route)
+	}
+}
+
+func TestEveryEndpointHasARoute(t *testing.T) {
+	routes := testRouter.registeredRoutes()
+
+	for key := range endpoints {
+		method, path, _ := strings.Cut(key, " ")
+		found := false
+		for _, route := range routes {
+			if route.path == path && (route.method == "" || route.method == method) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%s is documented, but there's no route for it", key)
+		}
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	rr := httptest.NewRecorder()
+	testRouter.UnAuthedRouter.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/openapi.json", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d", rr.Code)
+	}
+
+	var doc openapi.Document
+	if err := json.NewDecoder(rr.Body).Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenAPI != openapi.Version {
+		t.Errorf("openapi = %q", doc.OpenAPI)
+	}
+
+	refresh := doc.Paths["/api/auth/refresh"]["post"]
+	if refresh == nil || refresh.Security == nil || len(*refresh.Security) != 0 {
+		t.Errorf("refresh should be public: %+v", refresh)
+	}
+
+	members := doc.Paths["/api/v2/members"]["get"]
+	if members == nil || members.Security == nil || len(*members.Security) != 3 {
+		t.Fatalf("v2 members should allow API keys: %+v", members)
+	}
+	if _, ok := members.Responses["default"].Content["application/problem+json"]; !ok {
+		t.Errorf("v2 errors should be problem details: %+v", members.Responses["default"])
+	}
+	if _, ok := doc.Components.Schemas["MemberPage"]; !ok {
+		t.Errorf("MemberPage schema is missing")
+	}
+
+	update := doc.Paths["/api/v2/members/{email}"]["put"]
+	if update == nil || update.Security != nil {
+		t.Errorf("updating a member should need a sign in: %+v", update)
+	}
+}
+
+func TestServeDocs(t *testing.T) {
+	rr := httptest.NewRecorder()
+	testRouter.UnAuthedRouter.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/docs", nil))
+
+	policy := rr.Header().Get("Content-Security-Policy")
+	if rr.Code != http.StatusOK || !strings.Contains(policy, "script-src 'self' "+swaggerUI+";") {
+		t.Fatalf("status = %d, policy %q", rr.Code, policy)
+	}
+
+	page := rr.Body.String()
+	for _, script := range strings.Split(page, "<script")[1:] {
+		if tag := script[:strings.Index(script, ">")]; !strings.Contains(tag, "src=") {
+			t.Error("the policy doesn't allow inline scripts")
+		}
+	}
+	// everything the page loads from elsewhere has to be the Swagger UI the policy allows
+	for _, url := range strings.Fields(strings.NewReplacer(`"`, " ").Replace(page)) {
+		if strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, swaggerUI) {
+			t.Errorf("%s isn't allowed by the policy", url)
+		}
+	}
+
+	rr = httptest.NewRecorder()
+	testRouter.UnAuthedRouter.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/docs/docs.js", nil))
+	if rr.Code != http.StatusOK || !strings.Contains(rr.Body.String(), "SwaggerUIBundle") {
+		t.Errorf("docs.js = %d %q", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type OpenWindowHTTPHandler interface {
+	OpenWindow(w http.ResponseWriter, req *http.Request)
+	OpenWindowCalendar(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupOpenWindowRoutes(openWindow OpenWindowHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/resource/openwindow", accessControl.Restrict(openWindow.OpenWindow, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete)
+	// calendar apps can't log in, and open house times aren't a secret
+	r.UnAuthedRouter.HandleFunc("/api/calendar/openwindows.ics", openWindow.OpenWindowCalendar).Methods(http.MethodGet)
+}
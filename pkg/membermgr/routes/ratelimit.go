@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/ratelimit"
+
+	"github.com/gorilla/mux"
+)
+
+// rateLimit limits how often one address can use a route
+//
+//	use it on routes that don't need a login and cost something, like sending an email
+func (r Router) rateLimit(route *mux.Route, limit ratelimit.Limit) *mux.Route {
+	return route.Handler(ratelimit.Middleware(r.rateLimits, limit, r.clientIP)(route.GetHandler()))
+}
@@ -15,15 +15,19 @@ type ResourceHTTPHandler interface {
 	UpdateResourceACL(w http.ResponseWriter, req *http.Request)
 	Open(w http.ResponseWriter, req *http.Request)
 	DeleteResourceACL(w http.ResponseWriter, req *http.Request)
+	Schedule(w http.ResponseWriter, req *http.Request)
+	PostAccessEvent(w http.ResponseWriter, req *http.Request)
 }
 
 func (r Router) setupResourceRoutes(resource ResourceHTTPHandler, accessControl rbac.RBAC) {
-	r.authedRouter.HandleFunc("/resource", accessControl.Restrict(resource.Resource, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPut, http.MethodDelete, http.MethodGet)
-	r.authedRouter.HandleFunc("/resource/status", accessControl.Restrict(resource.Status, []rbac.UserRole{rbac.Admin})).Methods(http.MethodGet)
-	r.authedRouter.HandleFunc("/resource/register", accessControl.Restrict(resource.Register, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/resource/member/bulk", accessControl.Restrict(resource.AddMultipleMembersToResource, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/resource/deleteacls", accessControl.Restrict(resource.DeleteResourceACL, []rbac.UserRole{rbac.Admin})).Methods(http.MethodDelete)
-	r.authedRouter.HandleFunc("/resource/updateacls", accessControl.Restrict(resource.UpdateResourceACL, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/resource/open", accessControl.Restrict(resource.Open, []rbac.UserRole{rbac.Admin})).Methods(http.MethodPost)
-	r.authedRouter.HandleFunc("/resource/member", accessControl.Restrict(resource.RemoveMember, []rbac.UserRole{rbac.Admin})).Methods(http.MethodDelete)
+	r.authedRouter.HandleFunc("/resource", accessControl.Restrict(resource.Resource, rbac.ManageResources)).Methods(http.MethodPut, http.MethodDelete, http.MethodGet)
+	r.authedRouter.HandleFunc("/resource/status", accessControl.Restrict(resource.Status, rbac.ManageResources)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/resource/register", accessControl.Restrict(resource.Register, rbac.ManageResources)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/member/bulk", accessControl.RestrictResourceBody(resource.AddMultipleMembersToResource, rbac.GrantAccess, "resourceID")).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/deleteacls", accessControl.Restrict(resource.DeleteResourceACL, rbac.ManageResources)).Methods(http.MethodDelete)
+	r.authedRouter.HandleFunc("/resource/updateacls", accessControl.Restrict(resource.UpdateResourceACL, rbac.ManageResources)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/open", accessControl.RestrictResourceBody(resource.Open, rbac.OpenResources, "resourceID")).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/member", accessControl.RestrictResourceBody(resource.RemoveMember, rbac.GrantAccess, "resourceID")).Methods(http.MethodDelete)
+	r.authedRouter.HandleFunc("/resource/schedule", accessControl.Restrict(resource.Schedule, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete)
+	r.allowAPIKeys(r.authedRouter.HandleFunc("/access-events", accessControl.RestrictResourceNameBody(resource.PostAccessEvent, rbac.PostAccessEvents, "door")).Methods(http.MethodPost))
 }
@@ -0,0 +1,125 @@
+package routes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/gorilla/mux"
+	"github.com/shaj13/go-guardian/v2/auth"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/basic"
+	"github.com/shaj13/go-guardian/v2/auth/strategies/union"
+)
+
+type stubResources struct{}
+
+func (stubResources) Resource(w http.ResponseWriter, req *http.Request)                     {}
+func (stubResources) AddMultipleMembersToResource(w http.ResponseWriter, req *http.Request) {}
+func (stubResources) RemoveMember(w http.ResponseWriter, req *http.Request)                 {}
+func (stubResources) Register(w http.ResponseWriter, req *http.Request)                     {}
+func (stubResources) Status(w http.ResponseWriter, req *http.Request)                       {}
+func (stubResources) UpdateResourceACL(w http.ResponseWriter, req *http.Request)            {}
+func (stubResources) Open(w http.ResponseWriter, req *http.Request)                         {}
+func (stubResources) DeleteResourceACL(w http.ResponseWriter, req *http.Request)            {}
+func (stubResources) Schedule(w http.ResponseWriter, req *http.Request)                     {}
+func (stubResources) PostAccessEvent(w http.ResponseWriter, req *http.Request)              {}
+
+// stubHosts makes host@test.com the host of the lathe, resource IDs are their names
+type stubHosts struct{}
+
+func (stubHosts) IsResourceHost(email string, resourceID string) (bool, error) {
+	return email == "host@test.com" && resourceID == "lathe", nil
+}
+
+func (stubHosts) GetResourceByName(name string) (models.Resource, error) {
+	if name != "lathe" && name != "frontdoor" {
+		return models.Resource{}, errors.New("resource not found")
+	}
+	return models.Resource{ID: name, Name: name}, nil
+}
+
+func resourceRouter() Router {
+	groups := map[string][]string{
+		"host@test.com": {string(models.RoleMember), string(models.RoleAreaHost)},
+		"bridge-key":    rbac.ScopeGroups([]string{string(rbac.PostAccessEvents)}, []string{"lathe"}),
+	}
+	strategy := union.New(basic.New(func(ctx context.Context, r *http.Request, userName, password string) (auth.Info, error) {
+		userGroups, ok := groups[userName]
+		if !ok {
+			return nil, errors.New("invalid credentials")
+		}
+		return auth.NewDefaultUser(userName, userName, userGroups, nil), nil
+	}))
+
+	r := Router{authedRouter: mux.NewRouter().PathPrefix("/api/").Subrouter(), apiKeyRoutes: map[*mux.Route]bool{}}
+	r.setupResourceRoutes(stubResources{}, rbac.New(strategy, stubHosts{}))
+	return r
+}
+
+// TestResourceBodiesCantNameAnotherResource handlers decode bodies into structs, which match keys whatever their case,
+// so a key that differs in case mustn't get a resource past the check for another one
+func TestResourceBodiesCantNameAnotherResource(t *testing.T) {
+	r := resourceRouter()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+	}{
+		{"hosts can grant access to their resource", http.MethodPost, "/api/resource/member/bulk", `{"resourceID": "lathe", "emails": ["member@test.com"]}`, http.StatusOK},
+		{"granting with a second resource in another case", http.MethodPost, "/api/resource/member/bulk", `{"resourceID": "lathe", "RESOURCEID": "frontdoor", "emails": ["member@test.com"]}`, http.StatusForbidden},
+		{"granting with the resource in another case", http.MethodPost, "/api/resource/member/bulk", `{"resourceid": "frontdoor", "emails": ["member@test.com"]}`, http.StatusForbidden},
+		{"opening with a second resource in another case", http.MethodPost, "/api/resource/open", `{"resourceID": "lathe", "ResourceId": "frontdoor"}`, http.StatusForbidden},
+		{"revoking with a second resource in another case", http.MethodDelete, "/api/resource/member", `{"resourceID": "lathe", "RESOURCEID": "frontdoor", "email": "member@test.com"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			request.SetBasicAuth("host@test.com", "password")
+			response := httptest.NewRecorder()
+
+			r.authedRouter.ServeHTTP(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+		})
+	}
+}
+
+func TestAccessEventsCantNameAnotherDoor(t *testing.T) {
+	r := resourceRouter()
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{"keys can post for their door", `{"door": "lathe", "uid": "111"}`, http.StatusOK},
+		{"a second door in another case", `{"door": "lathe", "DOOR": "frontdoor", "uid": "111"}`, http.StatusForbidden},
+		{"the door in another case", `{"Door": "frontdoor", "uid": "111"}`, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/api/access-events", strings.NewReader(tt.body))
+			request.SetBasicAuth("bridge-key", "password")
+			response := httptest.NewRecorder()
+
+			r.authedRouter.ServeHTTP(response, request)
+
+			if response.Code != tt.expectedCode {
+				t.Errorf("expected %d, got %d", tt.expectedCode, response.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type ResourceGroupHTTPHandler interface {
+	ResourceGroup(w http.ResponseWriter, req *http.Request)
+	GetResourceGroupMembers(w http.ResponseWriter, req *http.Request)
+	AddResourceGroupMembers(w http.ResponseWriter, req *http.Request)
+	RemoveResourceGroupMember(w http.ResponseWriter, req *http.Request)
+	ResourceGroupResource(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupResourceGroupRoutes(group ResourceGroupHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/resource/group", accessControl.Restrict(group.ResourceGroup, rbac.ManageResources)).Methods(http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete)
+	r.authedRouter.HandleFunc("/resource/group/{id}/members", accessControl.Restrict(group.GetResourceGroupMembers, rbac.ManageResources)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/resource/group/member/bulk", accessControl.Restrict(group.AddResourceGroupMembers, rbac.ManageResources)).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/resource/group/member", accessControl.Restrict(group.RemoveResourceGroupMember, rbac.ManageResources)).Methods(http.MethodDelete)
+	r.authedRouter.HandleFunc("/resource/group/resource", accessControl.Restrict(group.ResourceGroupResource, rbac.ManageResources)).Methods(http.MethodPost, http.MethodDelete)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type RoleHTTPHandler interface {
+	GetRoles(w http.ResponseWriter, req *http.Request)
+	MemberRoles(w http.ResponseWriter, req *http.Request)
+	ResetTwoFactor(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupRoleRoutes(role RoleHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/roles", accessControl.Restrict(role.GetRoles, rbac.ManageRoles)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/email/{email}/roles", accessControl.Restrict(role.MemberRoles, rbac.ManageRoles)).Methods(http.MethodGet, http.MethodPut)
+	r.authedRouter.HandleFunc("/member/email/{email}/2fa", accessControl.Restrict(role.ResetTwoFactor, rbac.ManageRoles)).Methods(http.MethodDelete)
+}
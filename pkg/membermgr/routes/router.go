@@ -3,9 +3,13 @@ package routes
 import (
 	_ "embed"
 	"net/http"
+	"time"
 
+	config "github.com/HackRVA/memberserver/configs"
 	api "github.com/HackRVA/memberserver/pkg/membermgr/controllers"
 	"github.com/HackRVA/memberserver/pkg/membermgr/controllers/auth"
+	v2 "github.com/HackRVA/memberserver/pkg/membermgr/controllers/v2"
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/ratelimit"
 	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
 	"github.com/HackRVA/memberserver/pkg/membermgr/ui"
 
@@ -18,6 +22,13 @@ type Router struct {
 	authedRouter   *mux.Router
 	api            api.API
 	authStrategy   union.Union
+	hosts          rbac.HostStore
+	// apiKeyRoutes are the only routes API keys can use
+	apiKeyRoutes map[*mux.Route]bool
+	rateLimits   ratelimit.Counter
+	clientIP     ratelimit.KeyFunc
+	// accountLimit is for the routes anyone can use to make accounts and send emails
+	accountLimit ratelimit.Limit
 }
 
 // setupMiddleWare must run before other routes, so, we give it a separate function
@@ -26,9 +37,11 @@ func setupMiddleware(authedRouter *mux.Router, userServer UserHTTPHandler, auth
 	return authedRouter
 }
 
-func New(api api.API, auth *auth.AuthController) Router {
+func New(api api.API, auth *auth.AuthController, hosts rbac.HostStore) Router {
 	unAuthedRouter := mux.NewRouter()
 	authedRouter := unAuthedRouter.PathPrefix("/api/").Subrouter()
+	// outermost, so errors from the middleware shared with v1 are problem details on v2 routes
+	authedRouter.Use(v2.Problems)
 	setupMiddleware(authedRouter, api.UserServer, auth)
 
 	router := Router{
@@ -36,7 +49,17 @@ func New(api api.API, auth *auth.AuthController) Router {
 		authedRouter:   authedRouter,
 		api:            api,
 		authStrategy:   auth.AuthStrategy,
+		hosts:          hosts,
+		apiKeyRoutes:   map[*mux.Route]bool{},
+		rateLimits:     auth.RateLimits,
+		clientIP:       auth.ClientIP,
+		accountLimit: ratelimit.Limit{
+			Name:     "accounts",
+			Requests: config.Get().RateLimitPerMinute,
+			Window:   time.Minute,
+		},
 	}
+	authedRouter.Use(router.restrictAPIKeys)
 
 	router.RegisterRoutes(auth)
 
@@ -48,13 +71,28 @@ func (r *Router) mountFS() {
 }
 
 func (r *Router) RegisterRoutes(auth *auth.AuthController) *mux.Router {
-	accessControl := rbac.New(r.authStrategy)
+	accessControl := rbac.New(r.authStrategy, r.hosts)
 	r.setupUserRoutes(r.api.UserServer, auth)
 	r.setupMemberRoutes(r.api.MemberServer, accessControl)
+	r.setupRoleRoutes(r.api.RoleServer, accessControl)
+	r.setupAPIKeyRoutes(r.api.APIKeyServer, accessControl)
+	r.setupSessionRoutes(r.api.SessionServer, accessControl)
+	r.setupLockoutRoutes(r.api.LockoutServer, accessControl)
 	r.setupResourceRoutes(r.api.ResourceServer, accessControl)
+	r.setupResourceGroupRoutes(r.api.ResourceServer, accessControl)
+	r.setupDeviceRoutes(r.api.ResourceServer, accessControl)
+	r.setupDoorModeRoutes(r.api.ResourceServer, accessControl)
+	r.setupOpenWindowRoutes(r.api.ResourceServer, accessControl)
+	r.setupResourceHostRoutes(r.api.ResourceServer, accessControl)
+	r.setupCertificationRoutes(r.api.CertificationServer, accessControl)
+	r.setupAuditRoutes(r.api.AuditServer, accessControl)
+	r.setupAccessEventRoutes(r.api.AccessEventServer, accessControl)
+	r.setupOccupancyRoutes(r.api.OccupancyServer)
 	r.setupPaymentRoutes(r.api, accessControl)
 	r.setupReportsRoutes(r.api.ReportsServer, accessControl)
 	r.setupVersionRoutes(r.api.VersionServer)
+	r.setupV2Routes(r.api.V2MemberServer, r.api.V2ResourceServer, accessControl)
+	r.setupOpenAPIRoutes()
 
 	r.mountFS()
 
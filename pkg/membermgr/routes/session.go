@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type SessionHTTPHandler interface {
+	GetSessions(w http.ResponseWriter, req *http.Request)
+	RevokeSessions(w http.ResponseWriter, req *http.Request)
+	RevokeSession(w http.ResponseWriter, req *http.Request)
+}
+
+func (r Router) setupSessionRoutes(session SessionHTTPHandler, accessControl rbac.AccessControl) {
+	r.authedRouter.HandleFunc("/member/email/{email}/sessions", accessControl.Restrict(session.GetSessions, rbac.ManageSessions)).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/member/email/{email}/sessions", accessControl.Restrict(session.RevokeSessions, rbac.ManageSessions)).Methods(http.MethodDelete)
+	r.authedRouter.HandleFunc("/member/email/{email}/sessions/{id}", accessControl.Restrict(session.RevokeSession, rbac.ManageSessions)).Methods(http.MethodDelete)
+}
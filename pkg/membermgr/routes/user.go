@@ -6,17 +6,47 @@ import (
 
 type UserHTTPHandler interface {
 	GetUser(w http.ResponseWriter, r *http.Request)
+	GetUserPermissions(w http.ResponseWriter, r *http.Request)
 }
 
 type AuthHTTPHandler interface {
 	RegisterUser(w http.ResponseWriter, r *http.Request)
 	Login(w http.ResponseWriter, r *http.Request)
 	Logout(w http.ResponseWriter, r *http.Request)
+	Refresh(w http.ResponseWriter, r *http.Request)
+	VerifyEmail(w http.ResponseWriter, r *http.Request)
+	ForgotPassword(w http.ResponseWriter, r *http.Request)
+	ResetPassword(w http.ResponseWriter, r *http.Request)
+	OIDCProvider(w http.ResponseWriter, r *http.Request)
+	OIDCLogin(w http.ResponseWriter, r *http.Request)
+	OIDCCallback(w http.ResponseWriter, r *http.Request)
+	TwoFactorStatus(w http.ResponseWriter, r *http.Request)
+	EnrollTwoFactor(w http.ResponseWriter, r *http.Request)
+	ApproveTwoFactor(w http.ResponseWriter, r *http.Request)
+	TwoFactorQRCode(w http.ResponseWriter, r *http.Request)
+	ConfirmTwoFactor(w http.ResponseWriter, r *http.Request)
+	RegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request)
+	DisableTwoFactor(w http.ResponseWriter, r *http.Request)
 }
 
 func (r Router) setupUserRoutes(userServer UserHTTPHandler, auth AuthHTTPHandler) {
 	r.authedRouter.HandleFunc("/user", userServer.GetUser)
+	r.authedRouter.HandleFunc("/user/permissions", userServer.GetUserPermissions).Methods(http.MethodGet)
 	r.authedRouter.HandleFunc("/auth/login", auth.Login).Methods(http.MethodPost)
 	r.authedRouter.HandleFunc("/auth/logout", auth.Logout).Methods(http.MethodDelete)
-	r.UnAuthedRouter.HandleFunc("/api/auth/register", auth.RegisterUser)
+	r.authedRouter.HandleFunc("/auth/2fa", auth.TwoFactorStatus).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/auth/2fa/enroll", auth.EnrollTwoFactor).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/auth/2fa/qr", auth.TwoFactorQRCode).Methods(http.MethodGet)
+	r.authedRouter.HandleFunc("/auth/2fa/confirm", auth.ConfirmTwoFactor).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/auth/2fa/recovery-codes", auth.RegenerateRecoveryCodes).Methods(http.MethodPost)
+	r.authedRouter.HandleFunc("/auth/2fa/disable", auth.DisableTwoFactor).Methods(http.MethodPost)
+	r.UnAuthedRouter.HandleFunc("/api/auth/refresh", auth.Refresh).Methods(http.MethodPost)
+	r.rateLimit(r.UnAuthedRouter.HandleFunc("/api/auth/register", auth.RegisterUser), r.accountLimit)
+	r.rateLimit(r.UnAuthedRouter.HandleFunc("/api/auth/verify", auth.VerifyEmail).Methods(http.MethodGet), r.accountLimit)
+	r.rateLimit(r.UnAuthedRouter.HandleFunc("/api/auth/2fa/approve", auth.ApproveTwoFactor).Methods(http.MethodGet), r.accountLimit)
+	r.rateLimit(r.UnAuthedRouter.HandleFunc("/api/auth/password/forgot", auth.ForgotPassword).Methods(http.MethodPost), r.accountLimit)
+	r.rateLimit(r.UnAuthedRouter.HandleFunc("/api/auth/password/reset", auth.ResetPassword).Methods(http.MethodPost), r.accountLimit)
+	r.UnAuthedRouter.HandleFunc("/api/auth/oidc", auth.OIDCProvider).Methods(http.MethodGet)
+	r.UnAuthedRouter.HandleFunc("/api/auth/oidc/login", auth.OIDCLogin).Methods(http.MethodGet)
+	r.UnAuthedRouter.HandleFunc("/api/auth/oidc/callback", auth.OIDCCallback).Methods(http.MethodGet)
 }
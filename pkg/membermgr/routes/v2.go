@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/middleware/rbac"
+)
+
+type V2MemberHTTPHandler interface {
+	GetMembers(w http.ResponseWriter, req *http.Request)
+	CreateMember(w http.ResponseWriter, req *http.Request)
+	GetMember(w http.ResponseWriter, req *http.Request)
+	UpdateMember(w http.ResponseWriter, req *http.Request)
+	AssignRFID(w http.ResponseWriter, req *http.Request)
+	GetSelf(w http.ResponseWriter, req *http.Request)
+	AssignSelfRFID(w http.ResponseWriter, req *http.Request)
+	GetTiers(w http.ResponseWriter, req *http.Request)
+}
+
+type V2ResourceHTTPHandler interface {
+	GetResources(w http.ResponseWriter, req *http.Request)
+	CreateResource(w http.ResponseWriter, req *http.Request)
+	GetResource(w http.ResponseWriter, req *http.Request)
+	UpdateResource(w http.ResponseWriter, req *http.Request)
+	DeleteResource(w http.ResponseWriter, req *http.Request)
+	PushACLs(w http.ResponseWriter, req *http.Request)
+	DeleteACLs(w http.ResponseWriter, req *http.Request)
+	GrantAccess(w http.ResponseWriter, req *http.Request)
+	RevokeAccess(w http.ResponseWriter, req *http.Request)
+}
+
+// setupV2Routes mounts /api/v2, it sits beside the v1 routes until the UI and scripts have moved over
+func (r Router) setupV2Routes(member V2MemberHTTPHandler, resource V2ResourceHTTPHandler, accessControl rbac.AccessControl) {
+	v2 := r.authedRouter.PathPrefix("/v2").Subrouter()
+
+	r.allowAPIKeys(v2.HandleFunc("/members", accessControl.Restrict(member.GetMembers, rbac.ViewMembers)).Methods(http.MethodGet))
+	v2.HandleFunc("/members", accessControl.Restrict(member.CreateMember, rbac.ManageMembers)).Methods(http.MethodPost)
+	r.allowAPIKeys(v2.HandleFunc("/members/{email}", accessControl.Restrict(member.GetMember, rbac.ViewMembers)).Methods(http.MethodGet))
+	v2.HandleFunc("/members/{email}", accessControl.Restrict(member.UpdateMember, rbac.ManageMembers)).Methods(http.MethodPut)
+	v2.HandleFunc("/members/{email}/rfid", accessControl.Restrict(member.AssignRFID, rbac.ManageMembers)).Methods(http.MethodPut)
+	v2.HandleFunc("/me", member.GetSelf).Methods(http.MethodGet)
+	v2.HandleFunc("/me/rfid", member.AssignSelfRFID).Methods(http.MethodPut)
+	v2.HandleFunc("/tiers", accessControl.Restrict(member.GetTiers, rbac.ViewMembers)).Methods(http.MethodGet)
+
+	v2.HandleFunc("/resources", accessControl.Restrict(resource.GetResources, rbac.ManageResources)).Methods(http.MethodGet)
+	v2.HandleFunc("/resources", accessControl.Restrict(resource.CreateResource, rbac.ManageResources)).Methods(http.MethodPost)
+	v2.HandleFunc("/resources/{id}", accessControl.Restrict(resource.GetResource, rbac.ManageResources)).Methods(http.MethodGet)
+	v2.HandleFunc("/resources/{id}", accessControl.Restrict(resource.UpdateResource, rbac.ManageResources)).Methods(http.MethodPut)
+	v2.HandleFunc("/resources/{id}", accessControl.Restrict(resource.DeleteResource, rbac.ManageResources)).Methods(http.MethodDelete)
+	v2.HandleFunc("/resources/{id}/members/{email}", accessControl.RestrictResource(resource.GrantAccess, rbac.GrantAccess, "id")).Methods(http.MethodPut)
+	v2.HandleFunc("/resources/{id}/members/{email}", accessControl.RestrictResource(resource.RevokeAccess, rbac.GrantAccess, "id")).Methods(http.MethodDelete)
+	v2.HandleFunc("/acls", accessControl.Restrict(resource.PushACLs, rbac.ManageResources)).Methods(http.MethodPut)
+	v2.HandleFunc("/acls", accessControl.Restrict(resource.DeleteACLs, rbac.ManageResources)).Methods(http.MethodDelete)
+}
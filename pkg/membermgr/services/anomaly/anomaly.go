@@ -0,0 +1,83 @@
+// Package anomaly watches access events for suspicious patterns and raises alerts.
+package anomaly
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+)
+
+// Detector runs every enabled rule over the access events
+type Detector struct {
+	rules      []Rule
+	notifier   notifier
+	mailer     mailer
+	adminEmail string
+	logger     logger
+
+	mu sync.Mutex
+}
+
+// New creates a detector with the rules that are enabled in the config
+func New(store store, notifier notifier, mailer mailer, logger logger, c config.Config) *Detector {
+	var rules []Rule
+
+	if c.AnomalyImpossibleTravel {
+		rules = append(rules, newImpossibleTravel(time.Duration(c.AnomalyImpossibleTravelSeconds)*time.Second))
+	}
+	if c.AnomalyInactiveMember {
+		rules = append(rules, &inactiveMember{store: store})
+	}
+	if c.AnomalyFirstNightAccess {
+		rules = append(rules, newFirstNightAccess(store, c.AnomalyNightStartHour, c.AnomalyNightEndHour))
+	}
+	if c.AnomalyDeniedBurst {
+		rules = append(rules, newDeniedBurst(c.AnomalyDeniedBurstCount, time.Duration(c.AnomalyDeniedBurstSeconds)*time.Second))
+	}
+
+	return &Detector{
+		rules:      rules,
+		notifier:   notifier,
+		mailer:     mailer,
+		adminEmail: c.AdminEmail,
+		logger:     logger,
+	}
+}
+
+// Run checks the events from the access event bus until the channel is closed
+func (d *Detector) Run(events <-chan models.AccessEvent) {
+	for e := range events {
+		for _, alert := range d.Check(e) {
+			d.raise(alert)
+		}
+	}
+}
+
+// Check runs an event through the rules and returns the alerts it triggered
+func (d *Detector) Check(e models.AccessEvent) []Alert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var alerts []Alert
+	for _, rule := range d.rules {
+		if alert, ok := rule.Check(e); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+func (d *Detector) raise(alert Alert) {
+	d.logger.Infof("access anomaly: %s: %s", alert.Rule, alert.Message)
+	d.notifier.Send(fmt.Sprintf("suspicious access (%s): %s", alert.Rule, alert.Message))
+
+	go func() {
+		if _, err := d.mailer.SendCommunication(mail.AccessAnomaly, d.adminEmail, alert); err != nil {
+			d.logger.Errorf("error sending access anomaly email: %s", err)
+		}
+	}()
+}
@@ -0,0 +1,176 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+
+	"github.com/sirupsen/logrus"
+)
+
+type stubNotifier struct {
+	sent []string
+}
+
+func (n *stubNotifier) Send(msg string) {
+	n.sent = append(n.sent, msg)
+}
+
+type stubMailer struct{}
+
+func (m stubMailer) SendCommunication(communication mail.CommunicationTemplate, recipient string, model interface{}) (bool, error) {
+	return true, nil
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		AdminEmail:                     "admin@test.com",
+		AnomalyImpossibleTravel:        true,
+		AnomalyImpossibleTravelSeconds: 30,
+		AnomalyInactiveMember:          true,
+		AnomalyFirstNightAccess:        true,
+		AnomalyNightStartHour:          22,
+		AnomalyNightEndHour:            6,
+		AnomalyDeniedBurst:             true,
+		AnomalyDeniedBurstCount:        3,
+		AnomalyDeniedBurstSeconds:      60,
+	}
+}
+
+func testStore() *in_memory.In_memory {
+	return &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"active@test.com":   {ID: "1", Name: "active", Email: "active@test.com", RFID: "111", Level: uint8(models.Standard)},
+			"inactive@test.com": {ID: "2", Name: "inactive", Email: "inactive@test.com", RFID: "222", Level: uint8(models.Inactive)},
+		},
+	}
+}
+
+func TestRules(t *testing.T) {
+	evening := time.Date(2023, time.March, 1, 18, 0, 0, 0, time.Local)
+	night := time.Date(2023, time.March, 1, 23, 0, 0, 0, time.Local)
+
+	active := func(door string, at time.Time) models.AccessEvent {
+		return models.AccessEvent{MemberID: "1", Username: "active", RFID: "111", Door: door, EventTime: at, IsKnown: true}
+	}
+	denied := func(door string, at time.Time) models.AccessEvent {
+		return models.AccessEvent{RFID: "999", Door: door, EventTime: at, Access: models.AccessDenied}
+	}
+
+	tests := []struct {
+		TestName      string
+		config        func(c *config.Config)
+		events        []models.AccessEvent
+		expectedRules []string
+	}{
+		{
+			TestName: "normal swipes don't alert",
+			events:   []models.AccessEvent{active("frontdoor", evening), active("woodshop", evening.Add(5*time.Minute))},
+		},
+		{
+			TestName:      "should flag a fob at two doors at once",
+			events:        []models.AccessEvent{active("frontdoor", evening), active("backdoor", evening.Add(10*time.Second))},
+			expectedRules: []string{ruleImpossibleTravel},
+		},
+		{
+			TestName: "should not flag impossible travel when it's disabled",
+			config:   func(c *config.Config) { c.AnomalyImpossibleTravel = false },
+			events:   []models.AccessEvent{active("frontdoor", evening), active("backdoor", evening.Add(10*time.Second))},
+		},
+		{
+			TestName:      "should flag an inactive member",
+			events:        []models.AccessEvent{{MemberID: "2", Username: "inactive", RFID: "222", Door: "frontdoor", EventTime: evening}},
+			expectedRules: []string{ruleInactiveMember},
+		},
+		{
+			TestName:      "should flag the first night access once",
+			events:        []models.AccessEvent{active("frontdoor", night), active("frontdoor", night.Add(time.Hour))},
+			expectedRules: []string{ruleFirstNightAccess},
+		},
+		{
+			TestName:      "should flag a burst of denied swipes once",
+			events:        []models.AccessEvent{denied("frontdoor", evening), denied("frontdoor", evening.Add(10*time.Second)), denied("frontdoor", evening.Add(20*time.Second)), denied("frontdoor", evening.Add(30*time.Second))},
+			expectedRules: []string{ruleDeniedBurst},
+		},
+		{
+			TestName: "should count members' denied swipes",
+			events: []models.AccessEvent{
+				{MemberID: "1", RFID: "111", Door: "woodshop", EventTime: evening, IsKnown: true, Access: models.AccessDenied},
+				{MemberID: "1", RFID: "111", Door: "woodshop", EventTime: evening.Add(10 * time.Second), IsKnown: true, Access: models.AccessDenied},
+				{MemberID: "1", RFID: "111", Door: "woodshop", EventTime: evening.Add(20 * time.Second), IsKnown: true, Access: models.AccessDenied},
+			},
+			expectedRules: []string{ruleDeniedBurst},
+		},
+		{
+			TestName: "should not count unknown fobs the device let in",
+			events: []models.AccessEvent{
+				{RFID: "999", Door: "frontdoor", EventTime: evening, Access: "Granted"},
+				{RFID: "999", Door: "frontdoor", EventTime: evening.Add(10 * time.Second), Access: "Granted"},
+				{RFID: "999", Door: "frontdoor", EventTime: evening.Add(20 * time.Second), Access: "Granted"},
+			},
+		},
+		{
+			TestName: "should not flag spread out denied swipes",
+			events:   []models.AccessEvent{denied("frontdoor", evening), denied("frontdoor", evening.Add(time.Minute)), denied("frontdoor", evening.Add(2*time.Minute))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			c := testConfig()
+			if tt.config != nil {
+				tt.config(&c)
+			}
+			detector := New(testStore(), &stubNotifier{}, stubMailer{}, logrus.New(), c)
+
+			var rules []string
+			for _, e := range tt.events {
+				for _, alert := range detector.Check(e) {
+					rules = append(rules, alert.Rule)
+				}
+			}
+
+			if len(rules) != len(tt.expectedRules) {
+				t.Fatalf("expected alerts %v, got %v", tt.expectedRules, rules)
+			}
+			for i := range rules {
+				if rules[i] != tt.expectedRules[i] {
+					t.Fatalf("expected alerts %v, got %v", tt.expectedRules, rules)
+				}
+			}
+		})
+	}
+}
+
+func TestFirstNightAccessChecksHistory(t *testing.T) {
+	store := testStore()
+	lastWeek := time.Date(2023, time.February, 22, 23, 30, 0, 0, time.Local)
+	store.LogAccessEvent(models.LogMessage{EventTime: lastWeek.Unix(), IsKnown: "true", RFID: "111", Door: "frontdoor", MemberID: "1"})
+
+	detector := New(store, &stubNotifier{}, stubMailer{}, logrus.New(), testConfig())
+
+	night := time.Date(2023, time.March, 1, 2, 0, 0, 0, time.Local)
+	alerts := detector.Check(models.AccessEvent{MemberID: "1", RFID: "111", Door: "frontdoor", EventTime: night, IsKnown: true})
+	if len(alerts) != 0 {
+		t.Errorf("member has been in at night before, got %v", alerts)
+	}
+}
+
+func TestRun(t *testing.T) {
+	notifier := &stubNotifier{}
+	detector := New(testStore(), notifier, stubMailer{}, logrus.New(), testConfig())
+
+	events := make(chan models.AccessEvent, 1)
+	events <- models.AccessEvent{MemberID: "2", Username: "inactive", RFID: "222", Door: "frontdoor", EventTime: time.Now()}
+	close(events)
+
+	detector.Run(events)
+
+	if len(notifier.sent) != 1 {
+		t.Errorf("expected an alert to be sent, got %v", notifier.sent)
+	}
+}
@@ -0,0 +1,26 @@
+package anomaly
+
+import (
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+)
+
+type logger interface {
+	Errorf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+}
+
+type notifier interface {
+	Send(msg string)
+}
+
+type mailer interface {
+	SendCommunication(communication mail.CommunicationTemplate, recipient string, model interface{}) (bool, error)
+}
+
+type store interface {
+	GetMemberByRFID(rfid string) (models.Member, error)
+	CountAccessBetweenHours(memberID string, startHour int, endHour int, before time.Time) (int, error)
+}
@@ -0,0 +1,181 @@
+package anomaly
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// Rule looks at each access event and decides if it's suspicious
+type Rule interface {
+	Check(e models.AccessEvent) (Alert, bool)
+}
+
+// Alert is a suspicious access event
+type Alert struct {
+	Rule    string
+	Message string
+}
+
+const (
+	ruleImpossibleTravel = "impossible travel"
+	ruleInactiveMember   = "inactive member"
+	ruleFirstNightAccess = "first night access"
+	ruleDeniedBurst      = "denied swipes"
+)
+
+type lastSwipe struct {
+	door string
+	at   time.Time
+}
+
+// impossibleTravel flags a fob that is used at two doors closer together in time than anyone could walk
+type impossibleTravel struct {
+	window time.Duration
+	last   map[string]lastSwipe
+}
+
+func newImpossibleTravel(window time.Duration) *impossibleTravel {
+	return &impossibleTravel{window: window, last: map[string]lastSwipe{}}
+}
+
+func (r *impossibleTravel) Check(e models.AccessEvent) (Alert, bool) {
+	if len(e.RFID) == 0 {
+		return Alert{}, false
+	}
+
+	previous, seen := r.last[e.RFID]
+	r.last[e.RFID] = lastSwipe{door: e.Door, at: e.EventTime}
+
+	if !seen || previous.door == e.Door {
+		return Alert{}, false
+	}
+
+	gap := e.EventTime.Sub(previous.at)
+	if gap < 0 {
+		gap = -gap
+	}
+
+	if gap >= r.window {
+		return Alert{}, false
+	}
+
+	return Alert{
+		Rule:    ruleImpossibleTravel,
+		Message: fmt.Sprintf("fob %s (%s) was used on %s and %s %s apart", e.RFID, displayName(e), previous.door, e.Door, gap),
+	}, true
+}
+
+// inactiveMember flags swipes by members whose membership has lapsed
+type inactiveMember struct {
+	store store
+}
+
+func (r *inactiveMember) Check(e models.AccessEvent) (Alert, bool) {
+	if len(e.MemberID) == 0 {
+		return Alert{}, false
+	}
+
+	m, err := r.store.GetMemberByRFID(e.RFID)
+	if err != nil || m.Level != uint8(models.Inactive) {
+		return Alert{}, false
+	}
+
+	return Alert{
+		Rule:    ruleInactiveMember,
+		Message: fmt.Sprintf("%s swiped on %s, but their membership is inactive", m.Name, e.Door),
+	}, true
+}
+
+// firstNightAccess flags the first time a member comes in during the night
+type firstNightAccess struct {
+	store     store
+	startHour int
+	endHour   int
+	// seen remembers members that have been in at night so we don't ask the database again
+	seen map[string]bool
+}
+
+func newFirstNightAccess(store store, startHour int, endHour int) *firstNightAccess {
+	return &firstNightAccess{store: store, startHour: startHour, endHour: endHour, seen: map[string]bool{}}
+}
+
+func (r *firstNightAccess) isNight(t time.Time) bool {
+	hour := t.Local().Hour()
+	if r.startHour > r.endHour {
+		return hour >= r.startHour || hour < r.endHour
+	}
+	return hour >= r.startHour && hour < r.endHour
+}
+
+func (r *firstNightAccess) Check(e models.AccessEvent) (Alert, bool) {
+	if !e.IsKnown || len(e.MemberID) == 0 || r.seen[e.MemberID] || !r.isNight(e.EventTime) {
+		return Alert{}, false
+	}
+
+	count, err := r.store.CountAccessBetweenHours(e.MemberID, r.startHour, r.endHour, e.EventTime)
+	if err != nil {
+		return Alert{}, false
+	}
+
+	r.seen[e.MemberID] = true
+	if count > 0 {
+		return Alert{}, false
+	}
+
+	return Alert{
+		Rule:    ruleFirstNightAccess,
+		Message: fmt.Sprintf("%s came in on %s at %s, the first time they've been in at night", displayName(e), e.Door, e.EventTime.Local().Format(time.Kitchen)),
+	}, true
+}
+
+// deniedBurst flags a door that denies a lot of swipes in a short time
+//
+//	members' fobs count too, e.g. when their schedule doesn't allow them in
+type deniedBurst struct {
+	count     int
+	window    time.Duration
+	denied    map[string][]time.Time
+	lastAlert map[string]time.Time
+}
+
+func newDeniedBurst(count int, window time.Duration) *deniedBurst {
+	return &deniedBurst{count: count, window: window, denied: map[string][]time.Time{}, lastAlert: map[string]time.Time{}}
+}
+
+func (r *deniedBurst) Check(e models.AccessEvent) (Alert, bool) {
+	if e.Access != models.AccessDenied {
+		return Alert{}, false
+	}
+
+	recent := []time.Time{e.EventTime}
+	for _, t := range r.denied[e.Door] {
+		if e.EventTime.Sub(t) < r.window {
+			recent = append(recent, t)
+		}
+	}
+	r.denied[e.Door] = recent
+
+	if len(recent) < r.count {
+		return Alert{}, false
+	}
+
+	// one alert per burst
+	if last, ok := r.lastAlert[e.Door]; ok && e.EventTime.Sub(last) < r.window {
+		return Alert{}, false
+	}
+	r.lastAlert[e.Door] = e.EventTime
+
+	return Alert{
+		Rule:    ruleDeniedBurst,
+		Message: fmt.Sprintf("%s denied %d swipes in %s", e.Door, len(recent), r.window),
+	}, true
+}
+
+func displayName(e models.AccessEvent) string {
+	if len(e.Username) > 0 {
+		return e.Username
+	}
+	return "unknown"
+}
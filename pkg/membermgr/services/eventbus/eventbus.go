@@ -0,0 +1,66 @@
+// Package eventbus fans access events out to everyone listening in process,
+// e.g. the live stream on the dashboard.
+package eventbus
+
+import (
+	"sync"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// subscriberBuffer is how many events a slow subscriber can fall behind before events are dropped for it
+const subscriberBuffer = 64
+
+// Bus is an in process publish/subscribe bus for access events
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan models.AccessEvent]struct{}
+}
+
+// New creates an empty bus
+func New() *Bus {
+	return &Bus{subscribers: map[chan models.AccessEvent]struct{}{}}
+}
+
+// Subscribe returns a channel that receives every event published from now on
+//
+//	call the returned func to unsubscribe, which also closes the channel
+func (b *Bus) Subscribe() (<-chan models.AccessEvent, func()) {
+	ch := make(chan models.AccessEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends an event to every subscriber
+//
+//	publishing never blocks; a subscriber that isn't keeping up misses the event
+func (b *Bus) Publish(e models.AccessEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribers is the number of current subscribers
+func (b *Bus) Subscribers() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subscribers)
+}
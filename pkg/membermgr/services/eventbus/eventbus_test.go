@@ -0,0 +1,44 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+func TestPublish(t *testing.T) {
+	bus := New()
+
+	first, unsubscribeFirst := bus.Subscribe()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	bus.Publish(models.AccessEvent{Username: "member", Door: "frontdoor"})
+
+	for _, ch := range []<-chan models.AccessEvent{first, second} {
+		e := <-ch
+		if e.Username != "member" || e.Door != "frontdoor" {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	}
+
+	unsubscribeFirst()
+	unsubscribeFirst()
+
+	if _, open := <-first; open {
+		t.Error("expected the channel to be closed after unsubscribing")
+	}
+
+	if bus.Subscribers() != 1 {
+		t.Errorf("expected 1 subscriber, got %d", bus.Subscribers())
+	}
+
+	// shouldn't block on a subscriber that isn't reading
+	for i := 0; i < subscriberBuffer*2; i++ {
+		bus.Publish(models.AccessEvent{})
+	}
+
+	if len(second) != subscriberBuffer {
+		t.Errorf("expected the subscriber's buffer to be full, got %d", len(second))
+	}
+}
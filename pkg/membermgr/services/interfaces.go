@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/eventbus"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
 	"github.com/HackRVA/memberserver/pkg/mqtt"
 	"github.com/sirupsen/logrus"
@@ -18,7 +19,10 @@ type (
 		GetMembersWithLimit(limit int, offset int, active bool) []models.Member
 		GetByEmail(email string) (models.Member, error)
 		Update(models.Member) error
+		UpdateAtVersion(member models.Member, version int) error
 		AssignRFID(email string, rfid string) (models.Member, error)
+		AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error)
+		GetPage(query models.MemberQuery) ([]models.Member, int, error)
 		GetTiers() []models.Tier
 		FindNonMembersOnSlack() []string
 		GetMemberFromSubscription(subscriptionID string) (models.Member, error)
@@ -34,21 +38,39 @@ type (
 		OnAccessEventHandler(payload models.LogMessage)
 		OnHeartBeatHandler(client go_mqtt.Client, msg go_mqtt.Message)
 		OnRemoveInvalidRequestHandler(client go_mqtt.Client, msg go_mqtt.Message)
+		DiscoveryHandler(client go_mqtt.Client, msg go_mqtt.Message)
 	}
 
 	Resource interface {
 		MQTTHandler
 		UpdateResourceACL(r models.Resource) error
 		UpdateResources()
+		PushResource(r models.Resource)
 		EnableValidUIDs()
 		RemovedInvalidUIDs()
 		RemoveMember(memberAccess models.MemberAccess)
 		Open(resource models.Resource)
+		HoldOpen(r models.Resource)
+		Release(r models.Resource)
 		RemoveOne(member models.Member)
 		PushOne(m models.Member)
+		AddGroupMembers(groupID string, emails []string) ([]models.MemberResourceRelation, error)
+		RemoveGroupMember(groupID string, email string) error
+		AddGroupResource(groupID string, resourceID string) error
+		RemoveGroupResource(groupID string, resourceID string) error
+		SetDoorMode(mode models.DoorMode) error
 		DeleteResourceACL()
 		CheckStatus(r models.Resource)
+		ApproveDevice(mac string, approval models.ApproveDeviceRequest) (models.Resource, error)
+		SubscribeResource(r models.Resource)
+		RegisterResource(name string, address string, isDefault bool, driver string) (models.Resource, error)
+		UpdateResource(res models.Resource) (*models.Resource, error)
+		UpdateResourceAtVersion(res models.Resource, version int) (*models.Resource, error)
+		DeleteResource(id string) error
+		DeleteResourceAtVersion(id string, version int) error
+		SubscribeDiscovery()
 		MQTT() mqtt.MQTTServer
+		AccessEvents() *eventbus.Bus
 	}
 
 	Logger interface {
@@ -78,6 +100,12 @@ type (
 		GetMemberChurn() (int, error)
 		GetMemberCountsChartByMonth(date time.Time) models.ReportChart
 		GetMemberCountsCharts(chartType string) ([]models.ReportChart, error)
+		GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error)
+	}
+
+	Occupancy interface {
+		Occupancy() models.Occupancy
+		WhosHere() ([]models.PresentMember, error)
 	}
 
 	Job interface {
@@ -90,6 +118,10 @@ type (
 		EnableValidUIDs()
 		UpdateResources()
 		UpdateMemberCounts()
+		RevokeExpiredCertifications()
+		ProcessTemporaryGrants()
+		ProcessOpenWindows()
+		ApplyAccessEventRetention()
 	}
 
 	Scheduler interface {
@@ -77,3 +77,53 @@ func TestWelcomeTemplate(t *testing.T) {
 		t.Fatalf("Failed to generate content.  Result is empty")
 	}
 }
+
+var accountEmailModel = struct {
+	Name      string
+	Link      string
+	ExpiresIn string
+}{
+	Name:      "Member Name",
+	Link:      "http://localhost:3000/api/auth/verify?token=abc",
+	ExpiresIn: "48 hours",
+}
+
+func TestVerifyEmailTemplate(t *testing.T) {
+	content, err := generator.generateEmailContent("../../../membermgr/templates/verify_email.html.tmpl", accountEmailModel)
+	if err != nil {
+		t.Fatalf("Failed to generate content. %v", err)
+	}
+	if len(content) == 0 {
+		t.Fatalf("Failed to generate content.  Result is empty")
+	}
+}
+
+func TestPasswordResetTemplate(t *testing.T) {
+	content, err := generator.generateEmailContent("../../../membermgr/templates/password_reset.html.tmpl", accountEmailModel)
+	if err != nil {
+		t.Fatalf("Failed to generate content. %v", err)
+	}
+	if len(content) == 0 {
+		t.Fatalf("Failed to generate content.  Result is empty")
+	}
+}
+
+func TestTwoFactorApprovalTemplate(t *testing.T) {
+	content, err := generator.generateEmailContent("../../../membermgr/templates/two_factor_approval.html.tmpl", accountEmailModel)
+	if err != nil {
+		t.Fatalf("Failed to generate content. %v", err)
+	}
+	if len(content) == 0 {
+		t.Fatalf("Failed to generate content.  Result is empty")
+	}
+}
+
+func TestAccountExistsTemplate(t *testing.T) {
+	content, err := generator.generateEmailContent("../../../membermgr/templates/account_exists.html.tmpl", accountEmailModel)
+	if err != nil {
+		t.Fatalf("Failed to generate content. %v", err)
+	}
+	if len(content) == 0 {
+		t.Fatalf("Failed to generate content.  Result is empty")
+	}
+}
@@ -15,11 +15,17 @@ import (
 type CommunicationTemplate string
 
 const (
+	AccessAnomaly               CommunicationTemplate = "AccessAnomaly"
 	AccessRevokedMember         CommunicationTemplate = "AccessRevokedMember"
 	AccessRevokedLeadership     CommunicationTemplate = "AccessRevokedLeadership"
+	AccountExists               CommunicationTemplate = "AccountExists"
+	CertificationExpired        CommunicationTemplate = "CertificationExpired"
 	IpChanged                   CommunicationTemplate = "IpChanged"
 	PendingRevokationLeadership CommunicationTemplate = "PendingRevokationLeadership"
 	PendingRevokationMember     CommunicationTemplate = "PendingRevokationMember"
+	PasswordReset               CommunicationTemplate = "PasswordReset"
+	TwoFactorApproval           CommunicationTemplate = "TwoFactorApproval"
+	VerifyEmail                 CommunicationTemplate = "VerifyEmail"
 	Welcome                     CommunicationTemplate = "Welcome"
 )
 
@@ -109,6 +115,35 @@ func (m *mailer) SendCommunication(communication CommunicationTemplate, recipien
 	return true, nil
 }
 
+// SendAccountEmail sends an email the recipient asked for, like a verification or password reset link
+//
+//	unlike SendCommunication it ignores the notification settings and throttling,
+//	the recipient is waiting for it
+func (m *mailer) SendAccountEmail(communication CommunicationTemplate, recipient string, model interface{}) error {
+	c, err := m.db.GetCommunication(communication.String())
+	if err != nil {
+		log.Printf("%v not found. Err: %v", communication.String(), err)
+		return err
+	}
+
+	content, err := m.generator.generateEmailContent("./templates/"+c.Template, model)
+	if err != nil {
+		log.Errorf("Error generating email content. Error: %v", err)
+		return err
+	}
+
+	if len(m.config.EmailOverrideAddress) > 0 {
+		recipient = m.config.EmailOverrideAddress
+	}
+
+	if _, err := m.m.SendHtmlMail(recipient, c.Subject, content); err != nil {
+		log.Printf("Failed to send mail to %v.  Err: %v", recipient, err)
+		return err
+	}
+
+	return nil
+}
+
 func (m *mailer) IsThrottled(c models.Communication, member models.Member) bool {
 
 	if c.FrequencyThrottle > 0 {
@@ -166,6 +166,27 @@ func TestEnableInfoEmailsUnsetShouldNotSendInfoEmails(t *testing.T) {
 	}
 }
 
+func TestAccountEmailsIgnoreNotificationSettings(t *testing.T) {
+	db := dbMock{}
+	m := mailApiMock{}
+	c, _ := config.Load()
+	c.EnableNotificationEmailsToMembers = false
+	c.EnableInfoEmails = false
+
+	mailer := NewMailer(&db, &m, c)
+	mailer.generator = generatorMock{}
+
+	if err := mailer.SendAccountEmail(VerifyEmail, "member@hackrva.org", memberModel); err != nil {
+		t.Errorf("Error sending account email %v", err)
+	}
+	if !m.MailSent {
+		t.Error("Account emails should be sent even when notifications are disabled")
+	}
+	if db.logCommunicationCalled {
+		t.Error("Account emails should not be logged")
+	}
+}
+
 type dbMock struct {
 	memberResult           models.Member
 	memberError            error
@@ -48,19 +48,33 @@ func (m memberService) GetByEmail(email string) (models.Member, error) {
 	return m.store.GetMemberByEmail(email)
 }
 
+func (m memberService) GetPage(query models.MemberQuery) ([]models.Member, int, error) {
+	return m.store.GetMemberPage(query)
+}
+
 func (m memberService) Update(member models.Member) error {
+	return m.UpdateAtVersion(member, datastore.AnyVersion)
+}
+
+// UpdateAtVersion updates the member unless it has changed since it was at version
+func (m memberService) UpdateAtVersion(member models.Member, version int) error {
 	defer m.CheckStatus(member.SubscriptionID)
-	return m.store.UpdateMember(member)
+	return m.store.UpdateMemberAtVersion(member, version)
 }
 
 func (m memberService) AssignRFID(email string, rfid string) (models.Member, error) {
+	return m.AssignRFIDAtVersion(email, rfid, datastore.AnyVersion)
+}
+
+// AssignRFIDAtVersion gives the member a new fob unless it has changed since it was at version
+func (m memberService) AssignRFIDAtVersion(email string, rfid string, version int) (models.Member, error) {
 	if len(rfid) == 0 {
 		return models.Member{}, errors.New("not a valid rfid")
 	}
 
 	// we need to push to resources after we add rfid to DB
 	defer m.resourceManager.PushOne(models.Member{Email: email})
-	return m.store.AssignRFID(email, rfid)
+	return m.store.AssignRFIDAtVersion(email, rfid, version)
 }
 
 func (ms memberService) GetMemberBySubscriptionID(subscriptionID string) (models.Member, error) {
@@ -0,0 +1,15 @@
+package occupancy
+
+type logger interface {
+	Errorf(format string, args ...interface{})
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+}
+
+type notifier interface {
+	Send(msg string)
+}
+
+type publisher interface {
+	Publish(address string, topic string, payload interface{})
+}
@@ -0,0 +1,289 @@
+// Package occupancy works out who is in the space from their swipes.
+//
+// A swipe on an entry reader puts a member in the space until they swipe
+// an exit reader or they haven't swiped anywhere for the dwell timeout.
+package occupancy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// expireInterval - how often members that haven't swiped in a while are dropped
+const expireInterval = time.Minute
+
+// keyholderRefreshInterval - how often the keyholders are read again, so new and expired certifications are picked up
+const keyholderRefreshInterval = 5 * time.Minute
+
+type presence struct {
+	name      string
+	door      string
+	since     time.Time
+	lastSeen  time.Time
+	keyholder bool
+}
+
+// Tracker keeps track of the members in the space
+type Tracker struct {
+	store    datastore.OccupancyStore
+	mqtt     publisher
+	notifier notifier
+	logger   logger
+
+	dwell                  time.Duration
+	exitReaders            map[string]bool
+	keyholderCertification string
+	brokerAddress          string
+	topic                  string
+	now                    func() time.Time
+
+	mu        sync.Mutex
+	present   map[string]*presence
+	updatedAt time.Time
+
+	// keyholders is cached so swipes don't wait on the database
+	keyholderMu sync.Mutex
+	keyholders  map[string]bool
+}
+
+// New creates a tracker with nobody in the space
+func New(store datastore.OccupancyStore, mqtt publisher, notifier notifier, logger logger, c config.Config) *Tracker {
+	exitReaders := map[string]bool{}
+	for _, name := range strings.Split(c.OccupancyExitReaders, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			exitReaders[name] = true
+		}
+	}
+
+	return &Tracker{
+		store:                  store,
+		mqtt:                   mqtt,
+		notifier:               notifier,
+		logger:                 logger,
+		dwell:                  time.Duration(c.OccupancyDwellMinutes) * time.Minute,
+		exitReaders:            exitReaders,
+		keyholderCertification: c.KeyholderCertification,
+		brokerAddress:          c.MQTTBrokerAddress,
+		topic:                  c.OccupancyTopic,
+		now:                    time.Now,
+		present:                map[string]*presence{},
+	}
+}
+
+// Run tracks the events from the access event bus until the channel is closed
+func (t *Tracker) Run(events <-chan models.AccessEvent) {
+	ticker := time.NewTicker(expireInterval)
+	defer ticker.Stop()
+	keyholderTicker := time.NewTicker(keyholderRefreshInterval)
+	defer keyholderTicker.Stop()
+
+	t.RefreshKeyholders()
+
+	for {
+		select {
+		case e, open := <-events:
+			if !open {
+				return
+			}
+			t.Record(e)
+		case <-ticker.C:
+			t.Expire()
+		case <-keyholderTicker.C:
+			t.RefreshKeyholders()
+		}
+	}
+}
+
+// RefreshKeyholders reads the members with the keyholder certification again
+//
+//	if that fails the last list is kept
+func (t *Tracker) RefreshKeyholders() {
+	if len(t.keyholderCertification) == 0 {
+		return
+	}
+
+	ids, err := t.store.GetCertifiedMemberIDs(t.keyholderCertification)
+	if err != nil {
+		t.logger.Errorf("error getting keyholders: %s", err)
+		return
+	}
+
+	keyholders := map[string]bool{}
+	for _, id := range ids {
+		keyholders[id] = true
+	}
+
+	t.keyholderMu.Lock()
+	t.keyholders = keyholders
+	t.keyholderMu.Unlock()
+}
+
+// Record updates who is in the space with a swipe
+//
+//	denied swipes and fobs that don't belong to a member don't count
+func (t *Tracker) Record(e models.AccessEvent) {
+	if !e.IsKnown || len(e.MemberID) == 0 {
+		return
+	}
+
+	exit := t.exitReaders[e.Door]
+	keyholder := false
+	if !exit {
+		keyholder = t.isKeyholder(e.MemberID)
+	}
+
+	t.mu.Lock()
+	now := t.now()
+	before := t.occupancyLocked()
+	var left []presence
+
+	if exit {
+		if p, ok := t.present[e.MemberID]; ok {
+			left = append(left, *p)
+			delete(t.present, e.MemberID)
+		}
+	} else if p, ok := t.present[e.MemberID]; ok {
+		p.lastSeen = now
+		p.door = e.Door
+		p.keyholder = keyholder
+	} else {
+		t.present[e.MemberID] = &presence{name: e.Username, door: e.Door, since: now, lastSeen: now, keyholder: keyholder}
+	}
+
+	left = append(left, t.expireLocked(now)...)
+	after := t.changedLocked(before, now)
+	t.mu.Unlock()
+
+	t.announce(before, after, left)
+}
+
+// Expire drops the members that haven't swiped anywhere for the dwell timeout
+func (t *Tracker) Expire() {
+	t.mu.Lock()
+	now := t.now()
+	before := t.occupancyLocked()
+	left := t.expireLocked(now)
+	after := t.changedLocked(before, now)
+	t.mu.Unlock()
+
+	t.announce(before, after, left)
+}
+
+// Occupancy is how many members are in the space right now
+func (t *Tracker) Occupancy() models.Occupancy {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.occupancyLocked()
+}
+
+// WhosHere lists the members in the space that share their presence
+func (t *Tracker) WhosHere() ([]models.PresentMember, error) {
+	sharing, err := t.store.GetPresenceSharing()
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	members := []models.PresentMember{}
+	for _, id := range sharing {
+		p, ok := t.present[id]
+		if !ok {
+			continue
+		}
+		members = append(members, models.PresentMember{Name: p.name, Door: p.door, Since: p.since, Keyholder: p.keyholder})
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Since.Before(members[j].Since)
+	})
+
+	return members, nil
+}
+
+func (t *Tracker) isKeyholder(memberID string) bool {
+	if len(t.keyholderCertification) == 0 {
+		return false
+	}
+
+	t.keyholderMu.Lock()
+	loaded := t.keyholders != nil
+	t.keyholderMu.Unlock()
+	if !loaded {
+		t.RefreshKeyholders()
+	}
+
+	t.keyholderMu.Lock()
+	defer t.keyholderMu.Unlock()
+	return t.keyholders[memberID]
+}
+
+// expireLocked drops stale members and returns the ones that left
+func (t *Tracker) expireLocked(now time.Time) []presence {
+	var left []presence
+	for id, p := range t.present {
+		if now.Sub(p.lastSeen) >= t.dwell {
+			left = append(left, *p)
+			delete(t.present, id)
+		}
+	}
+	return left
+}
+
+func (t *Tracker) occupancyLocked() models.Occupancy {
+	o := models.Occupancy{Count: len(t.present), UpdatedAt: t.updatedAt}
+	for _, p := range t.present {
+		if p.keyholder {
+			o.Keyholders++
+		}
+	}
+	return o
+}
+
+// changedLocked bumps UpdatedAt when the occupancy changed
+func (t *Tracker) changedLocked(before models.Occupancy, now time.Time) models.Occupancy {
+	after := t.occupancyLocked()
+	if after.Count != before.Count || after.Keyholders != before.Keyholders {
+		t.updatedAt = now
+		after.UpdatedAt = now
+	}
+	return after
+}
+
+// announce publishes a change in occupancy to mqtt and raises a flag when the last keyholder leaves
+func (t *Tracker) announce(before models.Occupancy, after models.Occupancy, left []presence) {
+	if after.Count == before.Count && after.Keyholders == before.Keyholders {
+		return
+	}
+
+	if before.Keyholders > 0 && after.Keyholders == 0 {
+		var keyholders []string
+		for _, p := range left {
+			if p.keyholder {
+				keyholders = append(keyholders, p.name)
+			}
+		}
+		t.notifier.Send(fmt.Sprintf("the last keyholder has left (%s), %d members are still in the space", strings.Join(keyholders, ", "), after.Count))
+	}
+
+	if len(t.topic) == 0 {
+		return
+	}
+
+	j, err := json.Marshal(after)
+	if err != nil {
+		t.logger.Errorf("error marshalling occupancy: %s", err)
+		return
+	}
+
+	t.mqtt.Publish(t.brokerAddress, t.topic, string(j))
+}
@@ -0,0 +1,168 @@
+package occupancy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+type stubNotifier struct {
+	sent []string
+}
+
+func (n *stubNotifier) Send(msg string) {
+	n.sent = append(n.sent, msg)
+}
+
+type stubPublisher struct {
+	published []models.Occupancy
+}
+
+func (p *stubPublisher) Publish(address string, topic string, payload interface{}) {
+	var o models.Occupancy
+	json.Unmarshal([]byte(payload.(string)), &o)
+	p.published = append(p.published, o)
+}
+
+func setupTracker() (*Tracker, *stubNotifier, *stubPublisher, *time.Time) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"keyholder@test.com": {ID: "1", Name: "keyholder", Email: "keyholder@test.com"},
+			"member@test.com":    {ID: "2", Name: "member", Email: "member@test.com"},
+		},
+		MemberCertifications: []models.MemberCertification{
+			{MemberID: "1", CertificationName: "Keyholder"},
+		},
+	}
+	store.SetPresenceSharing("member@test.com", true)
+
+	notifier := &stubNotifier{}
+	publisher := &stubPublisher{}
+	tracker := New(store, publisher, notifier, logrus.New(), config.Config{
+		OccupancyDwellMinutes:  60,
+		OccupancyExitReaders:   "exit, backdoor-exit",
+		OccupancyTopic:         "occupancy",
+		KeyholderCertification: "Keyholder",
+	})
+
+	now := time.Date(2023, time.March, 1, 18, 0, 0, 0, time.UTC)
+	tracker.now = func() time.Time { return now }
+
+	return tracker, notifier, publisher, &now
+}
+
+func swipe(memberID string, name string, door string) models.AccessEvent {
+	return models.AccessEvent{MemberID: memberID, Username: name, Door: door, IsKnown: true}
+}
+
+func TestOccupancy(t *testing.T) {
+	tracker, notifier, publisher, now := setupTracker()
+
+	tracker.Record(swipe("1", "keyholder", "frontdoor"))
+	tracker.Record(swipe("2", "member", "frontdoor"))
+	tracker.Record(swipe("2", "member", "woodshop"))
+	tracker.Record(models.AccessEvent{RFID: "999", Door: "frontdoor"})
+	tracker.Record(models.AccessEvent{MemberID: "3", Door: "frontdoor", IsKnown: false})
+
+	o := tracker.Occupancy()
+	if o.Count != 2 || o.Keyholders != 1 {
+		t.Fatalf("expected 2 members and 1 keyholder, got %+v", o)
+	}
+
+	if len(publisher.published) != 2 {
+		t.Errorf("expected a publish for each change, got %d", len(publisher.published))
+	}
+
+	here, _ := tracker.WhosHere()
+	if len(here) != 1 || here[0].Name != "member" || here[0].Door != "woodshop" {
+		t.Errorf("expected only the member that shares their presence, got %+v", here)
+	}
+
+	tracker.Record(swipe("1", "keyholder", "exit"))
+
+	if tracker.Occupancy().Count != 1 {
+		t.Errorf("expected the exit reader to remove the keyholder, got %+v", tracker.Occupancy())
+	}
+
+	if len(notifier.sent) != 1 || !strings.Contains(notifier.sent[0], "keyholder") {
+		t.Errorf("expected a notification that the last keyholder left, got %v", notifier.sent)
+	}
+
+	*now = now.Add(61 * time.Minute)
+	tracker.Expire()
+
+	if tracker.Occupancy().Count != 0 {
+		t.Errorf("expected the member to time out, got %+v", tracker.Occupancy())
+	}
+
+	last := publisher.published[len(publisher.published)-1]
+	if last.Count != 0 || !last.UpdatedAt.Equal(*now) {
+		t.Errorf("expected the empty space to be published, got %+v", last)
+	}
+}
+
+func TestKeyholderTimesOut(t *testing.T) {
+	tracker, notifier, _, now := setupTracker()
+
+	tracker.Record(swipe("1", "keyholder", "frontdoor"))
+	*now = now.Add(30 * time.Minute)
+	tracker.Record(swipe("2", "member", "frontdoor"))
+
+	*now = now.Add(31 * time.Minute)
+	tracker.Expire()
+
+	o := tracker.Occupancy()
+	if o.Count != 1 || o.Keyholders != 0 {
+		t.Fatalf("expected only the member to be left, got %+v", o)
+	}
+
+	if len(notifier.sent) != 1 || !strings.Contains(notifier.sent[0], "1 members are still in the space") {
+		t.Errorf("expected a notification that the last keyholder left, got %v", notifier.sent)
+	}
+}
+
+// countingStore counts how often the keyholders are read
+type countingStore struct {
+	*in_memory.In_memory
+	keyholderReads int
+}
+
+func (s *countingStore) GetCertifiedMemberIDs(certificationName string) ([]string, error) {
+	s.keyholderReads++
+	return s.In_memory.GetCertifiedMemberIDs(certificationName)
+}
+
+func TestKeyholdersAreCached(t *testing.T) {
+	tracker, _, _, _ := setupTracker()
+	store := &countingStore{In_memory: tracker.store.(*in_memory.In_memory)}
+	tracker.store = store
+
+	tracker.Record(swipe("1", "keyholder", "frontdoor"))
+	tracker.Record(swipe("2", "member", "frontdoor"))
+	tracker.Record(swipe("1", "keyholder", "woodshop"))
+
+	if store.keyholderReads != 1 {
+		t.Errorf("expected the keyholders to be read once, got %d", store.keyholderReads)
+	}
+
+	if tracker.Occupancy().Keyholders != 1 {
+		t.Fatalf("expected 1 keyholder, got %+v", tracker.Occupancy())
+	}
+
+	// training recorded since the last refresh is picked up by the next one
+	store.MemberCertifications = append(store.MemberCertifications, models.MemberCertification{MemberID: "2", CertificationName: "Keyholder"})
+	tracker.RefreshKeyholders()
+	tracker.Record(swipe("2", "member", "exit"))
+	tracker.Record(swipe("2", "member", "frontdoor"))
+
+	if store.keyholderReads != 2 || tracker.Occupancy().Keyholders != 2 {
+		t.Errorf("expected the refresh to find the new keyholder, got %d reads and %+v", store.keyholderReads, tracker.Occupancy())
+	}
+}
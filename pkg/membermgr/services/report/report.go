@@ -16,6 +16,7 @@ type ReportService interface {
 	GetMemberChurn() (int, error)
 	GetMemberCountsCharts(chartType string) ([]models.ReportChart, error)
 	GetMemberCountsChartByMonth(date time.Time) models.ReportChart
+	GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error)
 }
 
 type Report struct {
@@ -39,6 +40,11 @@ func (r Report) GetMemberChurn() (int, error) {
 	return r.Store.GetMemberChurn()
 }
 
+// GetUnknownFobs lists the fobs with the most unknown swipes, to match them up with lost fobs
+func (r Report) GetUnknownFobs(since time.Time, limit int) ([]models.UnknownFob, error) {
+	return r.Store.GetUnknownFobs(since, limit)
+}
+
 func (r Report) GetMemberCountsChartByMonth(date time.Time) models.ReportChart {
 	return makeDistritutionChartByMonth(date, r.Store)
 }
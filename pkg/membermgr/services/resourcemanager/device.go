@@ -0,0 +1,139 @@
+package resourcemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// DiscoveryHandler is the mqtt messageHandler for devices announcing themselves on the discovery topic
+//
+//	we expect the payload to be json that marshals to `DeviceAnnouncement`
+func (rm *ResourceManager) DiscoveryHandler(client mqtt.Client, msg mqtt.Message) {
+	var announcement models.DeviceAnnouncement
+
+	if err := json.Unmarshal(msg.Payload(), &announcement); err != nil {
+		rm.logger.Errorf("error unmarshalling discovery payload: %s", err)
+		return
+	}
+
+	rm.OnDeviceAnnouncement(announcement)
+}
+
+// OnDeviceAnnouncement records a device that announced itself
+//
+//	new devices wait for an admin to approve them, devices that were already approved
+//	are told their name again in case they forgot it
+func (rm *ResourceManager) OnDeviceAnnouncement(announcement models.DeviceAnnouncement) {
+	mac, err := models.NormalizeMAC(announcement.MAC)
+	if err != nil {
+		rm.logger.Errorf("device announced itself with an invalid mac address %q", announcement.MAC)
+		return
+	}
+	announcement.MAC = mac
+
+	d, err := rm.AnnounceDevice(announcement)
+	if err != nil {
+		rm.logger.Errorf("error recording device %s: %s", mac, err)
+		return
+	}
+
+	if len(d.ResourceID) > 0 {
+		r, err := rm.GetResourceByID(d.ResourceID)
+		if err != nil {
+			rm.logger.Errorf("error getting the resource for device %s: %s", mac, err)
+			return
+		}
+		rm.provision(d, r)
+		return
+	}
+
+	rm.logger.Infof("device %s (%s %s) is waiting for approval", mac, d.Model, d.Firmware)
+
+	if d.FirstSeen.Equal(d.LastSeen) {
+		rm.notifier.Send(fmt.Sprintf("a new %s device (%s) at %s is waiting to be approved", d.Model, mac, d.Address))
+	}
+}
+
+// ApproveDevice turns a pending device into a resource
+//
+//	the device is told its name, we subscribe to its topics and push its access list
+func (rm *ResourceManager) ApproveDevice(mac string, approval models.ApproveDeviceRequest) (models.Resource, error) {
+	mac, err := models.NormalizeMAC(mac)
+	if err != nil {
+		return models.Resource{}, err
+	}
+
+	name := strings.TrimSpace(approval.Name)
+	if len(name) == 0 {
+		return models.Resource{}, errors.New("name is required")
+	}
+
+	d, err := rm.GetDevice(mac)
+	if err != nil {
+		return models.Resource{}, err
+	}
+
+	if len(d.ResourceID) > 0 {
+		return models.Resource{}, fmt.Errorf("device %s has already been approved", mac)
+	}
+
+	if _, err := rm.GetResourceByName(name); err == nil {
+		return models.Resource{}, fmt.Errorf("a resource named %s already exists", name)
+	}
+
+	// the resource and the device's approval are written together, so a failure doesn't leave a resource without its device
+	r, err := rm.DataStore.ApproveDevice(mac, name, approval.IsDefault)
+	if err != nil {
+		return r, err
+	}
+
+	rm.SubscribeResource(r)
+	rm.provision(d, r)
+
+	if err := rm.UpdateResourceACL(r); err != nil {
+		rm.logger.Errorf("error pushing the access list to %s: %s", r.Name, err)
+	}
+
+	return r, nil
+}
+
+// SubscribeDiscovery listens for devices announcing themselves
+func (rm ResourceManager) SubscribeDiscovery() {
+	c := config.Get()
+	rm.mqtt.Subscribe(c.MQTTBrokerAddress, c.MQTTDiscoveryTopic, rm.DiscoveryHandler)
+}
+
+// reprovision tells a discovered device its resource's current name
+func (rm ResourceManager) reprovision(r models.Resource) {
+	d, err := rm.GetResourceDevice(r.ID)
+	if err != nil {
+		rm.logger.Errorf("error looking up the device for %s: %s", r.Name, err)
+		return
+	}
+
+	if len(d.MAC) == 0 {
+		return
+	}
+
+	rm.provision(d, r)
+}
+
+// provision tells a device the name it should use as its topic prefix
+func (rm ResourceManager) provision(d models.Device, r models.Resource) {
+	c := config.Get()
+
+	b, err := json.Marshal(models.DeviceProvisioning{Name: r.Name})
+	if err != nil {
+		rm.logger.Error(err)
+		return
+	}
+
+	rm.mqtt.Publish(c.MQTTBrokerAddress, c.MQTTProvisionTopic+"/"+d.MAC, string(b))
+}
@@ -0,0 +1,136 @@
+package resourcemanager_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+type recordingMQTTServer struct {
+	published    []string
+	subscribed   []string
+	unsubscribed []string
+}
+
+func (m *recordingMQTTServer) Publish(address string, topic string, payload interface{}) {
+	m.published = append(m.published, topic)
+}
+
+func (m *recordingMQTTServer) Subscribe(address string, topic string, handler mqtt.MessageHandler) {
+	m.subscribed = append(m.subscribed, topic)
+}
+
+func (m *recordingMQTTServer) Unsubscribe(address string, topics ...string) {
+	m.unsubscribed = append(m.unsubscribed, topics...)
+}
+
+type recordingNotifier struct {
+	sent []string
+}
+
+func (n *recordingNotifier) Send(msg string) {
+	n.sent = append(n.sent, msg)
+}
+
+func TestDeviceDiscovery(t *testing.T) {
+	store := &in_memory.In_memory{}
+	notifier := &recordingNotifier{}
+	ms := &recordingMQTTServer{}
+	rm := resourcemanager.New(ms, store, notifier, logrus.New())
+
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "not a mac"})
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "AA:BB:CC:DD:EE:01", Model: "esp-rfid", Firmware: "1.0"})
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "aa-bb-cc-dd-ee-01", Model: "esp-rfid", Firmware: "1.1"})
+
+	pending, _ := store.GetPendingDevices()
+	if len(pending) != 1 || pending[0].MAC != "aa:bb:cc:dd:ee:01" || pending[0].Firmware != "1.1" {
+		t.Fatalf("expected one pending device, got %+v", pending)
+	}
+
+	if len(notifier.sent) != 1 {
+		t.Errorf("expected admins to hear about a new device once, got %v", notifier.sent)
+	}
+
+	if len(ms.published) != 0 {
+		t.Errorf("pending devices shouldn't be provisioned, got %v", ms.published)
+	}
+}
+
+func TestApproveDeviceProvisionsIt(t *testing.T) {
+	store := &in_memory.In_memory{}
+	ms := &recordingMQTTServer{}
+	rm := resourcemanager.New(ms, store, &recordingNotifier{}, logrus.New())
+	t.Cleanup(func() { delete(in_memory.Resources, "woodshop") })
+
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:01", Address: "10.0.0.5"})
+
+	r, err := rm.ApproveDevice("aa:bb:cc:dd:ee:01", models.ApproveDeviceRequest{Name: "woodshop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Name != "woodshop" || r.Address != "10.0.0.5" {
+		t.Errorf("unexpected resource: %+v", r)
+	}
+
+	expected := []string{"memberserver/provision/aa:bb:cc:dd:ee:01", "woodshop/update"}
+	if strings.Join(ms.published, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v to be published, got %v", expected, ms.published)
+	}
+
+	if len(ms.subscribed) != 4 || !strings.HasPrefix(ms.subscribed[0], "woodshop/") {
+		t.Errorf("expected the resource's topics to be subscribed, got %v", ms.subscribed)
+	}
+
+	if _, err := rm.ApproveDevice("aa:bb:cc:dd:ee:01", models.ApproveDeviceRequest{Name: "woodshop"}); err == nil {
+		t.Error("expected approving a device twice to fail")
+	}
+
+	// an approved device that announces itself again is reminded of its name
+	ms.published = nil
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:01", Address: "10.0.0.5"})
+	if len(ms.published) != 1 || ms.published[0] != "memberserver/provision/aa:bb:cc:dd:ee:01" {
+		t.Errorf("expected the device to be provisioned again, got %v", ms.published)
+	}
+}
+
+func TestRenamedDeviceIsProvisionedAgain(t *testing.T) {
+	store := &in_memory.In_memory{}
+	ms := &recordingMQTTServer{}
+	rm := resourcemanager.New(ms, store, &recordingNotifier{}, logrus.New())
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "woodshop")
+		delete(in_memory.Resources, "wood shop")
+	})
+
+	rm.OnDeviceAnnouncement(models.DeviceAnnouncement{MAC: "aa:bb:cc:dd:ee:01", Address: "10.0.0.5"})
+
+	r, err := rm.ApproveDevice("aa:bb:cc:dd:ee:01", models.ApproveDeviceRequest{Name: "woodshop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// an update that keeps the name doesn't provision the device again
+	ms.published = nil
+	r.Address = "10.0.0.6"
+	if _, err := rm.UpdateResource(r); err != nil {
+		t.Fatal(err)
+	}
+	if len(ms.published) != 0 {
+		t.Errorf("expected nothing to be published, got %v", ms.published)
+	}
+
+	r.Name = "wood shop"
+	if _, err := rm.UpdateResource(r); err != nil {
+		t.Fatal(err)
+	}
+	if len(ms.published) != 1 || ms.published[0] != "memberserver/provision/aa:bb:cc:dd:ee:01" {
+		t.Errorf("expected the device to be told its new name, got %v", ms.published)
+	}
+}
@@ -0,0 +1,136 @@
+package resourcemanager
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// reconnectThreshold - a heartbeat after this long without one means the device has been offline
+const reconnectThreshold = 10 * time.Minute
+
+// SetDoorMode persists a lockdown or hold-open and applies it to the affected devices
+//
+//	an empty ResourceID applies the mode to every resource
+func (rm ResourceManager) SetDoorMode(mode models.DoorMode) error {
+	if !mode.Mode.Valid() {
+		return errors.New("invalid door mode")
+	}
+
+	resources := rm.GetResources()
+	if len(mode.ResourceID) > 0 {
+		r, err := rm.GetResourceByID(mode.ResourceID)
+		if err != nil {
+			return err
+		}
+		resources = []models.Resource{r}
+	}
+
+	if err := rm.DataStore.SetDoorMode(mode); err != nil {
+		return err
+	}
+
+	go func() {
+		for _, r := range resources {
+			rm.applyDoorMode(r)
+		}
+	}()
+
+	return nil
+}
+
+// doorMode returns the mode in effect for a resource
+func (rm ResourceManager) doorMode(resourceID string) models.DoorModeName {
+	modes, err := rm.GetDoorModes()
+	if err != nil {
+		rm.logger.Errorf("error getting door modes: %s", err)
+		return models.DoorModeNormal
+	}
+
+	return models.EffectiveDoorMode(modes, resourceID)
+}
+
+// applyDoorMode sends the commands that put a device into the mode in effect for it
+func (rm ResourceManager) applyDoorMode(r models.Resource) {
+	mode := rm.doorMode(r.ID)
+	rm.logger.Infof("applying door mode %s to %s", mode, r.Name)
+
+	driver := rm.driver(r)
+
+	var err error
+	switch mode {
+	case models.DoorModeLockdown:
+		err = driver.RemoveAllUsers(r)
+		if err == nil {
+			err = rm.pushAdmins(r)
+		}
+	case models.DoorModeHoldOpen:
+		err = driver.HoldOpen(r)
+	default:
+		err = driver.Lock(r)
+		// admins that were added for a lockdown shouldn't keep access to doors they can't normally open
+		if clearErr := driver.RemoveAllUsers(r); err == nil {
+			err = clearErr
+		}
+		rm.pushResourceACL(r)
+	}
+
+	if err != nil {
+		rm.logger.Errorf("error applying door mode %s to %s: %s", mode, r.Name, err)
+		rm.notifier.Send(fmt.Sprintf("%s may not be in %s mode: %s", r.Name, mode, err))
+		return
+	}
+
+	rm.logger.Infof("applied door mode %s to %s", mode, r.Name)
+}
+
+// pushAdmins adds every admin to a device, whether or not they normally have access to it
+func (rm ResourceManager) pushAdmins(r models.Resource) error {
+	admins, err := rm.GetMembersWithRole(models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("error getting admins, nobody will have access to %s during the lockdown: %w", r.Name, err)
+	}
+
+	driver := rm.driver(r)
+	var failed []string
+	for _, m := range admins {
+		if len(m.RFID) == 0 || m.Level == uint8(models.Inactive) {
+			continue
+		}
+		if err := driver.AddUser(r, rm.deviceUser(r, m.Name, m.RFID, m.Level, nil)); err != nil {
+			rm.logger.Errorf("error adding %s to %s: %s", m.Name, r.Name, err)
+			failed = append(failed, m.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("error adding %s to %s", strings.Join(failed, ", "), r.Name)
+	}
+	return nil
+}
+
+// HoldOpen holds a door open for a scheduled window, unless it is locked down
+func (rm ResourceManager) HoldOpen(r models.Resource) {
+	if rm.doorMode(r.ID) == models.DoorModeLockdown {
+		rm.logger.Infof("%s is locked down, not holding it open", r.Name)
+		return
+	}
+
+	if err := rm.driver(r).HoldOpen(r); err != nil {
+		rm.logger.Errorf("error holding %s open: %s", r.Name, err)
+	}
+}
+
+// Release locks a door at the end of a scheduled window, unless a door mode is holding it open
+func (rm ResourceManager) Release(r models.Resource) {
+	if rm.doorMode(r.ID) != models.DoorModeNormal {
+		return
+	}
+
+	if err := rm.driver(r).Lock(r); err != nil {
+		rm.logger.Errorf("error locking %s: %s", r.Name, err)
+	}
+}
@@ -0,0 +1,74 @@
+package resourcemanager_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// commandRecorder keeps the payloads published to devices, door modes are applied in the background
+type commandRecorder struct {
+	mu       sync.Mutex
+	payloads []string
+}
+
+func (c *commandRecorder) Publish(address string, topic string, payload interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.payloads = append(c.payloads, fmt.Sprint(payload))
+}
+
+func (c *commandRecorder) Subscribe(address string, topic string, handler mqtt.MessageHandler) {}
+
+func (c *commandRecorder) Unsubscribe(address string, topics ...string) {}
+
+func (c *commandRecorder) waitFor(n int) []string {
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		c.mu.Lock()
+		if len(c.payloads) >= n {
+			payloads := append([]string{}, c.payloads...)
+			c.mu.Unlock()
+			return payloads
+		}
+		c.mu.Unlock()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string{}, c.payloads...)
+}
+
+// TestNormalModeClearsLockdownAdmins admins added for a lockdown shouldn't keep access once the door is back to normal
+func TestNormalModeClearsLockdownAdmins(t *testing.T) {
+	store := &in_memory.In_memory{}
+	recorder := &commandRecorder{}
+	rm := resourcemanager.New(recorder, store, &recordingNotifier{}, logrus.New())
+	t.Cleanup(func() { delete(in_memory.Resources, "lockdown-door") })
+
+	r, _ := rm.RegisterResource("lockdown-door", "", false, "")
+	store.SetDoorMode(models.DoorMode{ResourceID: r.ID, Mode: models.DoorModeLockdown})
+
+	if err := rm.SetDoorMode(models.DoorMode{ResourceID: r.ID, Mode: models.DoorModeNormal}); err != nil {
+		t.Fatal(err)
+	}
+
+	payloads := recorder.waitFor(3)
+	if len(payloads) != 3 {
+		t.Fatalf("expected lock, clear and the access list, got %v", payloads)
+	}
+
+	for i, command := range []string{"lock", "deletusers", "adduser"} {
+		if !strings.Contains(payloads[i], `"cmd":"`+command+`"`) {
+			t.Errorf("expected command %d to be %s, got %s", i, command, payloads[i])
+		}
+	}
+}
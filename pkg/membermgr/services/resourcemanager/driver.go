@@ -0,0 +1,66 @@
+package resourcemanager
+
+import (
+	"errors"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// errUnsupportedCommand is returned by drivers for commands their devices can't carry out
+var errUnsupportedCommand = errors.New("command isn't supported by the device")
+
+// DeviceDriver speaks a door controller's protocol
+//
+//	the resource manager decides what each device should be doing and the driver
+//	knows how to tell that to a particular generation of hardware
+type DeviceDriver interface {
+	// AddUser gives a member's fob access to the device
+	AddUser(r models.Resource, u DeviceUser) error
+	// RemoveUser takes a fob off the device
+	RemoveUser(r models.Resource, rfid string) error
+	// RemoveAllUsers clears every fob off the device
+	RemoveAllUsers(r models.Resource) error
+	// PushACL replaces the device's access list
+	PushACL(r models.Resource, acl []string) error
+	// Open unlocks the door for a moment
+	Open(r models.Resource) error
+	// HoldOpen unlocks the door until it's locked
+	HoldOpen(r models.Resource) error
+	// Lock locks a door that was held open
+	Lock(r models.Resource) error
+	// RequestStatus asks the device to check that its access list is up to date
+	RequestStatus(r models.Resource) error
+}
+
+// DeviceUser is a member as a device sees them
+type DeviceUser struct {
+	Name string
+	RFID string
+	// ValidUntil is set for temporary grants, so the device stops honoring the fob even if it misses the revocation
+	ValidUntil *time.Time
+	// Windows is only set for devices whose firmware supports time windows
+	Windows []models.TimeWindow
+}
+
+// driver picks the driver for a resource
+//
+//	resources that were built from a member's access don't carry their driver, so we look it up
+func (rm ResourceManager) driver(r models.Resource) DeviceDriver {
+	name := r.Driver
+	if len(name) == 0 && len(r.ID) > 0 {
+		if resource, err := rm.GetResourceByID(r.ID); err == nil {
+			name = resource.Driver
+		}
+	}
+	if len(name) == 0 && len(r.Name) > 0 {
+		if resource, err := rm.GetResourceByName(r.Name); err == nil {
+			name = resource.Driver
+		}
+	}
+
+	if d, ok := rm.drivers[name]; ok {
+		return d
+	}
+	return rm.drivers[models.DriverESPRFID]
+}
@@ -0,0 +1,122 @@
+package resourcemanager
+
+import (
+	"encoding/json"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+const (
+	commandDeleteUID   = "deletuid"
+	commandDeleteUsers = "deletusers" // not a type-o this is how the command is defined in the rfid reader
+	commandAddUser     = "adduser"
+	commandOpenDoor    = "opendoor"
+	commandListUser    = "listusr"
+	commandHoldOpen    = "holdopen"
+	commandLock        = "lock"
+)
+
+// espRFIDDriver talks to esp-rfid readers over mqtt
+//
+//	commands are published to the resource's name and access lists to `<name>/update`
+type espRFIDDriver struct {
+	mqtt mqttServer
+}
+
+func (d espRFIDDriver) publish(topic string, payload interface{}) {
+	d.mqtt.Publish(config.Get().MQTTBrokerAddress, topic, payload)
+}
+
+func (d espRFIDDriver) AddUser(r models.Resource, u DeviceUser) error {
+	request := models.MemberRequest{
+		ResourceAddress: r.Address,
+		Command:         commandAddUser,
+		UserName:        u.Name,
+		RFID:            u.RFID,
+		AccessType:      1,
+		ValidUntil:      -86400,
+		Windows:         u.Windows,
+	}
+
+	if u.ValidUntil != nil {
+		request.ValidUntil = int(u.ValidUntil.Unix())
+	}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	d.publish(r.Name, string(b))
+	return nil
+}
+
+func (d espRFIDDriver) RemoveUser(r models.Resource, rfid string) error {
+	b, err := json.Marshal(&models.MemberRequest{
+		ResourceAddress: r.Address,
+		Command:         commandDeleteUID,
+		RFID:            rfid,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.publish(r.Name, string(b))
+	return nil
+}
+
+func (d espRFIDDriver) RemoveAllUsers(r models.Resource) error {
+	b, err := json.Marshal(&models.DeleteMemberRequest{
+		ResourceAddress: r.Address,
+		Command:         commandDeleteUsers,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.publish(r.Name, string(b))
+	return nil
+}
+
+func (d espRFIDDriver) PushACL(r models.Resource, acl []string) error {
+	j, err := json.Marshal(&models.ACLUpdateRequest{ACL: acl})
+	if err != nil {
+		return err
+	}
+
+	d.publish(r.Name+"/update", j)
+	return nil
+}
+
+func (d espRFIDDriver) Open(r models.Resource) error {
+	return d.command(r, commandOpenDoor)
+}
+
+func (d espRFIDDriver) HoldOpen(r models.Resource) error {
+	return d.command(r, commandHoldOpen)
+}
+
+func (d espRFIDDriver) Lock(r models.Resource) error {
+	return d.command(r, commandLock)
+}
+
+// RequestStatus asks the reader for a hash of its access list, which it sends back on `<name>/result`
+func (d espRFIDDriver) RequestStatus(r models.Resource) error {
+	d.publish(r.Name+"/cmd", "aclhash")
+	return nil
+}
+
+func (d espRFIDDriver) command(r models.Resource, command string) error {
+	b, err := json.Marshal(models.MQTTRequest{
+		Door:    r.Name,
+		Command: command,
+		Address: r.Address,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.publish(r.Name, string(b))
+	return nil
+}
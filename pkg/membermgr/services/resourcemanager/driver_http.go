@@ -0,0 +1,198 @@
+package resourcemanager
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// httpDriverTimeout - how long we wait on a device before giving up
+const httpDriverTimeout = 10 * time.Second
+
+type aclStore interface {
+	GetResourceACL(r models.Resource) ([]string, error)
+}
+
+// httpDriver talks to devices that take their whole access list over plain http
+//
+//	POST /update replaces the device's access list and GET / responds with a hash of it.
+//	Since the device only takes whole lists, the driver keeps the list it last sent to each
+//	device and sends the whole thing again whenever a fob is added or removed.
+type httpDriver struct {
+	client *http.Client
+	store  aclStore
+
+	acls map[string][]string
+	mu   *sync.Mutex
+}
+
+func newHTTPDriver(store aclStore) httpDriver {
+	return httpDriver{
+		client: &http.Client{Timeout: httpDriverTimeout},
+		store:  store,
+		acls:   map[string][]string{},
+		mu:     &sync.Mutex{},
+	}
+}
+
+func (d httpDriver) AddUser(r models.Resource, u DeviceUser) error {
+	return d.change(r, func(acl []string) []string {
+		for _, rfid := range acl {
+			if rfid == u.RFID {
+				return acl
+			}
+		}
+		return append(acl, u.RFID)
+	})
+}
+
+func (d httpDriver) RemoveUser(r models.Resource, rfid string) error {
+	return d.change(r, func(acl []string) []string {
+		kept := []string{}
+		for _, v := range acl {
+			if v != rfid {
+				kept = append(kept, v)
+			}
+		}
+		return kept
+	})
+}
+
+func (d httpDriver) RemoveAllUsers(r models.Resource) error {
+	return d.change(r, func([]string) []string {
+		return []string{}
+	})
+}
+
+func (d httpDriver) PushACL(r models.Resource, acl []string) error {
+	return d.change(r, func([]string) []string {
+		return append([]string{}, acl...)
+	})
+}
+
+func (d httpDriver) Open(r models.Resource) error {
+	return fmt.Errorf("open %s: %w", r.Name, errUnsupportedCommand)
+}
+
+func (d httpDriver) HoldOpen(r models.Resource) error {
+	return fmt.Errorf("hold open %s: %w", r.Name, errUnsupportedCommand)
+}
+
+func (d httpDriver) Lock(r models.Resource) error {
+	return fmt.Errorf("lock %s: %w", r.Name, errUnsupportedCommand)
+}
+
+// RequestStatus compares the hash the device reports with the list in the DB
+// and sends that list if they don't match
+func (d httpDriver) RequestStatus(r models.Resource) error {
+	resp, err := d.client.Get(deviceURL(r, "/"))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", r.Name, resp.Status)
+	}
+
+	var status models.ACLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return err
+	}
+
+	acl, err := d.store.GetResourceACL(r)
+	if err != nil {
+		return err
+	}
+	if acl == nil {
+		acl = []string{}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// the DB is the source of truth, so the next change starts from it too
+	d.acls[r.Name] = acl
+
+	if status.Hash == aclHash(acl) {
+		return nil
+	}
+
+	return d.post(r, acl)
+}
+
+// change applies a change to a device's access list and sends the result to the device
+func (d httpDriver) change(r models.Resource, apply func(acl []string) []string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	acl, err := d.acl(r)
+	if err != nil {
+		return err
+	}
+
+	acl = apply(acl)
+	d.acls[r.Name] = acl
+
+	return d.post(r, acl)
+}
+
+// acl is the list we last sent to a device, or the list from the DB if we haven't sent it one yet
+func (d httpDriver) acl(r models.Resource) ([]string, error) {
+	if acl, ok := d.acls[r.Name]; ok {
+		return acl, nil
+	}
+
+	acl, err := d.store.GetResourceACL(r)
+	if err != nil {
+		return nil, err
+	}
+	if acl == nil {
+		acl = []string{}
+	}
+
+	d.acls[r.Name] = acl
+	return acl, nil
+}
+
+func (d httpDriver) post(r models.Resource, acl []string) error {
+	b, err := json.Marshal(models.ACLUpdateRequest{ACL: acl})
+	if err != nil {
+		return err
+	}
+
+	resp, err := d.client.Post(deviceURL(r, "/update"), "application/json", bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with %s", r.Name, resp.Status)
+	}
+
+	return nil
+}
+
+// deviceURL builds a url from the resource's address, which may or may not include the scheme
+func deviceURL(r models.Resource, path string) string {
+	address := strings.TrimSuffix(r.Address, "/")
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	return address + path
+}
+
+// aclHash hashes an access list the way devices do
+func aclHash(acl []string) string {
+	h := sha1.New()
+	h.Write([]byte(strings.Join(acl, "\n")))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
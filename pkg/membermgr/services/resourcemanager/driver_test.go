@@ -0,0 +1,145 @@
+package resourcemanager
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// fakeHTTPDevice behaves like test/resourcedummy
+type fakeHTTPDevice struct {
+	acl     []string
+	updates int
+	mu      sync.Mutex
+}
+
+func (d *fakeHTTPDevice) server() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/update", func(w http.ResponseWriter, req *http.Request) {
+		var update models.ACLUpdateRequest
+		if err := json.NewDecoder(req.Body).Decode(&update); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		d.mu.Lock()
+		d.acl = update.ACL
+		d.updates++
+		d.mu.Unlock()
+
+		json.NewEncoder(w).Encode(update.ACL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		json.NewEncoder(w).Encode(models.ACLResponse{Hash: aclHash(d.acl)})
+	})
+	return httptest.NewServer(mux)
+}
+
+type stubACLStore struct {
+	acl []string
+}
+
+func (s stubACLStore) GetResourceACL(r models.Resource) ([]string, error) {
+	return s.acl, nil
+}
+
+func TestHTTPDriver(t *testing.T) {
+	device := &fakeHTTPDevice{}
+	server := device.server()
+	defer server.Close()
+
+	driver := newHTTPDriver(stubACLStore{acl: []string{"111", "222"}})
+	r := models.Resource{Name: "laser", Address: strings.TrimPrefix(server.URL, "http://"), Driver: models.DriverHTTP}
+
+	// the first change starts from the access list in the DB
+	if err := driver.AddUser(r, DeviceUser{Name: "carol", RFID: "333"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(device.acl, ",") != "111,222,333" {
+		t.Errorf("expected the whole list to be sent, got %v", device.acl)
+	}
+
+	// adding someone who's already there doesn't add them twice
+	driver.AddUser(r, DeviceUser{Name: "carol", RFID: "333"})
+	if len(device.acl) != 3 {
+		t.Errorf("expected 3 fobs, got %v", device.acl)
+	}
+
+	if err := driver.RemoveUser(r, "222"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(device.acl, ",") != "111,333" {
+		t.Errorf("expected 222 to be removed, got %v", device.acl)
+	}
+
+	if err := driver.RemoveAllUsers(r); err != nil {
+		t.Fatal(err)
+	}
+	if len(device.acl) != 0 {
+		t.Errorf("expected an empty list, got %v", device.acl)
+	}
+
+	if err := driver.Open(r); !errors.Is(err, errUnsupportedCommand) {
+		t.Errorf("expected open to be unsupported, got %v", err)
+	}
+}
+
+func TestHTTPDriverStatus(t *testing.T) {
+	device := &fakeHTTPDevice{}
+	server := device.server()
+	defer server.Close()
+
+	store := &stubACLStore{acl: []string{"111"}}
+	driver := newHTTPDriver(store)
+	r := models.Resource{Name: "laser", Address: server.URL, Driver: models.DriverHTTP}
+
+	driver.PushACL(r, []string{"111"})
+
+	// the device agrees with us, so nothing is sent
+	if err := driver.RequestStatus(r); err != nil {
+		t.Fatal(err)
+	}
+	if device.updates != 1 {
+		t.Errorf("expected 1 update, got %d", device.updates)
+	}
+
+	// the device lost its list, so it's sent again
+	device.acl = nil
+	if err := driver.RequestStatus(r); err != nil {
+		t.Fatal(err)
+	}
+	if device.updates != 2 || strings.Join(device.acl, ",") != "111" {
+		t.Errorf("expected the list to be sent again, got %d updates and %v", device.updates, device.acl)
+	}
+
+	// the device has the list we last sent, but a change in the DB didn't reach it
+	store.acl = []string{"111", "222"}
+	if err := driver.RequestStatus(r); err != nil {
+		t.Fatal(err)
+	}
+	if device.updates != 3 || strings.Join(device.acl, ",") != "111,222" {
+		t.Errorf("expected the list from the DB to be sent, got %d updates and %v", device.updates, device.acl)
+	}
+}
+
+func TestDriverSelection(t *testing.T) {
+	rm := ResourceManager{drivers: map[string]DeviceDriver{
+		models.DriverESPRFID: espRFIDDriver{},
+		models.DriverHTTP:    newHTTPDriver(stubACLStore{}),
+	}}
+
+	if _, ok := rm.driver(models.Resource{Driver: models.DriverHTTP}).(httpDriver); !ok {
+		t.Error("expected the http driver")
+	}
+	if _, ok := rm.driver(models.Resource{Driver: "carrier pigeon"}).(espRFIDDriver); !ok {
+		t.Error("expected unknown drivers to fall back to esp-rfid")
+	}
+}
@@ -0,0 +1,64 @@
+package resourcemanager
+
+import "github.com/HackRVA/memberserver/pkg/membermgr/models"
+
+// AddGroupMembers grants members access to every resource in a group and pushes them to the devices
+func (rm ResourceManager) AddGroupMembers(groupID string, emails []string) ([]models.MemberResourceRelation, error) {
+	relations, err := rm.AddMembersToResourceGroup(emails, groupID)
+	if err != nil {
+		return relations, err
+	}
+
+	for _, email := range emails {
+		member, err := rm.GetMemberByEmail(email)
+		if err != nil {
+			rm.logger.Errorf("error getting member %s: %s", email, err)
+			continue
+		}
+		rm.PushOne(member)
+	}
+
+	return relations, nil
+}
+
+// RemoveGroupMember revokes a member's access to the resources in a group and removes them from the devices
+func (rm ResourceManager) RemoveGroupMember(groupID string, email string) error {
+	revoked, err := rm.RemoveMemberFromResourceGroup(email, groupID)
+	if err != nil {
+		return err
+	}
+
+	for _, access := range revoked {
+		rm.RemoveMember(access)
+	}
+
+	return nil
+}
+
+// AddGroupResource adds a resource to a group and pushes the group's members to the device
+func (rm ResourceManager) AddGroupResource(groupID string, resourceID string) error {
+	granted, err := rm.AddResourceToResourceGroup(groupID, resourceID)
+	if err != nil {
+		return err
+	}
+
+	for _, access := range granted {
+		rm.pushMemberAccess(access)
+	}
+
+	return nil
+}
+
+// RemoveGroupResource removes a resource from a group and removes the group's members from the device
+func (rm ResourceManager) RemoveGroupResource(groupID string, resourceID string) error {
+	revoked, err := rm.RemoveResourceFromResourceGroup(groupID, resourceID)
+	if err != nil {
+		return err
+	}
+
+	for _, access := range revoked {
+		rm.RemoveMember(access)
+	}
+
+	return nil
+}
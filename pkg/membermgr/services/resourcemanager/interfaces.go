@@ -23,4 +23,5 @@ type notifier interface {
 type mqttServer interface {
 	Publish(address string, topic string, payload interface{})
 	Subscribe(address string, topic string, handler mqtt.MessageHandler)
+	Unsubscribe(address string, topics ...string)
 }
@@ -3,9 +3,12 @@ package resourcemanager
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/dbstore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/schedule"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 )
@@ -77,27 +80,75 @@ func (rm *ResourceManager) ReceiveHandler(client mqtt.Client, msg mqtt.Message)
 	rm.OnAccessEventHandler(payload)
 }
 
-// OnAccessEvent - post the event to slack. This could also get shoved in the DB eventually
+// OnAccessEvent - post the event to slack and store it in the access log
 func (rm *ResourceManager) OnAccessEventHandler(payload models.LogMessage) {
 	m, err := rm.GetMemberByRFID(payload.RFID)
 	if err != nil {
-		rm.logger.Errorf("swipe on %s of unknown fob: %s", payload.Door, payload.RFID)
+		go rm.onUnknownFob(payload)
 		return
 	}
 
 	defer func(m models.Member, p models.LogMessage) {
+		go rm.checkSchedule(m, p)
 		go rm.notifier.Send(fmt.Sprintf("name: %s, rfid: %s, door: %s, time: %d", m.Name, p.RFID, p.Door, p.EventTime))
-		go rm.LogAccessEvent(models.LogMessage{
+		event := models.LogMessage{
 			Type:      p.Type,
 			EventTime: p.EventTime,
 			IsKnown:   p.IsKnown,
 			Username:  m.Name,
 			RFID:      p.RFID,
 			Door:      p.Door,
-		})
+			Access:    p.Access,
+			MemberID:  m.ID,
+		}
+		go rm.LogAccessEvent(event)
+		rm.publishAccessEvent(event)
 	}(m, payload)
 }
 
+// publishAccessEvent sends an access event to anyone watching the live stream
+func (rm *ResourceManager) publishAccessEvent(p models.LogMessage) {
+	if rm.events == nil {
+		return
+	}
+
+	isKnown, _ := strconv.ParseBool(p.IsKnown)
+	rm.events.Publish(models.AccessEvent{
+		Type:      p.Type,
+		EventTime: time.Unix(p.EventTime, 0),
+		IsKnown:   isKnown,
+		Username:  p.Username,
+		RFID:      p.RFID,
+		Door:      p.Door,
+		Access:    p.Access,
+		MemberID:  p.MemberID,
+	})
+}
+
+// checkSchedule evaluates an access event against the schedules for the member's tier
+//
+//	most devices don't know about schedules, so we raise an alert when a swipe falls outside of one
+func (rm *ResourceManager) checkSchedule(m models.Member, p models.LogMessage) {
+	r, err := rm.GetResourceByName(p.Door)
+	if err != nil {
+		rm.logger.Errorf("error fetching resource: %s", err)
+		return
+	}
+
+	schedules, err := rm.GetAccessSchedules(r.ID)
+	if err != nil {
+		rm.logger.Errorf("error fetching access schedules for %s: %s", r.Name, err)
+		return
+	}
+
+	if schedule.Allows(schedules, m.Level, time.Unix(p.EventTime, 0)) {
+		return
+	}
+
+	rm.logger.Infof("%s swiped on %s outside of their access schedule", m.Name, p.Door)
+	rm.notifier.Send(fmt.Sprintf("outside of access schedule - name: %s, rfid: %s, door: %s, time: %d", m.Name, p.RFID, p.Door, p.EventTime))
+}
+
 type HeartBeat struct {
 	ResourceName string `json:"door"`
 }
@@ -111,9 +162,32 @@ func (rm *ResourceManager) OnHeartBeatHandler(client mqtt.Client, msg mqtt.Messa
 		return
 	}
 
-	dbstore.ResourceHeartbeat(models.Resource{
+	r := models.Resource{
 		Name: hb.ResourceName,
-	})
+	}
+
+	lastHeartbeat := dbstore.GetLastHeartbeat(r)
+	dbstore.ResourceHeartbeat(r)
+
+	if time.Since(lastHeartbeat) > reconnectThreshold {
+		go rm.reapplyDoorMode(hb.ResourceName)
+	}
+}
+
+// reapplyDoorMode puts a device that has just come back online into a lockdown or hold-open that is in effect
+func (rm *ResourceManager) reapplyDoorMode(resourceName string) {
+	r, err := rm.GetResourceByName(resourceName)
+	if err != nil {
+		rm.logger.Errorf("error fetching resource: %s", err)
+		return
+	}
+
+	if rm.doorMode(r.ID) == models.DoorModeNormal {
+		return
+	}
+
+	rm.logger.Infof("%s reconnected, re-applying its door mode", r.Name)
+	rm.applyDoorMode(r)
 }
 
 // go through and remove members rfid fobs that are listed as invalid
@@ -0,0 +1,133 @@
+package resourcemanager
+
+import (
+	"sync"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// resourceSubscriptions is the set of resources whose topics we're subscribed to
+type resourceSubscriptions struct {
+	names map[string]bool
+	mu    sync.Mutex
+}
+
+func newResourceSubscriptions() *resourceSubscriptions {
+	return &resourceSubscriptions{names: map[string]bool{}}
+}
+
+// add returns false if the resource was already subscribed to
+func (s *resourceSubscriptions) add(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.names[name] {
+		return false
+	}
+	s.names[name] = true
+	return true
+}
+
+// remove returns false if the resource wasn't subscribed to
+func (s *resourceSubscriptions) remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.names[name] {
+		return false
+	}
+	delete(s.names, name)
+	return true
+}
+
+func resourceTopics(name string) []string {
+	return []string{name + "/send", name + "/result", name + "/sync", name + "/cleanup"}
+}
+
+// SubscribeResource subscribes to the topics that a resource publishes on
+func (rm *ResourceManager) SubscribeResource(r models.Resource) {
+	if !rm.subscriptions.add(r.Name) {
+		return
+	}
+
+	address := config.Get().MQTTBrokerAddress
+	topics := resourceTopics(r.Name)
+
+	rm.mqtt.Subscribe(address, topics[0], rm.ReceiveHandler)
+	rm.mqtt.Subscribe(address, topics[1], rm.HealthCheckHandler)
+	rm.mqtt.Subscribe(address, topics[2], rm.OnHeartBeatHandler)
+	rm.mqtt.Subscribe(address, topics[3], rm.OnRemoveInvalidRequestHandler)
+}
+
+// UnsubscribeResource stops listening to a resource's topics
+func (rm *ResourceManager) UnsubscribeResource(r models.Resource) {
+	if !rm.subscriptions.remove(r.Name) {
+		return
+	}
+
+	rm.mqtt.Unsubscribe(config.Get().MQTTBrokerAddress, resourceTopics(r.Name)...)
+}
+
+// RegisterResource stores a new resource and subscribes to its topics
+func (rm *ResourceManager) RegisterResource(name string, address string, isDefault bool, driver string) (models.Resource, error) {
+	r, err := rm.DataStore.RegisterResource(name, address, isDefault, driver)
+	if err != nil {
+		return r, err
+	}
+
+	rm.SubscribeResource(r)
+	return r, nil
+}
+
+// UpdateResource updates a resource and moves its subscriptions over when it's renamed
+//
+//	a renamed device that was discovered is told its new name, since it's also its topic prefix
+func (rm *ResourceManager) UpdateResource(res models.Resource) (*models.Resource, error) {
+	return rm.UpdateResourceAtVersion(res, datastore.AnyVersion)
+}
+
+// UpdateResourceAtVersion is UpdateResource, unless the resource has changed since it was at version
+func (rm *ResourceManager) UpdateResourceAtVersion(res models.Resource, version int) (*models.Resource, error) {
+	previous, err := rm.GetResourceByID(res.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := rm.DataStore.UpdateResourceAtVersion(res, version)
+	if err != nil {
+		return r, err
+	}
+
+	if previous.Name != r.Name {
+		rm.UnsubscribeResource(previous)
+	}
+	rm.SubscribeResource(*r)
+
+	if previous.Name != r.Name {
+		rm.reprovision(*r)
+	}
+
+	return r, nil
+}
+
+// DeleteResource deletes a resource and stops listening to its topics
+func (rm *ResourceManager) DeleteResource(id string) error {
+	return rm.DeleteResourceAtVersion(id, datastore.AnyVersion)
+}
+
+// DeleteResourceAtVersion is DeleteResource, unless the resource has changed since it was at version
+func (rm *ResourceManager) DeleteResourceAtVersion(id string, version int) error {
+	r, err := rm.GetResourceByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := rm.DataStore.DeleteResourceAtVersion(id, version); err != nil {
+		return err
+	}
+
+	rm.UnsubscribeResource(r)
+	return nil
+}
@@ -0,0 +1,70 @@
+package resourcemanager_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestSubscriptionsFollowResources(t *testing.T) {
+	ms := &recordingMQTTServer{}
+	rm := resourcemanager.New(ms, &in_memory.In_memory{}, &recordingNotifier{}, logrus.New())
+	t.Cleanup(func() {
+		delete(in_memory.Resources, "frontdoor")
+		delete(in_memory.Resources, "front door")
+	})
+
+	r, err := rm.RegisterResource("frontdoor", "10.0.0.5", false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "frontdoor/send,frontdoor/result,frontdoor/sync,frontdoor/cleanup"
+	if got := strings.Join(ms.subscribed, ","); got != expected {
+		t.Fatalf("expected %s to be subscribed, got %s", expected, got)
+	}
+
+	// subscribing again, e.g. at startup, doesn't subscribe twice
+	rm.SubscribeResource(r)
+	if len(ms.subscribed) != 4 {
+		t.Errorf("expected 4 subscriptions, got %v", ms.subscribed)
+	}
+
+	// an update that keeps the name keeps the subscriptions
+	r.Address = "10.0.0.6"
+	if _, err := rm.UpdateResource(r); err != nil {
+		t.Fatal(err)
+	}
+	if len(ms.subscribed) != 4 || len(ms.unsubscribed) != 0 {
+		t.Errorf("expected the subscriptions to be left alone, got %v and %v", ms.subscribed, ms.unsubscribed)
+	}
+
+	ms.subscribed = nil
+	r.Name = "front door"
+	if _, err := rm.UpdateResource(r); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(ms.unsubscribed, ","); got != expected {
+		t.Errorf("expected the old topics to be unsubscribed, got %s", got)
+	}
+	if len(ms.subscribed) != 4 || ms.subscribed[0] != "front door/send" {
+		t.Errorf("expected the new topics to be subscribed, got %v", ms.subscribed)
+	}
+
+	ms.unsubscribed = nil
+	if err := rm.DeleteResource(r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if len(ms.unsubscribed) != 4 || ms.unsubscribed[0] != "front door/send" {
+		t.Errorf("expected the deleted resource to be unsubscribed, got %v", ms.unsubscribed)
+	}
+
+	if err := rm.DeleteResource(r.ID); err == nil {
+		t.Error("expected deleting an unknown resource to fail")
+	}
+}
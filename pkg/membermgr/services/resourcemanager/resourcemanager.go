@@ -2,12 +2,12 @@ package resourcemanager
 
 import (
 	"crypto/sha1"
-	"encoding/json"
 	"fmt"
 
-	config "github.com/HackRVA/memberserver/configs"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/eventbus"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/schedule"
 	"github.com/HackRVA/memberserver/pkg/mqtt"
 
 	"time"
@@ -15,13 +15,6 @@ import (
 	"strings"
 )
 
-const (
-	commandDeleteUID = "deletuid"
-	commandAddUser   = "adduser"
-	commandOpenDoor  = "opendoor"
-	commandListUser  = "listusr"
-)
-
 // Resource manager keeps the resources up to date by
 //  pushing new updates and checking in on their health
 
@@ -30,6 +23,13 @@ type ResourceManager struct {
 	datastore.DataStore
 	notifier notifier
 	logger   logger
+	// unknownFobs is shared between copies of the ResourceManager
+	unknownFobs *unknownFobAlerts
+	events      *eventbus.Bus
+	// subscriptions is shared between copies of the ResourceManager
+	subscriptions *resourceSubscriptions
+	// drivers are the device drivers by name, see Resource.Driver
+	drivers map[string]DeviceDriver
 }
 
 const (
@@ -42,13 +42,22 @@ const (
 )
 
 func New(ms mqttServer, store datastore.DataStore, notifier notifier, logger logger) *ResourceManager {
-	return &ResourceManager{ms, store, notifier, logger}
+	drivers := map[string]DeviceDriver{
+		models.DriverESPRFID: espRFIDDriver{ms},
+		models.DriverHTTP:    newHTTPDriver(store),
+	}
+	return &ResourceManager{ms, store, notifier, logger, newUnknownFobAlerts(), eventbus.New(), newResourceSubscriptions(), drivers}
 }
 
 func (rm ResourceManager) MQTT() mqtt.MQTTServer {
 	return rm.mqtt
 }
 
+// AccessEvents is the bus that every access event is published to as it comes in
+func (rm ResourceManager) AccessEvents() *eventbus.Bus {
+	return rm.events
+}
+
 // UpdateResourceACL pulls a resource's accesslist from the DB and pushes it to the resource
 func (rm ResourceManager) UpdateResourceACL(r models.Resource) error {
 	// get acl for that resource
@@ -58,19 +67,9 @@ func (rm ResourceManager) UpdateResourceACL(r models.Resource) error {
 		return err
 	}
 
-	updateRequest := &models.ACLUpdateRequest{}
-	updateRequest.ACL = accessList
-
-	j, err := json.Marshal(updateRequest)
-	if err != nil {
-		return err
-	}
-	rm.logger.Infof("access list: %s", j)
-
-	// publish the update to mqtt broker
-	rm.mqtt.Publish(config.Get().MQTTBrokerAddress, r.Name+"/update", j)
+	rm.logger.Infof("access list for %s: %v", r.Name, accessList)
 
-	return nil
+	return rm.driver(r).PushACL(r, accessList)
 }
 
 // UpdateResources - publish an MQTT message to add a member to the actual device
@@ -78,24 +77,35 @@ func (rm ResourceManager) UpdateResources() {
 	resources := rm.GetResources()
 
 	for _, r := range resources {
-		members, _ := rm.GetResourceACLWithMemberInfo(r)
-		for _, m := range members {
-			if m.Level == uint8(models.Inactive) {
-				continue
-			}
-
-			b, _ := json.Marshal(&models.MemberRequest{
-				ResourceAddress: r.Address,
-				Command:         commandAddUser,
-				UserName:        m.Name,
-				RFID:            m.RFID,
-				AccessType:      1,
-				ValidUntil:      -86400,
-			})
-			rm.mqtt.Publish(config.Get().MQTTBrokerAddress, r.Name, string(b))
-
-			time.Sleep(2 * time.Second)
+		rm.PushResource(r)
+	}
+}
+
+// PushResource - publish every member with access to a single device
+func (rm ResourceManager) PushResource(r models.Resource) {
+	if rm.doorMode(r.ID) == models.DoorModeLockdown {
+		rm.logger.Infof("%s is locked down, not pushing its access list", r.Name)
+		return
+	}
+
+	rm.pushResourceACL(r)
+}
+
+// pushResourceACL adds every member with access to a device, one at a time
+func (rm ResourceManager) pushResourceACL(r models.Resource) {
+	driver := rm.driver(r)
+
+	members, _ := rm.GetResourceACLWithMemberInfo(r)
+	for _, m := range members {
+		if m.Level == uint8(models.Inactive) {
+			continue
 		}
+
+		if err := driver.AddUser(r, rm.deviceUser(r, m.Name, m.RFID, m.Level, m.ValidUntil)); err != nil {
+			rm.logger.Errorf("error adding %s to %s: %s", m.Name, r.Name, err)
+		}
+
+		time.Sleep(2 * time.Second)
 	}
 }
 
@@ -132,24 +142,17 @@ func (rm ResourceManager) RemovedInvalidUIDs() {
 }
 
 func (rm ResourceManager) RemoveMember(memberAccess models.MemberAccess) {
-	b, _ := json.Marshal(&models.MemberRequest{
-		ResourceAddress: memberAccess.ResourceAddress,
-		Command:         commandDeleteUID,
-		RFID:            memberAccess.RFID,
-	})
-
-	rm.mqtt.Publish(config.Get().MQTTBrokerAddress, memberAccess.ResourceName, string(b))
+	r := models.Resource{ID: memberAccess.ResourceID, Name: memberAccess.ResourceName, Address: memberAccess.ResourceAddress}
+	if err := rm.driver(r).RemoveUser(r, memberAccess.RFID); err != nil {
+		rm.logger.Errorf("error removing %s from %s: %s", memberAccess.Email, r.Name, err)
+	}
 	rm.logger.Debugf("attempting to remove member %s from rfid device %s : %s", memberAccess.Email, memberAccess.ResourceName, memberAccess.ResourceAddress)
 }
 
 func (rm ResourceManager) Open(resource models.Resource) {
-	b, _ := json.Marshal(models.MQTTRequest{
-		Door:    resource.Name,
-		Command: commandOpenDoor,
-		Address: resource.Address,
-	})
-
-	rm.mqtt.Publish(config.Get().MQTTBrokerAddress, resource.Name, string(b))
+	if err := rm.driver(resource).Open(resource); err != nil {
+		rm.logger.Errorf("error opening %s: %s", resource.Name, err)
+	}
 }
 
 // RemoveOne - remove a member from all resources
@@ -165,6 +168,7 @@ func (rm ResourceManager) RemoveOne(member models.Member) {
 	for _, m := range memberAccess {
 		rm.RemoveMember(models.MemberAccess{
 			Email:           member.Email,
+			ResourceID:      m.ResourceID,
 			ResourceAddress: m.ResourceAddress,
 			ResourceName:    m.ResourceName,
 			Name:            member.Name,
@@ -177,27 +181,60 @@ func (rm ResourceManager) RemoveOne(member models.Member) {
 func (rm ResourceManager) PushOne(m models.Member) {
 	memberAccess, _ := rm.GetMembersAccess(m)
 	for _, m := range memberAccess {
-		b, _ := json.Marshal(&models.MemberRequest{
-			ResourceAddress: m.ResourceAddress,
-			Command:         commandAddUser,
-			UserName:        m.Name,
-			RFID:            m.RFID,
-			AccessType:      1,
-			ValidUntil:      -86400,
-		})
-		rm.mqtt.Publish(config.Get().MQTTBrokerAddress, m.ResourceName, string(b))
+		rm.pushMemberAccess(m)
+	}
+}
+
+// pushMemberAccess adds a member to a single device
+func (rm ResourceManager) pushMemberAccess(m models.MemberAccess) {
+	if rm.doorMode(m.ResourceID) == models.DoorModeLockdown {
+		rm.logger.Infof("%s is locked down, not adding %s", m.ResourceName, m.Email)
+		return
+	}
+
+	r := models.Resource{ID: m.ResourceID, Name: m.ResourceName, Address: m.ResourceAddress}
+	if resource, err := rm.GetResourceByID(m.ResourceID); err == nil {
+		r.SupportsTimeWindows = resource.SupportsTimeWindows
+		r.Driver = resource.Driver
+	}
+
+	if err := rm.driver(r).AddUser(r, rm.deviceUser(r, m.Name, m.RFID, m.Level, m.ValidUntil)); err != nil {
+		rm.logger.Errorf("error adding %s to %s: %s", m.Email, r.Name, err)
 	}
 }
 
+// deviceUser builds the member that gets added to a device
+//
+//	if the device's firmware supports time windows, we send along the schedules for the member's tier
+//	temporary grants carry their expiry so the device stops honoring the fob even if it misses the revocation
+func (rm ResourceManager) deviceUser(r models.Resource, name string, rfid string, level uint8, validUntil *time.Time) DeviceUser {
+	u := DeviceUser{
+		Name:       name,
+		RFID:       rfid,
+		ValidUntil: validUntil,
+	}
+
+	if !r.SupportsTimeWindows {
+		return u
+	}
+
+	schedules, err := rm.GetAccessSchedules(r.ID)
+	if err != nil {
+		rm.logger.Errorf("error getting access schedules for %s: %s", r.Name, err)
+		return u
+	}
+
+	u.Windows = schedule.Windows(schedules, level)
+	return u
+}
+
 func (rm ResourceManager) DeleteResourceACL() {
 	resources := rm.GetResources()
 
 	for _, r := range resources {
-		b, _ := json.Marshal(&models.DeleteMemberRequest{
-			ResourceAddress: r.Address,
-			Command:         "deletusers", // not a type-o this is how the command is defined in the rfid reader
-		})
-		rm.mqtt.Publish(config.Get().MQTTBrokerAddress, r.Name, string(b))
+		if err := rm.driver(r).RemoveAllUsers(r); err != nil {
+			rm.logger.Errorf("error clearing the access list on %s: %s", r.Name, err)
+		}
 	}
 }
 
@@ -206,8 +243,15 @@ func (rm ResourceManager) DeleteResourceACL() {
 //	the resource has the correct and up to date access list
 //	It will do this by hashing the list retrieved from the DB and comparing it
 //	with the hash that the resource reports
+//	Locked down resources are skipped, since their access list is meant to differ
 func (rm ResourceManager) CheckStatus(r models.Resource) {
-	rm.mqtt.Publish(config.Get().MQTTBrokerAddress, r.Name+"/cmd", "aclhash")
+	if rm.doorMode(r.ID) == models.DoorModeLockdown {
+		return
+	}
+
+	if err := rm.driver(r).RequestStatus(r); err != nil {
+		rm.logger.Errorf("error checking the status of %s: %s", r.Name, err)
+	}
 }
 
 func (rm ResourceManager) hash(accessList []string) string {
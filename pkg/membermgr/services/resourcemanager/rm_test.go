@@ -3,7 +3,10 @@ package resourcemanager_test
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/resourcemanager"
@@ -32,6 +35,10 @@ func (mqtt *stubMQTTServer) Subscribe(address string, topic string, handler mqtt
 
 }
 
+func (mqtt *stubMQTTServer) Unsubscribe(address string, topics ...string) {
+
+}
+
 // TestUpdateResourceACL we just want to test that the mqtt message looks reasonable
 func TestUpdateResourceACL(t *testing.T) {
 	resourceManager := resourcemanager.New(&stubMQTTServer{}, &in_memory.In_memory{}, slackNotifier{}, logrus.New())
@@ -70,7 +77,7 @@ func TestUpdateResources(t *testing.T) {
 
 	// add some stuff to the store
 	for _, v := range resources {
-		resourceManager.RegisterResource(v.Name, v.Address, v.IsDefault)
+		resourceManager.RegisterResource(v.Name, v.Address, v.IsDefault, v.Driver)
 	}
 
 	want := `should just straight up send it"{\"doorip\":\"\",\"cmd\":\"adduser\",\"user\":\"test\",\"uid\":\"\",\"acctype\":1,\"validuntil\":-86400}"`
@@ -87,3 +94,107 @@ func TestUpdateResources(t *testing.T) {
 
 	pub = []string{}
 }
+
+// TestPushResource only the one device should be sent its access list
+func TestPushResource(t *testing.T) {
+	resourceManager := resourcemanager.New(&stubMQTTServer{}, &in_memory.In_memory{}, slackNotifier{}, logrus.New())
+
+	var pushed models.Resource
+	for _, name := range []string{"frontdoor", "backdoor", "shop"} {
+		r, _ := resourceManager.RegisterResource(name, "", false, "")
+		if name == "backdoor" {
+			pushed = r
+		}
+	}
+
+	pub = []string{}
+	resourceManager.PushResource(pushed)
+	if len(pub) != 1 {
+		t.Fatalf("expected one update, received: %d", len(pub))
+	}
+	if !strings.HasPrefix(pub[0], "backdoor\"") {
+		t.Errorf("expected the update to go to backdoor, got: %s", pub[0])
+	}
+
+	pub = []string{}
+}
+
+type temporaryGrantStore struct {
+	*in_memory.In_memory
+	validUntil time.Time
+}
+
+func (s temporaryGrantStore) GetResourceACLWithMemberInfo(r models.Resource) ([]models.MemberAccess, error) {
+	return []models.MemberAccess{{Email: "guest@test.com", Name: "guest", RFID: "222", Level: uint8(models.Standard), ValidUntil: &s.validUntil}}, nil
+}
+
+// TestPushResourceKeepsExpiry temporary grants shouldn't become permanent when the whole list is pushed again
+func TestPushResourceKeepsExpiry(t *testing.T) {
+	store := temporaryGrantStore{&in_memory.In_memory{}, time.Now().Add(24 * time.Hour).Truncate(time.Second)}
+	resourceManager := resourcemanager.New(&stubMQTTServer{}, store, slackNotifier{}, logrus.New())
+
+	pub = []string{}
+	resourceManager.PushResource(models.Resource{ID: "frontdoor", Name: "frontdoor"})
+	if len(pub) != 1 {
+		t.Fatalf("expected one update, received: %d", len(pub))
+	}
+
+	want := fmt.Sprintf(`\"validuntil\":%d`, store.validUntil.Unix())
+	if !strings.Contains(pub[0], want) {
+		t.Errorf("expected the grant's expiry to be sent, got: %s", pub[0])
+	}
+
+	pub = []string{}
+}
+
+// TestUnknownFobIsLogged swipes from fobs that don't belong to anyone should still end up in the access log
+func TestUnknownFobIsLogged(t *testing.T) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {ID: "1", Name: "member", Email: "member@test.com", RFID: "111"},
+		},
+	}
+	resourceManager := resourcemanager.New(&stubMQTTServer{}, store, slackNotifier{}, logrus.New())
+
+	resourceManager.OnAccessEventHandler(models.LogMessage{Type: "access", EventTime: time.Now().Unix(), IsKnown: "true", RFID: "999", Door: "frontdoor"})
+
+	var page models.AccessEventPage
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		page, _ = store.GetAccessEvents(models.AccessEventFilter{})
+		if len(page.Events) > 0 {
+			break
+		}
+	}
+
+	if len(page.Events) != 1 {
+		t.Fatalf("expected the unknown swipe to be logged, got %d events", len(page.Events))
+	}
+
+	if page.Events[0].IsKnown || page.Events[0].RFID != "999" || len(page.Events[0].MemberID) > 0 {
+		t.Errorf("expected an unknown swipe, got %+v", page.Events[0])
+	}
+}
+
+// TestAccessEventIsPublished swipes should go out on the bus for the live stream
+func TestAccessEventIsPublished(t *testing.T) {
+	store := &in_memory.In_memory{
+		Members: map[string]models.Member{
+			"member@test.com": {ID: "1", Name: "member", Email: "member@test.com", RFID: "111"},
+		},
+	}
+	resourceManager := resourcemanager.New(&stubMQTTServer{}, store, slackNotifier{}, logrus.New())
+
+	events, unsubscribe := resourceManager.AccessEvents().Subscribe()
+	defer unsubscribe()
+
+	resourceManager.OnAccessEventHandler(models.LogMessage{Type: "access", EventTime: time.Now().Unix(), IsKnown: "true", RFID: "111", Door: "frontdoor"})
+
+	select {
+	case e := <-events:
+		if e.Username != "member" || e.MemberID != "1" || e.Door != "frontdoor" || !e.IsKnown {
+			t.Errorf("unexpected event: %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the swipe to be published")
+	}
+}
@@ -0,0 +1,83 @@
+package resourcemanager
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+const (
+	// unknownFobAlertThreshold - how many unknown swipes at a door within unknownFobAlertWindow raise an alert
+	unknownFobAlertThreshold = 3
+	unknownFobAlertWindow    = 5 * time.Minute
+	// unknownFobAlertCooldown - a door won't raise another alert until this long after the last one
+	unknownFobAlertCooldown = 15 * time.Minute
+)
+
+// unknownFobAlerts counts recent unknown swipes per door so that
+// someone trying a pile of fobs at the door doesn't flood slack
+type unknownFobAlerts struct {
+	mu        sync.Mutex
+	attempts  map[string][]time.Time
+	lastAlert map[string]time.Time
+}
+
+func newUnknownFobAlerts() *unknownFobAlerts {
+	return &unknownFobAlerts{
+		attempts:  map[string][]time.Time{},
+		lastAlert: map[string]time.Time{},
+	}
+}
+
+// record an unknown swipe at a door
+//
+//	returns the number of unknown swipes in the window and whether an alert should go out
+func (u *unknownFobAlerts) record(door string, at time.Time) (int, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	recent := []time.Time{at}
+	for _, t := range u.attempts[door] {
+		if at.Sub(t) < unknownFobAlertWindow {
+			recent = append(recent, t)
+		}
+	}
+	u.attempts[door] = recent
+
+	if len(recent) < unknownFobAlertThreshold {
+		return len(recent), false
+	}
+
+	if last, ok := u.lastAlert[door]; ok && at.Sub(last) < unknownFobAlertCooldown {
+		return len(recent), false
+	}
+
+	u.lastAlert[door] = at
+	return len(recent), true
+}
+
+// onUnknownFob stores a swipe from a fob that doesn't belong to a member
+// and alerts when a door sees repeated unknown swipes
+func (rm *ResourceManager) onUnknownFob(payload models.LogMessage) {
+	rm.logger.Errorf("swipe on %s of unknown fob: %s", payload.Door, payload.RFID)
+
+	payload.IsKnown = "false"
+	payload.MemberID = ""
+	if err := rm.LogAccessEvent(payload); err != nil {
+		rm.logger.Errorf("error logging unknown fob: %s", err)
+	}
+	rm.publishAccessEvent(payload)
+
+	if rm.unknownFobs == nil {
+		return
+	}
+
+	attempts, alert := rm.unknownFobs.record(payload.Door, time.Now())
+	if !alert {
+		return
+	}
+
+	rm.notifier.Send(fmt.Sprintf("%d swipes of unknown fobs on %s in the last %s, latest rfid: %s", attempts, payload.Door, unknownFobAlertWindow, payload.RFID))
+}
@@ -0,0 +1,36 @@
+package resourcemanager
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnknownFobAlerts(t *testing.T) {
+	alerts := newUnknownFobAlerts()
+	start := time.Date(2023, time.March, 1, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		TestName         string
+		door             string
+		at               time.Duration
+		expectedAttempts int
+		expectedAlert    bool
+	}{
+		{TestName: "first unknown swipe doesn't alert", door: "frontdoor", at: 0, expectedAttempts: 1},
+		{TestName: "second unknown swipe doesn't alert", door: "frontdoor", at: time.Minute, expectedAttempts: 2},
+		{TestName: "other doors are counted separately", door: "backdoor", at: time.Minute, expectedAttempts: 1},
+		{TestName: "repeated unknown swipes alert", door: "frontdoor", at: 2 * time.Minute, expectedAttempts: 3, expectedAlert: true},
+		{TestName: "alerts are rate limited", door: "frontdoor", at: 3 * time.Minute, expectedAttempts: 4},
+		{TestName: "old swipes fall out of the window", door: "frontdoor", at: 9 * time.Minute, expectedAttempts: 1},
+		{TestName: "starts counting again", door: "frontdoor", at: 20 * time.Minute, expectedAttempts: 1},
+		{TestName: "starts counting again", door: "frontdoor", at: 21 * time.Minute, expectedAttempts: 2},
+		{TestName: "alerts again after the cooldown", door: "frontdoor", at: 22 * time.Minute, expectedAttempts: 3, expectedAlert: true},
+	}
+
+	for _, tt := range tests {
+		attempts, alert := alerts.record(tt.door, start.Add(tt.at))
+		if attempts != tt.expectedAttempts || alert != tt.expectedAlert {
+			t.Errorf("%s: got %d attempts, alert %t; want %d attempts, alert %t", tt.TestName, attempts, alert, tt.expectedAttempts, tt.expectedAlert)
+		}
+	}
+}
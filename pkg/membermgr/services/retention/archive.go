@@ -0,0 +1,86 @@
+package retention
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// archivePageSize is how many events are read from the store at a time while archiving
+const archivePageSize = 1000
+
+// archive writes the events before the cutoff to a gzipped ndjson file in the archive directory
+//
+//	events that were already pseudonymised were archived the first time around, so they're skipped
+//	when we're pseudonymising. Nothing is written when there is nothing to archive.
+func (p Policy) archive(cutoff time.Time, now time.Time) (string, int, error) {
+	filter := models.AccessEventFilter{To: &cutoff, Limit: archivePageSize}
+	if p.mode == ModePseudonymise {
+		notPseudonymised := false
+		filter.Pseudonymised = &notPseudonymised
+	}
+
+	page, err := p.store.GetAccessEvents(filter)
+	if err != nil || len(page.Events) == 0 {
+		return "", 0, err
+	}
+
+	if err := os.MkdirAll(p.archiveDir, 0o750); err != nil {
+		return "", 0, err
+	}
+
+	name := "access-events-before-" + cutoff.Format("2006-01-02") + "-" + now.Format("20060102T150405") + ".ndjson.gz"
+	path := filepath.Join(p.archiveDir, name)
+
+	// write to a temporary file so that a half written archive is never mistaken for a complete one
+	tmp, err := os.CreateTemp(p.archiveDir, name+".*.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := gzip.NewWriter(tmp)
+	encoder := json.NewEncoder(zw)
+
+	archived := 0
+	for {
+		for _, e := range page.Events {
+			if err := encoder.Encode(e); err != nil {
+				return "", 0, err
+			}
+			archived++
+		}
+
+		if len(page.NextCursor) == 0 {
+			break
+		}
+
+		after, err := models.DecodeAccessEventCursor(page.NextCursor)
+		if err != nil {
+			return "", 0, err
+		}
+		filter.After = &after
+
+		page, err = p.store.GetAccessEvents(filter)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", 0, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, err
+	}
+
+	return path, archived, nil
+}
@@ -0,0 +1,14 @@
+package retention
+
+import (
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+type store interface {
+	GetAccessEvents(filter models.AccessEventFilter) (models.AccessEventPage, error)
+	RollupAccessEvents(before time.Time) error
+	DeleteAccessEvents(before time.Time) (int64, error)
+	PseudonymiseAccessEvents(before time.Time, key string) (int64, error)
+}
@@ -0,0 +1,103 @@
+// Package retention keeps the access log from growing forever.
+//
+// Old access events are rolled up into daily per-resource counts, optionally archived,
+// and then deleted or pseudonymised.
+package retention
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+)
+
+const (
+	// ModeDelete removes expired access events
+	ModeDelete = "delete"
+	// ModePseudonymise keeps expired access events but strips the member's name and fob
+	ModePseudonymise = "pseudonymise"
+)
+
+// Policy is how long access events are kept and what happens to them afterwards
+type Policy struct {
+	store      store
+	days       int
+	mode       string
+	archiveDir string
+	// pseudonymKey is the HMAC key for fobs, it's kept apart from the secrets that sign tokens
+	pseudonymKey string
+}
+
+// Result is what a run of the policy did
+type Result struct {
+	Cutoff        time.Time
+	Archived      int
+	ArchivePath   string
+	Deleted       int64
+	Pseudonymised int64
+}
+
+// New creates a retention policy from the config
+func New(store store, c config.Config) Policy {
+	return Policy{
+		store:        store,
+		days:         c.AccessEventRetentionDays,
+		mode:         c.AccessEventRetentionMode,
+		archiveDir:   c.AccessEventArchiveDir,
+		pseudonymKey: c.AccessEventPseudonymKey,
+	}
+}
+
+// Enabled is false when access events should be kept forever
+func (p Policy) Enabled() bool {
+	return p.days > 0
+}
+
+// Cutoff is the start of the oldest day that is kept
+func (p Policy) Cutoff(now time.Time) time.Time {
+	day := now.Local().AddDate(0, 0, -p.days)
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// Apply rolls up, archives and then deletes or pseudonymises the events before the cutoff
+//
+//	events that arrive late, e.g. from a device that was offline, are rolled up by the next run.
+//	If archiving fails nothing is deleted or pseudonymised
+func (p Policy) Apply(now time.Time) (Result, error) {
+	result := Result{Cutoff: p.Cutoff(now)}
+
+	if !p.Enabled() {
+		return result, nil
+	}
+
+	if p.mode != ModeDelete && p.mode != ModePseudonymise {
+		return result, fmt.Errorf("unknown access event retention mode %q", p.mode)
+	}
+
+	if p.mode == ModePseudonymise && len(p.pseudonymKey) == 0 {
+		return result, errors.New("ACCESS_EVENT_PSEUDONYM_KEY needs to be set to pseudonymise access events")
+	}
+
+	if err := p.store.RollupAccessEvents(result.Cutoff); err != nil {
+		return result, err
+	}
+
+	if len(p.archiveDir) > 0 {
+		path, archived, err := p.archive(result.Cutoff, now)
+		if err != nil {
+			return result, fmt.Errorf("error archiving access events: %v", err)
+		}
+		result.ArchivePath = path
+		result.Archived = archived
+	}
+
+	var err error
+	if p.mode == ModeDelete {
+		result.Deleted, err = p.store.DeleteAccessEvents(result.Cutoff)
+		return result, err
+	}
+
+	result.Pseudonymised, err = p.store.PseudonymiseAccessEvents(result.Cutoff, p.pseudonymKey)
+	return result, err
+}
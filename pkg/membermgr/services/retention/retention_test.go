@@ -0,0 +1,254 @@
+package retention
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	config "github.com/HackRVA/memberserver/configs"
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+var now = time.Date(2023, time.June, 15, 12, 0, 0, 0, time.Local)
+
+// retentionStore has two old swipes on the same day and one recent swipe
+func retentionStore() *in_memory.In_memory {
+	store := &in_memory.In_memory{}
+
+	old := time.Date(2023, time.January, 10, 18, 0, 0, 0, time.Local)
+	swipes := []models.LogMessage{
+		{Type: "access", IsKnown: "true", Username: "alice", RFID: "111", Door: "frontdoor", MemberID: "1", EventTime: old.Unix()},
+		{Type: "access", IsKnown: "true", Username: "alice", RFID: "111", Door: "frontdoor", MemberID: "1", EventTime: old.Add(time.Hour).Unix()},
+		{Type: "access", IsKnown: "true", Username: "bob", RFID: "222", Door: "frontdoor", MemberID: "2", EventTime: now.Add(-time.Hour).Unix()},
+	}
+	for _, swipe := range swipes {
+		store.LogAccessEvent(swipe)
+	}
+
+	return store
+}
+
+func policyConfig(mode string, archiveDir string) config.Config {
+	return config.Config{
+		AccessEventPseudonymKey:  "pseudonym key",
+		AccessEventRetentionDays: 90,
+		AccessEventRetentionMode: mode,
+		AccessEventArchiveDir:    archiveDir,
+	}
+}
+
+func accessCounts(t *testing.T, store *in_memory.In_memory) map[string]int {
+	t.Helper()
+
+	stats, err := store.GetAccessStats(time.Time{}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	for _, s := range stats {
+		counts[s.Date.Format("2006-01-02")] += s.AccessCount
+	}
+	return counts
+}
+
+func TestDisabledPolicyKeepsEverything(t *testing.T) {
+	store := retentionStore()
+	c := policyConfig(ModeDelete, "")
+	c.AccessEventRetentionDays = 0
+
+	if _, err := New(store, c).Apply(now); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.AccessEvents) != 3 || len(store.AccessEventDaily) != 0 {
+		t.Errorf("expected nothing to change, got %d events and %d aggregates", len(store.AccessEvents), len(store.AccessEventDaily))
+	}
+}
+
+func TestDeleteKeepsAccessStats(t *testing.T) {
+	store := retentionStore()
+	before := accessCounts(t, store)
+
+	result, err := New(store, policyConfig(ModeDelete, "")).Apply(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Deleted != 2 || len(store.AccessEvents) != 1 {
+		t.Fatalf("expected the 2 old events to be deleted, got %+v", result)
+	}
+
+	after := accessCounts(t, store)
+	for day, count := range before {
+		if after[day] != count {
+			t.Errorf("expected %d swipes on %s, got %d", count, day, after[day])
+		}
+	}
+
+	// running it again doesn't count the rolled up days twice
+	if _, err := New(store, policyConfig(ModeDelete, "")).Apply(now); err != nil {
+		t.Fatal(err)
+	}
+	if again := accessCounts(t, store); again["2023-01-10"] != 2 {
+		t.Errorf("expected 2 swipes after a second run, got %d", again["2023-01-10"])
+	}
+}
+
+func TestPseudonymise(t *testing.T) {
+	store := retentionStore()
+
+	result, err := New(store, policyConfig(ModePseudonymise, "")).Apply(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Pseudonymised != 2 || len(store.AccessEvents) != 3 {
+		t.Fatalf("expected the 2 old events to be pseudonymised, got %+v", result)
+	}
+
+	first, second, recent := store.AccessEvents[0], store.AccessEvents[1], store.AccessEvents[2]
+	if first.Username != "" || first.MemberID != "" || !strings.HasPrefix(first.RFID, "anon-") {
+		t.Errorf("expected the member to be stripped, got %+v", first)
+	}
+	if first.RFID != second.RFID {
+		t.Errorf("expected the same fob to get the same pseudonym, got %s and %s", first.RFID, second.RFID)
+	}
+	if recent.Username != "bob" || recent.RFID != "222" {
+		t.Errorf("expected the recent event to be left alone, got %+v", recent)
+	}
+
+	if counts := accessCounts(t, store); counts["2023-01-10"] != 2 {
+		t.Errorf("expected 2 swipes on the old day, got %d", counts["2023-01-10"])
+	}
+}
+
+func TestPseudonymiseNeedsAKey(t *testing.T) {
+	store := retentionStore()
+	c := policyConfig(ModePseudonymise, "")
+	c.AccessSecret = "secret"
+	c.AccessEventPseudonymKey = ""
+
+	if _, err := New(store, c).Apply(now); err == nil {
+		t.Error("expected an error without a pseudonym key")
+	}
+
+	if store.AccessEvents[0].RFID != "111" {
+		t.Errorf("expected the events to be left alone, got %+v", store.AccessEvents[0])
+	}
+}
+
+func TestLateEventsAreRolledUp(t *testing.T) {
+	store := retentionStore()
+
+	if _, err := New(store, policyConfig(ModePseudonymise, "")).Apply(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// a device that was offline catches up with a swipe from a day that was already rolled up
+	late := time.Date(2023, time.January, 10, 21, 0, 0, 0, time.Local)
+	store.LogAccessEvent(models.LogMessage{Type: "access", IsKnown: "true", Username: "carol", RFID: "333", Door: "frontdoor", MemberID: "3", EventTime: late.Unix()})
+
+	if counts := accessCounts(t, store); counts["2023-01-10"] != 3 {
+		t.Fatalf("expected the late swipe to be counted before it's rolled up, got %d", counts["2023-01-10"])
+	}
+
+	if _, err := New(store, policyConfig(ModeDelete, "")).Apply(now); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.AccessEvents) != 1 {
+		t.Fatalf("expected only the recent event to be left, got %d", len(store.AccessEvents))
+	}
+	if counts := accessCounts(t, store); counts["2023-01-10"] != 3 {
+		t.Errorf("expected the late swipe to be rolled up, got %d", counts["2023-01-10"])
+	}
+}
+
+func TestArchiveBeforeDelete(t *testing.T) {
+	store := retentionStore()
+	dir := t.TempDir()
+
+	result, err := New(store, policyConfig(ModeDelete, dir)).Apply(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Archived != 2 || len(result.ArchivePath) == 0 {
+		t.Fatalf("expected 2 archived events, got %+v", result)
+	}
+
+	f, err := os.Open(result.ArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var archived []models.AccessEvent
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var e models.AccessEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatal(err)
+		}
+		archived = append(archived, e)
+	}
+
+	if len(archived) != 2 || archived[0].Username != "alice" {
+		t.Errorf("expected alice's 2 swipes in the archive, got %+v", archived)
+	}
+
+	// nothing left to archive, so no new file is written
+	result, err = New(store, policyConfig(ModeDelete, dir)).Apply(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Archived != 0 || len(result.ArchivePath) > 0 {
+		t.Errorf("expected nothing to be archived, got %+v", result)
+	}
+
+	files, _ := os.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("expected 1 archive file, got %d", len(files))
+	}
+}
+
+func TestUnknownModeDoesNothing(t *testing.T) {
+	store := retentionStore()
+
+	if _, err := New(store, policyConfig("shred", "")).Apply(now); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+
+	if len(store.AccessEvents) != 3 {
+		t.Errorf("expected every event to be kept, got %d", len(store.AccessEvents))
+	}
+}
+
+func TestUnknownFobsArentCounted(t *testing.T) {
+	store := retentionStore()
+	old := time.Date(2023, time.January, 10, 20, 0, 0, 0, time.Local)
+	store.LogAccessEvent(models.LogMessage{Type: "access", IsKnown: "false", RFID: "999", Door: "frontdoor", EventTime: old.Unix()})
+
+	if counts := accessCounts(t, store); counts["2023-01-10"] != 2 {
+		t.Fatalf("expected the unknown swipe not to be counted, got %d", counts["2023-01-10"])
+	}
+
+	if _, err := New(store, policyConfig(ModeDelete, "")).Apply(now); err != nil {
+		t.Fatal(err)
+	}
+
+	if counts := accessCounts(t, store); counts["2023-01-10"] != 2 {
+		t.Errorf("expected the unknown swipe not to be rolled up, got %d", counts["2023-01-10"])
+	}
+}
@@ -0,0 +1,225 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency of a recurrence rule
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+// maxIterations stops a bad rule from looping forever
+const maxIterations = 100000
+
+var byDay = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// RRule is the subset of the iCalendar RRULE that we support
+//
+//	FREQ=DAILY|WEEKLY|MONTHLY;INTERVAL=n;BYDAY=MO,WE;UNTIL=20240101T000000Z;COUNT=n
+type RRule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []time.Weekday
+	Until    time.Time
+	Count    int
+}
+
+// Occurrence is a single instance of a recurring window
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// ParseRRule parses a recurrence rule. An empty rule is valid and means the window happens once
+func ParseRRule(rule string) (*RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	if len(rule) == 0 {
+		return nil, nil
+	}
+
+	r := &RRule{Interval: 1}
+
+	for _, part := range strings.Split(rule, ";") {
+		key, value, found := strings.Cut(part, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid rule part: %q", part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = Frequency(strings.ToUpper(value))
+			if r.Freq != Daily && r.Freq != Weekly && r.Freq != Monthly {
+				return nil, fmt.Errorf("unsupported frequency: %s", value)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid interval: %s", value)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				weekday, ok := byDay[strings.ToUpper(d)]
+				if !ok {
+					return nil, fmt.Errorf("invalid day: %s", d)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = until
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid count: %s", value)
+			}
+			r.Count = count
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	if len(r.Freq) == 0 {
+		return nil, errors.New("FREQ is required")
+	}
+
+	if len(r.ByDay) > 0 && r.Freq != Weekly {
+		return nil, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		loc := time.Local
+		if strings.HasSuffix(layout, "Z") {
+			loc = time.UTC
+		}
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid until: %s", value)
+}
+
+// Occurrences returns the instances of a window that overlap [from, to)
+//
+//	start and end are the first instance. A nil rule means the window only happens once
+func Occurrences(start time.Time, end time.Time, rule *RRule, from time.Time, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+	duration := end.Sub(start)
+
+	add := func(s time.Time) {
+		if s.Before(to) && s.Add(duration).After(from) {
+			occurrences = append(occurrences, Occurrence{Start: s, End: s.Add(duration)})
+		}
+	}
+
+	if rule == nil {
+		add(start)
+		return occurrences
+	}
+
+	count := 0
+	for i := 0; i < maxIterations; i++ {
+		for _, s := range rule.period(start, i) {
+			if s.Before(start) {
+				continue
+			}
+
+			if !s.Before(to) || (!rule.Until.IsZero() && s.After(rule.Until)) {
+				return occurrences
+			}
+
+			count++
+			if rule.Count > 0 && count > rule.Count {
+				return occurrences
+			}
+
+			add(s)
+		}
+	}
+
+	return occurrences
+}
+
+// period returns the candidate starts in the i-th period of the rule, in order
+func (r RRule) period(start time.Time, i int) []time.Time {
+	n := i * r.Interval
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{start.AddDate(0, 0, n)}
+	case Monthly:
+		s := start.AddDate(0, n, 0)
+		// skip months that don't have the day e.g. the 31st
+		if s.Day() != start.Day() {
+			return nil
+		}
+		return []time.Time{s}
+	}
+
+	days := r.ByDay
+	if len(days) == 0 {
+		days = []time.Weekday{start.Weekday()}
+	}
+
+	// weeks start on monday like they do in iCalendar
+	weekStart := start.AddDate(0, 0, -((int(start.Weekday())+6)%7)+7*n)
+
+	var starts []time.Time
+	for offset := 0; offset < 7; offset++ {
+		day := weekStart.AddDate(0, 0, offset)
+		for _, d := range days {
+			if day.Weekday() == d {
+				starts = append(starts, day)
+			}
+		}
+	}
+
+	return starts
+}
+
+// Transitions returns the occurrences that start, and the occurrences that end, in (from, to]
+func Transitions(start time.Time, end time.Time, rule *RRule, from time.Time, to time.Time) (starting []Occurrence, ending []Occurrence) {
+	for _, o := range Occurrences(start, end, rule, from, to.Add(time.Nanosecond)) {
+		if o.Start.After(from) && !o.Start.After(to) {
+			starting = append(starting, o)
+		}
+		if o.End.After(from) && !o.End.After(to) {
+			ending = append(ending, o)
+		}
+	}
+	return starting, ending
+}
+
+// Active reports whether an occurrence is in progress at t
+func Active(start time.Time, end time.Time, rule *RRule, t time.Time) bool {
+	for _, o := range Occurrences(start, end, rule, t, t.Add(time.Nanosecond)) {
+		if !o.Start.After(t) && o.End.After(t) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRRule(t *testing.T) {
+	tests := []struct {
+		TestName string
+		rule     string
+		wantErr  bool
+		wantNil  bool
+	}{
+		{TestName: "empty rule happens once", rule: "", wantNil: true},
+		{TestName: "weekly by day", rule: "FREQ=WEEKLY;BYDAY=TU,TH"},
+		{TestName: "accepts the RRULE prefix", rule: "RRULE:FREQ=DAILY;COUNT=3"},
+		{TestName: "until in utc", rule: "FREQ=MONTHLY;UNTIL=20241231T000000Z"},
+		{TestName: "requires a frequency", rule: "INTERVAL=2", wantErr: true},
+		{TestName: "rejects yearly", rule: "FREQ=YEARLY", wantErr: true},
+		{TestName: "rejects a bad day", rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{TestName: "rejects by day on daily", rule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{TestName: "rejects a zero interval", rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			r, err := ParseRRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, wantErr %t", err, tt.wantErr)
+			}
+			if !tt.wantErr && (r == nil) != tt.wantNil {
+				t.Errorf("got rule %v, wantNil %t", r, tt.wantNil)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	// 2024-01-02 is a Tuesday
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.Local)
+	end := start.Add(3 * time.Hour)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		TestName  string
+		rule      string
+		from      time.Time
+		wantCount int
+		wantFirst time.Time
+		wantLast  time.Time
+	}{
+		{
+			TestName:  "once",
+			from:      from,
+			wantCount: 1,
+			wantFirst: start,
+			wantLast:  start,
+		},
+		{
+			TestName:  "every tuesday and thursday",
+			rule:      "FREQ=WEEKLY;BYDAY=TU,TH",
+			from:      from,
+			wantCount: 9,
+			wantFirst: start,
+			wantLast:  time.Date(2024, 1, 30, 19, 0, 0, 0, time.Local),
+		},
+		{
+			TestName:  "every other week",
+			rule:      "FREQ=WEEKLY;INTERVAL=2",
+			from:      from,
+			wantCount: 3,
+			wantFirst: start,
+			wantLast:  time.Date(2024, 1, 30, 19, 0, 0, 0, time.Local),
+		},
+		{
+			TestName:  "daily with a count",
+			rule:      "FREQ=DAILY;COUNT=3",
+			from:      from,
+			wantCount: 3,
+			wantFirst: start,
+			wantLast:  time.Date(2024, 1, 4, 19, 0, 0, 0, time.Local),
+		},
+		{
+			TestName:  "daily until",
+			rule:      "FREQ=DAILY;UNTIL=20240105",
+			from:      from,
+			wantCount: 3,
+			wantFirst: start,
+			wantLast:  time.Date(2024, 1, 4, 19, 0, 0, 0, time.Local),
+		},
+		{
+			TestName:  "count is from the first instance, not from the range",
+			rule:      "FREQ=DAILY;COUNT=3",
+			from:      time.Date(2024, 1, 4, 0, 0, 0, 0, time.Local),
+			wantCount: 1,
+			wantFirst: time.Date(2024, 1, 4, 19, 0, 0, 0, time.Local),
+			wantLast:  time.Date(2024, 1, 4, 19, 0, 0, 0, time.Local),
+		},
+		{
+			TestName:  "includes an instance that is in progress",
+			rule:      "FREQ=DAILY",
+			from:      time.Date(2024, 1, 10, 20, 0, 0, 0, time.Local),
+			wantCount: 22,
+			wantFirst: time.Date(2024, 1, 10, 19, 0, 0, 0, time.Local),
+			wantLast:  time.Date(2024, 1, 31, 19, 0, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			rule, err := ParseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := Occurrences(start, end, rule, tt.from, to)
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d occurrences want %d: %v", len(got), tt.wantCount, got)
+			}
+
+			if !got[0].Start.Equal(tt.wantFirst) || !got[len(got)-1].Start.Equal(tt.wantLast) {
+				t.Errorf("got %s - %s want %s - %s", got[0].Start, got[len(got)-1].Start, tt.wantFirst, tt.wantLast)
+			}
+
+			if !got[0].End.Equal(got[0].Start.Add(3 * time.Hour)) {
+				t.Errorf("occurrence should last as long as the first instance")
+			}
+		})
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	start := time.Date(2024, 1, 2, 19, 0, 0, 0, time.Local)
+	end := start.Add(time.Hour)
+	rule, _ := ParseRRule("FREQ=DAILY")
+
+	tests := []struct {
+		TestName     string
+		from         time.Time
+		to           time.Time
+		wantStarting int
+		wantEnding   int
+		wantActive   bool
+	}{
+		{
+			TestName:     "window starting",
+			from:         time.Date(2024, 1, 3, 18, 59, 0, 0, time.Local),
+			to:           time.Date(2024, 1, 3, 19, 0, 0, 0, time.Local),
+			wantStarting: 1,
+			wantActive:   true,
+		},
+		{
+			TestName:   "window ending",
+			from:       time.Date(2024, 1, 3, 19, 59, 0, 0, time.Local),
+			to:         time.Date(2024, 1, 3, 20, 0, 0, 0, time.Local),
+			wantEnding: 1,
+		},
+		{
+			TestName:   "in the middle of a window",
+			from:       time.Date(2024, 1, 3, 19, 29, 0, 0, time.Local),
+			to:         time.Date(2024, 1, 3, 19, 30, 0, 0, time.Local),
+			wantActive: true,
+		},
+		{
+			TestName: "outside of a window",
+			from:     time.Date(2024, 1, 3, 12, 0, 0, 0, time.Local),
+			to:       time.Date(2024, 1, 3, 12, 1, 0, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			starting, ending := Transitions(start, end, rule, tt.from, tt.to)
+			if len(starting) != tt.wantStarting || len(ending) != tt.wantEnding {
+				t.Errorf("got %d starting and %d ending want %d and %d", len(starting), len(ending), tt.wantStarting, tt.wantEnding)
+			}
+
+			if got := Active(start, end, rule, tt.to); got != tt.wantActive {
+				t.Errorf("got active %t want %t", got, tt.wantActive)
+			}
+		})
+	}
+}
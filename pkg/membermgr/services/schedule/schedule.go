@@ -0,0 +1,81 @@
+// the schedule package decides whether a member may use a resource at a given time
+package schedule
+
+import (
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+// ForLevel returns the schedules that apply to a member level
+func ForLevel(schedules []models.AccessSchedule, level uint8) []models.AccessSchedule {
+	var applicable []models.AccessSchedule
+	for _, s := range schedules {
+		if s.TierID != 0 && s.TierID != level {
+			continue
+		}
+		applicable = append(applicable, s)
+	}
+	return applicable
+}
+
+// Allows reports whether a member of the given level may use a resource at time t
+//
+//	a level without any schedules is not restricted
+func Allows(schedules []models.AccessSchedule, level uint8, t time.Time) bool {
+	applicable := ForLevel(schedules, level)
+	if len(applicable) == 0 {
+		return true
+	}
+
+	for _, s := range applicable {
+		if contains(s, t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Windows converts the schedules that apply to a member level to the format devices understand
+func Windows(schedules []models.AccessSchedule, level uint8) []models.TimeWindow {
+	var windows []models.TimeWindow
+	for _, s := range ForLevel(schedules, level) {
+		windows = append(windows, s.TimeWindow())
+	}
+	return windows
+}
+
+func contains(s models.AccessSchedule, t time.Time) bool {
+	start, err := models.ParseClock(s.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := models.ParseClock(s.EndTime)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	today := int(t.Weekday())
+	yesterday := (today + 6) % 7
+
+	if start < end {
+		return hasDay(s.Weekdays, today) && minute >= start && minute < end
+	}
+
+	// the window runs past midnight, so it belongs to the day it started on
+	if hasDay(s.Weekdays, today) && minute >= start {
+		return true
+	}
+	return hasDay(s.Weekdays, yesterday) && minute < end
+}
+
+func hasDay(days []int, day int) bool {
+	for _, d := range days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+)
+
+var weekdays = []int{1, 2, 3, 4, 5}
+
+func TestAllows(t *testing.T) {
+	schedules := []models.AccessSchedule{
+		{ResourceID: "woodshop", TierID: uint8(models.Classic), Weekdays: weekdays, StartTime: "18:00", EndTime: "22:00"},
+		{ResourceID: "woodshop", TierID: uint8(models.Standard), Weekdays: []int{5}, StartTime: "22:00", EndTime: "02:00"},
+	}
+
+	// 2024-01-01 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		TestName string
+		level    models.MemberLevel
+		at       time.Time
+		want     bool
+	}{
+		{"classic inside weekday window", models.Classic, monday(19, 30), true},
+		{"classic before weekday window", models.Classic, monday(17, 59), false},
+		{"classic at the end of the window", models.Classic, monday(22, 0), false},
+		{"classic on the weekend", models.Classic, monday(19, 0).AddDate(0, 0, 5), false},
+		{"premium has no schedule", models.Premium, monday(3, 0), true},
+		{"standard friday night", models.Standard, monday(23, 0).AddDate(0, 0, 4), true},
+		{"standard past midnight into saturday", models.Standard, monday(1, 30).AddDate(0, 0, 5), true},
+		{"standard early friday morning", models.Standard, monday(1, 30).AddDate(0, 0, 4), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			got := Allows(schedules, uint8(tt.level), tt.at)
+			if got != tt.want {
+				t.Errorf("got %t want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsEveryTier(t *testing.T) {
+	schedules := []models.AccessSchedule{
+		{ResourceID: "frontdoor", Weekdays: []int{0, 1, 2, 3, 4, 5, 6}, StartTime: "00:00", EndTime: "24:00"},
+	}
+
+	at := time.Date(2024, 1, 1, 23, 59, 0, 0, time.Local)
+	if !Allows(schedules, uint8(models.Classic), at) {
+		t.Errorf("expected an all day schedule to allow access at %s", at)
+	}
+
+	if len(Windows(schedules, uint8(models.Premium))) != 1 {
+		t.Errorf("expected a schedule without a tier to apply to every tier")
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	config "github.com/HackRVA/memberserver/configs"
 	"github.com/HackRVA/memberserver/pkg/membermgr/datastore"
 	"github.com/HackRVA/memberserver/pkg/membermgr/integrations"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services"
 	"github.com/HackRVA/memberserver/pkg/membermgr/services/mail"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/retention"
 	"github.com/HackRVA/memberserver/pkg/paypal"
 )
 
@@ -23,6 +26,7 @@ type JobController struct {
 	paymentProvider integrations.PaymentProvider
 	member          services.Member
 	logger          logger
+	openWindows     *openWindowDoors
 }
 
 func New(db datastore.DataStore, logger logger, member services.Member, resource services.Resource) JobController {
@@ -37,6 +41,7 @@ func New(db datastore.DataStore, logger logger, member services.Member, resource
 		DataStore:       db,
 		member:          member,
 		logger:          logger,
+		openWindows:     newOpenWindowDoors(),
 	}
 }
 
@@ -78,14 +83,12 @@ func (j JobController) CheckResourceInit() {
 
 	resources := j.DataStore.GetResources()
 
-	config, _ := config.Load()
+	// new devices announce themselves on the discovery topic and wait to be approved
+	j.resourceManager.SubscribeDiscovery()
 
 	// on startup we will subscribe to resources and publish an initial status check
 	for _, r := range resources {
-		j.resourceManager.MQTT().Subscribe(config.MQTTBrokerAddress, r.Name+"/send", j.resourceManager.ReceiveHandler)
-		j.resourceManager.MQTT().Subscribe(config.MQTTBrokerAddress, r.Name+"/result", j.resourceManager.HealthCheckHandler)
-		j.resourceManager.MQTT().Subscribe(config.MQTTBrokerAddress, r.Name+"/sync", j.resourceManager.OnHeartBeatHandler)
-		j.resourceManager.MQTT().Subscribe(config.MQTTBrokerAddress, r.Name+"/cleanup", j.resourceManager.OnRemoveInvalidRequestHandler)
+		j.resourceManager.SubscribeResource(r)
 		j.resourceManager.CheckStatus(r)
 	}
 }
@@ -178,7 +181,129 @@ func (j JobController) UpdateResources() {
 	j.resourceManager.UpdateResources()
 }
 
+// RevokeExpiredCertifications removes access to resources that require a certification
+// from members whose certification has expired, and lets them know
+func (j JobController) RevokeExpiredCertifications() {
+	j.logger.Infof("[scheduled-job] revoking access for expired certifications")
+
+	expired, err := j.DataStore.GetExpiredCertifiedAccess()
+	if err != nil {
+		j.logger.Errorf("error getting expired certifications: %s", err)
+		return
+	}
+
+	mailer := mail.NewMailer(j.DataStore, j.mailAPI, j.config)
+
+	for _, e := range expired {
+		j.logger.Infof("[scheduled-job] %s certification expired for %s, removing access to %s", e.CertificationName, e.Email, e.ResourceName)
+
+		if err := j.DataStore.RemoveUserFromResource(e.Email, e.ResourceID); err != nil {
+			j.logger.Errorf("error removing %s from %s: %s", e.Email, e.ResourceName, err)
+			continue
+		}
+
+		j.resourceManager.RemoveMember(e.MemberAccess)
+
+		mailer.SendCommunication(mail.CertificationExpired, e.Email, struct {
+			Name          string
+			Certification string
+			Resource      string
+		}{
+			Name:          e.Name,
+			Certification: e.CertificationName,
+			Resource:      e.ResourceName,
+		})
+	}
+}
+
+// ApplyAccessEventRetention rolls up old access events into daily counts,
+// archives them if an archive directory is set and then deletes or pseudonymises them
+func (j JobController) ApplyAccessEventRetention() {
+	policy := retention.New(j.DataStore, j.config)
+	if !policy.Enabled() {
+		return
+	}
+
+	j.logger.Infof("[scheduled-job] applying access event retention")
+
+	result, err := policy.Apply(time.Now())
+	if err != nil {
+		j.logger.Errorf("error applying access event retention: %s", err)
+		return
+	}
+
+	detail := fmt.Sprintf("events before %s: %d archived, %d deleted, %d pseudonymised",
+		result.Cutoff.Format("2006-01-02"), result.Archived, result.Deleted, result.Pseudonymised)
+	if len(result.ArchivePath) > 0 {
+		detail += " (archived to " + result.ArchivePath + ")"
+	}
+
+	j.logger.Infof("[scheduled-job] access event retention: %s", detail)
+
+	if result.Archived == 0 && result.Deleted == 0 && result.Pseudonymised == 0 {
+		return
+	}
+
+	err = j.DataStore.LogAuditEvent(models.AuditEntry{
+		Actor:  models.AuditActorScheduler,
+		Action: models.AuditActionAccessEventRetention,
+		Detail: detail,
+	})
+	if err != nil {
+		j.logger.Errorf("error writing audit log: %s", err)
+	}
+}
+
 func (j JobController) UpdateMemberCounts() {
 	j.logger.Infof("[scheduled-job] updating member counts")
 	j.DataStore.UpdateMemberCounts()
 }
+
+// ProcessTemporaryGrants pushes grants that have started and revokes grants that have expired
+//
+//	every grant that's in effect is pushed, not just the ones that started since the last run,
+//	so grants that started while the server was down still reach the devices; pushing a member twice is harmless
+func (j JobController) ProcessTemporaryGrants() {
+	active, err := j.DataStore.GetActiveScheduledResourceGrants()
+	if err != nil {
+		j.logger.Errorf("error getting temporary grants: %s", err)
+	}
+
+	pushed := map[string]bool{}
+	for _, g := range active {
+		if pushed[g.Email] {
+			continue
+		}
+		pushed[g.Email] = true
+
+		j.logger.Debugf("[scheduled-job] temporary access to %s is in effect for %s", g.ResourceName, g.Email)
+		j.resourceManager.PushOne(models.Member{Email: g.Email})
+	}
+
+	expired, err := j.DataStore.GetExpiredResourceGrants()
+	if err != nil {
+		j.logger.Errorf("error getting expired grants: %s", err)
+		return
+	}
+
+	for _, g := range expired {
+		j.logger.Infof("[scheduled-job] temporary access to %s expired for %s", g.ResourceName, g.Email)
+
+		j.resourceManager.RemoveMember(g)
+
+		if err := j.DataStore.RemoveUserFromResource(g.Email, g.ResourceID); err != nil {
+			j.logger.Errorf("error removing %s from %s: %s", g.Email, g.ResourceName, err)
+			continue
+		}
+
+		err := j.DataStore.LogAuditEvent(models.AuditEntry{
+			Actor:  models.AuditActorScheduler,
+			Action: models.AuditActionGrantExpired,
+			Target: g.Email,
+			Detail: fmt.Sprintf("access to %s expired at %s", g.ResourceName, g.ValidUntil.Format(time.RFC3339)),
+		})
+		if err != nil {
+			j.logger.Errorf("error writing audit log: %s", err)
+		}
+	}
+}
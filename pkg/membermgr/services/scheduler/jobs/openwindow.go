@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services/schedule"
+)
+
+// doorState is what the open windows want a door to be doing
+type doorState int
+
+const (
+	// doorUnknown - the door hasn't been reconciled since the server started
+	doorUnknown doorState = iota
+	doorLocked
+	doorOpened
+	doorHeldOpen
+)
+
+// openWindowDoors remembers what ProcessOpenWindows last told each door to do
+//
+//	it is shared between copies of the JobController
+type openWindowDoors struct {
+	mu    sync.Mutex
+	state map[string]doorState
+}
+
+func newOpenWindowDoors() *openWindowDoors {
+	return &openWindowDoors{state: map[string]doorState{}}
+}
+
+// desiredDoorStates works out what every door with an open window should be doing at t
+//
+//	a hold-open window wins over one that only opens the door when it starts
+func (j JobController) desiredDoorStates(windows []models.OpenWindow, t time.Time) map[string]doorState {
+	desired := map[string]doorState{}
+
+	for _, w := range windows {
+		rule, err := schedule.ParseRRule(w.RRule)
+		if err != nil {
+			j.logger.Errorf("invalid recurrence rule on open window %s: %s", w.Title, err)
+			continue
+		}
+
+		if _, ok := desired[w.ResourceID]; !ok {
+			desired[w.ResourceID] = doorLocked
+		}
+
+		if !schedule.Active(w.Start, w.End, rule, t) {
+			continue
+		}
+
+		if w.HoldOpen {
+			desired[w.ResourceID] = doorHeldOpen
+			continue
+		}
+		if desired[w.ResourceID] != doorHeldOpen {
+			desired[w.ResourceID] = doorOpened
+		}
+	}
+
+	return desired
+}
+
+// ProcessOpenWindows opens and locks doors to match the open windows that are in progress
+//
+//	the state each door should be in is worked out from scratch every time, and only doors that
+//	aren't already in that state are sent a command. After a restart every door is sent its state,
+//	so a window that started or ended while the server was down still takes effect.
+func (j JobController) ProcessOpenWindows() {
+	windows, err := j.DataStore.GetOpenWindows("")
+	if err != nil {
+		j.logger.Errorf("error getting open windows: %s", err)
+		return
+	}
+
+	desired := j.desiredDoorStates(windows, time.Now())
+
+	j.openWindows.mu.Lock()
+	defer j.openWindows.mu.Unlock()
+
+	// a door whose windows were deleted while it was held open still needs to be locked
+	for resourceID, state := range j.openWindows.state {
+		if _, ok := desired[resourceID]; !ok && state != doorLocked {
+			desired[resourceID] = doorLocked
+		}
+	}
+
+	for resourceID, want := range desired {
+		have := j.openWindows.state[resourceID]
+		if have == want {
+			continue
+		}
+
+		r, err := j.DataStore.GetResourceByID(resourceID)
+		if err != nil {
+			j.logger.Errorf("error getting resource for open window: %s", err)
+			continue
+		}
+
+		switch {
+		case want == doorHeldOpen:
+			j.logger.Infof("[scheduled-job] holding %s open for an open window", r.Name)
+			j.resourceManager.HoldOpen(r)
+		case want == doorOpened && have != doorHeldOpen && j.lockedDown(resourceID):
+			j.logger.Infof("[scheduled-job] %s is locked down, not opening it for an open window", r.Name)
+		case want == doorOpened && have != doorHeldOpen:
+			j.logger.Infof("[scheduled-job] opening %s for an open window", r.Name)
+			j.resourceManager.Open(r)
+		default:
+			j.logger.Infof("[scheduled-job] no open window is holding %s open, locking it", r.Name)
+			j.resourceManager.Release(r)
+		}
+
+		j.openWindows.state[resourceID] = want
+	}
+}
+
+// lockedDown is true while a door mode has the resource locked down
+//
+//	a door that can't be checked is treated as locked down, so an open window doesn't open it by mistake
+func (j JobController) lockedDown(resourceID string) bool {
+	modes, err := j.DataStore.GetDoorModes()
+	if err != nil {
+		j.logger.Errorf("error getting door modes for an open window: %s", err)
+		return true
+	}
+
+	return models.EffectiveDoorMode(modes, resourceID) == models.DoorModeLockdown
+}
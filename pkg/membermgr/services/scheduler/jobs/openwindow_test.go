@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/membermgr/datastore/in_memory"
+	"github.com/HackRVA/memberserver/pkg/membermgr/models"
+	"github.com/HackRVA/memberserver/pkg/membermgr/services"
+
+	"github.com/sirupsen/logrus"
+)
+
+// doorCommands records the door commands the job sends
+type doorCommands struct {
+	services.Resource
+	sent []string
+}
+
+func (d *doorCommands) Open(r models.Resource)     { d.sent = append(d.sent, "open "+r.Name) }
+func (d *doorCommands) HoldOpen(r models.Resource) { d.sent = append(d.sent, "holdopen "+r.Name) }
+func (d *doorCommands) Release(r models.Resource)  { d.sent = append(d.sent, "lock "+r.Name) }
+
+func TestProcessOpenWindows(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.RegisterResource("classroom", "", false, "")
+	t.Cleanup(func() { delete(in_memory.Resources, "classroom") })
+
+	now := time.Now()
+	store.AddOpenWindow(models.OpenWindow{ResourceID: "classroom", Title: "class", Start: now.Add(-time.Hour), End: now.Add(time.Hour), HoldOpen: true})
+
+	doors := &doorCommands{}
+	j := JobController{DataStore: store, resourceManager: doors, logger: logrus.New(), openWindows: newOpenWindowDoors()}
+
+	// the window started before the job first ran, e.g. the server was down
+	j.ProcessOpenWindows()
+	j.ProcessOpenWindows()
+	if len(doors.sent) != 1 || doors.sent[0] != "holdopen classroom" {
+		t.Fatalf("expected the door to be held open once, got %v", doors.sent)
+	}
+
+	// the window ended between checks
+	store.OpenWindows[0].End = now.Add(-time.Minute)
+	j.ProcessOpenWindows()
+	if len(doors.sent) != 2 || doors.sent[1] != "lock classroom" {
+		t.Fatalf("expected the door to be locked, got %v", doors.sent)
+	}
+
+	// after a restart the door is sent the state it should be in
+	restarted := JobController{DataStore: store, resourceManager: doors, logger: logrus.New(), openWindows: newOpenWindowDoors()}
+	restarted.ProcessOpenWindows()
+	if len(doors.sent) != 3 || doors.sent[2] != "lock classroom" {
+		t.Fatalf("expected the door to be locked after a restart, got %v", doors.sent)
+	}
+}
+
+func TestProcessOpenWindowsLockdown(t *testing.T) {
+	store := &in_memory.In_memory{}
+	store.RegisterResource("workshop", "", false, "")
+	t.Cleanup(func() { delete(in_memory.Resources, "workshop") })
+
+	now := time.Now()
+	store.AddOpenWindow(models.OpenWindow{ResourceID: "workshop", Title: "open shop", Start: now.Add(-time.Hour), End: now.Add(time.Hour)})
+	store.SetDoorMode(models.DoorMode{ResourceID: "workshop", Mode: models.DoorModeLockdown})
+
+	doors := &doorCommands{}
+	j := JobController{DataStore: store, resourceManager: doors, logger: logrus.New(), openWindows: newOpenWindowDoors()}
+
+	j.ProcessOpenWindows()
+	j.ProcessOpenWindows()
+	for _, command := range doors.sent {
+		if command == "open workshop" {
+			t.Fatalf("expected a locked down door not to be opened, got %v", doors.sent)
+		}
+	}
+}
@@ -23,6 +23,18 @@ const (
 
 	// checkIPInterval - check the IP Address daily
 	checkIPInterval = 24
+
+	// certificationExpiryInterval - revoke access for expired certifications every hour
+	certificationExpiryInterval = 1
+
+	// temporaryGrantInterval - activate and revoke temporary grants every 15 minutes
+	temporaryGrantInterval = 15
+
+	// openWindowInterval - open and lock doors for scheduled windows every minute
+	openWindowInterval = 1
+
+	// accessEventRetentionInterval - roll up and expire old access events daily
+	accessEventRetentionInterval = 24
 )
 
 type Scheduler struct{}
@@ -47,6 +59,10 @@ func (s *Scheduler) Setup(j services.Job) {
 		{interval: resourceUpdateInterval * time.Hour, initFunc: j.UpdateResources, tickFunc: j.UpdateResources},
 		{interval: checkIPInterval * time.Hour, initFunc: j.CheckIPAddressInterval, tickFunc: j.CheckIPAddressInterval},
 		{interval: updateMemberCountInterval * time.Hour, initFunc: j.UpdateMemberCounts, tickFunc: j.UpdateMemberCounts},
+		{interval: certificationExpiryInterval * time.Hour, initFunc: j.RevokeExpiredCertifications, tickFunc: j.RevokeExpiredCertifications},
+		{interval: temporaryGrantInterval * time.Minute, initFunc: j.ProcessTemporaryGrants, tickFunc: j.ProcessTemporaryGrants},
+		{interval: openWindowInterval * time.Minute, initFunc: j.ProcessOpenWindows, tickFunc: j.ProcessOpenWindows},
+		{interval: accessEventRetentionInterval * time.Hour, initFunc: j.ApplyAccessEventRetention, tickFunc: j.ApplyAccessEventRetention},
 	}
 
 	for _, task := range tasks {
@@ -2,6 +2,7 @@ package mqtt
 
 import (
 	"math/rand"
+	"sync"
 	"time"
 
 	mqtt "github.com/eclipse/paho.mqtt.golang"
@@ -11,16 +12,21 @@ import (
 type MQTTServer interface {
 	Publish(address string, topic string, payload interface{})
 	Subscribe(address string, topic string, handler mqtt.MessageHandler)
+	Unsubscribe(address string, topics ...string)
 }
 
-type server struct{}
+type server struct {
+	// subscribers holds the client that is subscribed to each topic
+	subscribers map[string]mqtt.Client
+	mu          sync.Mutex
+}
 
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
 func New() *server {
-	return &server{}
+	return &server{subscribers: map[string]mqtt.Client{}}
 }
 
 func randStringRunes(n int) string {
@@ -53,6 +59,36 @@ func (m *server) Subscribe(address string, topic string, handler mqtt.MessageHan
 		return
 	}
 	log.Debug("Connected to server\n")
+
+	m.mu.Lock()
+	previous, subscribed := m.subscribers[topic]
+	m.subscribers[topic] = client
+	m.mu.Unlock()
+
+	// only one client should be handling a topic, otherwise every message is handled twice
+	if subscribed {
+		previous.Disconnect(250)
+	}
+}
+
+// Unsubscribe - stop listening to MQTT topics
+func (m *server) Unsubscribe(address string, topics ...string) {
+	for _, topic := range topics {
+		m.mu.Lock()
+		client, subscribed := m.subscribers[topic]
+		delete(m.subscribers, topic)
+		m.mu.Unlock()
+
+		if !subscribed {
+			continue
+		}
+
+		// the client subscribes again whenever it reconnects, so it has to be disconnected as well
+		if token := client.Unsubscribe(topic); token.Wait() && token.Error() != nil {
+			log.Error(token.Error())
+		}
+		client.Disconnect(250)
+	}
 }
 
 // Publish - publish to an MQTT topic
@@ -0,0 +1,165 @@
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/HackRVA/memberserver/pkg/oidc/oidctest"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const redirectURL = "http://localhost:3000/api/auth/oidc/callback"
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.Provider) {
+	t.Helper()
+	mock := oidctest.NewProvider("memberserver", "client secret")
+	t.Cleanup(mock.Close)
+
+	return New(Config{
+		Issuer:       mock.Issuer(),
+		ClientID:     "memberserver",
+		ClientSecret: "client secret",
+		RedirectURL:  redirectURL,
+	}), mock
+}
+
+// authorize follows the provider's redirect back to us and returns the code and state
+func authorize(t *testing.T, authCodeURL string) (string, string) {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	res, err := client.Get(authCodeURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	callback, err := url.Parse(res.Header.Get("Location"))
+	if err != nil || res.StatusCode != http.StatusFound {
+		t.Fatalf("expected a redirect back, got %s", res.Status)
+	}
+
+	return callback.Query().Get("code"), callback.Query().Get("state")
+}
+
+func TestExchange(t *testing.T) {
+	provider, mock := newTestProvider(t)
+	mock.SetUser(oidctest.User{Email: "member@example.com", EmailVerified: true})
+	ctx := context.Background()
+
+	state, nonce, verifier := NewState(), NewState(), NewVerifier()
+	authCodeURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, returnedState := authorize(t, authCodeURL)
+	if returnedState != state {
+		t.Errorf("got state %s want %s", returnedState, state)
+	}
+
+	if _, err := provider.Exchange(ctx, code, nonce, "the wrong verifier"); err == nil {
+		t.Error("expected the code to need the verifier")
+	}
+
+	code, _ = authorize(t, authCodeURL)
+	claims, err := provider.Exchange(ctx, code, nonce, verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Email != "member@example.com" || !claims.EmailVerified {
+		t.Errorf("unexpected claims %+v", claims)
+	}
+
+	if _, err := provider.Exchange(ctx, code, nonce, verifier); err == nil {
+		t.Error("expected a code to only work once")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	provider, mock := newTestProvider(t)
+	other := oidctest.NewProvider("memberserver", "client secret")
+	t.Cleanup(other.Close)
+
+	now := time.Now()
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":            mock.Issuer(),
+			"sub":            "1234",
+			"aud":            []string{"memberserver", "something else"},
+			"iat":            now.Unix(),
+			"exp":            now.Add(time.Hour).Unix(),
+			"nonce":          "nonce",
+			"email":          "member@example.com",
+			"email_verified": "true",
+		}
+		for k, v := range changes {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		TestName string
+		token    string
+		wantErr  bool
+	}{
+		{
+			TestName: "should accept a token with a list audience and a string email_verified",
+			token:    mock.IDToken(claims(nil)),
+		},
+		{
+			TestName: "should reject a token for another client",
+			token:    mock.IDToken(claims(jwt.MapClaims{"aud": "another client"})),
+			wantErr:  true,
+		},
+		{
+			TestName: "should reject a token from another issuer",
+			token:    mock.IDToken(claims(jwt.MapClaims{"iss": other.Issuer()})),
+			wantErr:  true,
+		},
+		{
+			TestName: "should reject an expired token",
+			token:    mock.IDToken(claims(jwt.MapClaims{"exp": now.Add(-time.Hour).Unix()})),
+			wantErr:  true,
+		},
+		{
+			TestName: "should reject a replayed token",
+			token:    mock.IDToken(claims(jwt.MapClaims{"nonce": "another nonce"})),
+			wantErr:  true,
+		},
+		{
+			TestName: "should reject a token signed by someone else",
+			token:    other.IDToken(claims(nil)),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			_, err := provider.verify(context.Background(), tt.token, "nonce")
+			if tt.wantErr && err == nil {
+				t.Error("expected an error")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDiscoveryChecksIssuer(t *testing.T) {
+	_, mock := newTestProvider(t)
+	provider := New(Config{Issuer: mock.Issuer() + "/", ClientID: "memberserver"})
+
+	if _, err := provider.AuthCodeURL(context.Background(), "state", "nonce", "verifier"); err == nil {
+		t.Error("expected an issuer that doesn't match discovery to be rejected")
+	}
+}
@@ -0,0 +1,48 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRequestAge - slack recommends rejecting requests older than 5 minutes to prevent replays
+const maxRequestAge = 5 * time.Minute
+
+// VerifyRequest checks that a request came from slack using the app's signing secret
+//
+//	https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifyRequest(signingSecret string, header http.Header, body []byte, now time.Time) error {
+	if len(signingSecret) == 0 {
+		return errors.New("slack signing secret isn't set")
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid slack request timestamp")
+	}
+
+	age := now.Sub(time.Unix(ts, 0))
+	if age > maxRequestAge || age < -maxRequestAge {
+		return errors.New("slack request is too old")
+	}
+
+	if !hmac.Equal([]byte(Sign(signingSecret, timestamp, body)), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("invalid slack signature")
+	}
+
+	return nil
+}
+
+// Sign computes the signature slack sends with a request
+func Sign(signingSecret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
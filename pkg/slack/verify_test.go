@@ -0,0 +1,63 @@
+package slack
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyRequest(t *testing.T) {
+	now := time.Now()
+	body := []byte("command=%2Fdoor&text=lockdown")
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	stale := strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10)
+
+	tests := []struct {
+		TestName  string
+		secret    string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{
+			TestName:  "should accept a signed request",
+			secret:    "secret",
+			timestamp: timestamp,
+			signature: Sign("secret", timestamp, body),
+		},
+		{
+			TestName:  "should reject a bad signature",
+			secret:    "secret",
+			timestamp: timestamp,
+			signature: Sign("wrong", timestamp, body),
+			wantErr:   true,
+		},
+		{
+			TestName:  "should reject an old request",
+			secret:    "secret",
+			timestamp: stale,
+			signature: Sign("secret", stale, body),
+			wantErr:   true,
+		},
+		{
+			TestName:  "should reject everything without a secret",
+			timestamp: timestamp,
+			signature: Sign("", timestamp, body),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("X-Slack-Request-Timestamp", tt.timestamp)
+			header.Set("X-Slack-Signature", tt.signature)
+
+			err := VerifyRequest(tt.secret, header, body, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA1 key from the RFC's test vectors
+var rfc6238Secret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the RFC's codes are 8 digits, ours are the last 6 of them
+	tests := []struct {
+		TestName string
+		unix     int64
+		expected string
+	}{
+		{"should match the rfc at 59", 59, "287082"},
+		{"should match the rfc at 1111111109", 1111111109, "081804"},
+		{"should match the rfc at 1234567890", 1234567890, "005924"},
+		{"should match the rfc at 2000000000", 2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			code, err := Code(rfc6238Secret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code != tt.expected {
+				t.Errorf("got %s want %s", code, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret := NewSecret()
+	now := time.Now()
+	current, _ := Code(secret, Step(now))
+	previous, _ := Code(secret, Step(now)-1)
+	stale, _ := Code(secret, Step(now)-3)
+
+	tests := []struct {
+		TestName string
+		code     string
+		valid    bool
+		step     int64
+	}{
+		{"should accept the current code", current, true, Step(now)},
+		{"should accept a code with spaces", current[:3] + " " + current[3:], true, Step(now)},
+		{"should allow for a slow clock", previous, true, Step(now) - 1},
+		{"should reject an old code", stale, false, 0},
+		{"should reject a short code", current[:5], false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.TestName, func(t *testing.T) {
+			step, valid := Validate(secret, tt.code, now)
+			if valid != tt.valid || step != tt.step {
+				t.Errorf("got %d %t want %d %t", step, valid, tt.step, tt.valid)
+			}
+		})
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("HackRVA", "admin@test.com", "SECRET")
+	if !strings.HasPrefix(uri, "otpauth://totp/HackRVA:admin@test.com?") || !strings.Contains(uri, "secret=SECRET") || !strings.Contains(uri, "issuer=HackRVA") {
+		t.Errorf("unexpected uri %s", uri)
+	}
+}
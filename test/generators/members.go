@@ -36,6 +36,7 @@ func Seed(db datastore.DataStore, numMembers int) {
 	FakeMemberCounts(24, db)
 	FakeAccessEvents(50, db)
 	RegisterTestUser(db)
+	db.SetMemberRoles(TestMember().Email, []models.Role{models.RoleAdmin})
 }
 
 func FakeAccessEvents(numOfEvents int, db datastore.DataStore) {
@@ -79,15 +80,10 @@ func FakeMember() models.Member {
 
 func TestMember() models.Member {
 	return models.Member{
-		Name:  "test",
-		Email: "test@test.com",
-		Level: uint8(models.Premium),
-		RFID:  faker.Lorem().Characters(10),
-		Resources: []models.MemberResource{
-			{
-				Name: "admin",
-			},
-		},
+		Name:           "test",
+		Email:          "test@test.com",
+		Level:          uint8(models.Premium),
+		RFID:           faker.Lorem().Characters(10),
 		SubscriptionID: faker.Internet().MacAddress(),
 	}
 }
@@ -95,13 +91,14 @@ func TestMember() models.Member {
 func RegisterTestUser(db datastore.DataStore) {
 	db.RegisterUser(models.Credentials{
 		Email:    "test@test.com",
-		Password: "test",
+		Password: "memberserver-dev",
 	})
+	db.VerifyUser("test@test.com")
 }
 
 func FakeResources(db datastore.DataStore) {
-	db.RegisterResource(faker.App().Name(), string(faker.Internet().IpV4Address()), false)
-	db.RegisterResource(faker.App().Name(), string(faker.Internet().IpV4Address()), true)
+	db.RegisterResource(faker.App().Name(), string(faker.Internet().IpV4Address()), false, "")
+	db.RegisterResource(faker.App().Name(), string(faker.Internet().IpV4Address()), true, "")
 }
 
 func FakeMemberCounts(numberOfMonths int, db datastore.DataStore) {
@@ -1,5 +1,6 @@
 import { Routes } from '@angular/router';
-import { adminGuard, anonymousGuard, authGuard } from './shared/guards';
+import { anonymousGuard, authGuard, permissionGuard } from './shared/guards';
+import { Permission } from './shared/types';
 
 export const routes: Routes = [
   {
@@ -28,19 +29,22 @@ export const routes: Routes = [
       },
       {
         path: 'report',
-        canActivate: [adminGuard],
+        canActivate: [permissionGuard],
+        data: { permission: Permission.ViewReports },
         loadComponent: () =>
           import('./pages/report').then((m) => m.ReportComponent),
       },
       {
         path: 'member',
-        canActivate: [adminGuard],
+        canActivate: [permissionGuard],
+        data: { permission: Permission.ViewMembers },
         loadComponent: () =>
           import('./pages/member').then((m) => m.MemberComponent),
       },
       {
         path: 'resource',
-        canActivate: [adminGuard],
+        canActivate: [permissionGuard],
+        data: { permission: Permission.ManageResources },
         loadComponent: () =>
           import('./pages/resource').then((m) => m.ResourceComponent),
       },
@@ -2,6 +2,7 @@ import { Component } from '@angular/core';
 import { MatButtonModule } from '@angular/material/button';
 import { MatSnackBar, MatSnackBarModule } from '@angular/material/snack-bar';
 import { AuthService } from '@md-shared/services/auth.service';
+import { Permission, hasPermission } from '@md-shared/types';
 import { MinionComponent } from '@md-shared/components/minion';
 import { HomeService } from './services';
 
@@ -14,14 +15,17 @@ import { HomeService } from './services';
   styleUrl: './home.component.scss',
 })
 export class HomeComponent {
-  isAdmin: boolean = false;
+  canOpenDoor: boolean = false;
   isProcessing: boolean = false;
   constructor(
     private readonly authService: AuthService,
     private readonly homeService: HomeService,
     private readonly snackBar: MatSnackBar
   ) {
-    this.isAdmin = this.authService.user$.getValue().isAdmin;
+    this.canOpenDoor = hasPermission(
+      this.authService.user$.getValue(),
+      Permission.OpenResources
+    );
   }
 
   openDoor(): void {
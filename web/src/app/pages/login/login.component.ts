@@ -1,5 +1,5 @@
 import { CommonModule } from '@angular/common';
-import { Component } from '@angular/core';
+import { Component, OnInit } from '@angular/core';
 import {
   FormControl,
   FormGroup,
@@ -13,9 +13,20 @@ import { MatButtonModule } from '@angular/material/button';
 import { MatIconModule } from '@angular/material/icon';
 import { MatSnackBar, MatSnackBarModule } from '@angular/material/snack-bar';
 import { AuthService, LocalStorageService } from '@md-shared/services';
-import { AuthResponse } from '@md-shared/types';
+import {
+  AuthResponse,
+  OIDCProvider,
+  RecoveryCodes,
+  SecondFactor,
+  TwoFactorChallenge,
+  TwoFactorEnrollment,
+} from '@md-shared/types';
+import { HttpErrorResponse, HttpStatusCode } from '@angular/common/http';
 import { passwordMatchValidator } from './validator';
 
+// password, then a code for users with two-factor, or enrolment when it's required
+type LoginStep = 'password' | 'code' | 'enroll' | 'recovery';
+
 @Component({
   selector: 'md-login',
   standalone: true,
@@ -32,10 +43,22 @@ import { passwordMatchValidator } from './validator';
   templateUrl: './login.component.html',
   styleUrl: './login.component.scss',
 })
-export class LoginComponent {
+export class LoginComponent implements OnInit {
   showLoginForm: boolean = true;
   hasFailed: boolean = false;
   hide: boolean = true;
+  oidcProvider: OIDCProvider = { enabled: false, name: '' };
+  step: LoginStep = 'password';
+  useRecoveryCode: boolean = false;
+  enrollment: TwoFactorEnrollment = null;
+  qrCodeUrl: string = null;
+  // set when the server emails a link to confirm enrolling before it starts
+  enrollmentEmailed: boolean = false;
+  recoveryCodes: string[] = [];
+
+  codeFormGroup: FormGroup = new FormGroup({
+    code: new FormControl<string>(null, [Validators.required]),
+  });
 
   loginFormGroup: FormGroup = new FormGroup({
     email: new FormControl<string>(null, [
@@ -63,29 +86,143 @@ export class LoginComponent {
     private readonly snackBar: MatSnackBar
   ) {}
 
+  ngOnInit(): void {
+    // signing in with the oidc provider redirects back here with the token in the fragment
+    const token: string = new URLSearchParams(
+      window.location.hash.substring(1)
+    ).get('token');
+
+    if (token) {
+      this.localStorageService.upsert<string>(
+        this.authService.getSessionKey(),
+        token
+      );
+      window.location.replace('/');
+      return;
+    }
+
+    this.authService.oidcProvider().subscribe({
+      next: (provider: OIDCProvider) => (this.oidcProvider = provider),
+    });
+  }
+
+  oidcLogin(): void {
+    window.location.href = this.authService.oidcLoginUrl();
+  }
+
   toggleForm(): void {
     this.showLoginForm = !this.showLoginForm;
   }
 
   login(): void {
-    this.authService.login(this.loginFormGroup.value).subscribe({
-      next: (response: AuthResponse) => {
-        if (response) {
-          this.localStorageService.upsert<string>(
-            this.authService.getSessionKey(),
-            response.token
-          );
-          window.location.reload();
-        }
+    this.authService
+      .login(this.loginFormGroup.value, this.secondFactor())
+      .subscribe({
+        next: (response: AuthResponse) => {
+          if (response) {
+            this.localStorageService.upsert<string>(
+              this.authService.getSessionKey(),
+              response.token
+            );
+            window.location.reload();
+          }
+        },
+        error: (error: HttpErrorResponse) => {
+          if (error.status === HttpStatusCode.TooManyRequests) {
+            const form: FormGroup =
+              this.step === 'code' ? this.codeFormGroup : this.loginFormGroup;
+            form.setErrors({ apiError: this.tooManyAttempts(error) });
+            return;
+          }
+
+          const challenge: TwoFactorChallenge =
+            error.status === HttpStatusCode.Unauthorized ? error.error : null;
+
+          if (challenge?.setupRequired) {
+            this.startEnrollment();
+            return;
+          }
+
+          if (challenge?.twoFactorRequired) {
+            if (this.step === 'code') {
+              this.codeFormGroup.setErrors({
+                apiError: "That code didn't work.",
+              });
+            }
+            this.step = 'code';
+            return;
+          }
+
+          this.loginFormGroup.setErrors({
+            apiError: 'Email and/or password are incorrect.',
+          });
+        },
+      });
+  }
+
+  toggleRecoveryCode(): void {
+    this.useRecoveryCode = !this.useRecoveryCode;
+    this.codeFormGroup.reset();
+  }
+
+  confirmTwoFactor(): void {
+    this.authService
+      .confirmTwoFactor(
+        this.loginFormGroup.value,
+        this.codeFormGroup.value.code
+      )
+      .subscribe({
+        next: (response: RecoveryCodes) => {
+          this.recoveryCodes = response.codes;
+          this.step = 'recovery';
+          this.codeFormGroup.reset();
+        },
+        error: () => {
+          this.codeFormGroup.setErrors({ apiError: "That code didn't work." });
+        },
+      });
+  }
+
+  finishEnrollment(): void {
+    this.step = 'code';
+  }
+
+  private startEnrollment(): void {
+    this.step = 'enroll';
+    this.authService.enrollTwoFactor(this.loginFormGroup.value).subscribe({
+      next: (enrollment: TwoFactorEnrollment) => {
+        this.enrollment = enrollment;
+        this.authService
+          .twoFactorQRCode(this.loginFormGroup.value)
+          .subscribe((qrCode: Blob) => {
+            this.qrCodeUrl = URL.createObjectURL(qrCode);
+          });
       },
-      error: (_) => {
-        this.loginFormGroup.setErrors({
-          apiError: 'Email and/or password are incorrect.',
-        });
+      error: (error: HttpErrorResponse) => {
+        this.enrollmentEmailed = error.status === HttpStatusCode.Forbidden;
       },
     });
   }
 
+  private tooManyAttempts(error: HttpErrorResponse): string {
+    const seconds: number = Number(error.headers.get('Retry-After'));
+    if (seconds > 60) {
+      return `Too many attempts. Try again in ${Math.ceil(
+        seconds / 60
+      )} minutes.`;
+    }
+    return `Too many attempts. Try again in ${seconds || 1} seconds.`;
+  }
+
+  private secondFactor(): SecondFactor {
+    if (this.step !== 'code') {
+      return {};
+    }
+
+    const value: string = this.codeFormGroup.value.code;
+    return this.useRecoveryCode ? { recoveryCode: value } : { code: value };
+  }
+
   register(): void {
     this.authService.register(this.registerFormGroup.value).subscribe({
       next: () => {
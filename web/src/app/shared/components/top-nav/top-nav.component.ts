@@ -7,6 +7,7 @@ import { MatIconModule } from '@angular/material/icon';
 import { MatTabsModule } from '@angular/material/tabs';
 import { MatMenuModule } from '@angular/material/menu';
 import { NavigationLink } from '../../types/navigation-link';
+import { AuthUser, Permission, hasPermission } from '../../types/auth.types';
 import { AuthService } from '../../services';
 
 @Component({
@@ -25,18 +26,30 @@ import { AuthService } from '../../services';
   styleUrl: './top-nav.component.scss',
 })
 export class TopNavComponent {
-  private _adminNav: NavigationLink[] = [
+  private _nav: NavigationLink[] = [
     { routeName: 'Home', routeLink: 'home', routeIcon: 'home' },
     { routeName: 'User', routeLink: 'user', routeIcon: 'person' },
-    { routeName: 'Reports', routeLink: 'report', routeIcon: 'show_chart' },
-    { routeName: 'Members', routeLink: 'member', routeIcon: 'people' },
-    { routeName: 'Resources', routeLink: 'resource', routeIcon: 'devices' },
+    {
+      routeName: 'Reports',
+      routeLink: 'report',
+      routeIcon: 'show_chart',
+      permission: Permission.ViewReports,
+    },
+    {
+      routeName: 'Members',
+      routeLink: 'member',
+      routeIcon: 'people',
+      permission: Permission.ViewMembers,
+    },
+    {
+      routeName: 'Resources',
+      routeLink: 'resource',
+      routeIcon: 'devices',
+      permission: Permission.ManageResources,
+    },
   ];
 
-  navLinks: NavigationLink[] = [
-    { routeName: 'Home', routeLink: 'home', routeIcon: 'home' },
-    { routeName: 'User', routeLink: 'user', routeIcon: 'person' },
-  ];
+  navLinks: NavigationLink[] = [];
 
   username: string = '';
 
@@ -55,11 +68,11 @@ export class TopNavComponent {
   }
 
   private runBeforeInit(): void {
-    this.username = this.authService.user$.getValue().email;
-    const isAdmin: boolean = this.authService.user$.getValue().isAdmin;
-
-    if (isAdmin) {
-      this.navLinks = this._adminNav;
-    }
+    const user: AuthUser = this.authService.user$.getValue();
+    this.username = user.email;
+    this.navLinks = this._nav.filter(
+      (link: NavigationLink) =>
+        !link.permission || hasPermission(user, link.permission)
+    );
   }
 }
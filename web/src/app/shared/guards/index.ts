@@ -1,3 +1,3 @@
-export * from './admin.guard';
+export * from './permission.guard';
 export * from './auth.guard';
 export * from './anonymous.guard';
@@ -7,18 +7,22 @@ import {
 import { AuthService } from '../services/auth.service';
 import { inject } from '@angular/core';
 import { map } from 'rxjs';
-import { AuthUser } from '../types/auth.types';
+import { AuthUser, Permission, hasPermission } from '../types/auth.types';
 
-export const adminGuard: CanActivateFn = (
+// permissionGuard only lets users through whose permissions include the route's `permission` data
+export const permissionGuard: CanActivateFn = (
   route: ActivatedRouteSnapshot,
   state: RouterStateSnapshot
 ) => {
   const authService: AuthService = inject(AuthService);
   const router: Router = inject(Router);
+  const permission: Permission = route.data['permission'];
 
   return authService.user$.pipe(
     map((user: AuthUser) => {
-      return user.isAdmin ? true : router.createUrlTree(['404']);
+      return hasPermission(user, permission)
+        ? true
+        : router.createUrlTree(['404']);
     })
   );
 };
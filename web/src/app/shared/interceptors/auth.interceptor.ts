@@ -9,9 +9,20 @@ import {
 import { AuthService } from '../services';
 import { inject } from '@angular/core';
 import { AuthUser } from '../types';
-import { Observable, catchError, throwError } from 'rxjs';
+import { Observable, catchError, switchMap, throwError } from 'rxjs';
 import { Router } from '@angular/router';
 
+// failing here means signing in again, refreshing wouldn't help
+const noRefreshUrls: string[] = ['/api/auth/refresh', '/api/auth/login'];
+
+const withToken = (
+  req: HttpRequest<unknown>,
+  token: string
+): HttpRequest<unknown> =>
+  req.clone({
+    headers: req.headers.set('Authorization', 'Bearer ' + token),
+  });
+
 export const authInterceptor: HttpInterceptorFn = (
   req: HttpRequest<unknown>,
   next: HttpHandlerFn
@@ -21,19 +32,32 @@ export const authInterceptor: HttpInterceptorFn = (
   const authService: AuthService = inject(AuthService);
   const auth: AuthUser = authService.user$.getValue();
 
-  if (auth.isLogin) {
-    const authRequest: HttpRequest<any> = req.clone({
-      headers: req.headers.set('Authorization', 'Bearer ' + auth.token),
-    });
+  if (auth.isLogin && !noRefreshUrls.includes(req.url)) {
+    const signOut = (error: HttpErrorResponse): Observable<never> => {
+      authService.resetAndNext();
+      router.navigate(['login']);
+      return throwError(() => error);
+    };
 
-    return next(authRequest).pipe(
+    return next(withToken(req, auth.token)).pipe(
       catchError((error: HttpErrorResponse) => {
-        if (error.status === HttpStatusCode.Unauthorized) {
-          authService.resetAndNext();
-          router.navigate(['login']);
+        if (error.status !== HttpStatusCode.Unauthorized) {
+          return throwError(() => error);
         }
 
-        return throwError(() => error);
+        // the access token is short lived, get a new one and try once more
+        return authService.refresh().pipe(
+          catchError(() => signOut(error)),
+          switchMap((token: string) =>
+            next(withToken(req, token)).pipe(
+              catchError((retryError: HttpErrorResponse) =>
+                retryError.status === HttpStatusCode.Unauthorized
+                  ? signOut(retryError)
+                  : throwError(() => retryError)
+              )
+            )
+          )
+        );
       })
     );
   }
@@ -1,11 +1,23 @@
 import { Injectable } from '@angular/core';
 import { BehaviorSubject, Observable, lastValueFrom, of } from 'rxjs';
-import { catchError, map } from 'rxjs/operators';
+import {
+  catchError,
+  finalize,
+  map,
+  shareReplay,
+  switchMap,
+  tap,
+} from 'rxjs/operators';
 import {
   AuthResponse,
   AuthUser,
   LoginRequest,
+  OIDCProvider,
+  RecoveryCodes,
   RegisterRequest,
+  SecondFactor,
+  TwoFactorEnrollment,
+  UserPermissions,
 } from '../types';
 import { JwtPayload, jwtDecode } from 'jwt-decode';
 import {
@@ -27,11 +39,14 @@ export class AuthService {
     token: null,
     isLogin: false,
     email: null,
-    isAdmin: false,
+    permissions: [],
   };
 
   user$ = new BehaviorSubject<AuthUser>(AuthService.defaultUserState);
 
+  // requests that fail together share one refresh
+  private refreshing$: Observable<string> | null = null;
+
   constructor(
     private readonly http: HttpClient,
     private readonly localStorageService: LocalStorageService
@@ -41,20 +56,51 @@ export class AuthService {
     return this.http.post<void>(this._authUrlSegment + '/register', request);
   }
 
-  login(request: LoginRequest): Observable<AuthResponse> {
+  login(
+    request: LoginRequest,
+    secondFactor: SecondFactor = {}
+  ): Observable<AuthResponse> {
     return this.http.post<AuthResponse>(
       this._authUrlSegment + '/login',
+      secondFactor,
+      { headers: this.passwordHeaders(request) }
+    );
+  }
+
+  enrollTwoFactor(request: LoginRequest): Observable<TwoFactorEnrollment> {
+    return this.http.post<TwoFactorEnrollment>(
+      this._authUrlSegment + '/2fa/enroll',
       {},
-      {
-        headers: new HttpHeaders({
-          'Content-Type': 'application/json',
-          Authorization:
-            'Basic ' + btoa(`${request.email}:${request.password}`),
-        }),
-      }
+      { headers: this.passwordHeaders(request) }
     );
   }
 
+  twoFactorQRCode(request: LoginRequest): Observable<Blob> {
+    return this.http.get(this._authUrlSegment + '/2fa/qr', {
+      headers: this.passwordHeaders(request),
+      responseType: 'blob',
+    });
+  }
+
+  confirmTwoFactor(
+    request: LoginRequest,
+    code: string
+  ): Observable<RecoveryCodes> {
+    return this.http.post<RecoveryCodes>(
+      this._authUrlSegment + '/2fa/confirm',
+      { code },
+      { headers: this.passwordHeaders(request) }
+    );
+  }
+
+  oidcProvider(): Observable<OIDCProvider> {
+    return this.http.get<OIDCProvider>(this._authUrlSegment + '/oidc');
+  }
+
+  oidcLoginUrl(): string {
+    return this._authUrlSegment + '/oidc/login';
+  }
+
   validateSession(): Promise<any> {
     const jwt: string = this.localStorageService.get(this._sessionAuthKey);
 
@@ -62,6 +108,9 @@ export class AuthService {
       this.rehydrateAuthUser(jwt);
       return lastValueFrom(
         this.http.get(this._userUrlSegment).pipe(
+          switchMap((user: any) =>
+            this.loadPermissions().pipe(map((_) => user))
+          ),
           catchError((_: HttpErrorResponse) => {
             this.rehydrateAuthUser(null);
             return of(null);
@@ -73,6 +122,37 @@ export class AuthService {
     return lastValueFrom(of(null));
   }
 
+  // the pages a user can see follow the permissions the server grants them, not their role names
+  loadPermissions(): Observable<UserPermissions> {
+    return this.http
+      .get<UserPermissions>(this._userUrlSegment + '/permissions')
+      .pipe(
+        tap((response: UserPermissions) =>
+          this.user$.next({
+            ...this.user$.getValue(),
+            permissions: response.permissions,
+          })
+        )
+      );
+  }
+
+  refresh(): Observable<string> {
+    if (!this.refreshing$) {
+      this.refreshing$ = this.http
+        .post<AuthResponse>(this._authUrlSegment + '/refresh', {})
+        .pipe(
+          map((response: AuthResponse) => {
+            this.rehydrateAuthUser(response.token);
+            return response.token;
+          }),
+          finalize(() => (this.refreshing$ = null)),
+          shareReplay(1)
+        );
+    }
+
+    return this.refreshing$;
+  }
+
   logout(): Observable<void> {
     return this.http.delete<null>(this._authUrlSegment + '/logout').pipe(
       map((_) => {
@@ -89,6 +169,13 @@ export class AuthService {
     this.user$.next(AuthService.defaultUserState);
   }
 
+  private passwordHeaders(request: LoginRequest): HttpHeaders {
+    return new HttpHeaders({
+      'Content-Type': 'application/json',
+      Authorization: 'Basic ' + btoa(`${request.email}:${request.password}`),
+    });
+  }
+
   private rehydrateAuthUser(token: string): void {
     this.localStorageService.update(this._sessionAuthKey, token);
 
@@ -96,13 +183,13 @@ export class AuthService {
       const claims: JwtPayload & {
         ID: string;
         Name: string;
-        Groups: string[];
       } = jwtDecode(token);
       this.user$.next({
         isLogin: true,
         email: claims.ID,
         token: token,
-        isAdmin: claims.Groups?.includes('admin'),
+        // a refreshed token keeps the permissions that were loaded with the session
+        permissions: this.user$.getValue().permissions,
       });
     } catch (error) {
       this.resetAndNext();
@@ -1,4 +1,5 @@
 import { HttpClient } from '@angular/common/http';
+import { of } from 'rxjs';
 import { AuthService, VersionService } from '../services';
 
 export class SharedSpies {
@@ -18,10 +19,14 @@ export class SharedSpies {
   }
 
   static createAuthServiceSpy(): jasmine.SpyObj<AuthService> {
-    return jasmine.createSpyObj<AuthService>('AuthService', [
+    const spy = jasmine.createSpyObj<AuthService>('AuthService', [
       'logout',
       'login',
       'register',
+      'oidcProvider',
+      'refresh',
     ]);
+    spy.oidcProvider.and.returnValue(of({ enabled: false, name: '' }));
+    return spy;
   }
 }
@@ -6,12 +6,56 @@ export type AuthUser = {
   token: string;
   isLogin: boolean;
   email: string;
-  isAdmin: boolean;
+  // what the server says the user's roles allow
+  permissions: Permission[];
 };
 
+// the permissions the dashboard checks, they match the server's
+export enum Permission {
+  ViewMembers = 'members.view',
+  ManageResources = 'resources.manage',
+  OpenResources = 'resources.open',
+  ViewReports = 'reports.view',
+}
+
+export type UserPermissions = {
+  permissions: Permission[];
+};
+
+export function hasPermission(
+  user: AuthUser,
+  permission: Permission
+): boolean {
+  return user.permissions?.includes(permission) ?? false;
+}
+
 export type RegisterRequest = {
   email: string;
   password: string;
 };
 
 export type LoginRequest = RegisterRequest;
+
+export type SecondFactor = {
+  code?: string;
+  recoveryCode?: string;
+};
+
+export type TwoFactorChallenge = {
+  twoFactorRequired: boolean;
+  setupRequired: boolean;
+};
+
+export type TwoFactorEnrollment = {
+  secret: string;
+  uri: string;
+};
+
+export type RecoveryCodes = {
+  codes: string[];
+};
+
+export type OIDCProvider = {
+  enabled: boolean;
+  name: string;
+};
@@ -1,5 +1,9 @@
+import { Permission } from './auth.types';
+
 export type NavigationLink = {
   routeName: string;
   routeLink: string;
   routeIcon: string;
+  // links with a permission are only shown to users who have it
+  permission?: Permission;
 };